@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ReplicaSetKey identifies the replicaset that stamped out the object
+	ReplicaSetKey = "auto.nephio.org/replicaset"
+	// ReplicaIndexKey identifies the index of the replica
+	ReplicaIndexKey = "auto.nephio.org/replica-index"
+)
+
+// GetCondition returns the condition based on the condition kind
+func (r *ReplicaSet) GetCondition(t resourcev1alpha1.ConditionType) resourcev1alpha1.Condition {
+	return r.Status.GetCondition(t)
+}
+
+// SetConditions sets the conditions on the resource. it allows for 0, 1 or more conditions
+// to be set at once
+func (r *ReplicaSet) SetConditions(c ...resourcev1alpha1.Condition) {
+	r.Status.SetConditions(c...)
+}
+
+// GetReplicas returns the amount of replicas, default 1
+func (r *ReplicaSet) GetReplicas() int32 {
+	if r.Spec.Replicas == nil {
+		return 1
+	}
+	return *r.Spec.Replicas
+}
+
+// GetTemplate returns the template as an unstructured object
+func (r *ReplicaSet) GetTemplate() (*unstructured.Unstructured, error) {
+	if len(r.Spec.Template.Raw) == 0 {
+		return nil, fmt.Errorf("template is empty")
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(r.Spec.Template.Raw); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// GetGroupVersionKind returns the gvk of the object selector
+func (r ObjectSelector) GetGroupVersionKind() (schema.GroupVersionKind, error) {
+	if r.APIVersion == nil || r.Kind == nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("selector %s requires apiVersion and kind", r.VariableName)
+	}
+	gv, err := schema.ParseGroupVersion(*r.APIVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return gv.WithKind(*r.Kind), nil
+}
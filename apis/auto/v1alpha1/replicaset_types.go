@@ -21,6 +21,7 @@ import (
 
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/meta"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -85,6 +86,9 @@ type ReplicaSetStatus struct {
 	// 1 conditions is used:
 	// - a condition for the ready status
 	resourcev1alpha1.ConditionedStatus `json:",inline" yaml:",inline"`
+	// UsedTemplateRef identifies the apiVersion and kind of the template the
+	// replicas were last stamped out from
+	UsedTemplateRef *corev1.ObjectReference `json:"usedTemplateRef,omitempty" yaml:"usedTemplateRef,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -39,7 +39,7 @@ const (
 	NephioPurposeKey          = "nephio.org/purpose"
 	NephioIndexKey            = "nephio.org/index"
 	NephioProviderKey         = "nephio.org/provider"
-	NephioWiringKey           = "nephio.org/wiring"
+	NephioWiringKey           = "nephio.org/wiring" // overlay
 	// user defined topology
 	// endpoint
 	NephioInventorySlot      = "inv.nephio.org/slot"
@@ -48,10 +48,12 @@ const (
 	NephioInventoryConnector = "inv.nephio.org/connector"
 	NephioInventoryPort      = "inv.nephio.org/port"
 	// node
-	NephioInventoryRack   = "inv.nephio.org/rack"
-	NephioInventoryRoom   = "inv.nephio.org/room"
-	NephioInventoryRow    = "inv.nephio.org/row"
-	NephioInventoryColumn = "inv.nephio.org/column"
+	NephioInventoryRack             = "inv.nephio.org/rack"
+	NephioInventoryRoom             = "inv.nephio.org/room"
+	NephioInventoryRow              = "inv.nephio.org/row"
+	NephioInventoryColumn           = "inv.nephio.org/column"
+	NephioInventoryNodePool         = "inv.nephio.org/node-pool"
+	NephioInventoryAvailabilityZone = "inv.nephio.org/availability-zone"
 	// logical index
 	NephioInventoryPodIndex        = "inv.nephio.org/pod-index"
 	NephioInventoryPlaneIndex      = "inv.nephio.org/plane-index"
@@ -66,3 +68,8 @@ const (
 	NephioWireGRPCAddress = "wire.nephio.org/grpc-address"
 	NephioWireGRPCPort    = "wire.nephio.org/grpc-port"
 )
+
+const (
+	// NephioWiringOverlay is the value of the NephioWiringKey for overlay wiring
+	NephioWiringOverlay = "overlay"
+)
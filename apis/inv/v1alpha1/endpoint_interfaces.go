@@ -63,8 +63,9 @@ func (r *Endpoint) GetLinkName() string {
 }
 
 // IsNodeDiverse returns false if a the endpoint nodeName matches
-// the name in the node slice
-// Only the
+// the name in the node slice. The node at index idx is not considered
+// since it is the node that is being selected. The node slice can hold
+// any amount of nodes.
 func (r *Endpoint) IsNodeDiverse(idx int, nodes []string) bool {
 	for nodeIdx, nodeName := range nodes {
 		// only for the nodes
@@ -75,6 +76,33 @@ func (r *Endpoint) IsNodeDiverse(idx int, nodes []string) bool {
 	return true
 }
 
+// IsDiverse returns true if the endpoint is node diverse from the selected
+// endpoints and has a different value for each of the label keys. The endpoint
+// at index idx is not considered, nil entries are not selected yet.
+// An endpoint without one of the label keys is not diverse as the diversity
+// cannot be guaranteed.
+func (r *Endpoint) IsDiverse(idx int, eps []*Endpoint, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := r.Labels[key]; !ok {
+			return false
+		}
+	}
+	for epIdx, ep := range eps {
+		if epIdx == idx || ep == nil {
+			continue
+		}
+		if ep.Spec.NodeName == r.Spec.NodeName {
+			return false
+		}
+		for _, key := range keys {
+			if ep.Labels[key] == r.Labels[key] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // IsAllocated returns a bool that indicates if the endpoint
 // was already allocated by another owner
 func (r *Endpoint) IsAllocated(cr client.Object) bool {
@@ -51,13 +51,30 @@ func (r *Link) GetTopologies() []string {
 	return topologies
 }
 
+// Validate validates the link spec; a link requires exactly 2 endpoints
+// with a topology and an endpoint cannot be referenced twice
 func (r *Link) Validate() error {
 	if len(r.Spec.Endpoints) != 2 {
 		return fmt.Errorf("a link requires exactly 2 endpoints, got: %v", len(r.Spec.Endpoints))
 	}
+	endpoints := map[string]struct{}{}
+	for idx, ep := range r.Spec.Endpoints {
+		if ep.Topology == "" {
+			return fmt.Errorf("endpoint %d requires a topology", idx)
+		}
+		if _, ok := endpoints[ep.String()]; ok {
+			return fmt.Errorf("endpoint %s is referenced more than once", ep.String())
+		}
+		endpoints[ep.String()] = struct{}{}
+	}
 	return nil
 }
 
+// String returns the endpoint as <topology>:<nodeName>:<interfaceName>
+func (r LinkEndpointSpec) String() string {
+	return fmt.Sprintf("%s:%s:%s", r.Topology, r.NodeName, r.InterfaceName)
+}
+
 // BuildLink returns a Link from a client Object a crName and
 // a Link Spec/Status
 func BuildLink(meta metav1.ObjectMeta, spec LinkSpec, status LinkStatus) *Link {
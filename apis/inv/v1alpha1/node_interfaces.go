@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,6 +43,27 @@ func (r *NodeList) GetItems() []client.Object {
 	return objs
 }
 
+// Validate validates the node; the topology label must match the namespace
+// of the node and the provider is mandatory
+func (r *Node) Validate() error {
+	if topology, ok := r.GetLabels()[NephioTopologyKey]; ok && topology != r.GetNamespace() {
+		return fmt.Errorf("label %s must match the namespace %s, got: %s", NephioTopologyKey, r.GetNamespace(), topology)
+	}
+	return r.Spec.Validate()
+}
+
+// Validate validates the node spec; the provider is mandatory and
+// the topology label cannot be set as a user defined label
+func (r NodeSpec) Validate() error {
+	if r.Provider == "" {
+		return fmt.Errorf("a node requires a provider")
+	}
+	if _, ok := r.Labels[NephioTopologyKey]; ok {
+		return fmt.Errorf("label %s is reserved and cannot be a user defined label", NephioTopologyKey)
+	}
+	return nil
+}
+
 // BuildNode returns a Node from a client Object a crName and
 // an Node Spec/Status
 func BuildNode(meta metav1.ObjectMeta, spec NodeSpec, status NodeStatus) *Node {
@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+)
+
+// GetCondition returns the condition based on the condition kind
+func (r *NodePool) GetCondition(t resourcev1alpha1.ConditionType) resourcev1alpha1.Condition {
+	return r.Status.GetCondition(t)
+}
+
+// SetConditions sets the conditions on the resource. it allows for 0, 1 or more conditions
+// to be set at once
+func (r *NodePool) SetConditions(c ...resourcev1alpha1.Condition) {
+	r.Status.SetConditions(c...)
+}
+
+// GetNodes returns the amount of nodes in the nodepool, default 1
+func (r *NodePool) GetNodes() uint32 {
+	if r.Spec.Nodes == nil {
+		return 1
+	}
+	return *r.Spec.Nodes
+}
+
+// GetNodeName returns the name of the node with index idx in the nodepool
+func (r *NodePool) GetNodeName(idx uint32) string {
+	return fmt.Sprintf("%s-%d", r.GetName(), idx)
+}
@@ -28,6 +28,10 @@ import (
 
 // NodePoolSpec defines the desired state of NodePool
 type NodePoolSpec struct {
+	// Nodes defines the amount of nodes in the nodepool
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=1
+	Nodes *uint32 `json:"nodes,omitempty" yaml:"nodes,omitempty"`
 	// Provider defines the provider implementing this nodepool.
 	Provider string `json:"provider" yaml:"provider"`
 	// UserDefinedLabels define metadata  associated to the resource.
@@ -58,6 +62,7 @@ type NodePoolStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="TOPOLOGY",type="string",JSONPath=".metadata.namespace"
+// +kubebuilder:printcolumn:name="NODES",type="integer",JSONPath=".spec.nodes"
 // +kubebuilder:resource:categories={nephio,inv}
 // NodePool is the Schema for the NodePool API
 type NodePool struct {
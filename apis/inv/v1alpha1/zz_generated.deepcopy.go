@@ -728,6 +728,11 @@ func (in *NodePoolList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(uint32)
+		**out = **in
+	}
 	in.UserDefinedLabels.DeepCopyInto(&out.UserDefinedLabels)
 	if in.Location != nil {
 		in, out := &in.Location, &out.Location
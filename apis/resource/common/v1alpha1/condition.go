@@ -49,6 +49,16 @@ const (
 	ConditionReasonAction   ConditionReason = "Action"
 )
 
+// Reasons a claim failed
+const (
+	ConditionReasonIndexNotReady    ConditionReason = "IndexNotReady"
+	ConditionReasonPoolExhausted    ConditionReason = "PoolExhausted"
+	ConditionReasonSelectorNoMatch  ConditionReason = "SelectorNoMatch"
+	ConditionReasonConflict         ConditionReason = "Conflict"
+	ConditionReasonValidationFailed ConditionReason = "ValidationFailed"
+	ConditionReasonQuotaExceeded    ConditionReason = "QuotaExceeded"
+)
+
 // Reasons a resource is synced or not
 const (
 	ConditionReasonReconcileSuccess ConditionReason = "ReconcileSuccess"
@@ -221,6 +231,18 @@ func Failed(msg string) Condition {
 	}}
 }
 
+// FailedWithReason returns a condition that indicates the resource
+// failed to get reconciled for the given reason.
+func FailedWithReason(reason ConditionReason, msg string) Condition {
+	return Condition{metav1.Condition{
+		Type:               string(ConditionTypeReady),
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(reason),
+		Message:            msg,
+	}}
+}
+
 // ReconcileSuccess returns a condition indicating that the controller
 // successfully completed the reconciliation of the resource.
 func ReconcileSuccess() Condition {
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// GetCondition returns the condition based on the condition type
+func (r *ClaimQuota) GetCondition(ct resourcev1alpha1.ConditionType) resourcev1alpha1.Condition {
+	return r.Status.GetCondition(ct)
+}
+
+// SetConditions sets the conditions on the resource. it allows for 0, 1 or more conditions
+// to be set at once
+func (r *ClaimQuota) SetConditions(c ...resourcev1alpha1.Condition) {
+	r.Status.SetConditions(c...)
+}
+
+// GetSelector returns the label selector of the claims the quota applies to
+func (r *ClaimQuota) GetSelector() (labels.Selector, error) {
+	if r.Spec.Selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(r.Spec.Selector)
+}
+
+// GetUsageSelector returns the label selector of the entries in the backend
+// that are held by the claims the quota applies to
+func (r *ClaimQuota) GetUsageSelector() (labels.Selector, error) {
+	selector, err := r.GetSelector()
+	if err != nil {
+		return nil, err
+	}
+	req, err := labels.NewRequirement(resourcev1alpha1.NephioNsnNamespaceKey, selection.Equals, []string{r.GetNamespace()})
+	if err != nil {
+		return nil, err
+	}
+	return selector.Add(*req), nil
+}
+
+// Selects returns true if the quota applies to a claim with the namespace and labels
+func (r *ClaimQuota) Selects(namespace string, l map[string]string) (bool, error) {
+	if namespace != r.GetNamespace() {
+		return false, nil
+	}
+	selector, err := r.GetSelector()
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(l)), nil
+}
+
+// GetLimits returns the limits of the resource in the index
+func (r *ClaimQuota) GetLimits(index corev1.ObjectReference, resource QuotaResource) []QuotaLimit {
+	limits := []QuotaLimit{}
+	cacheID := resourcev1alpha1.GetCacheID(index)
+	for _, l := range r.Spec.Limits {
+		if l.Resource == resource && resourcev1alpha1.GetCacheID(l.Index) == cacheID {
+			limits = append(limits, l)
+		}
+	}
+	return limits
+}
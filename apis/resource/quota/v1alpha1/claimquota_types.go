@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ClaimQuotaSpec defines the desired state of ClaimQuota
+type ClaimQuotaSpec struct {
+	// Selector selects the claims in the namespace of the quota the limits
+	// apply to, all claims in the namespace are selected if not set
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
+	// Limits define the max amount of resources the selected claims may hold
+	// per index
+	Limits []QuotaLimit `json:"limits" yaml:"limits"`
+}
+
+// QuotaResource is a resource held by claims in an index
+type QuotaResource string
+
+const (
+	// QuotaResourceAddresses are the ip addresses claimed in a NetworkInstance
+	QuotaResourceAddresses QuotaResource = "addresses"
+	// QuotaResourcePrefixes are the ip prefixes claimed in a NetworkInstance
+	QuotaResourcePrefixes QuotaResource = "prefixes"
+	// QuotaResourceVLANs are the vlans claimed in a VLANIndex
+	QuotaResourceVLANs QuotaResource = "vlans"
+)
+
+// QuotaLimit defines the max amount of a resource in an index
+type QuotaLimit struct {
+	// Index is the NetworkInstance for addresses and prefixes or the
+	// VLANIndex for vlans
+	Index corev1.ObjectReference `json:"index" yaml:"index"`
+	// Resource defines the resource that is limited
+	// +kubebuilder:validation:Enum=addresses;prefixes;vlans
+	Resource QuotaResource `json:"resource" yaml:"resource"`
+	// Max defines the max amount of the resource
+	// +kubebuilder:validation:Minimum=0
+	Max int64 `json:"max" yaml:"max"`
+}
+
+// ClaimQuotaStatus defines the observed state of ClaimQuota
+type ClaimQuotaStatus struct {
+	// ConditionedStatus provides the status of the quota using conditions
+	resourcev1alpha1.ConditionedStatus `json:",inline" yaml:",inline"`
+	// Usage reports the amount of the resource held by the selected claims
+	// for every limit
+	Usage []QuotaUsage `json:"usage,omitempty" yaml:"usage,omitempty"`
+}
+
+// QuotaUsage defines the amount of a resource held in an index
+type QuotaUsage struct {
+	Index    corev1.ObjectReference `json:"index" yaml:"index"`
+	Resource QuotaResource          `json:"resource" yaml:"resource"`
+	Used     int64                  `json:"used" yaml:"used"`
+	Max      int64                  `json:"max" yaml:"max"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:categories={nephio,resource}
+// ClaimQuota is the Schema for the claim quota API
+type ClaimQuota struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   ClaimQuotaSpec   `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Status ClaimQuotaStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClaimQuotaList contains a list of ClaimQuotas
+type ClaimQuotaList struct {
+	metav1.TypeMeta `json:",inline" yaml:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Items           []ClaimQuota `json:"items" yaml:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClaimQuota{}, &ClaimQuotaList{})
+}
+
+var (
+	ClaimQuotaKind             = reflect.TypeOf(ClaimQuota{}).Name()
+	ClaimQuotaGroupKind        = schema.GroupKind{Group: GroupVersion.Group, Kind: ClaimQuotaKind}.String()
+	ClaimQuotaKindAPIVersion   = ClaimQuotaKind + "." + GroupVersion.String()
+	ClaimQuotaGroupVersionKind = GroupVersion.WithKind(ClaimQuotaKind)
+)
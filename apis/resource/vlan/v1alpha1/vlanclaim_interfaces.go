@@ -100,18 +100,18 @@ func (r *VLANClaim) GetVLANClaimCtx() (*VLANClaimCtx, error) {
 		switch {
 		case len(split) == 1: // size based range
 			vlanClaimCtx.Kind = VLANClaimTypeSize
-			s, err := strconv.Atoi(split[0])
+			s, err := strconv.ParseUint(split[0], 10, 16)
 			if err != nil {
 				return nil, err
 			}
 			vlanClaimCtx.Size = uint16(s)
 		case len(split) == 2: // start:stop range
 			vlanClaimCtx.Kind = VLANClaimTypeRange
-			s, err := strconv.Atoi(split[0])
+			s, err := strconv.ParseUint(split[0], 10, 16)
 			if err != nil {
 				return nil, err
 			}
-			e, err := strconv.Atoi(split[1])
+			e, err := strconv.ParseUint(split[1], 10, 16)
 			if err != nil {
 				return nil, err
 			}
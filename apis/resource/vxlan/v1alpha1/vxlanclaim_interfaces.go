@@ -100,12 +100,19 @@ func BuildVXLANClaim(meta metav1.ObjectMeta, spec VXLANClaimSpec, status VXLANCl
 }
 
 func (r *VXLANClaim) GetVXLANClaimCtx() (*VXLANClaimCtx, error) {
-	return &VXLANClaimCtx{Kind: VXLANClaimTypeDynamic}, nil
+	vxlanClaimCtx := &VXLANClaimCtx{
+		Kind: VXLANClaimTypeDynamic,
+	}
+	if r.Spec.VXLANID != nil {
+		vxlanClaimCtx.Kind = VXLANClaimTypeStatic
+		vxlanClaimCtx.Start = *r.Spec.VXLANID
+	}
+	return vxlanClaimCtx, nil
 }
 
 type VXLANClaimCtx struct {
-	Kind VXLANClaimType
-	//Start uint16
+	Kind  VXLANClaimType
+	Start uint32
 	//Size  uint16
 }
 
@@ -113,7 +120,7 @@ type VXLANClaimType string
 
 const (
 	VXLANClaimTypeDynamic VXLANClaimType = "dynamic"
-	//VLANClaimTypeStatic  VLANClaimType = "static"
+	VXLANClaimTypeStatic  VXLANClaimType = "static"
 	//VLANClaimTypeSize    VLANClaimType = "size"
 	//VLANClaimTypeRange   VLANClaimType = "range"
 )
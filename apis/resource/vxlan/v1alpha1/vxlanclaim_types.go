@@ -30,6 +30,8 @@ import (
 type VXLANClaimSpec struct {
 	// VXLANIndex defines the vxlan index for the VXLAN Claim
 	VXLANIndex corev1.ObjectReference `json:"vxlanIndex" yaml:"vxlanIndex"`
+	// VXLANID defines the vxlan ID for the VXLAN claim
+	VXLANID *uint32 `json:"vxlanID,omitempty" yaml:"vxlanID,omitempty"`
 	// ClaimLabels define the user defined labels and selector labels used
 	// in resource claim
 	resourcev1alpha1.ClaimLabels `json:",inline" yaml:",inline"`
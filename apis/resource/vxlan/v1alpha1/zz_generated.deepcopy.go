@@ -103,6 +103,11 @@ func (in *VXLANClaimList) DeepCopyObject() runtime.Object {
 func (in *VXLANClaimSpec) DeepCopyInto(out *VXLANClaimSpec) {
 	*out = *in
 	out.VXLANIndex = in.VXLANIndex
+	if in.VXLANID != nil {
+		in, out := &in.VXLANID, &out.VXLANID
+		*out = new(uint32)
+		**out = **in
+	}
 	in.ClaimLabels.DeepCopyInto(&out.ClaimLabels)
 }
 
@@ -15,3 +15,45 @@ limitations under the License.
 */
 
 package v1alpha1
+
+import (
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+)
+
+// GetCondition returns the condition based on the condition kind
+func (r *Interconnect) GetCondition(t resourcev1alpha1.ConditionType) resourcev1alpha1.Condition {
+	return r.Status.GetCondition(t)
+}
+
+// SetConditions sets the conditions on the resource. it allows for 0, 1 or more conditions
+// to be set at once
+func (r *Interconnect) SetConditions(c ...resourcev1alpha1.Condition) {
+	r.Status.SetConditions(c...)
+}
+
+// GetTopologies returns the topologies used by the interconnect
+func (r *Interconnect) GetTopologies() []string {
+	topologies := []string{}
+	topologies = append(topologies, r.Spec.Topologies...)
+	for _, l := range r.Spec.Links {
+		for _, ep := range l.Endpoints {
+			if ep.Topology != nil {
+				topologies = append(topologies, *ep.Topology)
+			}
+		}
+	}
+	return topologies
+}
+
+// GetEndpointTopology returns the topology of the endpoint with index epIdx
+// of the interconnect link. The topology of the endpoint takes precedence over
+// the topologies defined globally in the spec.
+func (r *Interconnect) GetEndpointTopology(l InterconnectLink, epIdx int) string {
+	if epIdx < len(l.Endpoints) && l.Endpoints[epIdx].Topology != nil {
+		return *l.Endpoints[epIdx].Topology
+	}
+	if epIdx < len(r.Spec.Topologies) {
+		return r.Spec.Topologies[epIdx]
+	}
+	return ""
+}
@@ -16,7 +16,6 @@ limitations under the License.
 
 package v1alpha1
 
-/*
 import (
 	"reflect"
 
@@ -82,7 +81,6 @@ type InterconnectLinkEndpoint struct {
 	SelectorPolicy *SelectorPolicy `json:"selectorPolicy,omitempty" yaml:"selectorPolicy,omitempty"`
 }
 
-
 // InterconnectStatus defines the observed state of Interconnect
 type InterconnectStatus struct {
 	// ConditionedStatus provides the status of the Interconnect using conditions
@@ -128,4 +126,3 @@ var (
 		Kind:    InterconnectKind,
 	})
 )
-*/
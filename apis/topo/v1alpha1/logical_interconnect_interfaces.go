@@ -17,6 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"strings"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
 )
 
@@ -32,9 +36,62 @@ func (r *LogicalInterconnect) SetConditions(c ...resourcev1alpha1.Condition) {
 }
 
 func (r *LogicalInterconnect) GetTopologies() []string {
+	return r.Spec.GetTopologies()
+}
+
+// GetTopologies returns the topologies of the endpoints in the spec
+func (r *LogicalInterconnectSpec) GetTopologies() []string {
 	topologies := []string{}
-	for _, ep := range r.Spec.Endpoints {
+	for _, ep := range r.Endpoints {
 		topologies = append(topologies, ep.Topologies...)
 	}
 	return topologies
 }
+
+// GetNodeDiversity returns the amount of diverse nodes required by the policy,
+// 0 if node diversity is not requested
+func (r *SelectorPolicy) GetNodeDiversity() int {
+	if r == nil || r.NodeDiversity == nil || *r.NodeDiversity < 2 {
+		return 0
+	}
+	return int(*r.NodeDiversity)
+}
+
+// GetDiversityLabelKeys returns the label keys for which the selected nodes
+// must have a different value
+func (r *SelectorPolicy) GetDiversityLabelKeys() []string {
+	keys := []string{}
+	if r == nil {
+		return keys
+	}
+	if r.RackDiversity != nil && *r.RackDiversity {
+		keys = append(keys, invv1alpha1.NephioInventoryRack)
+	}
+	if r.RedundancyGroupDiversity != nil && *r.RedundancyGroupDiversity {
+		keys = append(keys, invv1alpha1.NephioInventoryRedundancyGroup)
+	}
+	if r.AvailabilityZoneDiversity != nil && *r.AvailabilityZoneDiversity {
+		keys = append(keys, invv1alpha1.NephioInventoryAvailabilityZone)
+	}
+	return keys
+}
+
+// IsDiverse returns true if the policy requests any form of diversity
+func (r *SelectorPolicy) IsDiverse() bool {
+	return r.GetNodeDiversity() > 0 || len(r.GetDiversityLabelKeys()) > 0
+}
+
+// String returns a human readable representation of the diversity policy
+func (r *SelectorPolicy) String() string {
+	policies := []string{}
+	if n := r.GetNodeDiversity(); n > 0 {
+		policies = append(policies, fmt.Sprintf("node diversity %d", n))
+	}
+	for _, key := range r.GetDiversityLabelKeys() {
+		policies = append(policies, fmt.Sprintf("%s diversity", key))
+	}
+	if len(policies) == 0 {
+		return "no diversity"
+	}
+	return strings.Join(policies, ", ")
+}
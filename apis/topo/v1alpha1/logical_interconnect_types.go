@@ -65,6 +65,15 @@ type SelectorPolicy struct {
 	// NodeDiversity defines the amount of different nodes to be used when
 	// selecting endpoints
 	NodeDiversity *uint16 `json:"nodeDiversity,omitempty" yaml:"nodeDiversity,omitempty"`
+	// RackDiversity is a selection policy to select endpoints on nodes in
+	// different racks, based on the inv.nephio.org/rack label
+	RackDiversity *bool `json:"rackDiversity,omitempty" yaml:"rackDiversity,omitempty"`
+	// RedundancyGroupDiversity is a selection policy to select endpoints on nodes in
+	// different redundancy groups, based on the inv.nephio.org/redundancy-group label
+	RedundancyGroupDiversity *bool `json:"redundancyGroupDiversity,omitempty" yaml:"redundancyGroupDiversity,omitempty"`
+	// AvailabilityZoneDiversity is a selection policy to select endpoints on nodes in
+	// different availability zones, based on the inv.nephio.org/availability-zone label
+	AvailabilityZoneDiversity *bool `json:"availabilityZoneDiversity,omitempty" yaml:"availabilityZoneDiversity,omitempty"`
 	// TopologyDiversity is a selection policy to select endpoints in diverse topologies
 	// TopologyDiversity *bool `json:"topologyDiversity,omitempty" yaml:"topologyDiversity,omitempty"`
 }
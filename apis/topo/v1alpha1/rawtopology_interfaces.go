@@ -17,6 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"sort"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
 )
 
@@ -30,3 +34,71 @@ func (r *RawTopology) GetCondition(t resourcev1alpha1.ConditionType) resourcev1a
 func (r *RawTopology) SetConditions(c ...resourcev1alpha1.Condition) {
 	r.Status.SetConditions(c...)
 }
+
+// Validate validates the raw topology; nodes and links need to be valid,
+// links can only reference nodes of the topology and an endpoint can
+// only be used once
+func (r *RawTopology) Validate() error {
+	if _, ok := r.Spec.Labels[invv1alpha1.NephioTopologyKey]; ok {
+		return fmt.Errorf("label %s is reserved and cannot be a user defined label", invv1alpha1.NephioTopologyKey)
+	}
+	if err := r.validateNodes(); err != nil {
+		return err
+	}
+	if err := r.validateLink2Nodes(); err != nil {
+		return err
+	}
+	return r.validateLinks()
+}
+
+func (r *RawTopology) validateNodes() error {
+	nodeNames := make([]string, 0, len(r.Spec.Nodes))
+	for nodeName := range r.Spec.Nodes {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		if err := r.Spec.Nodes[nodeName].Validate(); err != nil {
+			return fmt.Errorf("invalid node %s, err: %s", nodeName, err.Error())
+		}
+	}
+	return nil
+}
+
+func (r *RawTopology) validateLink2Nodes() error {
+	invalidNodeRef := []string{}
+	for _, l := range r.Spec.Links {
+		for _, e := range l.Endpoints {
+			epString := fmt.Sprintf("%s:%s", e.NodeName, e.InterfaceName)
+			if _, ok := r.Spec.Nodes[e.NodeName]; !ok {
+				invalidNodeRef = append(invalidNodeRef, epString)
+			}
+		}
+	}
+	if len(invalidNodeRef) != 0 {
+		return fmt.Errorf("endpoints %q has no node reference", invalidNodeRef)
+	}
+	return nil
+}
+
+func (r *RawTopology) validateLinks() error {
+	endpoints := map[string]struct{}{}
+	// dups accumulates duplicate links
+	dups := []string{}
+	for linkIdx, l := range r.Spec.Links {
+		if len(l.Endpoints) != 2 {
+			return fmt.Errorf("link %d requires exactly 2 endpoints, got: %v", linkIdx, len(l.Endpoints))
+		}
+		for _, e := range l.Endpoints {
+			epString := fmt.Sprintf("%s:%s", e.NodeName, e.InterfaceName)
+			if _, ok := endpoints[epString]; ok {
+				dups = append(dups, epString)
+			}
+			endpoints[epString] = struct{}{}
+		}
+	}
+	if len(dups) != 0 {
+		return fmt.Errorf("endpoints %q appeared more than once in the links section of the topology file", dups)
+	}
+	return nil
+}
@@ -27,6 +27,201 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Interconnect) DeepCopyInto(out *Interconnect) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Interconnect.
+func (in *Interconnect) DeepCopy() *Interconnect {
+	if in == nil {
+		return nil
+	}
+	out := new(Interconnect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Interconnect) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterconnectLink) DeepCopyInto(out *InterconnectLink) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.LogicalLinkId != nil {
+		in, out := &in.LogicalLinkId, &out.LogicalLinkId
+		*out = new(int)
+		**out = **in
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.Lacp != nil {
+		in, out := &in.Lacp, &out.Lacp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]InterconnectLinkEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.UserDefinedLabels.DeepCopyInto(&out.UserDefinedLabels)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterconnectLink.
+func (in *InterconnectLink) DeepCopy() *InterconnectLink {
+	if in == nil {
+		return nil
+	}
+	out := new(InterconnectLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterconnectLinkEndpoint) DeepCopyInto(out *InterconnectLinkEndpoint) {
+	*out = *in
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(string)
+		**out = **in
+	}
+	if in.LogicalEndpointName != nil {
+		in, out := &in.LogicalEndpointName, &out.LogicalEndpointName
+		*out = new(string)
+		**out = **in
+	}
+	if in.InterfaceName != nil {
+		in, out := &in.InterfaceName, &out.InterfaceName
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeName != nil {
+		in, out := &in.NodeName, &out.NodeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SelectorPolicy != nil {
+		in, out := &in.SelectorPolicy, &out.SelectorPolicy
+		*out = new(SelectorPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterconnectLinkEndpoint.
+func (in *InterconnectLinkEndpoint) DeepCopy() *InterconnectLinkEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(InterconnectLinkEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterconnectList) DeepCopyInto(out *InterconnectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Interconnect, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterconnectList.
+func (in *InterconnectList) DeepCopy() *InterconnectList {
+	if in == nil {
+		return nil
+	}
+	out := new(InterconnectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InterconnectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterconnectSpec) DeepCopyInto(out *InterconnectSpec) {
+	*out = *in
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]InterconnectLink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Topologies != nil {
+		in, out := &in.Topologies, &out.Topologies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterconnectSpec.
+func (in *InterconnectSpec) DeepCopy() *InterconnectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InterconnectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterconnectStatus) DeepCopyInto(out *InterconnectStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterconnectStatus.
+func (in *InterconnectStatus) DeepCopy() *InterconnectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InterconnectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogicalInterconnect) DeepCopyInto(out *LogicalInterconnect) {
 	*out = *in
@@ -288,6 +483,21 @@ func (in *SelectorPolicy) DeepCopyInto(out *SelectorPolicy) {
 		*out = new(uint16)
 		**out = **in
 	}
+	if in.RackDiversity != nil {
+		in, out := &in.RackDiversity, &out.RackDiversity
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RedundancyGroupDiversity != nil {
+		in, out := &in.RedundancyGroupDiversity, &out.RedundancyGroupDiversity
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AvailabilityZoneDiversity != nil {
+		in, out := &in.AvailabilityZoneDiversity, &out.AvailabilityZoneDiversity
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorPolicy.
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/proto/resource"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultAddress = "127.0.0.1:9999"
+	defaultTimeout = 30 * time.Second
+)
+
+// Flags are the flags to connect to the resource backend
+type Flags struct {
+	Address    string
+	Insecure   bool
+	SkipVerify bool
+	TokenFile  string
+	Timeout    time.Duration
+	// HealthService selects the serving instance of an active/standby backend
+	HealthService string
+}
+
+// AddFlags adds the flags to connect to the resource backend to the flag set
+func (r *Flags) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&r.Address, "address", defaultAddress,
+		"address of the resource backend, a comma separated list of addresses for an active/standby backend")
+	fs.BoolVar(&r.Insecure, "insecure", false, "connect to the resource backend without tls")
+	fs.BoolVar(&r.SkipVerify, "skip-verify", false, "skip the verification of the resource backend certificate")
+	fs.StringVar(&r.TokenFile, "token-file", "", "file with the bearer token sent with every request")
+	fs.DurationVar(&r.Timeout, "timeout", defaultTimeout, "timeout of a request to the resource backend")
+	fs.StringVar(&r.HealthService, "health-service", ipamv1alpha1.GroupVersion.String(),
+		"grpc health service of the resource backend, the requests are sent to the instance that is serving, empty disables the health check")
+}
+
+// Connect returns a client of the resource backend and a function that
+// closes the connection, the requests wait for the backend to be serving
+func (r *Flags) Connect() (resourcepb.ResourceClient, func(), error) {
+	c, err := resource.New(&resource.Config{
+		Address:       r.Address,
+		Insecure:      r.Insecure,
+		SkipVerify:    r.SkipVerify,
+		TokenFile:     r.TokenFile,
+		HealthService: r.HealthService,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.Get(), func() { _ = c.Delete() }, nil
+}
+
+// Context returns the context of a request with the timeout of the flags
+func (r *Flags) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, r.Timeout)
+}
+
+// CallOptions are the options of a request to the resource backend
+func CallOptions() []grpc.CallOption {
+	return []grpc.CallOption{grpc.WaitForReady(true)}
+}
+
+// ResponseError returns the error of a response of the resource backend, nil
+// is returned if the response has no error
+func ResponseError(code resourcepb.ErrorCode, msg string) error {
+	if code == resourcepb.ErrorCode_NoError {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", code.String(), msg)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	OutputTable = "table"
+	OutputYAML  = "yaml"
+	OutputJSON  = "json"
+)
+
+// Printer prints the output of a command as a table, yaml or json
+type Printer struct {
+	Output string
+	// printed is set after the first print, such that the header of a table
+	// is printed once and the yaml documents are separated
+	printed bool
+	// widths of the table columns, which are kept across prints such that
+	// the rows of subsequent prints are aligned
+	widths []int
+}
+
+// AddFlags adds the output flag to the flag set
+func (r *Printer) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&r.Output, "output", "o", OutputTable, "output format, one of table, yaml or json")
+}
+
+// Validate returns an error if the output format is not supported
+func (r *Printer) Validate() error {
+	switch r.Output {
+	case OutputTable, OutputYAML, OutputJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported output %q, expecting one of %s, %s or %s", r.Output, OutputTable, OutputYAML, OutputJSON)
+}
+
+// Print prints the object as yaml or json, a table is printed with the
+// header and rows
+func (r *Printer) Print(w io.Writer, obj any, header []string, rows [][]string) error {
+	defer func() { r.printed = true }()
+	switch r.Output {
+	case OutputYAML:
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if r.printed {
+			fmt.Fprintln(w, "---")
+		}
+		_, err = w.Write(b)
+		return err
+	case OutputJSON:
+		b, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+	lines := rows
+	if !r.printed {
+		lines = append([][]string{header}, rows...)
+	}
+	for _, line := range lines {
+		for i, cell := range line {
+			if i >= len(r.widths) {
+				r.widths = append(r.widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > r.widths[i] {
+				r.widths[i] = n
+			}
+		}
+	}
+	for _, line := range lines {
+		var sb strings.Builder
+		for i, cell := range line {
+			sb.WriteString(cell)
+			if i < len(line)-1 {
+				sb.WriteString(strings.Repeat(" ", r.widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimRight(sb.String(), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TreePrefix returns the prefix of a node in a tree, where last indicates
+// for the node and its ancestors if they are the last child of their parent
+func TreePrefix(last []bool) string {
+	var sb strings.Builder
+	for i, l := range last {
+		switch {
+		case i < len(last)-1 && l:
+			sb.WriteString("   ")
+		case i < len(last)-1:
+			sb.WriteString("│  ")
+		case l:
+			sb.WriteString("└─ ")
+		default:
+			sb.WriteString("├─ ")
+		}
+	}
+	return sb.String()
+}
+
+// Percentage returns the utilization as a percentage
+func Percentage(utilization float64) string {
+	return fmt.Sprintf("%.2f%%", utilization*100)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	type row struct {
+		Name  string `json:"name"`
+		Owner string `json:"owner,omitempty"`
+	}
+	cases := map[string]struct {
+		output string
+		want   string
+	}{
+		// the columns of the second print are aligned with the first print
+		// and the header is printed once
+		OutputTable: {
+			output: OutputTable,
+			want:   "NAME  OWNER\na     IPClaim default/a\nlong\n",
+		},
+		OutputYAML: {
+			output: OutputYAML,
+			want:   "name: a\nowner: IPClaim default/a\n---\nname: long\n",
+		},
+		OutputJSON: {
+			output: OutputJSON,
+			want:   "{\n  \"name\": \"a\",\n  \"owner\": \"IPClaim default/a\"\n}\n{\n  \"name\": \"long\"\n}\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &Printer{Output: tc.output}
+			if err := p.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			var b bytes.Buffer
+			for _, r := range []row{{Name: "a", Owner: "IPClaim default/a"}, {Name: "long"}} {
+				if err := p.Print(&b, r, []string{"NAME", "OWNER"}, [][]string{{r.Name, r.Owner}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if b.String() != tc.want {
+				t.Errorf("want:\n%q\ngot:\n%q", tc.want, b.String())
+			}
+		})
+	}
+
+	if err := (&Printer{Output: "xml"}).Validate(); err == nil {
+		t.Errorf("want an error for an unsupported output")
+	}
+}
+
+func TestTreePrefix(t *testing.T) {
+	cases := map[string]struct {
+		last []bool
+		want string
+	}{
+		"Root":       {last: nil, want: ""},
+		"Child":      {last: []bool{false}, want: "├─ "},
+		"LastChild":  {last: []bool{true}, want: "└─ "},
+		"GrandChild": {last: []bool{false, true}, want: "│  └─ "},
+		"LastParent": {last: []bool{true, false}, want: "   ├─ "},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := TreePrefix(tc.last); got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Indices returns the indices of the backend of the group version, which are
+// filtered by namespace and name when set
+func Indices(ctx context.Context, c resourcepb.ResourceClient, gv schema.GroupVersion, namespace, name string) ([]backend.IndexSnapshot, error) {
+	resp, err := c.Export(ctx, &resourcepb.ExportRequest{GroupVersions: []string{gv.String()}}, CallOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	if err := ResponseError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+		return nil, err
+	}
+	s, err := backend.ParseSnapshot([]byte(resp.Snapshot))
+	if err != nil {
+		return nil, err
+	}
+	indices := []backend.IndexSnapshot{}
+	for _, b := range s.Backends {
+		for _, idx := range b.Indices {
+			if (namespace == "" || idx.Namespace == namespace) && (name == "" || idx.Name == name) {
+				indices = append(indices, idx)
+			}
+		}
+	}
+	return indices, nil
+}
+
+// Claim returns the namespace and name of the claim of the entry
+func Claim(labels map[string]string) string {
+	if labels[resourcev1alpha1.NephioNsnNameKey] == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", labels[resourcev1alpha1.NephioNsnNamespaceKey], labels[resourcev1alpha1.NephioNsnNameKey])
+}
+
+// Owner returns the kind, namespace and name of the owner of the entry
+func Owner(labels map[string]string) string {
+	if labels[resourcev1alpha1.NephioOwnerNsnNameKey] == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %s/%s",
+		meta.StringToGVK(labels[resourcev1alpha1.NephioOwnerGvkKey]).Kind,
+		labels[resourcev1alpha1.NephioOwnerNsnNamespaceKey],
+		labels[resourcev1alpha1.NephioOwnerNsnNameKey])
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"testing"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+)
+
+func TestClaimOwner(t *testing.T) {
+	cases := map[string]struct {
+		labels    map[string]string
+		wantClaim string
+		wantOwner string
+	}{
+		"Claimed": {
+			labels: map[string]string{
+				resourcev1alpha1.NephioNsnNamespaceKey:      "default",
+				resourcev1alpha1.NephioNsnNameKey:           "claim",
+				resourcev1alpha1.NephioOwnerGvkKey:          ipamv1alpha1.NetworkInstanceKindGVKString,
+				resourcev1alpha1.NephioOwnerNsnNamespaceKey: "default",
+				resourcev1alpha1.NephioOwnerNsnNameKey:      "vpc-1",
+			},
+			wantClaim: "default/claim",
+			wantOwner: "NetworkInstance default/vpc-1",
+		},
+		// the entries that are not claimed e.g. reserved entries have no
+		// claim and no owner
+		"NotClaimed": {
+			labels: map[string]string{resourcev1alpha1.NephioPrefixKindKey: "aggregate"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Claim(tc.labels); got != tc.wantClaim {
+				t.Errorf("claim: want %q, got %q", tc.wantClaim, got)
+			}
+			if got := Owner(tc.labels); got != tc.wantOwner {
+				t.Errorf("owner: want %q, got %q", tc.wantOwner, got)
+			}
+		})
+	}
+}
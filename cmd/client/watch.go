@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// WatchEvent is a claim event of the resource backend
+type WatchEvent struct {
+	Sequence uint64 `json:"sequence"`
+	Status   string `json:"status"`
+	Kind     string `json:"kind,omitempty"`
+	Claim    string `json:"claim,omitempty"`
+	Owner    string `json:"owner,omitempty"`
+}
+
+// NewWatchRunner returns a command runner that watches the events of the
+// claims of an owner kind, the claims are of the kind of gvk.
+func NewWatchRunner(ctx context.Context, gvk, ownerGvk schema.GroupVersionKind) *WatchRunner {
+	r := &WatchRunner{
+		Ctx: ctx,
+		gvk: gvk,
+	}
+	c := &cobra.Command{
+		Use:   "watch",
+		Args:  cobra.NoArgs,
+		Short: "watch the events of the claims of an owner kind till interrupted",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	r.Printer.AddFlags(c.Flags())
+	c.Flags().StringVar(
+		&r.OwnerGvk, "owner-gvk", meta.GVKToString(ownerGvk), "kind.version.group of the owners of the watched claims")
+	return r
+}
+
+type WatchRunner struct {
+	Command  *cobra.Command
+	Client   Flags
+	Printer  Printer
+	OwnerGvk string
+	Ctx      context.Context
+
+	gvk schema.GroupVersionKind
+}
+
+func (r *WatchRunner) runE(c *cobra.Command, args []string) error {
+	if err := r.Printer.Validate(); err != nil {
+		return err
+	}
+	rc, closeFn, err := r.Client.Connect()
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+
+	ctx, cancel := signal.NotifyContext(r.Ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	hostname, _ := os.Hostname()
+	stream, err := rc.WatchClaim(ctx, &resourcepb.WatchRequest{
+		Header: &resourcepb.Header{
+			Gvk:      meta.PointerResourcePBGVK(meta.GetResourcePbGVKFromSchemaGVK(r.gvk)),
+			OwnerGvk: meta.PointerResourcePBGVK(meta.StringToResourcePbGVK(r.OwnerGvk)),
+		},
+		ClientId: fmt.Sprintf("cli-%s-%d", hostname, os.Getpid()),
+	}, CallOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "cannot watch claims")
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil || status.Code(err) == codes.Canceled {
+				return nil
+			}
+			return errors.Wrap(err, "watch failed")
+		}
+		h := resp.GetHeader()
+		event := WatchEvent{
+			Sequence: resp.Sequence,
+			Status:   resp.StatusCode.String(),
+			Kind:     h.GetGvk().GetKind(),
+		}
+		if name := h.GetNsn().GetName(); name != "" {
+			event.Claim = fmt.Sprintf("%s/%s", h.GetNsn().GetNamespace(), name)
+		}
+		if name := h.GetOwnerNsn().GetName(); name != "" {
+			event.Owner = fmt.Sprintf("%s %s/%s", h.GetOwnerGvk().GetKind(), h.GetOwnerNsn().GetNamespace(), name)
+		}
+		if err := r.Printer.Print(c.OutOrStdout(), event,
+			[]string{"SEQUENCE", "STATUS", "KIND", "CLAIM", "OWNER"},
+			[][]string{{fmt.Sprint(event.Sequence), event.Status, event.Kind, event.Claim, event.Owner}},
+		); err != nil {
+			return err
+		}
+	}
+}
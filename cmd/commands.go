@@ -20,6 +20,9 @@ import (
 	"context"
 
 	"github.com/nokia/k8s-ipam/cmd/generate"
+	"github.com/nokia/k8s-ipam/cmd/ipam"
+	"github.com/nokia/k8s-ipam/cmd/snapshot"
+	"github.com/nokia/k8s-ipam/cmd/vlan"
 	"github.com/spf13/cobra"
 )
 
@@ -29,5 +32,8 @@ func GetCommands(ctx context.Context, name, version string) []*cobra.Command {
 	generateCmd := generate.NewCommand(ctx, name, version)
 
 	c = append(c, generateCmd)
+	c = append(c, snapshot.NewCommand(ctx, name, version))
+	c = append(c, ipam.NewCommand(ctx, name, version))
+	c = append(c, vlan.NewCommand(ctx, name, version))
 	return c
 }
@@ -0,0 +1,237 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/cmd/client"
+	"github.com/nokia/k8s-ipam/pkg/iputil"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewCommand returns the ipam command with the subcommands to claim and
+// release prefixes and to inspect the network instances of the backend.
+func NewCommand(ctx context.Context, parent, version string) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "ipam",
+		Short: "claim, release and inspect prefixes in the resource backend",
+	}
+	c.AddCommand(NewClaimRunner(ctx, parent).Command)
+	c.AddCommand(NewReleaseRunner(ctx, parent).Command)
+	c.AddCommand(NewTreeRunner(ctx, parent).Command)
+	c.AddCommand(NewOwnerRunner(ctx, parent).Command)
+	c.AddCommand(client.NewWatchRunner(ctx, ipamv1alpha1.IPClaimGroupVersionKind, ipamv1alpha1.IPClaimGroupVersionKind).Command)
+	return c
+}
+
+// NewClaimRunner returns a command runner that claims a prefix.
+func NewClaimRunner(ctx context.Context, parent string) *ClaimRunner {
+	r := &ClaimRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "claim NAME",
+		Args:  cobra.ExactArgs(1),
+		Short: "claim a prefix in a network instance, a dynamic prefix is claimed if no prefix is set",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	r.Printer.AddFlags(c.Flags())
+	c.Flags().StringVarP(&r.Namespace, "namespace", "n", "default", "namespace of the claim and the network instance")
+	c.Flags().StringVar(&r.NetworkInstance, "network-instance", "", "name of the network instance")
+	c.Flags().StringVar(&r.Kind, "kind", string(ipamv1alpha1.PrefixKindNetwork), "prefix kind, one of network, loopback, pool or aggregate")
+	c.Flags().StringVar(&r.Prefix, "prefix", "", "static prefix to claim")
+	c.Flags().Uint8Var(&r.PrefixLength, "prefix-length", 0, "prefix length of a dynamic prefix")
+	c.Flags().BoolVar(&r.CreatePrefix, "create-prefix", false, "create the prefix instead of claiming an address of the prefix")
+	c.Flags().StringVar(&r.AddressFamily, "address-family", "", "address family of a dynamic prefix, one of ipv4 or ipv6")
+	c.Flags().StringToStringVar(&r.Labels, "label", nil, "labels of the claimed prefix")
+	c.Flags().StringToStringVar(&r.Selector, "selector", nil, "labels that select the parent prefix of a dynamic prefix")
+	c.Flags().BoolVar(&r.DryRun, "dry-run", false, "show the prefix the claim would be assigned without claiming it")
+	_ = c.MarkFlagRequired("network-instance")
+	return r
+}
+
+type ClaimRunner struct {
+	Command         *cobra.Command
+	Client          client.Flags
+	Printer         client.Printer
+	Namespace       string
+	NetworkInstance string
+	Kind            string
+	Prefix          string
+	PrefixLength    uint8
+	CreatePrefix    bool
+	AddressFamily   string
+	Labels          map[string]string
+	Selector        map[string]string
+	DryRun          bool
+	Ctx             context.Context
+}
+
+func (r *ClaimRunner) runE(c *cobra.Command, args []string) error {
+	if err := r.Printer.Validate(); err != nil {
+		return err
+	}
+	spec := ipamv1alpha1.IPClaimSpec{
+		Kind:            ipamv1alpha1.PrefixKind(r.Kind),
+		NetworkInstance: corev1.ObjectReference{Namespace: r.Namespace, Name: r.NetworkInstance},
+		ClaimLabels: resourcev1alpha1.ClaimLabels{
+			UserDefinedLabels: resourcev1alpha1.UserDefinedLabels{Labels: r.Labels},
+		},
+	}
+	if r.Prefix != "" {
+		pi, err := iputil.New(r.Prefix)
+		if err != nil {
+			return errors.Wrap(err, "invalid prefix")
+		}
+		prefixLength := uint8(pi.GetPrefixLength().Int())
+		spec.Prefix = &r.Prefix
+		spec.PrefixLength = &prefixLength
+	}
+	if r.PrefixLength != 0 {
+		spec.PrefixLength = &r.PrefixLength
+	}
+	if r.CreatePrefix {
+		spec.CreatePrefix = &r.CreatePrefix
+	}
+	if r.AddressFamily != "" {
+		af := iputil.AddressFamily(r.AddressFamily)
+		spec.AddressFamily = &af
+	}
+	if len(r.Selector) > 0 {
+		spec.Selector = &metav1.LabelSelector{MatchLabels: r.Selector}
+	}
+	req, err := buildClaimRequest(args[0], r.Namespace, spec)
+	if err != nil {
+		return err
+	}
+	req.DryRun = r.DryRun
+
+	rc, closeFn, err := r.Client.Connect()
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+	ctx, cancel := r.Client.Context(r.Ctx)
+	defer cancel()
+	resp, err := rc.Claim(ctx, req, client.CallOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "cannot claim prefix")
+	}
+	if err := client.ResponseError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+		return errors.Wrap(err, "cannot claim prefix")
+	}
+	claim := &ipamv1alpha1.IPClaim{}
+	if err := json.Unmarshal([]byte(resp.Status), claim); err != nil {
+		return errors.Wrap(err, "cannot unmarshal claim")
+	}
+	return r.Printer.Print(c.OutOrStdout(), claim,
+		[]string{"NAME", "NETWORK-INSTANCE", "KIND", "PREFIX", "GATEWAY"},
+		[][]string{{
+			fmt.Sprintf("%s/%s", claim.Namespace, claim.Name),
+			claim.Spec.NetworkInstance.Name,
+			string(claim.Spec.Kind),
+			stringValue(claim.Status.Prefix),
+			stringValue(claim.Status.Gateway),
+		}},
+	)
+}
+
+// NewReleaseRunner returns a command runner that releases a prefix.
+func NewReleaseRunner(ctx context.Context, parent string) *ReleaseRunner {
+	r := &ReleaseRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "release NAME",
+		Args:  cobra.ExactArgs(1),
+		Short: "release the prefix of a claim in a network instance",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	c.Flags().StringVarP(&r.Namespace, "namespace", "n", "default", "namespace of the claim and the network instance")
+	c.Flags().StringVar(&r.NetworkInstance, "network-instance", "", "name of the network instance")
+	_ = c.MarkFlagRequired("network-instance")
+	return r
+}
+
+type ReleaseRunner struct {
+	Command         *cobra.Command
+	Client          client.Flags
+	Namespace       string
+	NetworkInstance string
+	Ctx             context.Context
+}
+
+func (r *ReleaseRunner) runE(c *cobra.Command, args []string) error {
+	req, err := buildClaimRequest(args[0], r.Namespace, ipamv1alpha1.IPClaimSpec{
+		NetworkInstance: corev1.ObjectReference{Namespace: r.Namespace, Name: r.NetworkInstance},
+	})
+	if err != nil {
+		return err
+	}
+
+	rc, closeFn, err := r.Client.Connect()
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+	ctx, cancel := r.Client.Context(r.Ctx)
+	defer cancel()
+	resp, err := rc.DeleteClaim(ctx, req, client.CallOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "cannot release prefix")
+	}
+	if err := client.ResponseError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+		return errors.Wrap(err, "cannot release prefix")
+	}
+	fmt.Fprintf(c.OutOrStdout(), "ipclaim %s/%s released\n", r.Namespace, args[0])
+	return nil
+}
+
+// buildClaimRequest returns the request of a claim that is owned by the claim
+// itself, the claim does not expire
+func buildClaimRequest(name, namespace string, spec ipamv1alpha1.IPClaimSpec) (*resourcepb.ClaimRequest, error) {
+	claim := ipamv1alpha1.BuildIPClaim(metav1.ObjectMeta{Namespace: namespace, Name: name}, spec, ipamv1alpha1.IPClaimStatus{})
+	claim.AddOwnerLabelsToCR()
+	b, err := json.Marshal(claim)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal claim")
+	}
+	return clientproxy.BuildResourcePb(claim, name, string(b), "never", ipamv1alpha1.IPClaimGroupVersionKind), nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/cmd/client"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/backend/ipam"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// systemLabels are the labels of the backend that are shown in the columns
+// of the tree instead of the labels column
+var systemLabels = []string{
+	resourcev1alpha1.NephioOwnerGvkKey,
+	resourcev1alpha1.NephioOwnerNsnNameKey,
+	resourcev1alpha1.NephioOwnerNsnNamespaceKey,
+	resourcev1alpha1.NephioGvkKey,
+	resourcev1alpha1.NephioNsnNameKey,
+	resourcev1alpha1.NephioNsnNamespaceKey,
+	resourcev1alpha1.NephioOwnerRefKey,
+	resourcev1alpha1.NephioPrefixKindKey,
+	resourcev1alpha1.NephioAddressFamilyKey,
+	resourcev1alpha1.NephioSubnetKey,
+}
+
+// NewTreeRunner returns a command runner that shows the prefix trees.
+func NewTreeRunner(ctx context.Context, parent string) *TreeRunner {
+	r := &TreeRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "tree",
+		Args:  cobra.NoArgs,
+		Short: "show the prefixes of the network instances as a tree with their free addresses",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	r.Printer.AddFlags(c.Flags())
+	c.Flags().StringVarP(&r.Namespace, "namespace", "n", "", "namespace of the network instances, all namespaces if not set")
+	c.Flags().StringVar(&r.NetworkInstance, "network-instance", "", "name of the network instance, all network instances if not set")
+	c.Flags().StringVar(&r.Prefix, "prefix", "", "only show the prefixes within the prefix")
+	c.Flags().Uint32Var(&r.Depth, "depth", 0, "levels of the tree to show, all levels if 0")
+	return r
+}
+
+type TreeRunner struct {
+	Command         *cobra.Command
+	Client          client.Flags
+	Printer         client.Printer
+	Namespace       string
+	NetworkInstance string
+	Prefix          string
+	Depth           uint32
+	Ctx             context.Context
+}
+
+func (r *TreeRunner) runE(c *cobra.Command, args []string) error {
+	if err := r.Printer.Validate(); err != nil {
+		return err
+	}
+	rc, closeFn, err := r.Client.Connect()
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+	ctx, cancel := r.Client.Context(r.Ctx)
+	defer cancel()
+
+	refs := []corev1.ObjectReference{}
+	if r.NetworkInstance != "" {
+		refs = append(refs, resourcev1alpha1.GetCacheID(corev1.ObjectReference{Namespace: r.Namespace, Name: r.NetworkInstance}))
+	} else {
+		indices, err := client.Indices(ctx, rc, ipamv1alpha1.GroupVersion, r.Namespace, "")
+		if err != nil {
+			return errors.Wrap(err, "cannot get network instances")
+		}
+		for _, idx := range indices {
+			refs = append(refs, corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name})
+		}
+	}
+
+	trees := []*ipam.Tree{}
+	rows := [][]string{}
+	gvk := meta.GetResourcePbGVKFromSchemaGVK(ipamv1alpha1.NetworkInstanceGroupVersionKind)
+	for _, ref := range refs {
+		resp, err := rc.GetTree(ctx, &resourcepb.TreeRequest{
+			Header: &resourcepb.Header{
+				Gvk: &gvk,
+				Nsn: &resourcepb.NSN{Namespace: ref.Namespace, Name: ref.Name},
+			},
+			Root:  r.Prefix,
+			Depth: r.Depth,
+		}, client.CallOptions()...)
+		if err != nil {
+			return errors.Wrapf(err, "cannot get the tree of network instance %s/%s", ref.Namespace, ref.Name)
+		}
+		if err := client.ResponseError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+			return errors.Wrapf(err, "cannot get the tree of network instance %s/%s", ref.Namespace, ref.Name)
+		}
+		t := &ipam.Tree{}
+		if err := json.Unmarshal([]byte(resp.Tree), t); err != nil {
+			return errors.Wrap(err, "invalid tree")
+		}
+		trees = append(trees, t)
+		rows = append(rows, []string{fmt.Sprintf("%s/%s", t.Namespace, t.Name), "", "", "", "", ""})
+		rows = appendRows(rows, t.Prefixes, nil)
+	}
+	return r.Printer.Print(c.OutOrStdout(), trees,
+		[]string{"PREFIX", "KIND", "OWNER", "LABELS", "FREE", "UTILIZATION"},
+		rows,
+	)
+}
+
+func appendRows(rows [][]string, nodes []*ipam.TreeNode, last []bool) [][]string {
+	for i, n := range nodes {
+		l := append(append([]bool{}, last...), i == len(nodes)-1)
+		rows = append(rows, []string{
+			client.TreePrefix(l) + n.Prefix,
+			n.Kind,
+			client.Owner(n.Labels),
+			userLabels(n.Labels),
+			n.Free,
+			client.Percentage(n.Utilization),
+		})
+		rows = appendRows(rows, n.Children, l)
+	}
+	return rows
+}
+
+// userLabels returns the labels without the system labels of the backend
+func userLabels(l map[string]string) string {
+	ul := labels.Set{}
+	for k, v := range l {
+		ul[k] = v
+	}
+	for _, k := range systemLabels {
+		delete(ul, k)
+	}
+	return ul.String()
+}
+
+// Holder is the prefix that holds an address in a network instance
+type Holder struct {
+	NetworkInstance string            `json:"networkInstance"`
+	Prefix          string            `json:"prefix"`
+	Kind            string            `json:"kind,omitempty"`
+	Claim           string            `json:"claim,omitempty"`
+	Owner           string            `json:"owner,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+}
+
+// NewOwnerRunner returns a command runner that looks up the owner of an address.
+func NewOwnerRunner(ctx context.Context, parent string) *OwnerRunner {
+	r := &OwnerRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "owner ADDRESS",
+		Args:  cobra.ExactArgs(1),
+		Short: "look up the most specific prefix that holds the address and its owner",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	r.Printer.AddFlags(c.Flags())
+	c.Flags().StringVarP(&r.Namespace, "namespace", "n", "", "namespace of the network instances, all namespaces if not set")
+	c.Flags().StringVar(&r.NetworkInstance, "network-instance", "", "name of the network instance, all network instances if not set")
+	return r
+}
+
+type OwnerRunner struct {
+	Command         *cobra.Command
+	Client          client.Flags
+	Printer         client.Printer
+	Namespace       string
+	NetworkInstance string
+	Ctx             context.Context
+}
+
+func (r *OwnerRunner) runE(c *cobra.Command, args []string) error {
+	if err := r.Printer.Validate(); err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(args[0])
+	if err != nil {
+		return errors.Wrap(err, "invalid address")
+	}
+	indices, err := getIndices(r.Ctx, &r.Client, r.Namespace, r.NetworkInstance)
+	if err != nil {
+		return err
+	}
+
+	holders := []Holder{}
+	rows := [][]string{}
+	for _, idx := range indices {
+		h := findHolder(idx, addr)
+		if h == nil {
+			continue
+		}
+		holders = append(holders, *h)
+		rows = append(rows, []string{h.NetworkInstance, h.Prefix, h.Kind, h.Claim, h.Owner})
+	}
+	if len(holders) == 0 {
+		return fmt.Errorf("address %s is not claimed", addr)
+	}
+	return r.Printer.Print(c.OutOrStdout(), holders,
+		[]string{"NETWORK-INSTANCE", "PREFIX", "KIND", "CLAIM", "OWNER"},
+		rows,
+	)
+}
+
+// findHolder returns the most specific prefix of the network instance that
+// contains the address, nil is returned if no prefix contains the address
+func findHolder(idx backend.IndexSnapshot, addr netip.Addr) *Holder {
+	var holder *Holder
+	bits := -1
+	for _, e := range idx.Entries {
+		pi, err := netip.ParsePrefix(e.ID)
+		if err != nil || !pi.Contains(addr) || pi.Bits() <= bits {
+			continue
+		}
+		bits = pi.Bits()
+		holder = &Holder{
+			NetworkInstance: fmt.Sprintf("%s/%s", idx.Namespace, idx.Name),
+			Prefix:          pi.String(),
+			Kind:            e.Labels[resourcev1alpha1.NephioPrefixKindKey],
+			Claim:           client.Claim(e.Labels),
+			Owner:           client.Owner(e.Labels),
+			Labels:          e.Labels,
+		}
+	}
+	return holder
+}
+
+func getIndices(ctx context.Context, f *client.Flags, namespace, name string) ([]backend.IndexSnapshot, error) {
+	rc, closeFn, err := f.Connect()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+	ctx, cancel := f.Context(ctx)
+	defer cancel()
+	indices, err := client.Indices(ctx, rc, ipamv1alpha1.GroupVersion, namespace, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get network instances")
+	}
+	return indices, nil
+}
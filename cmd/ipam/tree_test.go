@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+)
+
+func TestFindHolder(t *testing.T) {
+	idx := backend.IndexSnapshot{
+		Namespace: "default",
+		Name:      "vpc-1",
+		Entries: []backend.EntrySnapshot{
+			{ID: "10.0.0.0/8", Labels: map[string]string{
+				resourcev1alpha1.NephioPrefixKindKey:        string(ipamv1alpha1.PrefixKindAggregate),
+				resourcev1alpha1.NephioOwnerGvkKey:          ipamv1alpha1.NetworkInstanceKindGVKString,
+				resourcev1alpha1.NephioOwnerNsnNamespaceKey: "default",
+				resourcev1alpha1.NephioOwnerNsnNameKey:      "vpc-1",
+			}},
+			{ID: "10.0.0.0/24", Labels: map[string]string{
+				resourcev1alpha1.NephioPrefixKindKey:        string(ipamv1alpha1.PrefixKindNetwork),
+				resourcev1alpha1.NephioNsnNamespaceKey:      "default",
+				resourcev1alpha1.NephioNsnNameKey:           "net",
+				resourcev1alpha1.NephioOwnerGvkKey:          ipamv1alpha1.IPPrefixKindGVKString,
+				resourcev1alpha1.NephioOwnerNsnNamespaceKey: "default",
+				resourcev1alpha1.NephioOwnerNsnNameKey:      "net",
+			}},
+			{ID: "invalid"},
+		},
+	}
+	cases := map[string]struct {
+		addr string
+		want *Holder
+	}{
+		// the most specific prefix holds the address
+		"Network": {
+			addr: "10.0.0.1",
+			want: &Holder{NetworkInstance: "default/vpc-1", Prefix: "10.0.0.0/24", Kind: "network", Claim: "default/net", Owner: "IPPrefix default/net"},
+		},
+		"Aggregate": {
+			addr: "10.1.0.1",
+			want: &Holder{NetworkInstance: "default/vpc-1", Prefix: "10.0.0.0/8", Kind: "aggregate", Owner: "NetworkInstance default/vpc-1"},
+		},
+		"NotClaimed": {
+			addr: "192.168.0.1",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := findHolder(idx, netip.MustParseAddr(tc.addr))
+			if tc.want == nil {
+				if got != nil {
+					t.Errorf("want no holder, got %v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("want holder %v, got none", tc.want)
+			}
+			got.Labels = nil
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want holder %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUserLabels(t *testing.T) {
+	got := userLabels(map[string]string{
+		resourcev1alpha1.NephioPrefixKindKey: string(ipamv1alpha1.PrefixKindNetwork),
+		resourcev1alpha1.NephioNsnNameKey:    "net",
+		"purpose":                            "mgmt",
+	})
+	if got != "purpose=mgmt" {
+		t.Errorf("want the user defined labels only, got %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nokia/k8s-ipam/cmd/client"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the snapshot command with the export and import
+// subcommands of the resource backend snapshots.
+func NewCommand(ctx context.Context, parent, version string) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "snapshot",
+		Short: "export and import snapshots of the resource backend",
+	}
+	c.AddCommand(NewExportRunner(ctx, parent).Command)
+	c.AddCommand(NewImportRunner(ctx, parent).Command)
+	return c
+}
+
+// NewExportRunner returns a command runner that exports a snapshot.
+func NewExportRunner(ctx context.Context, parent string) *ExportRunner {
+	r := &ExportRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "export",
+		Args:  cobra.NoArgs,
+		Short: "export the indices and claims of the resource backend",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	c.Flags().StringVarP(
+		&r.File, "file", "f", "", "path of the snapshot file, the snapshot is written to stdout if not set")
+	c.Flags().StringSliceVar(
+		&r.GroupVersions, "group-version", nil, "group versions of the exported backends, all backends are exported if not set")
+	return r
+}
+
+type ExportRunner struct {
+	Command       *cobra.Command
+	Client        client.Flags
+	File          string
+	GroupVersions []string
+	Ctx           context.Context
+}
+
+func (r *ExportRunner) runE(c *cobra.Command, args []string) error {
+	rc, closeFn, err := r.Client.Connect()
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+
+	ctx, cancel := r.Client.Context(r.Ctx)
+	defer cancel()
+	resp, err := rc.Export(ctx, &resourcepb.ExportRequest{GroupVersions: r.GroupVersions}, client.CallOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "cannot export snapshot")
+	}
+	if err := client.ResponseError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+		return errors.Wrap(err, "cannot export snapshot")
+	}
+
+	if r.File == "" {
+		_, err := fmt.Fprint(c.OutOrStdout(), resp.Snapshot)
+		return err
+	}
+	if err := os.WriteFile(r.File, []byte(resp.Snapshot), 0600); err != nil {
+		return errors.Wrap(err, "cannot write snapshot file")
+	}
+	return nil
+}
+
+// NewImportRunner returns a command runner that imports a snapshot.
+func NewImportRunner(ctx context.Context, parent string) *ImportRunner {
+	r := &ImportRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "import",
+		Args:  cobra.NoArgs,
+		Short: "import the indices and claims of a snapshot in the resource backend",
+		Long: "import the indices and claims of a snapshot in the resource backend. The snapshot is " +
+			"validated against the claims in the backend and is only imported if no claim conflicts.",
+		RunE: r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	c.Flags().StringVarP(
+		&r.File, "file", "f", "", "path of the snapshot file, the snapshot is read from stdin if set to -")
+	c.Flags().BoolVar(
+		&r.DryRun, "dry-run", false, "validate the snapshot for conflicts without importing it")
+	_ = c.MarkFlagRequired("file")
+	return r
+}
+
+type ImportRunner struct {
+	Command *cobra.Command
+	Client  client.Flags
+	File    string
+	DryRun  bool
+	Ctx     context.Context
+}
+
+func (r *ImportRunner) runE(c *cobra.Command, args []string) error {
+	var b []byte
+	var err error
+	if r.File == "-" {
+		b, err = io.ReadAll(c.InOrStdin())
+	} else {
+		b, err = os.ReadFile(r.File)
+	}
+	if err != nil {
+		return errors.Wrap(err, "cannot read snapshot file")
+	}
+
+	rc, closeFn, err := r.Client.Connect()
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+
+	ctx, cancel := r.Client.Context(r.Ctx)
+	defer cancel()
+	resp, err := rc.Import(ctx, &resourcepb.ImportRequest{Snapshot: string(b), DryRun: r.DryRun}, client.CallOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "cannot import snapshot")
+	}
+	for _, conflict := range resp.Conflicts {
+		fmt.Fprintln(c.OutOrStdout(), conflict)
+	}
+	// the backends applied before a failed backend are reported in the error
+	if err := client.ResponseError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+		return errors.Wrap(err, "cannot import snapshot")
+	}
+	if len(resp.Conflicts) > 0 {
+		return fmt.Errorf("snapshot not imported, %d conflicts", len(resp.Conflicts))
+	}
+	if !resp.Applied {
+		fmt.Fprintln(c.OutOrStdout(), "snapshot validated, no conflicts")
+		return nil
+	}
+	fmt.Fprintln(c.OutOrStdout(), "snapshot imported")
+	return nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/cmd/client"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/db"
+	"github.com/nokia/k8s-ipam/pkg/db/vlandb"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// IndexTree is a vlan index with its claimed vlans, the utilization is the
+// fraction of the vlans that can be claimed that is claimed
+type IndexTree struct {
+	Namespace   string      `json:"namespace"`
+	Name        string      `json:"name"`
+	Claimed     int         `json:"claimed"`
+	Free        int         `json:"free"`
+	Utilization float64     `json:"utilization"`
+	VLANs       []VLANEntry `json:"vlans,omitempty"`
+}
+
+// VLANEntry is a claimed vlan
+type VLANEntry struct {
+	ID     uint16            `json:"id"`
+	Claim  string            `json:"claim,omitempty"`
+	Owner  string            `json:"owner,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// NewTreeRunner returns a command runner that shows the vlan indices.
+func NewTreeRunner(ctx context.Context, parent string) *TreeRunner {
+	r := &TreeRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "tree",
+		Args:  cobra.NoArgs,
+		Short: "show the claimed vlans of the vlan indices with their utilization",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	r.Printer.AddFlags(c.Flags())
+	c.Flags().StringVarP(&r.Namespace, "namespace", "n", "", "namespace of the vlan indices, all namespaces if not set")
+	c.Flags().StringVar(&r.Index, "index", "", "name of the vlan index, all vlan indices if not set")
+	return r
+}
+
+type TreeRunner struct {
+	Command   *cobra.Command
+	Client    client.Flags
+	Printer   client.Printer
+	Namespace string
+	Index     string
+	Ctx       context.Context
+}
+
+func (r *TreeRunner) runE(c *cobra.Command, args []string) error {
+	if err := r.Printer.Validate(); err != nil {
+		return err
+	}
+	indices, err := getIndices(r.Ctx, &r.Client, r.Namespace, r.Index)
+	if err != nil {
+		return err
+	}
+
+	trees := []*IndexTree{}
+	rows := [][]string{}
+	for _, idx := range indices {
+		t, err := buildTree(idx)
+		if err != nil {
+			return err
+		}
+		trees = append(trees, t)
+		rows = append(rows, []string{fmt.Sprintf("%s/%s", t.Namespace, t.Name), "", "", client.Percentage(t.Utilization)})
+		for i, v := range t.VLANs {
+			rows = append(rows, []string{client.TreePrefix([]bool{i == len(t.VLANs)-1}) + fmt.Sprint(v.ID), v.Claim, v.Owner, ""})
+		}
+	}
+	return r.Printer.Print(c.OutOrStdout(), trees,
+		[]string{"INDEX/VLAN", "CLAIM", "OWNER", "UTILIZATION"},
+		rows,
+	)
+}
+
+// buildTree returns the claimed vlans of the index, the reserved vlans are
+// not claimed
+func buildTree(idx backend.IndexSnapshot) (*IndexTree, error) {
+	t := &IndexTree{Namespace: idx.Namespace, Name: idx.Name}
+	d := vlandb.New()
+	for _, e := range idx.Entries {
+		id, err := strconv.ParseUint(e.ID, 10, 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid vlan in index %s/%s", idx.Namespace, idx.Name)
+		}
+		if d.Has(uint16(id)) {
+			continue
+		}
+		if err := d.Set(db.NewEntry(uint16(id), e.Labels)); err != nil {
+			return nil, errors.Wrapf(err, "invalid vlan in index %s/%s", idx.Namespace, idx.Name)
+		}
+		t.VLANs = append(t.VLANs, VLANEntry{
+			ID:     uint16(id),
+			Claim:  client.Claim(e.Labels),
+			Owner:  client.Owner(e.Labels),
+			Labels: e.Labels,
+		})
+	}
+	sort.Slice(t.VLANs, func(i, j int) bool { return t.VLANs[i].ID < t.VLANs[j].ID })
+	for iter := d.IterateFree(); iter.Next(); {
+		t.Free++
+	}
+	t.Claimed = len(t.VLANs)
+	if t.Claimed+t.Free > 0 {
+		t.Utilization = float64(t.Claimed) / float64(t.Claimed+t.Free)
+	}
+	return t, nil
+}
+
+// NewOwnerRunner returns a command runner that looks up the owner of a vlan.
+func NewOwnerRunner(ctx context.Context, parent string) *OwnerRunner {
+	r := &OwnerRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "owner VLAN",
+		Args:  cobra.ExactArgs(1),
+		Short: "look up the claim and owner that hold the vlan",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	r.Printer.AddFlags(c.Flags())
+	c.Flags().StringVarP(&r.Namespace, "namespace", "n", "", "namespace of the vlan indices, all namespaces if not set")
+	c.Flags().StringVar(&r.Index, "index", "", "name of the vlan index, all vlan indices if not set")
+	return r
+}
+
+type OwnerRunner struct {
+	Command   *cobra.Command
+	Client    client.Flags
+	Printer   client.Printer
+	Namespace string
+	Index     string
+	Ctx       context.Context
+}
+
+// Holder is the claim that holds a vlan in an index
+type Holder struct {
+	Index string `json:"index"`
+	VLANEntry
+}
+
+func (r *OwnerRunner) runE(c *cobra.Command, args []string) error {
+	if err := r.Printer.Validate(); err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(args[0], 10, 16)
+	if err != nil {
+		return errors.Wrap(err, "invalid vlan")
+	}
+	indices, err := getIndices(r.Ctx, &r.Client, r.Namespace, r.Index)
+	if err != nil {
+		return err
+	}
+
+	holders := []Holder{}
+	rows := [][]string{}
+	for _, idx := range indices {
+		t, err := buildTree(idx)
+		if err != nil {
+			return err
+		}
+		for _, v := range t.VLANs {
+			if v.ID != uint16(id) {
+				continue
+			}
+			h := Holder{Index: fmt.Sprintf("%s/%s", t.Namespace, t.Name), VLANEntry: v}
+			holders = append(holders, h)
+			rows = append(rows, []string{h.Index, fmt.Sprint(h.ID), h.Claim, h.Owner})
+		}
+	}
+	if len(holders) == 0 {
+		return fmt.Errorf("vlan %d is not claimed", id)
+	}
+	return r.Printer.Print(c.OutOrStdout(), holders,
+		[]string{"INDEX", "VLAN", "CLAIM", "OWNER"},
+		rows,
+	)
+}
+
+func getIndices(ctx context.Context, f *client.Flags, namespace, name string) ([]backend.IndexSnapshot, error) {
+	rc, closeFn, err := f.Connect()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+	ctx, cancel := f.Context(ctx)
+	defer cancel()
+	indices, err := client.Indices(ctx, rc, vlanv1alpha1.GroupVersion, namespace, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get vlan indices")
+	}
+	return indices, nil
+}
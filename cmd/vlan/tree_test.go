@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vlan
+
+import (
+	"reflect"
+	"testing"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+)
+
+func TestBuildTree(t *testing.T) {
+	claimLabels := map[string]string{
+		resourcev1alpha1.NephioNsnNamespaceKey:      "default",
+		resourcev1alpha1.NephioNsnNameKey:           "claim",
+		resourcev1alpha1.NephioOwnerGvkKey:          vlanv1alpha1.VLANClaimKindGVKString,
+		resourcev1alpha1.NephioOwnerNsnNamespaceKey: "default",
+		resourcev1alpha1.NephioOwnerNsnNameKey:      "claim",
+	}
+	tree, err := buildTree(backend.IndexSnapshot{
+		Namespace: "default",
+		Name:      "a",
+		Entries: []backend.EntrySnapshot{
+			{ID: "200", Labels: claimLabels},
+			{ID: "100", Labels: claimLabels},
+			// the reserved vlans are not claimed
+			{ID: "0"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []VLANEntry{
+		{ID: 100, Claim: "default/claim", Owner: "VLANClaim default/claim", Labels: claimLabels},
+		{ID: 200, Claim: "default/claim", Owner: "VLANClaim default/claim", Labels: claimLabels},
+	}
+	if !reflect.DeepEqual(tree.VLANs, want) {
+		t.Errorf("want the claimed vlans sorted %v, got %v", want, tree.VLANs)
+	}
+	// vlan 0, 1 and 4095 are reserved
+	if tree.Claimed != 2 || tree.Free+tree.Claimed != 4093 {
+		t.Errorf("want 2 claimed of 4093 vlans, got %d claimed, %d free", tree.Claimed, tree.Free)
+	}
+
+	if _, err := buildTree(backend.IndexSnapshot{Entries: []backend.EntrySnapshot{{ID: "vlan"}}}); err == nil {
+		t.Errorf("want an error for an invalid vlan")
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/cmd/client"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewCommand returns the vlan command with the subcommands to claim and
+// release vlans and to inspect the vlan indices of the backend.
+func NewCommand(ctx context.Context, parent, version string) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "vlan",
+		Short: "claim, release and inspect vlans in the resource backend",
+	}
+	c.AddCommand(NewClaimRunner(ctx, parent).Command)
+	c.AddCommand(NewReleaseRunner(ctx, parent).Command)
+	c.AddCommand(NewTreeRunner(ctx, parent).Command)
+	c.AddCommand(NewOwnerRunner(ctx, parent).Command)
+	c.AddCommand(client.NewWatchRunner(ctx, vlanv1alpha1.VLANClaimGroupVersionKind, vlanv1alpha1.VLANClaimGroupVersionKind).Command)
+	return c
+}
+
+// NewClaimRunner returns a command runner that claims a vlan.
+func NewClaimRunner(ctx context.Context, parent string) *ClaimRunner {
+	r := &ClaimRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "claim NAME",
+		Args:  cobra.ExactArgs(1),
+		Short: "claim a vlan or a vlan range in a vlan index, a dynamic vlan is claimed if no vlan id or range is set",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	r.Printer.AddFlags(c.Flags())
+	c.Flags().StringVarP(&r.Namespace, "namespace", "n", "default", "namespace of the claim and the vlan index")
+	c.Flags().StringVar(&r.Index, "index", "", "name of the vlan index")
+	c.Flags().Uint16Var(&r.VLANID, "vlan-id", 0, "static vlan to claim")
+	c.Flags().StringVar(&r.VLANRange, "range", "", "vlan range to claim, a start:stop range or the size of a dynamic range")
+	c.Flags().StringToStringVar(&r.Labels, "label", nil, "labels of the claimed vlan")
+	c.Flags().BoolVar(&r.DryRun, "dry-run", false, "show the vlan the claim would be assigned without claiming it")
+	_ = c.MarkFlagRequired("index")
+	return r
+}
+
+type ClaimRunner struct {
+	Command   *cobra.Command
+	Client    client.Flags
+	Printer   client.Printer
+	Namespace string
+	Index     string
+	VLANID    uint16
+	VLANRange string
+	Labels    map[string]string
+	DryRun    bool
+	Ctx       context.Context
+}
+
+func (r *ClaimRunner) runE(c *cobra.Command, args []string) error {
+	if err := r.Printer.Validate(); err != nil {
+		return err
+	}
+	spec := vlanv1alpha1.VLANClaimSpec{
+		VLANIndex: corev1.ObjectReference{Namespace: r.Namespace, Name: r.Index},
+		ClaimLabels: resourcev1alpha1.ClaimLabels{
+			UserDefinedLabels: resourcev1alpha1.UserDefinedLabels{Labels: r.Labels},
+		},
+	}
+	if r.VLANID != 0 {
+		spec.VLANID = &r.VLANID
+	}
+	if r.VLANRange != "" {
+		spec.VLANRange = &r.VLANRange
+	}
+	req, err := buildClaimRequest(args[0], r.Namespace, spec)
+	if err != nil {
+		return err
+	}
+	req.DryRun = r.DryRun
+
+	rc, closeFn, err := r.Client.Connect()
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+	ctx, cancel := r.Client.Context(r.Ctx)
+	defer cancel()
+	resp, err := rc.Claim(ctx, req, client.CallOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "cannot claim vlan")
+	}
+	if err := client.ResponseError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+		return errors.Wrap(err, "cannot claim vlan")
+	}
+	claim := &vlanv1alpha1.VLANClaim{}
+	if err := json.Unmarshal([]byte(resp.Status), claim); err != nil {
+		return errors.Wrap(err, "cannot unmarshal claim")
+	}
+	vlan := ""
+	if claim.Status.VLANID != nil {
+		vlan = fmt.Sprint(*claim.Status.VLANID)
+	}
+	if claim.Status.VLANRange != nil {
+		vlan = *claim.Status.VLANRange
+	}
+	return r.Printer.Print(c.OutOrStdout(), claim,
+		[]string{"NAME", "INDEX", "VLAN"},
+		[][]string{{fmt.Sprintf("%s/%s", claim.Namespace, claim.Name), claim.Spec.VLANIndex.Name, vlan}},
+	)
+}
+
+// NewReleaseRunner returns a command runner that releases a vlan.
+func NewReleaseRunner(ctx context.Context, parent string) *ReleaseRunner {
+	r := &ReleaseRunner{
+		Ctx: ctx,
+	}
+	c := &cobra.Command{
+		Use:   "release NAME",
+		Args:  cobra.ExactArgs(1),
+		Short: "release the vlans of a claim in a vlan index",
+		RunE:  r.runE,
+	}
+
+	r.Command = c
+	r.Client.AddFlags(c.Flags())
+	c.Flags().StringVarP(&r.Namespace, "namespace", "n", "default", "namespace of the claim and the vlan index")
+	c.Flags().StringVar(&r.Index, "index", "", "name of the vlan index")
+	_ = c.MarkFlagRequired("index")
+	return r
+}
+
+type ReleaseRunner struct {
+	Command   *cobra.Command
+	Client    client.Flags
+	Namespace string
+	Index     string
+	Ctx       context.Context
+}
+
+func (r *ReleaseRunner) runE(c *cobra.Command, args []string) error {
+	req, err := buildClaimRequest(args[0], r.Namespace, vlanv1alpha1.VLANClaimSpec{
+		VLANIndex: corev1.ObjectReference{Namespace: r.Namespace, Name: r.Index},
+	})
+	if err != nil {
+		return err
+	}
+
+	rc, closeFn, err := r.Client.Connect()
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to the resource backend")
+	}
+	defer closeFn()
+	ctx, cancel := r.Client.Context(r.Ctx)
+	defer cancel()
+	resp, err := rc.DeleteClaim(ctx, req, client.CallOptions()...)
+	if err != nil {
+		return errors.Wrap(err, "cannot release vlan")
+	}
+	if err := client.ResponseError(resp.ErrorCode, resp.ErrorMessage); err != nil {
+		return errors.Wrap(err, "cannot release vlan")
+	}
+	fmt.Fprintf(c.OutOrStdout(), "vlanclaim %s/%s released\n", r.Namespace, args[0])
+	return nil
+}
+
+// buildClaimRequest returns the request of a claim that is owned by the claim
+// itself, the claim does not expire
+func buildClaimRequest(name, namespace string, spec vlanv1alpha1.VLANClaimSpec) (*resourcepb.ClaimRequest, error) {
+	claim := vlanv1alpha1.BuildVLANClaim(metav1.ObjectMeta{Namespace: namespace, Name: name}, spec, vlanv1alpha1.VLANClaimStatus{})
+	claim.AddOwnerLabelsToCR()
+	b, err := json.Marshal(claim)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal claim")
+	}
+	return clientproxy.BuildResourcePb(claim, name, string(b), "never", vlanv1alpha1.VLANClaimGroupVersionKind), nil
+}
@@ -0,0 +1,149 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package claimquota
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
+	"github.com/nokia/k8s-ipam/controllers"
+	"github.com/nokia/k8s-ipam/controllers/ctrlconfig"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	perrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	controllers.Register("claimquotas", &reconciler{})
+}
+
+const (
+	// the usage changes with every claim, so it is refreshed periodically
+	usageRefreshInterval = 30 * time.Second
+	// error
+	errGetCr        = "cannot get resource"
+	errUpdateStatus = "cannot update status"
+)
+
+//+kubebuilder:rbac:groups=quota.resource.nephio.org,resources=claimquotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=quota.resource.nephio.org,resources=claimquotas/status,verbs=get;update;patch
+
+// Setup sets up the controller with the Manager.
+func (r *reconciler) Setup(ctx context.Context, mgr ctrl.Manager, cfg *ctrlconfig.ControllerConfig) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
+	// register scheme
+	if err := quotav1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+
+	// initialize reconciler
+	r.APIPatchingApplicator = resource.NewAPIPatchingApplicator(mgr.GetClient())
+	r.usageGetters = map[quotav1alpha1.QuotaResource]backend.QuotaUsageGetter{}
+	if g, ok := cfg.Ipam.(backend.QuotaUsageGetter); ok {
+		r.usageGetters[quotav1alpha1.QuotaResourceAddresses] = g
+		r.usageGetters[quotav1alpha1.QuotaResourcePrefixes] = g
+	}
+	if g, ok := cfg.Vlan.(backend.QuotaUsageGetter); ok {
+		r.usageGetters[quotav1alpha1.QuotaResourceVLANs] = g
+	}
+	// the usage is read from the backends, which are not available when the
+	// controllers run against a remote backend
+	if len(r.usageGetters) == 0 {
+		return nil, fmt.Errorf("the claimquotas controller requires the ipam or vlan backend to run in the same process")
+	}
+
+	return nil,
+		ctrl.NewControllerManagedBy(mgr).
+			Named("ClaimQuotaController").
+			For(&quotav1alpha1.ClaimQuota{}).
+			Complete(r)
+}
+
+// reconciler reports the usage of a ClaimQuota, the quota itself is enforced
+// by the backends
+type reconciler struct {
+	resource.APIPatchingApplicator
+
+	usageGetters map[quotav1alpha1.QuotaResource]backend.QuotaUsageGetter
+
+	l logr.Logger
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.l = log.FromContext(ctx)
+	r.l.Info("reconcile", "req", req)
+
+	cr := &quotav1alpha1.ClaimQuota{}
+	if err := r.Get(ctx, req.NamespacedName, cr); err != nil {
+		// There's no need to requeue if we no longer exist. Otherwise we'll be
+		// requeued implicitly because we return an error.
+		if resource.IgnoreNotFound(err) != nil {
+			r.l.Error(err, errGetCr)
+			return ctrl.Result{}, perrors.Wrap(resource.IgnoreNotFound(err), errGetCr)
+		}
+		return reconcile.Result{}, nil
+	}
+	orig := cr.DeepCopy()
+	cr = cr.DeepCopy()
+
+	usage, err := r.getUsage(cr)
+	if err != nil {
+		r.l.Error(err, "cannot get quota usage")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+	} else {
+		cr.Status.Usage = usage
+		cr.SetConditions(resourcev1alpha1.Ready())
+	}
+	if reflect.DeepEqual(orig.Status, cr.Status) {
+		return reconcile.Result{RequeueAfter: usageRefreshInterval}, nil
+	}
+	return reconcile.Result{RequeueAfter: usageRefreshInterval}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+}
+
+func (r *reconciler) getUsage(cr *quotav1alpha1.ClaimQuota) ([]quotav1alpha1.QuotaUsage, error) {
+	selector, err := cr.GetUsageSelector()
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]quotav1alpha1.QuotaUsage, 0, len(cr.Spec.Limits))
+	for _, l := range cr.Spec.Limits {
+		g, ok := r.usageGetters[l.Resource]
+		if !ok {
+			return nil, fmt.Errorf("no backend for resource %s", l.Resource)
+		}
+		used, err := g.GetQuotaUsage(l.Index, l.Resource, selector)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, quotav1alpha1.QuotaUsage{
+			Index:    l.Index,
+			Resource: l.Resource,
+			Used:     used,
+			Max:      l.Max,
+		})
+	}
+	return usage, nil
+}
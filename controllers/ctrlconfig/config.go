@@ -22,20 +22,26 @@ import (
 	"github.com/henderiw-nephio/network-node-operator/pkg/node"
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
 	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 )
 
 type ControllerConfig struct {
-	PorchClient     client.Client
-	Address         string // backend server address
-	IpamClientProxy clientproxy.Proxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim]
-	VlanClientProxy clientproxy.Proxy[*vlanv1alpha1.VLANIndex, *vlanv1alpha1.VLANClaim]
-	Poll            time.Duration
-	Copts           controller.Options
-	Ipam            backend.Backend
-	Vlan            backend.Backend
-	Noderegistry    node.NodeRegistry
+	PorchClient      client.Client
+	Address          string // backend server address
+	IpamClientProxy  clientproxy.Proxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim]
+	VlanClientProxy  clientproxy.Proxy[*vlanv1alpha1.VLANIndex, *vlanv1alpha1.VLANClaim]
+	VxlanClientProxy clientproxy.Proxy[*vxlanv1alpha1.VXLANIndex, *vxlanv1alpha1.VXLANClaim]
+	Poll             time.Duration
+	Ipam             backend.Backend
+	Vlan             backend.Backend
+	Noderegistry     node.NodeRegistry
+	// LeaseDuration is the duration of the leases that serialize the endpoint claims
+	LeaseDuration time.Duration
+	// LeaseRenewInterval is the interval at which a held lease is renewed
+	LeaseRenewInterval time.Duration
+	// Reconcilers holds the concurrency and requeue backoff of the reconcilers
+	Reconcilers ReconcilerOptions
 }
@@ -0,0 +1,138 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ctrlconfig
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nokia/k8s-ipam/pkg/backoff"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// ReconcilerConfig holds the concurrency and the requeue backoff of a reconciler
+type ReconcilerConfig struct {
+	// MaxConcurrentReconciles is the number of concurrent workers of the reconciler
+	MaxConcurrentReconciles int
+	// RequeueBaseDelay is the delay of the first requeue of a failed resource,
+	// the delay doubles on every subsequent failure
+	RequeueBaseDelay time.Duration
+	// RequeueMaxDelay caps the requeue delay of a failed resource
+	RequeueMaxDelay time.Duration
+}
+
+// ReconcilerOptions holds the default reconciler config and the overrides per
+// reconciler name, zero values in an override fall back to the default
+type ReconcilerOptions struct {
+	Default   ReconcilerConfig
+	Overrides map[string]ReconcilerConfig
+}
+
+// BindFlags binds the reconciler options to the flagset
+func (r *ReconcilerOptions) BindFlags(fs *flag.FlagSet) {
+	fs.IntVar(&r.Default.MaxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of concurrent workers of a reconciler.")
+	fs.DurationVar(&r.Default.RequeueBaseDelay, "requeue-base-delay", time.Second,
+		"The delay of the first requeue of a failed resource, the delay doubles on every subsequent failure.")
+	fs.DurationVar(&r.Default.RequeueMaxDelay, "requeue-max-delay", 2*time.Minute,
+		"The maximum requeue delay of a failed resource.")
+	fs.Func("reconciler-options",
+		"The options of a single reconciler, overriding the defaults, formatted as "+
+			"<name>:maxConcurrentReconciles=<int>,requeueBaseDelay=<duration>,requeueMaxDelay=<duration>. "+
+			"The flag can be repeated for multiple reconcilers.",
+		r.setOverride)
+}
+
+func (r *ReconcilerOptions) setOverride(s string) error {
+	name, opts, ok := strings.Cut(s, ":")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid reconciler options %q, expecting <name>:<key>=<value>,...", s)
+	}
+	if r.Overrides == nil {
+		r.Overrides = map[string]ReconcilerConfig{}
+	}
+	cfg := r.Overrides[name]
+	for _, opt := range strings.Split(opts, ",") {
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok {
+			return fmt.Errorf("invalid reconciler option %q, expecting <key>=<value>", opt)
+		}
+		var err error
+		switch k {
+		case "maxConcurrentReconciles":
+			cfg.MaxConcurrentReconciles, err = strconv.Atoi(v)
+		case "requeueBaseDelay":
+			cfg.RequeueBaseDelay, err = time.ParseDuration(v)
+		case "requeueMaxDelay":
+			cfg.RequeueMaxDelay, err = time.ParseDuration(v)
+		default:
+			return fmt.Errorf("unknown reconciler option %q", k)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid reconciler option %q, err: %s", opt, err.Error())
+		}
+	}
+	r.Overrides[name] = cfg
+	return nil
+}
+
+// SetMaxConcurrentReconciles sets the number of concurrent workers of the
+// reconciler with the given name, unless the reconciler options already
+// override it
+func (r *ReconcilerOptions) SetMaxConcurrentReconciles(name string, n int) {
+	if r.Overrides == nil {
+		r.Overrides = map[string]ReconcilerConfig{}
+	}
+	cfg := r.Overrides[name]
+	if cfg.MaxConcurrentReconciles > 0 {
+		return
+	}
+	cfg.MaxConcurrentReconciles = n
+	r.Overrides[name] = cfg
+}
+
+// Get returns the config of the reconciler with the given name
+func (r *ReconcilerOptions) Get(name string) ReconcilerConfig {
+	cfg := r.Default
+	o, ok := r.Overrides[name]
+	if !ok {
+		return cfg
+	}
+	if o.MaxConcurrentReconciles > 0 {
+		cfg.MaxConcurrentReconciles = o.MaxConcurrentReconciles
+	}
+	if o.RequeueBaseDelay > 0 {
+		cfg.RequeueBaseDelay = o.RequeueBaseDelay
+	}
+	if o.RequeueMaxDelay > 0 {
+		cfg.RequeueMaxDelay = o.RequeueMaxDelay
+	}
+	return cfg
+}
+
+// GetControllerOptions returns the controller options of the reconciler with
+// the given name, failed resources are requeued with an exponential backoff
+func (r *ControllerConfig) GetControllerOptions(name string) controller.Options {
+	cfg := r.Reconcilers.Get(name)
+	return controller.Options{
+		MaxConcurrentReconciles: cfg.MaxConcurrentReconciles,
+		RateLimiter:             backoff.NewRateLimiter(cfg.RequeueBaseDelay, cfg.RequeueMaxDelay),
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package interconnect
+
+import (
+	"fmt"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getLogicalInterconnectSpec transforms an interconnect link into a logical interconnect spec
+// such that the endpoint selector can be used for the endpoint selection of the interconnect link.
+// The node and interface name of the interconnect link endpoint are added to the selector.
+func getLogicalInterconnectSpec(cr *topov1alpha1.Interconnect, l topov1alpha1.InterconnectLink) (*topov1alpha1.LogicalInterconnectSpec, error) {
+	if len(l.Endpoints) != 2 {
+		return nil, fmt.Errorf("an interconnect link requires 2 endpoints, got: %d", len(l.Endpoints))
+	}
+	links := uint16(1)
+	if l.Links != nil {
+		links = uint16(*l.Links)
+	}
+	spec := &topov1alpha1.LogicalInterconnectSpec{
+		Links:     links,
+		Type:      l.Type,
+		Lacp:      l.Lacp,
+		Endpoints: make([]topov1alpha1.LogicalInterconnectEndpoint, 0, len(l.Endpoints)),
+	}
+	for epIdx, ep := range l.Endpoints {
+		topology := cr.GetEndpointTopology(l, epIdx)
+		if topology == "" {
+			return nil, fmt.Errorf("no topology found for endpoint %d", epIdx)
+		}
+		selector := &metav1.LabelSelector{}
+		if ep.Selector != nil {
+			selector = ep.Selector.DeepCopy()
+		}
+		if selector.MatchLabels == nil {
+			selector.MatchLabels = map[string]string{}
+		}
+		if ep.NodeName != nil {
+			selector.MatchLabels[invv1alpha1.NephioInventoryNodeNameKey] = *ep.NodeName
+		}
+		if ep.InterfaceName != nil {
+			selector.MatchLabels[invv1alpha1.NephioInventoryInterfaceNameKey] = *ep.InterfaceName
+		}
+		spec.Endpoints = append(spec.Endpoints, topov1alpha1.LogicalInterconnectEndpoint{
+			Name:           ep.LogicalEndpointName,
+			Topologies:     []string{topology},
+			Selector:       selector,
+			SelectorPolicy: ep.SelectorPolicy,
+		})
+	}
+	return spec, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interconnect
+
+import (
+	"reflect"
+	"testing"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestGetLogicalInterconnectSpec(t *testing.T) {
+	cases := map[string]struct {
+		topologies []string
+		link       topov1alpha1.InterconnectLink
+		want       *topov1alpha1.LogicalInterconnectSpec
+		wantErr    bool
+	}{
+		"GlobalTopologies": {
+			topologies: []string{"cluster01", "backbone"},
+			link: topov1alpha1.InterconnectLink{
+				Endpoints: []topov1alpha1.InterconnectLinkEndpoint{{}, {}},
+			},
+			want: &topov1alpha1.LogicalInterconnectSpec{
+				Links: 1,
+				Endpoints: []topov1alpha1.LogicalInterconnectEndpoint{
+					{Topologies: []string{"cluster01"}, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{}}},
+					{Topologies: []string{"backbone"}, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{}}},
+				},
+			},
+		},
+		"EndpointTopology": {
+			topologies: []string{"cluster01", "backbone"},
+			link: topov1alpha1.InterconnectLink{
+				Endpoints: []topov1alpha1.InterconnectLinkEndpoint{{}, {Topology: ptr.To("cluster02")}},
+			},
+			want: &topov1alpha1.LogicalInterconnectSpec{
+				Links: 1,
+				Endpoints: []topov1alpha1.LogicalInterconnectEndpoint{
+					{Topologies: []string{"cluster01"}, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{}}},
+					{Topologies: []string{"cluster02"}, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{}}},
+				},
+			},
+		},
+		"Selectors": {
+			link: topov1alpha1.InterconnectLink{
+				Links: ptr.To[uint32](2),
+				Type:  ptr.To("lag"),
+				Lacp:  ptr.To(true),
+				Endpoints: []topov1alpha1.InterconnectLinkEndpoint{
+					{
+						Topology:            ptr.To("cluster01"),
+						LogicalEndpointName: ptr.To("lag1"),
+						NodeName:            ptr.To("node1"),
+						InterfaceName:       ptr.To("e1-1"),
+					},
+					{
+						Topology:       ptr.To("backbone"),
+						Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "a"}},
+						SelectorPolicy: &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](2)},
+					},
+				},
+			},
+			want: &topov1alpha1.LogicalInterconnectSpec{
+				Links: 2,
+				Type:  ptr.To("lag"),
+				Lacp:  ptr.To(true),
+				Endpoints: []topov1alpha1.LogicalInterconnectEndpoint{
+					{
+						Name:       ptr.To("lag1"),
+						Topologies: []string{"cluster01"},
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+							invv1alpha1.NephioInventoryNodeNameKey:      "node1",
+							invv1alpha1.NephioInventoryInterfaceNameKey: "e1-1",
+						}},
+					},
+					{
+						Topologies:     []string{"backbone"},
+						Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "a"}},
+						SelectorPolicy: &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](2)},
+					},
+				},
+			},
+		},
+		"NoTopology": {
+			link: topov1alpha1.InterconnectLink{
+				Endpoints: []topov1alpha1.InterconnectLinkEndpoint{{Topology: ptr.To("cluster01")}, {}},
+			},
+			wantErr: true,
+		},
+		"SingleEndpoint": {
+			topologies: []string{"cluster01", "backbone"},
+			link: topov1alpha1.InterconnectLink{
+				Endpoints: []topov1alpha1.InterconnectLinkEndpoint{{}},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &topov1alpha1.Interconnect{Spec: topov1alpha1.InterconnectSpec{
+				Topologies: tc.topologies,
+				Links:      []topov1alpha1.InterconnectLink{tc.link},
+			}}
+			got, err := getLogicalInterconnectSpec(cr, tc.link)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error %t, got %v", tc.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGetLogicalInterconnectSpecSelectorCopy(t *testing.T) {
+	// the selector of the interconnect is not changed by the node and
+	// interface labels
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "a"}}
+	link := topov1alpha1.InterconnectLink{
+		Endpoints: []topov1alpha1.InterconnectLinkEndpoint{
+			{Topology: ptr.To("cluster01"), NodeName: ptr.To("node1"), Selector: selector},
+			{Topology: ptr.To("backbone")},
+		},
+	}
+	cr := &topov1alpha1.Interconnect{Spec: topov1alpha1.InterconnectSpec{Links: []topov1alpha1.InterconnectLink{link}}}
+	if _, err := getLogicalInterconnectSpec(cr, link); err != nil {
+		t.Fatal(err)
+	}
+	if len(selector.MatchLabels) != 1 {
+		t.Errorf("want the selector of the interconnect unchanged, got %v", selector.MatchLabels)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interconnect
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// queue records the requests that are added
+type queue struct {
+	requests []reconcile.Request
+}
+
+func (r *queue) Add(item interface{}) {
+	r.requests = append(r.requests, item.(reconcile.Request))
+}
+
+func TestEndpointEventHandler(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := topov1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	ready := &topov1alpha1.Interconnect{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ready"}}
+	ready.SetConditions(resourcev1alpha1.Ready())
+	notReady := &topov1alpha1.Interconnect{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "notready"}}
+	notReady.SetConditions(resourcev1alpha1.Failed("no endpoints"))
+	other := &topov1alpha1.Interconnect{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "notready"}}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(ready, notReady, other).Build()
+
+	cases := map[string]struct {
+		claimRef *corev1.ObjectReference
+		want     []reconcile.Request
+	}{
+		"Claimed": {
+			claimRef: &corev1.ObjectReference{
+				APIVersion: topov1alpha1.GroupVersion.String(),
+				Kind:       topov1alpha1.InterconnectKind,
+				Namespace:  "default",
+				Name:       "ready",
+			},
+			want: []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "ready"}}},
+		},
+		// an endpoint that is not claimed reevaluates the interconnects of
+		// the namespace that are not ready
+		"Unclaimed": {
+			want: []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "notready"}}},
+		},
+		"ClaimedByOtherKind": {
+			claimRef: &corev1.ObjectReference{
+				APIVersion: topov1alpha1.GroupVersion.String(),
+				Kind:       topov1alpha1.LogicalInterconnectKind,
+				Namespace:  "default",
+				Name:       "ready",
+			},
+			want: []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "notready"}}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ep := &invv1alpha1.Endpoint{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ep"}}
+			ep.Status.ClaimRef = tc.claimRef
+			q := &queue{}
+			h := &endpointEventHandler{client: c}
+			h.add(context.Background(), ep, q)
+			if !reflect.DeepEqual(q.requests, tc.want) {
+				t.Errorf("want requests %v, got %v", tc.want, q.requests)
+			}
+		})
+	}
+
+	// other objects are ignored
+	q := &queue{}
+	h := &endpointEventHandler{client: c}
+	h.add(context.Background(), ready, q)
+	if len(q.requests) != 0 {
+		t.Errorf("want no requests for an interconnect, got %v", q.requests)
+	}
+}
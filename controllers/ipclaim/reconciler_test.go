@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipclaim
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
+	"github.com/nokia/k8s-ipam/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeProxy is an ipam client proxy that returns the configured claim error
+type fakeProxy struct {
+	clientproxy.Proxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim]
+	err error
+}
+
+func (r *fakeProxy) Claim(ctx context.Context, o client.Object, d any) (*ipamv1alpha1.IPClaim, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	cr := o.(*ipamv1alpha1.IPClaim)
+	cr.Status.Prefix = ptr.To("10.0.0.2/24")
+	return cr, nil
+}
+
+func (r *fakeProxy) DeleteClaim(ctx context.Context, o client.Object, d any) error {
+	return nil
+}
+
+func TestReconcileClaimError(t *testing.T) {
+	cases := map[string]struct {
+		err           error
+		wantReason    resourcev1alpha1.ConditionReason
+		wantRequeue   bool
+		wantPrefix    *string
+		wantCondition metav1.ConditionStatus
+	}{
+		"Claimed": {
+			wantReason:    resourcev1alpha1.ConditionReasonReady,
+			wantPrefix:    ptr.To("10.0.0.2/24"),
+			wantCondition: metav1.ConditionTrue,
+		},
+		"IndexNotReady": {
+			err:           backend.NewError(resourcepb.ErrorCode_IndexNotReady, "index not ready"),
+			wantReason:    resourcev1alpha1.ConditionReasonIndexNotReady,
+			wantRequeue:   true,
+			wantPrefix:    ptr.To("10.0.0.1/24"),
+			wantCondition: metav1.ConditionFalse,
+		},
+		"PoolExhausted": {
+			err:           backend.NewError(resourcepb.ErrorCode_PoolExhausted, "no free prefix"),
+			wantReason:    resourcev1alpha1.ConditionReasonPoolExhausted,
+			wantCondition: metav1.ConditionFalse,
+		},
+		"Conflict": {
+			err:           backend.NewError(resourcepb.ErrorCode_Conflict, "prefix claimed by another owner"),
+			wantReason:    resourcev1alpha1.ConditionReasonConflict,
+			wantCondition: metav1.ConditionFalse,
+		},
+		"ValidationFailed": {
+			err:           backend.NewError(resourcepb.ErrorCode_ValidationFailed, "validated failed"),
+			wantReason:    resourcev1alpha1.ConditionReasonValidationFailed,
+			wantCondition: metav1.ConditionFalse,
+		},
+		"QuotaExceeded": {
+			err:           backend.NewError(resourcepb.ErrorCode_QuotaExceeded, "quota exceeded"),
+			wantReason:    resourcev1alpha1.ConditionReasonQuotaExceeded,
+			wantCondition: metav1.ConditionFalse,
+		},
+		"Internal": {
+			err:           fmt.Errorf("connection refused"),
+			wantReason:    resourcev1alpha1.ConditionReasonFailed,
+			wantRequeue:   true,
+			wantCondition: metav1.ConditionFalse,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := runtime.NewScheme()
+			if err := ipamv1alpha1.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			ni := ipamv1alpha1.BuildNetworkInstance(metav1.ObjectMeta{Namespace: "default", Name: "ni"},
+				ipamv1alpha1.NetworkInstanceSpec{}, ipamv1alpha1.NetworkInstanceStatus{})
+			cr := ipamv1alpha1.BuildIPClaim(metav1.ObjectMeta{Namespace: "default", Name: "claim"},
+				ipamv1alpha1.IPClaimSpec{
+					Kind:            ipamv1alpha1.PrefixKindNetwork,
+					NetworkInstance: corev1.ObjectReference{Name: "ni"},
+				},
+				ipamv1alpha1.IPClaimStatus{Prefix: ptr.To("10.0.0.1/24")})
+			c := fake.NewClientBuilder().WithScheme(s).
+				WithObjects(ni, cr).
+				WithStatusSubresource(&ipamv1alpha1.IPClaim{}).
+				Build()
+
+			r := &reconciler{
+				Client:      c,
+				ClientProxy: &fakeProxy{err: tc.err},
+				finalizer:   resource.NewAPIFinalizer(c, finalizer),
+			}
+			nsn := types.NamespacedName{Namespace: "default", Name: "claim"}
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: nsn})
+			if err != nil {
+				t.Fatalf("reconcile: %v", err)
+			}
+			if result.Requeue != tc.wantRequeue {
+				t.Errorf("want requeue %t, got %t", tc.wantRequeue, result.Requeue)
+			}
+
+			got := &ipamv1alpha1.IPClaim{}
+			if err := c.Get(ctx, nsn, got); err != nil {
+				t.Fatal(err)
+			}
+			cond := got.GetCondition(resourcev1alpha1.ConditionTypeReady)
+			if cond.Status != tc.wantCondition || cond.Reason != string(tc.wantReason) {
+				t.Errorf("want condition %s/%s, got %s/%s", tc.wantCondition, tc.wantReason, cond.Status, cond.Reason)
+			}
+			if ptr.Deref(got.Status.Prefix, "") != ptr.Deref(tc.wantPrefix, "") {
+				t.Errorf("want prefix %q, got %q", ptr.Deref(tc.wantPrefix, ""), ptr.Deref(got.Status.Prefix, ""))
+			}
+		})
+	}
+}
@@ -35,7 +35,6 @@ import (
 	perrors "github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -64,8 +63,11 @@ func (r *reconciler) Setup(ctx context.Context, mgr ctrl.Manager, cfg *ctrlconfi
 	r.APIPatchingApplicator = resource.NewAPIPatchingApplicator(mgr.GetClient())
 	r.finalizer = resource.NewAPIFinalizer(mgr.GetClient(), finalizer)
 
-	r.endpoint = endpoint.New(mgr.GetClient())
-	r.epLease = lease.New(mgr.GetClient(), types.NamespacedName{Namespace: os.Getenv("POD_NAMESPACE"), Name: "endpoint"})
+	r.endpoint = endpoint.New(mgr.GetClient(), endpoint.WithVtepClientProxy(cfg.VxlanClientProxy))
+	r.epLease = lease.NewSet(mgr.GetClient(), os.Getenv("POD_NAMESPACE"), "endpoint",
+		lease.WithDuration(cfg.LeaseDuration),
+		lease.WithRenewInterval(cfg.LeaseRenewInterval),
+	)
 
 	return nil,
 		ctrl.NewControllerManagedBy(mgr).
@@ -81,7 +83,7 @@ type reconciler struct {
 	finalizer *resource.APIFinalizer
 
 	endpoint endpoint.Endpoint
-	epLease  lease.Lease
+	epLease  lease.Set
 
 	claimedEndpoints []invv1alpha1.Endpoint
 
@@ -103,12 +105,9 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return reconcile.Result{}, nil
 	}
 
-	// for links owned by the logical interconnect link we dont do anything
-	for _, ownRef := range cr.OwnerReferences {
-		if ownRef.APIVersion == topov1alpha1.GroupVersion.String() &&
-			ownRef.Kind == topov1alpha1.LogicalInterconnectKind {
-			return reconcile.Result{}, nil
-		}
+	// for links owned by the (logical) interconnect we dont do anything
+	if isOwnedByInterconnect(cr) {
+		return reconcile.Result{}, nil
 	}
 
 	// initialize claimed endpoints
@@ -144,12 +143,17 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	cr = cr.DeepCopy()
-	// acquire endpoint lease to update the resource
-	if err := r.epLease.AcquireLease(ctx, cr); err != nil {
+	// acquire endpoint leases of the topologies to update the resource
+	if err := r.epLease.AcquireLeases(ctx, cr, cr.GetTopologies()); err != nil {
 		r.l.Error(err, "cannot acquire endpoint lease")
 		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
 		return reconcile.Result{Requeue: true, RequeueAfter: lease.RequeueInterval}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
+	defer func() {
+		if err := r.epLease.ReleaseLeases(ctx, cr, cr.GetTopologies()); err != nil {
+			r.l.Error(err, "cannot release lease")
+		}
+	}()
 
 	if err := r.claimResources(ctx, cr); err != nil {
 		// claim resources failed
@@ -170,13 +174,10 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 func (r *reconciler) claimResources(ctx context.Context, cr *invv1alpha1.Link) error {
 	r.l.Info("claim resources")
 
-	// LogicalInterconnectKind provides its own claim logic
+	// LogicalInterconnectKind and InterconnectKind provide their own claim logic
 	// so we ignore links which such owner reference
-	for _, ownRef := range cr.OwnerReferences {
-		if ownRef.APIVersion == topov1alpha1.GroupVersion.String() &&
-			ownRef.Kind == topov1alpha1.LogicalInterconnectKind {
-			return nil
-		}
+	if isOwnedByInterconnect(cr) {
+		return nil
 	}
 
 	// initialize the endpoint topologies
@@ -231,3 +232,15 @@ func (r *reconciler) claimResources(ctx context.Context, cr *invv1alpha1.Link) e
 
 	return nil
 }
+
+// isOwnedByInterconnect returns true if the link is owned by a logical interconnect
+// or an interconnect
+func isOwnedByInterconnect(cr *invv1alpha1.Link) bool {
+	for _, ownRef := range cr.OwnerReferences {
+		if ownRef.APIVersion == topov1alpha1.GroupVersion.String() &&
+			(ownRef.Kind == topov1alpha1.LogicalInterconnectKind || ownRef.Kind == topov1alpha1.InterconnectKind) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package logicalinterconnect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// esiIndexName is the index used to claim ESIs, one per namespace
+	esiIndexName  = "esi"
+	esiOffset     = 1
+	esiMaxEntryID = 65535
+	// lag indices are claimed per node, see getLagIndexName
+	lagOffset     = 1
+	lagMaxEntryID = 1024
+)
+
+// logicalEndpointIDs holds the ids claimed for a logical endpoint
+type logicalEndpointIDs struct {
+	esi   *uint32
+	lagID *uint32
+}
+
+func getLagIndexName(nodeName string) string {
+	return fmt.Sprintf("%s-lag", nodeName)
+}
+
+// getNodes returns the sorted unique node names of the logical endpoint
+func getNodes(lep invv1alpha1.LogicalEndpointSpec) []string {
+	nodes := []string{}
+	for _, ep := range lep.Endpoints {
+		found := false
+		for _, node := range nodes {
+			if node == ep.NodeName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			nodes = append(nodes, ep.NodeName)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func buildIDIndex(cr *topov1alpha1.LogicalInterconnect, name string, offset, maxEntryID uint32) *vxlanv1alpha1.VXLANIndex {
+	return vxlanv1alpha1.BuildVXLANIndex(
+		metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.GetNamespace(),
+		},
+		vxlanv1alpha1.VXLANIndexSpec{
+			Offset:     offset,
+			MaxEntryID: maxEntryID,
+		},
+		vxlanv1alpha1.VXLANIndexStatus{},
+	)
+}
+
+// buildIDClaim returns a claim on behalf of the logical interconnect,
+// the claim is uniquely identified in the index by the logical endpoint name
+func buildIDClaim(cr *topov1alpha1.LogicalInterconnect, lepName, indexName string, id *uint32) *vxlanv1alpha1.VXLANClaim {
+	return vxlanv1alpha1.BuildVXLANClaim(
+		metav1.ObjectMeta{
+			Name:      lepName,
+			Namespace: cr.GetNamespace(),
+			Labels: map[string]string{
+				resourcev1alpha1.NephioOwnerGvkKey:          topov1alpha1.LogicalInterconnectKindGVKString,
+				resourcev1alpha1.NephioOwnerNsnNameKey:      cr.GetName(),
+				resourcev1alpha1.NephioOwnerNsnNamespaceKey: cr.GetNamespace(),
+			},
+		},
+		vxlanv1alpha1.VXLANClaimSpec{
+			VXLANIndex: corev1.ObjectReference{
+				Name:      indexName,
+				Namespace: cr.GetNamespace(),
+			},
+			VXLANID: id,
+		},
+		vxlanv1alpha1.VXLANClaimStatus{},
+	)
+}
+
+func (r *reconciler) claimID(ctx context.Context, cr *topov1alpha1.LogicalInterconnect, lepName string, idx *vxlanv1alpha1.VXLANIndex, id *uint32) (*uint32, error) {
+	// creating the index is idempotent in the backend
+	if err := r.vxlanClientProxy.CreateIndex(ctx, idx); err != nil {
+		return nil, err
+	}
+	claim, err := r.vxlanClientProxy.Claim(ctx, buildIDClaim(cr, lepName, idx.GetName(), id), nil)
+	if err != nil {
+		return nil, err
+	}
+	if claim == nil || claim.Status.VXLANID == nil {
+		return nil, fmt.Errorf("no id claimed from index %s for %s", idx.GetName(), lepName)
+	}
+	return claim.Status.VXLANID, nil
+}
+
+func (r *reconciler) deleteIDClaim(ctx context.Context, cr *topov1alpha1.LogicalInterconnect, lepName string, idx *vxlanv1alpha1.VXLANIndex) error {
+	// the index is created to ensure the backend is initialized
+	// e.g. after a restart
+	if err := r.vxlanClientProxy.CreateIndex(ctx, idx); err != nil {
+		return err
+	}
+	return r.vxlanClientProxy.DeleteClaim(ctx, buildIDClaim(cr, lepName, idx.GetName(), nil), nil)
+}
+
+// claimLogicalEndpointIDs claims an ESI for a multi-homed logical endpoint
+// and a LAG ID that is unique on each node of the logical endpoint.
+// The LAG ID is claimed dynamically on the first node and statically on the
+// other nodes such that all nodes use the same LAG ID.
+func (r *reconciler) claimLogicalEndpointIDs(ctx context.Context, cr *topov1alpha1.LogicalInterconnect, lepName string, lep invv1alpha1.LogicalEndpointSpec) (*logicalEndpointIDs, error) {
+	ids := &logicalEndpointIDs{}
+	nodes := getNodes(lep)
+	if len(nodes) > 1 {
+		esi, err := r.claimID(ctx, cr, lepName, buildIDIndex(cr, esiIndexName, esiOffset, esiMaxEntryID), nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot claim esi for %s, err: %s", lepName, err.Error())
+		}
+		ids.esi = esi
+	}
+	for _, node := range nodes {
+		lagID, err := r.claimID(ctx, cr, lepName, buildIDIndex(cr, getLagIndexName(node), lagOffset, lagMaxEntryID), ids.lagID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot claim lag id for %s on node %s, err: %s", lepName, node, err.Error())
+		}
+		ids.lagID = lagID
+	}
+	return ids, nil
+}
+
+// releaseLogicalEndpointIDs releases the ESI and LAG IDs of the logical endpoint
+// that are no longer used by the new logical endpoint spec. When newLep is nil all ids
+// are released.
+func (r *reconciler) releaseLogicalEndpointIDs(ctx context.Context, cr *topov1alpha1.LogicalInterconnect, lepName string, lep invv1alpha1.LogicalEndpointSpec, newLep *invv1alpha1.LogicalEndpointSpec) error {
+	nodes := getNodes(lep)
+	newNodes := []string{}
+	if newLep != nil {
+		newNodes = getNodes(*newLep)
+	}
+	if len(nodes) > 1 && len(newNodes) <= 1 {
+		if err := r.deleteIDClaim(ctx, cr, lepName, buildIDIndex(cr, esiIndexName, esiOffset, esiMaxEntryID)); err != nil {
+			return err
+		}
+	}
+	for _, node := range nodes {
+		found := false
+		for _, newNode := range newNodes {
+			if node == newNode {
+				found = true
+				break
+			}
+		}
+		if !found {
+			if err := r.deleteIDClaim(ctx, cr, lepName, buildIDIndex(cr, getLagIndexName(node), lagOffset, lagMaxEntryID)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getLogicalEndpoints returns the logical endpoints owned by the logical interconnect
+func (r *reconciler) getLogicalEndpoints(ctx context.Context, cr *topov1alpha1.LogicalInterconnect) (map[string]invv1alpha1.LogicalEndpoint, error) {
+	leps := &invv1alpha1.LogicalEndpointList{}
+	if err := r.List(ctx, leps, client.InNamespace(cr.GetNamespace())); err != nil {
+		return nil, err
+	}
+	ownedLeps := map[string]invv1alpha1.LogicalEndpoint{}
+	for _, lep := range leps.Items {
+		for _, ref := range lep.GetOwnerReferences() {
+			if ref.UID == cr.GetUID() {
+				ownedLeps[lep.GetName()] = lep
+			}
+		}
+	}
+	return ownedLeps, nil
+}
+
+// releaseIDs releases the ids of all the logical endpoints owned by the logical interconnect
+func (r *reconciler) releaseIDs(ctx context.Context, cr *topov1alpha1.LogicalInterconnect) error {
+	leps, err := r.getLogicalEndpoints(ctx, cr)
+	if err != nil {
+		return err
+	}
+	for lepName, lep := range leps {
+		if err := r.releaseLogicalEndpointIDs(ctx, cr, lepName, lep.Spec, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateLogicalEndpointIDs writes the claimed ids in the status of the logical endpoints
+func (r *reconciler) updateLogicalEndpointIDs(ctx context.Context, cr *topov1alpha1.LogicalInterconnect, lepIDs map[string]*logicalEndpointIDs) error {
+	for lepName, ids := range lepIDs {
+		lep := &invv1alpha1.LogicalEndpoint{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: lepName}, lep); err != nil {
+			return err
+		}
+		if ptr.Equal(lep.Status.ESI, ids.esi) && ptr.Equal(lep.Status.LagId, ids.lagID) {
+			continue
+		}
+		lep.Status.ESI = ids.esi
+		lep.Status.LagId = ids.lagID
+		if err := r.Status().Update(ctx, lep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
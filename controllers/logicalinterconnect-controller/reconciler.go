@@ -26,17 +26,18 @@ import (
 	"github.com/nephio-project/nephio/controllers/pkg/resource"
 	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
 	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
 	"github.com/nokia/k8s-ipam/controllers"
 	"github.com/nokia/k8s-ipam/controllers/ctrlconfig"
 	"github.com/nokia/k8s-ipam/pkg/lease"
 	"github.com/nokia/k8s-ipam/pkg/meta"
 	"github.com/nokia/k8s-ipam/pkg/objects/endpoint"
+	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
 	"github.com/nokia/k8s-ipam/pkg/resources"
 	perrors "github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -75,8 +76,12 @@ func (r *reconciler) Setup(ctx context.Context, mgr ctrl.Manager, cfg *ctrlconfi
 			invv1alpha1.LogicalEndpointGroupVersionKind,
 		},
 	})
-	r.endpoint = endpoint.New(mgr.GetClient())
-	r.epLease = lease.New(mgr.GetClient(), types.NamespacedName{Namespace: os.Getenv("POD_NAMESPACE"), Name: "endpoint"})
+	r.endpoint = endpoint.New(mgr.GetClient(), endpoint.WithVtepClientProxy(cfg.VxlanClientProxy))
+	r.vxlanClientProxy = cfg.VxlanClientProxy
+	r.epLease = lease.NewSet(mgr.GetClient(), os.Getenv("POD_NAMESPACE"), "endpoint",
+		lease.WithDuration(cfg.LeaseDuration),
+		lease.WithRenewInterval(cfg.LeaseRenewInterval),
+	)
 
 	return nil,
 		ctrl.NewControllerManagedBy(mgr).
@@ -95,7 +100,9 @@ type reconciler struct {
 
 	resources resources.Resources
 	endpoint  endpoint.Endpoint
-	epLease   lease.Lease
+	epLease   lease.Set
+	// vxlanClientProxy is used to claim the ESI and LAG IDs of the logical endpoints
+	vxlanClientProxy clientproxy.Proxy[*vxlanv1alpha1.VXLANIndex, *vxlanv1alpha1.VXLANClaim]
 
 	claimedEndpoints []invv1alpha1.Endpoint
 
@@ -126,6 +133,12 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	cr = cr.DeepCopy()
 
 	if meta.WasDeleted(cr) {
+		// release the ESI and LAG IDs of the logical endpoints
+		if err := r.releaseIDs(ctx, cr); err != nil {
+			r.l.Error(err, "cannot release logical endpoint ids")
+			cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+			return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		}
 		// delete usedRef from endpoint status
 		if err := r.endpoint.DeleteClaim(ctx, r.claimedEndpoints); err != nil {
 			r.l.Error(err, "cannot delete endpoint claim")
@@ -149,15 +162,25 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
 
-	// acquire lease to update the resource
-	if err := r.epLease.AcquireLease(ctx, cr); err != nil {
+	// acquire leases of the topologies to update the resource
+	if err := r.epLease.AcquireLeases(ctx, cr, cr.GetTopologies()); err != nil {
 		r.l.Error(err, "cannot acquire lease")
 		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
 		return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
+	defer func() {
+		if err := r.epLease.ReleaseLeases(ctx, cr, cr.GetTopologies()); err != nil {
+			r.l.Error(err, "cannot release lease")
+		}
+	}()
 
 	if err := r.populateResources(ctx, cr); err != nil {
 		// populate resources failed
+		if errd := r.releaseIDs(ctx, cr); errd != nil {
+			err = errors.Join(err, errd)
+			r.l.Error(err, "cannot populate and release logical endpoint ids")
+			return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		}
 		if errd := r.resources.APIDelete(ctx, cr); errd != nil {
 			err = errors.Join(err, errd)
 			r.l.Error(err, "cannot populate and delete existingresources")
@@ -184,13 +207,13 @@ func (r *reconciler) populateResources(ctx context.Context, cr *topov1alpha1.Log
 	r.resources.Init(client.MatchingLabels{})
 
 	// we keep track of all selected enpoints to ensure they get labelled
-	s := newSelector(cr.Spec.Links, r.endpoint)
-	selectionResult, err := s.selectEndpoints(ctx, cr)
+	s := endpoint.NewSelector(cr.Spec.Links, r.endpoint, nil)
+	selectionResult, err := s.SelectEndpoints(ctx, cr, &cr.Spec)
 	if err != nil {
 		return err
 	}
 	// in this case the allocation was successfull
-	for _, l := range selectionResult.getLinkSpecs() {
+	for _, l := range selectionResult.GetLinkSpecs() {
 		linkName := fmt.Sprintf("%s-%s-%s-%s", l.Endpoints[0].NodeName, l.Endpoints[0].InterfaceName, l.Endpoints[1].NodeName, l.Endpoints[1].InterfaceName)
 		if err := r.resources.AddNewResource(cr, invv1alpha1.BuildLink(
 			metav1.ObjectMeta{
@@ -204,12 +227,29 @@ func (r *reconciler) populateResources(ctx context.Context, cr *topov1alpha1.Log
 			return err
 		}
 	}
-	// tbd determine multihoming
-	for epIdx, lep := range selectionResult.getLogicalEndpoints() {
+	// existing logical endpoints are used to release the ids that are no longer required
+	existingLeps, err := r.getLogicalEndpoints(ctx, cr)
+	if err != nil {
+		return err
+	}
+	lepIDs := map[string]*logicalEndpointIDs{}
+	for epIdx, lep := range selectionResult.GetLogicalEndpoints() {
+		lepName := fmt.Sprintf("%s-logical-ep%d", cr.GetName(), epIdx)
+		if existingLep, ok := existingLeps[lepName]; ok {
+			lep := lep
+			if err := r.releaseLogicalEndpointIDs(ctx, cr, lepName, existingLep.Spec, &lep); err != nil {
+				return err
+			}
+		}
+		// a multi-homed logical endpoint gets an ESI, all logical endpoints get a LAG ID
+		lepIDs[lepName], err = r.claimLogicalEndpointIDs(ctx, cr, lepName, lep)
+		if err != nil {
+			return err
+		}
 		// topology
 		if err := r.resources.AddNewResource(cr, invv1alpha1.BuildLogicalEndpoint(
 			metav1.ObjectMeta{
-				Name:      fmt.Sprintf("%s-logical-ep%d", cr.GetName(), epIdx),
+				Name:      lepName,
 				Namespace: cr.GetNamespace(),
 				//OwnerReferences: []metav1.OwnerReference{{APIVersion: cr.APIVersion, Kind: cr.Kind, Name: cr.Name, UID: cr.UID, Controller: pointer.Bool(true)}},
 			},
@@ -224,7 +264,7 @@ func (r *reconciler) populateResources(ctx context.Context, cr *topov1alpha1.Log
 	tobeDeletedClaims := []invv1alpha1.Endpoint{}
 	for _, cep := range r.claimedEndpoints {
 		found := false
-		for _, ep := range selectionResult.getSelectedEndpoints() {
+		for _, ep := range selectionResult.GetSelectedEndpoints() {
 			if cep.Name == ep.Name && cep.Namespace == ep.Namespace {
 				found = true
 				break
@@ -240,9 +280,14 @@ func (r *reconciler) populateResources(ctx context.Context, cr *topov1alpha1.Log
 	}
 
 	// updated the claim ref in the selected endpoints
-	if err := r.endpoint.Claim(ctx, cr, selectionResult.getSelectedEndpoints()); err != nil {
+	if err := r.endpoint.Claim(ctx, cr, selectionResult.GetSelectedEndpoints()); err != nil {
 		return err
 	}
 
-	return r.resources.APIApply(ctx, cr)
+	if err := r.resources.APIApply(ctx, cr); err != nil {
+		return err
+	}
+	// the status is not applied with the resource, so we update it once the
+	// logical endpoints exist
+	return r.updateLogicalEndpointIDs(ctx, cr, lepIDs)
 }
@@ -0,0 +1,188 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/controllers"
+	"github.com/nokia/k8s-ipam/controllers/ctrlconfig"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/resources"
+	perrors "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	controllers.Register("nodepools", &reconciler{})
+}
+
+const (
+	finalizer = "inv.nephio.org/finalizer"
+	// error
+	errGetCr        = "cannot get resource"
+	errUpdateStatus = "cannot update status"
+)
+
+//+kubebuilder:rbac:groups=inv.nephio.org,resources=nodepools,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=inv.nephio.org,resources=nodepools/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=inv.nephio.org,resources=nodepools/finalizers,verbs=update
+//+kubebuilder:rbac:groups=inv.nephio.org,resources=nodes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=inv.nephio.org,resources=nodes/status,verbs=get;update;patch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *reconciler) Setup(ctx context.Context, mgr ctrl.Manager, cfg *ctrlconfig.ControllerConfig) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
+	// register scheme
+	if err := invv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+
+	// initialize reconciler
+	r.APIPatchingApplicator = resource.NewAPIPatchingApplicator(mgr.GetClient())
+	r.finalizer = resource.NewAPIFinalizer(mgr.GetClient(), finalizer)
+	r.resources = resources.New(r.APIPatchingApplicator, resources.Config{
+		OwnerRef: true,
+		Owns: []schema.GroupVersionKind{
+			invv1alpha1.NodeGroupVersionKind,
+		},
+	})
+
+	return nil,
+		ctrl.NewControllerManagedBy(mgr).
+			Named("NodePoolController").
+			For(&invv1alpha1.NodePool{}).
+			Owns(&invv1alpha1.Node{}).
+			Complete(r)
+}
+
+// reconciler reconciles a NodePool object
+type reconciler struct {
+	resource.APIPatchingApplicator
+	finalizer *resource.APIFinalizer
+
+	resources resources.Resources
+
+	l logr.Logger
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.l = log.FromContext(ctx)
+	r.l.Info("reconcile", "req", req)
+
+	cr := &invv1alpha1.NodePool{}
+	if err := r.Get(ctx, req.NamespacedName, cr); err != nil {
+		// There's no need to requeue if we no longer exist. Otherwise we'll be
+		// requeued implicitly because we return an error.
+		if resource.IgnoreNotFound(err) != nil {
+			r.l.Error(err, errGetCr)
+			return ctrl.Result{}, perrors.Wrap(resource.IgnoreNotFound(err), errGetCr)
+		}
+		return reconcile.Result{}, nil
+	}
+	cr = cr.DeepCopy()
+
+	if meta.WasDeleted(cr) {
+		// delete the nodes owned by the nodepool
+		if err := r.resources.APIDelete(ctx, cr); err != nil {
+			r.l.Error(err, "cannot delete nodes")
+			cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+			return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		}
+		if err := r.finalizer.RemoveFinalizer(ctx, cr); err != nil {
+			r.l.Error(err, "cannot remove finalizer")
+			cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+			return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		}
+		r.l.Info("Successfully deleted resource")
+		return reconcile.Result{Requeue: false}, nil
+	}
+	if err := r.finalizer.AddFinalizer(ctx, cr); err != nil {
+		// If this is the first time we encounter this issue we'll be requeued
+		// implicitly when we update our status with the new error condition. If
+		// not, we requeue explicitly, which will trigger backoff.
+		r.l.Error(err, "cannot add finalizer")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+
+	if err := r.populateResources(ctx, cr); err != nil {
+		// populate resources failed, the nodes that were applied before are
+		// kept and the populate is retried
+		r.l.Error(err, "cannot populate resources")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+
+	cr.Status.UsedNodeConfigRef = cr.Spec.NodeConfig
+	cr.SetConditions(resourcev1alpha1.Ready())
+	return ctrl.Result{}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+}
+
+// populateResources materializes the nodes of the nodepool. Nodes are named by
+// index, scaling down removes the nodes with the highest index.
+func (r *reconciler) populateResources(ctx context.Context, cr *invv1alpha1.NodePool) error {
+	// initialize the resource list + provide the nodepool key
+	r.resources.Init(client.MatchingLabels{
+		invv1alpha1.NephioInventoryNodePool: cr.GetName(),
+	})
+
+	for idx := uint32(0); idx < cr.GetNodes(); idx++ {
+		if err := r.resources.AddNewResource(cr, buildNode(cr, idx).DeepCopy()); err != nil {
+			return err
+		}
+	}
+	return r.resources.APIApply(ctx, cr)
+}
+
+func buildNode(cr *invv1alpha1.NodePool, idx uint32) *invv1alpha1.Node {
+	nodeName := cr.GetNodeName(idx)
+	labels := map[string]string{}
+	for k, v := range cr.Spec.GetUserDefinedLabels() {
+		labels[k] = v
+	}
+	labels[invv1alpha1.NephioTopologyKey] = cr.Namespace
+	labels[invv1alpha1.NephioInventoryNodeNameKey] = nodeName
+	labels[invv1alpha1.NephioProviderKey] = cr.Spec.Provider
+	labels[invv1alpha1.NephioInventoryNodePool] = cr.GetName()
+	labels[invv1alpha1.NephioInventoryNodeIndex] = strconv.Itoa(int(idx))
+
+	return invv1alpha1.BuildNode(
+		metav1.ObjectMeta{
+			Name:      nodeName,
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		invv1alpha1.NodeSpec{
+			Provider:          cr.Spec.Provider,
+			UserDefinedLabels: cr.Spec.UserDefinedLabels,
+			Location:          cr.Spec.Location,
+			NodeConfig:        cr.Spec.NodeConfig,
+		},
+		invv1alpha1.NodeStatus{},
+	)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newTestReconciler(t *testing.T, pool *invv1alpha1.NodePool, funcs interceptor.Funcs) (*reconciler, client.Client) {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := invv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(pool).
+		WithStatusSubresource(pool).
+		WithInterceptorFuncs(funcs).
+		Build()
+	r := &reconciler{
+		APIPatchingApplicator: resource.NewAPIPatchingApplicator(c),
+		finalizer:             resource.NewAPIFinalizer(c, finalizer),
+	}
+	r.resources = resources.New(r.APIPatchingApplicator, resources.Config{
+		OwnerRef: true,
+		Owns:     []schema.GroupVersionKind{invv1alpha1.NodeGroupVersionKind},
+	})
+	return r, c
+}
+
+func newNodePool(nodes *uint32) *invv1alpha1.NodePool {
+	return &invv1alpha1.NodePool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: invv1alpha1.GroupVersion.String(),
+			Kind:       invv1alpha1.NodePoolKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "topo", Name: "pool", UID: "pool-uid"},
+		Spec: invv1alpha1.NodePoolSpec{
+			Nodes:    nodes,
+			Provider: "srl.nokia.com",
+			UserDefinedLabels: resourcev1alpha1.UserDefinedLabels{
+				Labels: map[string]string{"rack": "a"},
+			},
+		},
+	}
+}
+
+func reconcileNodePool(t *testing.T, r *reconciler) ctrl.Result {
+	t.Helper()
+	res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "topo", Name: "pool"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func listNodeNames(t *testing.T, c client.Client) []string {
+	t.Helper()
+	nodes := &invv1alpha1.NodeList{}
+	if err := c.List(context.Background(), nodes); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, n := range nodes.Items {
+		names = append(names, n.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func setNodes(t *testing.T, c client.Client, nodes uint32) {
+	t.Helper()
+	pool := &invv1alpha1.NodePool{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "topo", Name: "pool"}, pool); err != nil {
+		t.Fatal(err)
+	}
+	pool.Spec.Nodes = ptr.To(nodes)
+	if err := c.Update(context.Background(), pool); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getCondition(t *testing.T, c client.Client) resourcev1alpha1.Condition {
+	t.Helper()
+	pool := &invv1alpha1.NodePool{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "topo", Name: "pool"}, pool); err != nil {
+		t.Fatal(err)
+	}
+	return pool.GetCondition(resourcev1alpha1.ConditionTypeReady)
+}
+
+func TestReconcileNodes(t *testing.T) {
+	cases := map[string]struct {
+		nodes *uint32
+		want  []string
+	}{
+		"Default": {
+			want: []string{"pool-0"},
+		},
+		"Nodes": {
+			nodes: ptr.To[uint32](3),
+			want:  []string{"pool-0", "pool-1", "pool-2"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, c := newTestReconciler(t, newNodePool(tc.nodes), interceptor.Funcs{})
+			reconcileNodePool(t, r)
+
+			got := listNodeNames(t, c)
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Fatalf("want nodes %v, got %v", tc.want, got)
+			}
+			if cond := getCondition(t, c); cond.Status != metav1.ConditionTrue {
+				t.Errorf("want ready condition, got %v", cond)
+			}
+		})
+	}
+}
+
+func TestReconcileNodeLabels(t *testing.T) {
+	r, c := newTestReconciler(t, newNodePool(ptr.To[uint32](2)), interceptor.Funcs{})
+	reconcileNodePool(t, r)
+
+	node := &invv1alpha1.Node{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "topo", Name: "pool-1"}, node); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"rack":                                 "a",
+		invv1alpha1.NephioTopologyKey:          "topo",
+		invv1alpha1.NephioInventoryNodeNameKey: "pool-1",
+		invv1alpha1.NephioProviderKey:          "srl.nokia.com",
+		invv1alpha1.NephioInventoryNodePool:    "pool",
+		invv1alpha1.NephioInventoryNodeIndex:   "1",
+	}
+	for k, v := range want {
+		if node.GetLabels()[k] != v {
+			t.Errorf("want label %s=%s, got %q", k, v, node.GetLabels()[k])
+		}
+	}
+	if node.Spec.Provider != "srl.nokia.com" {
+		t.Errorf("want provider srl.nokia.com, got %s", node.Spec.Provider)
+	}
+	if refs := node.GetOwnerReferences(); len(refs) != 1 || refs[0].UID != "pool-uid" {
+		t.Errorf("want the nodepool as owner, got %v", refs)
+	}
+}
+
+func TestReconcileScaleDown(t *testing.T) {
+	r, c := newTestReconciler(t, newNodePool(ptr.To[uint32](3)), interceptor.Funcs{})
+	reconcileNodePool(t, r)
+
+	// the nodes with the highest index are removed
+	setNodes(t, c, 1)
+	reconcileNodePool(t, r)
+	if got := listNodeNames(t, c); fmt.Sprint(got) != fmt.Sprint([]string{"pool-0"}) {
+		t.Errorf("want nodes [pool-0], got %v", got)
+	}
+}
+
+func TestReconcilePopulateFailure(t *testing.T) {
+	failCreate := false
+	r, c := newTestReconciler(t, newNodePool(ptr.To[uint32](2)), interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if failCreate && obj.GetName() == "pool-2" {
+				return fmt.Errorf("create failed")
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	})
+	reconcileNodePool(t, r)
+
+	// a failed scale up keeps the nodes that were applied before
+	failCreate = true
+	setNodes(t, c, 3)
+	if res := reconcileNodePool(t, r); !res.Requeue {
+		t.Errorf("want requeue when the populate fails")
+	}
+	if got := listNodeNames(t, c); fmt.Sprint(got) != fmt.Sprint([]string{"pool-0", "pool-1"}) {
+		t.Errorf("want nodes [pool-0 pool-1], got %v", got)
+	}
+	if cond := getCondition(t, c); cond.Status != metav1.ConditionFalse {
+		t.Errorf("want failed condition, got %v", cond)
+	}
+
+	failCreate = false
+	reconcileNodePool(t, r)
+	if got := listNodeNames(t, c); fmt.Sprint(got) != fmt.Sprint([]string{"pool-0", "pool-1", "pool-2"}) {
+		t.Errorf("want nodes [pool-0 pool-1 pool-2], got %v", got)
+	}
+}
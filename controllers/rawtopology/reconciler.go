@@ -161,7 +161,7 @@ func (r *reconciler) populateResources(ctx context.Context, cr *topov1alpha1.Raw
 	})
 
 	// validate the input
-	if err := validate(cr); err != nil {
+	if err := cr.Validate(); err != nil {
 		return err
 	}
 	// add the node to the resourceList
@@ -195,52 +195,6 @@ func (r *reconciler) getTopologies(l invv1alpha1.LinkSpec) []string {
 	return topologies
 }
 
-func validate(topo *topov1alpha1.RawTopology) error {
-	if err := validateLink2Nodes(topo); err != nil {
-		return err
-	}
-
-	if err := validateLinks(topo); err != nil {
-		return err
-	}
-	return nil
-}
-
-func validateLink2Nodes(topo *topov1alpha1.RawTopology) error {
-	invalidNodeRef := []string{}
-	for _, l := range topo.Spec.Links {
-		for _, e := range l.Endpoints {
-			epString := fmt.Sprintf("%s:%s", e.NodeName, e.InterfaceName)
-			if _, ok := topo.Spec.Nodes[e.NodeName]; !ok {
-				invalidNodeRef = append(invalidNodeRef, epString)
-			}
-		}
-	}
-	if len(invalidNodeRef) != 0 {
-		return fmt.Errorf("endpoints %q has no node reference", invalidNodeRef)
-	}
-	return nil
-}
-
-func validateLinks(topo *topov1alpha1.RawTopology) error {
-	endpoints := map[string]struct{}{}
-	// dups accumulates duplicate links
-	dups := []string{}
-	for _, l := range topo.Spec.Links {
-		for _, e := range l.Endpoints {
-			epString := fmt.Sprintf("%s:%s", e.NodeName, e.InterfaceName)
-			if _, ok := endpoints[epString]; ok {
-				dups = append(dups, epString)
-			}
-			endpoints[epString] = struct{}{}
-		}
-	}
-	if len(dups) != 0 {
-		return fmt.Errorf("endpoints %q appeared more than once in the links section of the topology file", dups)
-	}
-	return nil
-}
-
 func buildNode(cr *topov1alpha1.RawTopology, nodeName string, nodeSpec invv1alpha1.NodeSpec) *invv1alpha1.Node {
 	labels := map[string]string{}
 	for k, v := range nodeSpec.Labels {
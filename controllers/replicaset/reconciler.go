@@ -0,0 +1,487 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package replicaset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	autov1alpha1 "github.com/nokia/k8s-ipam/apis/auto/v1alpha1"
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	"github.com/nokia/k8s-ipam/controllers"
+	"github.com/nokia/k8s-ipam/controllers/ctrlconfig"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/pipeline"
+	"github.com/nokia/k8s-ipam/pkg/resources"
+	perrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+func init() {
+	controllers.Register("replicasets", &reconciler{})
+}
+
+const (
+	finalizer = "auto.nephio.org/finalizer"
+	// error
+	errGetCr        = "cannot get resource"
+	errUpdateStatus = "cannot update status"
+)
+
+//+kubebuilder:rbac:groups=auto.nephio.org,resources=replicasets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=auto.nephio.org,resources=replicasets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=auto.nephio.org,resources=replicasets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=inv.nephio.org,resources=nodes;nodepools;links;endpoints,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=topo.nephio.org,resources=interconnects;logicalinterconnects;rawtopologies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=ipam.resource.nephio.org,resources=ipclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=vlan.resource.nephio.org,resources=vlanclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// replicaKinds are the kinds the replicas can be stamped out as, the rbac of
+// the controller is scoped to these kinds
+var replicaKinds = map[schema.GroupKind]struct{}{
+	{Group: invv1alpha1.GroupVersion.Group, Kind: invv1alpha1.NodeKind}:                  {},
+	{Group: invv1alpha1.GroupVersion.Group, Kind: invv1alpha1.NodePoolKind}:              {},
+	{Group: invv1alpha1.GroupVersion.Group, Kind: invv1alpha1.LinkKind}:                  {},
+	{Group: invv1alpha1.GroupVersion.Group, Kind: invv1alpha1.EndpointKind}:              {},
+	{Group: topov1alpha1.GroupVersion.Group, Kind: topov1alpha1.InterconnectKind}:        {},
+	{Group: topov1alpha1.GroupVersion.Group, Kind: topov1alpha1.LogicalInterconnectKind}: {},
+	{Group: topov1alpha1.GroupVersion.Group, Kind: topov1alpha1.RawTopologyKind}:         {},
+	{Group: ipamv1alpha1.GroupVersion.Group, Kind: ipamv1alpha1.IPClaimKind}:             {},
+	{Group: vlanv1alpha1.GroupVersion.Group, Kind: vlanv1alpha1.VLANClaimKind}:           {},
+}
+
+// selectorKinds are the kinds the object selectors can select besides the
+// replica kinds, the selected objects are only read
+var selectorKinds = map[schema.GroupKind]struct{}{
+	{Kind: "ConfigMap"}: {},
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *reconciler) Setup(ctx context.Context, mgr ctrl.Manager, cfg *ctrlconfig.ControllerConfig) (map[schema.GroupVersionKind]chan event.GenericEvent, error) {
+	// register scheme
+	if err := autov1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return nil, err
+	}
+
+	// initialize reconciler
+	r.APIPatchingApplicator = resource.NewAPIPatchingApplicator(mgr.GetClient())
+	r.finalizer = resource.NewAPIFinalizer(mgr.GetClient(), finalizer)
+	r.cache = mgr.GetCache()
+	r.handler = &objectEventHandler{client: mgr.GetClient()}
+	r.watches = map[schema.GroupVersionKind]struct{}{}
+
+	// the kinds of the replicas and of the selected objects are only known
+	// from the replicasets, hence they are watched once a replicaset uses them
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		Named("ReplicaSetController").
+		For(&autov1alpha1.ReplicaSet{}).
+		Build(r)
+	if err != nil {
+		return nil, err
+	}
+	r.ctrl = c
+	return nil, nil
+}
+
+// reconciler reconciles a ReplicaSet object
+type reconciler struct {
+	resource.APIPatchingApplicator
+	finalizer *resource.APIFinalizer
+
+	ctrl    controller.Controller
+	cache   cache.Cache
+	handler handler.EventHandler
+	m       sync.Mutex
+	watches map[schema.GroupVersionKind]struct{}
+
+	l logr.Logger
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.l = log.FromContext(ctx)
+	r.l.Info("reconcile", "req", req)
+
+	cr := &autov1alpha1.ReplicaSet{}
+	if err := r.Get(ctx, req.NamespacedName, cr); err != nil {
+		// There's no need to requeue if we no longer exist. Otherwise we'll be
+		// requeued implicitly because we return an error.
+		if resource.IgnoreNotFound(err) != nil {
+			r.l.Error(err, errGetCr)
+			return ctrl.Result{}, perrors.Wrap(resource.IgnoreNotFound(err), errGetCr)
+		}
+		return reconcile.Result{}, nil
+	}
+	cr = cr.DeepCopy()
+
+	// the replicas are owned by the kind of the template and the kind the
+	// replicas were last stamped out from, hence the resource inventory is
+	// initialized per reconcile
+	gvk, err := getTemplateGVK(cr)
+
+	if meta.WasDeleted(cr) {
+		// delete the replicas owned by the replicaset, the replicas stamped out
+		// before are deleted as well when the template is invalid or changed
+		gvks := getUsedGVKs(cr)
+		if err == nil {
+			gvks = append(gvks, gvk)
+		}
+		if err := r.getResources(gvks).APIDelete(ctx, cr); err != nil {
+			r.l.Error(err, "cannot delete replicas")
+			cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+			return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		}
+		if err := r.finalizer.RemoveFinalizer(ctx, cr); err != nil {
+			r.l.Error(err, "cannot remove finalizer")
+			cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+			return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		}
+		r.l.Info("Successfully deleted resource")
+		return reconcile.Result{Requeue: false}, nil
+	}
+	if err != nil {
+		r.l.Error(err, "cannot get template")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+	if err := validateSelectors(cr); err != nil {
+		r.l.Error(err, "cannot get selectors")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+	if err := r.finalizer.AddFinalizer(ctx, cr); err != nil {
+		// If this is the first time we encounter this issue we'll be requeued
+		// implicitly when we update our status with the new error condition. If
+		// not, we requeue explicitly, which will trigger backoff.
+		r.l.Error(err, "cannot add finalizer")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+
+	if err := r.watchKinds(cr, gvk); err != nil {
+		r.l.Error(err, "cannot watch replicas and selected objects")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+
+	replicas, err := r.buildReplicas(ctx, cr)
+	if err != nil {
+		r.l.Error(err, "cannot build replicas")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+	// validation failures are reported in the status, the existing replicas
+	// are left untouched until the replicaset is updated
+	if failures, err := validateReplicas(cr, replicas); err != nil || len(failures) > 0 {
+		if err == nil {
+			err = fmt.Errorf("validation failed: %s", strings.Join(failures, ", "))
+		}
+		r.l.Error(err, "cannot validate replicas")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+
+	// replicas of a previous template kind are garbage collected by the apply
+	res := r.getResources(append(getUsedGVKs(cr), gvk))
+	if err := r.applyReplicas(ctx, cr, res, replicas); err != nil {
+		r.l.Error(err, "cannot apply replicas")
+		cr.SetConditions(resourcev1alpha1.Failed(err.Error()))
+		return reconcile.Result{Requeue: true}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+	}
+
+	cr.Status.UsedTemplateRef = &corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+	}
+	cr.SetConditions(resourcev1alpha1.Ready())
+	return ctrl.Result{}, perrors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+}
+
+// getTemplateGVK returns the apiVersion and kind of the template
+func getTemplateGVK(cr *autov1alpha1.ReplicaSet) (schema.GroupVersionKind, error) {
+	t, err := cr.GetTemplate()
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	gvk := t.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("template requires apiVersion and kind")
+	}
+	if _, ok := replicaKinds[gvk.GroupKind()]; !ok {
+		return schema.GroupVersionKind{}, fmt.Errorf("template kind %s cannot be replicated", gvk.GroupKind())
+	}
+	return gvk, nil
+}
+
+// validateSelectors returns an error if an object selector selects a kind
+// that is neither a replica kind nor a selector kind
+func validateSelectors(cr *autov1alpha1.ReplicaSet) error {
+	for _, selector := range cr.Spec.ObjectSelectors {
+		gvk, err := selector.GetGroupVersionKind()
+		if err != nil {
+			return err
+		}
+		_, replicaKind := replicaKinds[gvk.GroupKind()]
+		_, selectorKind := selectorKinds[gvk.GroupKind()]
+		if !replicaKind && !selectorKind {
+			return fmt.Errorf("selector %s cannot select kind %s", selector.VariableName, gvk.GroupKind())
+		}
+	}
+	return nil
+}
+
+// getUsedGVKs returns the apiVersion and kind of the template the replicas
+// were last stamped out from
+func getUsedGVKs(cr *autov1alpha1.ReplicaSet) []schema.GroupVersionKind {
+	ref := cr.Status.UsedTemplateRef
+	if ref == nil {
+		return nil
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil || ref.Kind == "" {
+		return nil
+	}
+	return []schema.GroupVersionKind{gv.WithKind(ref.Kind)}
+}
+
+// getResources returns the resource inventory of the replicas of the given
+// kinds, kinds that are no longer served by the api server are skipped as no
+// replicas of these kinds can exist
+func (r *reconciler) getResources(gvks []schema.GroupVersionKind) resources.Resources {
+	owns := []schema.GroupVersionKind{}
+	seen := map[schema.GroupVersionKind]struct{}{}
+	for _, gvk := range gvks {
+		if _, ok := seen[gvk]; ok {
+			continue
+		}
+		seen[gvk] = struct{}{}
+		if _, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); apimeta.IsNoMatchError(err) {
+			continue
+		}
+		owns = append(owns, gvk)
+	}
+	return resources.New(r.APIPatchingApplicator, resources.Config{
+		OwnerRef: true,
+		Owns:     owns,
+	})
+}
+
+// watchKinds watches the kind of the replicas and the kinds of the selected
+// objects, every kind is watched once for all replicasets
+func (r *reconciler) watchKinds(cr *autov1alpha1.ReplicaSet, gvk schema.GroupVersionKind) error {
+	gvks := []schema.GroupVersionKind{gvk}
+	for _, selector := range cr.Spec.ObjectSelectors {
+		gvk, err := selector.GetGroupVersionKind()
+		if err != nil {
+			return err
+		}
+		gvks = append(gvks, gvk)
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	for _, gvk := range gvks {
+		if _, ok := r.watches[gvk]; ok {
+			continue
+		}
+		o := &unstructured.Unstructured{}
+		o.SetGroupVersionKind(gvk)
+		if err := r.ctrl.Watch(source.Kind(r.cache, o), r.handler); err != nil {
+			return err
+		}
+		r.watches[gvk] = struct{}{}
+	}
+	return nil
+}
+
+// applyReplicas applies the replicas and garbage collects the replicas that
+// are no longer needed
+func (r *reconciler) applyReplicas(ctx context.Context, cr *autov1alpha1.ReplicaSet, res resources.Resources, replicas []replica) error {
+	// initialize the resource list + provide the replicaset key
+	res.Init(client.MatchingLabels{
+		autov1alpha1.ReplicaSetKey: cr.GetName(),
+	})
+	for _, replica := range replicas {
+		if err := res.AddNewResource(cr, replica.obj); err != nil {
+			return err
+		}
+	}
+	return res.APIApply(ctx, cr)
+}
+
+// selectedObject is an object selected by an object selector
+type selectedObject struct {
+	variableName string
+	obj          *unstructured.Unstructured
+}
+
+// getSelectedObjects returns the objects selected by the object selectors in
+// the namespace of the replicaset
+func (r *reconciler) getSelectedObjects(ctx context.Context, cr *autov1alpha1.ReplicaSet) ([]selectedObject, error) {
+	objs := []selectedObject{}
+	for _, selector := range cr.Spec.ObjectSelectors {
+		gvk, err := selector.GetGroupVersionKind()
+		if err != nil {
+			return nil, err
+		}
+		ls, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		l := meta.GetUnstructuredListFromGVK(&gvk)
+		if err := r.List(ctx, l, client.InNamespace(cr.GetNamespace()), client.MatchingLabelsSelector{Selector: ls}); err != nil {
+			return nil, err
+		}
+		for _, o := range l.Items {
+			o := o
+			if selector.Name != nil && o.GetName() != *selector.Name {
+				continue
+			}
+			objs = append(objs, selectedObject{variableName: selector.VariableName, obj: &o})
+		}
+	}
+	return objs, nil
+}
+
+// replica is a replica stamped out from the template together with the
+// variables of the pipeline it was stamped out with
+type replica struct {
+	obj  *unstructured.Unstructured
+	vars pipeline.Variables
+}
+
+// buildReplicas stamps out the replicas of the template, without object
+// selectors the template is replicated once, otherwise it is replicated per
+// selected object with the selected object available as a variable to the
+// pipeline.
+func (r *reconciler) buildReplicas(ctx context.Context, cr *autov1alpha1.ReplicaSet) ([]replica, error) {
+	replicas := []replica{}
+	if len(cr.Spec.ObjectSelectors) == 0 {
+		for idx := int32(0); idx < cr.GetReplicas(); idx++ {
+			vars := pipeline.Variables{}
+			o, err := buildReplica(cr, fmt.Sprintf("%s-%d", cr.GetName(), idx), idx, vars)
+			if err != nil {
+				return nil, err
+			}
+			replicas = append(replicas, replica{obj: o, vars: vars})
+		}
+		return replicas, nil
+	}
+
+	objs, err := r.getSelectedObjects(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	for _, selected := range objs {
+		for idx := int32(0); idx < cr.GetReplicas(); idx++ {
+			vars := pipeline.Variables{selected.variableName: selected.obj.UnstructuredContent()}
+			o, err := buildReplica(cr,
+				fmt.Sprintf("%s-%s-%d", cr.GetName(), selected.obj.GetName(), idx),
+				idx,
+				vars)
+			if err != nil {
+				return nil, err
+			}
+			replicas = append(replicas, replica{obj: o, vars: vars})
+		}
+	}
+	return replicas, nil
+}
+
+// buildReplica builds a replica from the template and applies the mutators of
+// the pipeline, the index of the replica is added to the variables
+func buildReplica(cr *autov1alpha1.ReplicaSet, name string, idx int32, vars pipeline.Variables) (*unstructured.Unstructured, error) {
+	o, err := cr.GetTemplate()
+	if err != nil {
+		return nil, err
+	}
+	o.SetName(name)
+	vars[pipeline.ReplicaVariable] = int64(idx)
+	if cr.Spec.Pipeline != nil {
+		for _, fn := range cr.Spec.Pipeline.Mutators {
+			if fn.Expr == nil {
+				continue
+			}
+			if err := pipeline.Mutate(o.Object, *fn.Expr, vars); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// the name, the namespace and the labels are set after the mutators to
+	// ensure the replicas are unique and owned by the replicaset
+	o.SetName(name)
+	o.SetNamespace(cr.GetNamespace())
+	labels := o.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[autov1alpha1.ReplicaSetKey] = cr.GetName()
+	labels[autov1alpha1.ReplicaIndexKey] = strconv.Itoa(int(idx))
+	o.SetLabels(labels)
+	return o, nil
+}
+
+// validateReplicas evaluates the validators of the pipeline against the
+// replicas with the variables the replicas were stamped out with and returns
+// the validation failures
+func validateReplicas(cr *autov1alpha1.ReplicaSet, replicas []replica) ([]string, error) {
+	if cr.Spec.Pipeline == nil {
+		return nil, nil
+	}
+	failures := []string{}
+	var errs error
+	for _, replica := range replicas {
+		o := replica.obj
+		for _, fn := range cr.Spec.Pipeline.Validators {
+			if fn.Expr == nil {
+				continue
+			}
+			ok, err := pipeline.Validate(o.Object, *fn.Expr, replica.vars)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			if !ok {
+				failures = append(failures, fmt.Sprintf("%s: %s", o.GetName(), *fn.Expr))
+			}
+		}
+	}
+	return failures, errs
+}
@@ -0,0 +1,372 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicaset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nephio-project/nephio/controllers/pkg/resource"
+	autov1alpha1 "github.com/nokia/k8s-ipam/apis/auto/v1alpha1"
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var linkGroupVersionKind = invv1alpha1.GroupVersion.WithKind(invv1alpha1.LinkKind)
+
+// fakeController records the kinds that are watched
+type fakeController struct {
+	controller.Controller
+	watches int
+}
+
+func (r *fakeController) Watch(src source.Source, eventhandler handler.EventHandler, predicates ...predicate.Predicate) error {
+	r.watches++
+	return nil
+}
+
+func newTestReconciler(t *testing.T, objs ...client.Object) (*reconciler, client.Client) {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := autov1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := invv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		invv1alpha1.NodeGroupVersionKind,
+		linkGroupVersionKind,
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+	} {
+		mapper.Add(gvk, apimeta.RESTScopeNamespace)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithRESTMapper(mapper).
+		WithObjects(objs...).
+		WithStatusSubresource(&autov1alpha1.ReplicaSet{}).
+		Build()
+	return &reconciler{
+		APIPatchingApplicator: resource.NewAPIPatchingApplicator(c),
+		finalizer:             resource.NewAPIFinalizer(c, finalizer),
+		ctrl:                  &fakeController{},
+		watches:               map[schema.GroupVersionKind]struct{}{},
+	}, c
+}
+
+func newTemplate(t *testing.T, gvk schema.GroupVersionKind) runtime.RawExtension {
+	t.Helper()
+	o := &unstructured.Unstructured{}
+	o.SetGroupVersionKind(gvk)
+	o.SetName("template")
+	o.SetLabels(map[string]string{"rack": "a"})
+	if err := unstructured.SetNestedField(o.Object, "srl.nokia.com", "spec", "provider"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := o.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runtime.RawExtension{Raw: b}
+}
+
+func newReplicaSet(t *testing.T, replicas int32) *autov1alpha1.ReplicaSet {
+	return &autov1alpha1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: autov1alpha1.GroupVersion.String(),
+			Kind:       autov1alpha1.ReplicaSetKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "rs", UID: "rs-uid"},
+		Spec: autov1alpha1.ReplicaSetSpec{
+			Replicas: ptr.To(replicas),
+			Template: newTemplate(t, invv1alpha1.NodeGroupVersionKind),
+		},
+	}
+}
+
+func newConfigMap(name string, labels map[string]string, provider string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels},
+		Data:       map[string]string{"provider": provider},
+	}
+}
+
+func reconcileReplicaSet(t *testing.T, r *reconciler) {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "rs"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getReplicaSet(t *testing.T, c client.Client) *autov1alpha1.ReplicaSet {
+	t.Helper()
+	cr := &autov1alpha1.ReplicaSet{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "rs"}, cr); err != nil {
+		t.Fatal(err)
+	}
+	return cr
+}
+
+func updateReplicaSet(t *testing.T, c client.Client, fn func(cr *autov1alpha1.ReplicaSet)) {
+	t.Helper()
+	cr := getReplicaSet(t, c)
+	fn(cr)
+	if err := c.Update(context.Background(), cr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func listReplicas(t *testing.T, c client.Client, gvk schema.GroupVersionKind) []*unstructured.Unstructured {
+	t.Helper()
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(context.Background(), l); err != nil {
+		t.Fatal(err)
+	}
+	replicas := []*unstructured.Unstructured{}
+	for i := range l.Items {
+		replicas = append(replicas, &l.Items[i])
+	}
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].GetName() < replicas[j].GetName() })
+	return replicas
+}
+
+func replicaNames(replicas []*unstructured.Unstructured) []string {
+	names := []string{}
+	for _, o := range replicas {
+		names = append(names, o.GetName())
+	}
+	return names
+}
+
+func TestReconcileReplicas(t *testing.T) {
+	cases := map[string]struct {
+		replicas  int32
+		selectors []autov1alpha1.ObjectSelector
+		want      []string
+	}{
+		"Template": {
+			replicas: 2,
+			want:     []string{"rs-0", "rs-1"},
+		},
+		"SelectedObjects": {
+			replicas: 2,
+			selectors: []autov1alpha1.ObjectSelector{{
+				VariableName:  "cm",
+				APIVersion:    ptr.To("v1"),
+				Kind:          ptr.To("ConfigMap"),
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+			}},
+			want: []string{"rs-cm1-0", "rs-cm1-1", "rs-cm2-0", "rs-cm2-1"},
+		},
+		"SelectedObjectByName": {
+			replicas: 1,
+			selectors: []autov1alpha1.ObjectSelector{{
+				VariableName: "cm",
+				APIVersion:   ptr.To("v1"),
+				Kind:         ptr.To("ConfigMap"),
+				Name:         ptr.To("cm2"),
+			}},
+			want: []string{"rs-cm2-0"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := newReplicaSet(t, tc.replicas)
+			cr.Spec.ObjectSelectors = tc.selectors
+			r, c := newTestReconciler(t, cr,
+				newConfigMap("cm1", map[string]string{"pool": "a"}, "srl.nokia.com"),
+				newConfigMap("cm2", map[string]string{"pool": "a"}, "srl.nokia.com"),
+				newConfigMap("cm3", map[string]string{"pool": "b"}, "srl.nokia.com"),
+			)
+			reconcileReplicaSet(t, r)
+
+			replicas := listReplicas(t, c, invv1alpha1.NodeGroupVersionKind)
+			if got := replicaNames(replicas); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Fatalf("want replicas %v, got %v", tc.want, got)
+			}
+			for _, o := range replicas {
+				if o.GetNamespace() != "default" || o.GetLabels()[autov1alpha1.ReplicaSetKey] != "rs" {
+					t.Errorf("want replica %s in the namespace of the replicaset with its label, got %s %v",
+						o.GetName(), o.GetNamespace(), o.GetLabels())
+				}
+				if refs := o.GetOwnerReferences(); len(refs) != 1 || refs[0].UID != "rs-uid" {
+					t.Errorf("want replica %s owned by the replicaset, got %v", o.GetName(), refs)
+				}
+			}
+			if cond := getReplicaSet(t, c).GetCondition(resourcev1alpha1.ConditionTypeReady); cond.Status != metav1.ConditionTrue {
+				t.Errorf("want ready condition, got %v", cond)
+			}
+		})
+	}
+}
+
+func TestReconcileMutators(t *testing.T) {
+	cr := newReplicaSet(t, 2)
+	cr.Spec.ObjectSelectors = []autov1alpha1.ObjectSelector{{
+		VariableName: "cm",
+		APIVersion:   ptr.To("v1"),
+		Kind:         ptr.To("ConfigMap"),
+		Name:         ptr.To("cm1"),
+	}}
+	cr.Spec.Pipeline = &autov1alpha1.Pipeline{
+		Mutators: []autov1alpha1.Function{
+			{Expr: ptr.To(`spec.provider = "${cm.data.provider}"`)},
+			{Expr: ptr.To(`metadata.labels.position = "server-${replica}"`)},
+			// the name of the replica is kept
+			{Expr: ptr.To(`metadata.name = "other"`)},
+		},
+	}
+	r, c := newTestReconciler(t, cr, newConfigMap("cm1", nil, "server.nephio.com"))
+	reconcileReplicaSet(t, r)
+
+	replicas := listReplicas(t, c, invv1alpha1.NodeGroupVersionKind)
+	if got := replicaNames(replicas); fmt.Sprint(got) != fmt.Sprint([]string{"rs-cm1-0", "rs-cm1-1"}) {
+		t.Fatalf("want replicas [rs-cm1-0 rs-cm1-1], got %v", got)
+	}
+	for idx, o := range replicas {
+		if provider, _, _ := unstructured.NestedString(o.Object, "spec", "provider"); provider != "server.nephio.com" {
+			t.Errorf("want provider server.nephio.com, got %s", provider)
+		}
+		labels := o.GetLabels()
+		if want := fmt.Sprintf("server-%d", idx); labels["position"] != want {
+			t.Errorf("want position label %s, got %s", want, labels["position"])
+		}
+		if want := fmt.Sprint(idx); labels[autov1alpha1.ReplicaIndexKey] != want || labels["rack"] != "a" {
+			t.Errorf("want the labels of the template and the replica index %s, got %v", want, labels)
+		}
+	}
+}
+
+func TestReconcileValidators(t *testing.T) {
+	cr := newReplicaSet(t, 1)
+	r, c := newTestReconciler(t, cr)
+	reconcileReplicaSet(t, r)
+
+	// a validation failure is reported in the status and keeps the replicas
+	updateReplicaSet(t, c, func(cr *autov1alpha1.ReplicaSet) {
+		cr.Spec.Replicas = ptr.To[int32](2)
+		cr.Spec.Pipeline = &autov1alpha1.Pipeline{
+			Validators: []autov1alpha1.Function{
+				{Expr: ptr.To(`${self.spec.provider} == "server.nephio.com"`)},
+			},
+		}
+	})
+	reconcileReplicaSet(t, r)
+
+	cond := getReplicaSet(t, c).GetCondition(resourcev1alpha1.ConditionTypeReady)
+	if cond.Status != metav1.ConditionFalse || !strings.Contains(cond.Message, "validation failed: rs-0") {
+		t.Errorf("want validation failure in the condition, got %v", cond)
+	}
+	if got := replicaNames(listReplicas(t, c, invv1alpha1.NodeGroupVersionKind)); fmt.Sprint(got) != fmt.Sprint([]string{"rs-0"}) {
+		t.Errorf("want replicas [rs-0], got %v", got)
+	}
+}
+
+func TestReconcileScaleDown(t *testing.T) {
+	r, c := newTestReconciler(t, newReplicaSet(t, 3))
+	reconcileReplicaSet(t, r)
+
+	updateReplicaSet(t, c, func(cr *autov1alpha1.ReplicaSet) { cr.Spec.Replicas = ptr.To[int32](1) })
+	reconcileReplicaSet(t, r)
+	if got := replicaNames(listReplicas(t, c, invv1alpha1.NodeGroupVersionKind)); fmt.Sprint(got) != fmt.Sprint([]string{"rs-0"}) {
+		t.Errorf("want replicas [rs-0], got %v", got)
+	}
+}
+
+func TestReconcileTemplateKind(t *testing.T) {
+	r, c := newTestReconciler(t, newReplicaSet(t, 2))
+	reconcileReplicaSet(t, r)
+	if ref := getReplicaSet(t, c).Status.UsedTemplateRef; ref == nil || ref.Kind != invv1alpha1.NodeKind {
+		t.Fatalf("want used template kind %s, got %v", invv1alpha1.NodeKind, ref)
+	}
+
+	// the replicas of the previous template kind are garbage collected
+	updateReplicaSet(t, c, func(cr *autov1alpha1.ReplicaSet) { cr.Spec.Template = newTemplate(t, linkGroupVersionKind) })
+	reconcileReplicaSet(t, r)
+	if got := listReplicas(t, c, invv1alpha1.NodeGroupVersionKind); len(got) != 0 {
+		t.Errorf("want no replicas of the previous template kind, got %v", replicaNames(got))
+	}
+	if got := replicaNames(listReplicas(t, c, linkGroupVersionKind)); fmt.Sprint(got) != fmt.Sprint([]string{"rs-0", "rs-1"}) {
+		t.Errorf("want replicas [rs-0 rs-1], got %v", got)
+	}
+	if ref := getReplicaSet(t, c).Status.UsedTemplateRef; ref == nil || ref.Kind != invv1alpha1.LinkKind {
+		t.Errorf("want used template kind %s, got %v", invv1alpha1.LinkKind, ref)
+	}
+}
+
+func TestReconcileKinds(t *testing.T) {
+	cases := map[string]struct {
+		template  schema.GroupVersionKind
+		selectors []autov1alpha1.ObjectSelector
+		want      string
+	}{
+		"Template": {
+			template: corev1.SchemeGroupVersion.WithKind("Secret"),
+			want:     "cannot be replicated",
+		},
+		"Selector": {
+			template: invv1alpha1.NodeGroupVersionKind,
+			selectors: []autov1alpha1.ObjectSelector{{
+				VariableName: "secret",
+				APIVersion:   ptr.To("v1"),
+				Kind:         ptr.To("Secret"),
+			}},
+			want: "cannot select kind",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := newReplicaSet(t, 1)
+			cr.Spec.Template = newTemplate(t, tc.template)
+			cr.Spec.ObjectSelectors = tc.selectors
+			r, c := newTestReconciler(t, cr)
+			reconcileReplicaSet(t, r)
+
+			cond := getReplicaSet(t, c).GetCondition(resourcev1alpha1.ConditionTypeReady)
+			if cond.Status != metav1.ConditionFalse || !strings.Contains(cond.Message, tc.want) {
+				t.Errorf("want failed condition %q, got %v", tc.want, cond)
+			}
+			if r.ctrl.(*fakeController).watches != 0 {
+				t.Errorf("want no watches for a kind that is not allowed")
+			}
+		})
+	}
+}
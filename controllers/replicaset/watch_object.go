@@ -0,0 +1,111 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package replicaset
+
+import (
+	"context"
+
+	autov1alpha1 "github.com/nokia/k8s-ipam/apis/auto/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type adder interface {
+	Add(item interface{})
+}
+
+// objectEventHandler handles the events of the replicas and of the selected
+// objects, whose kinds are only known from the replicasets
+type objectEventHandler struct {
+	client client.Client
+}
+
+// Create enqueues a request for the replicasets of the object
+func (r *objectEventHandler) Create(ctx context.Context, evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	r.add(ctx, evt.Object, q)
+}
+
+// Update enqueues a request for the replicasets of the old and new object
+func (r *objectEventHandler) Update(ctx context.Context, evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	r.add(ctx, evt.ObjectOld, q)
+	r.add(ctx, evt.ObjectNew, q)
+}
+
+// Delete enqueues a request for the replicasets of the object
+func (r *objectEventHandler) Delete(ctx context.Context, evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	r.add(ctx, evt.Object, q)
+}
+
+// Generic enqueues a request for the replicasets of the object
+func (r *objectEventHandler) Generic(ctx context.Context, evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	r.add(ctx, evt.Object, q)
+}
+
+// add enqueues the replicaset that owns the replica and the replicasets whose
+// object selectors select the object
+func (r *objectEventHandler) add(ctx context.Context, obj client.Object, queue adder) {
+	log := log.FromContext(ctx)
+
+	// the replicas are labeled with the name of the replicaset
+	if name, ok := obj.GetLabels()[autov1alpha1.ReplicaSetKey]; ok {
+		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: obj.GetNamespace(),
+			Name:      name}})
+	}
+
+	rsl := &autov1alpha1.ReplicaSetList{}
+	if err := r.client.List(ctx, rsl, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "cannot list replicasets")
+		return
+	}
+	for _, rs := range rsl.Items {
+		if selects(&rs, obj) {
+			log.Info("event requeue replicaset", "name", rs.GetName())
+			queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: rs.GetNamespace(),
+				Name:      rs.GetName()}})
+		}
+	}
+}
+
+// selects returns true if one of the object selectors of the replicaset
+// selects the object
+func selects(cr *autov1alpha1.ReplicaSet, obj client.Object) bool {
+	for _, selector := range cr.Spec.ObjectSelectors {
+		gvk, err := selector.GetGroupVersionKind()
+		if err != nil || gvk != obj.GetObjectKind().GroupVersionKind() {
+			continue
+		}
+		if selector.Name != nil && *selector.Name != obj.GetName() {
+			continue
+		}
+		ls, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+		if err != nil {
+			continue
+		}
+		if ls.Matches(labels.Set(obj.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
@@ -18,7 +18,7 @@ package vlanclaim
 
 import (
 	"context"
-	"strings"
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -31,14 +31,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	"github.com/go-logr/logr"
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
 	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
 	"github.com/nokia/k8s-ipam/controllers"
 	"github.com/nokia/k8s-ipam/controllers/ctrlconfig"
+	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
 	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
 	"github.com/nokia/k8s-ipam/pkg/resource"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -81,6 +83,7 @@ func (r *reconciler) Setup(ctx context.Context, mgr ctrl.Manager, cfg *ctrlconfi
 			For(&vlanv1alpha1.VLANClaim{}).
 			WatchesRawSource(&source.Channel{Source: ge}, &handler.EnqueueRequestForObject{}).
 			//Watches(&source.Channel{Source: ge}, &handler.EnqueueRequestForObject{}).
+			WithOptions(cfg.GetControllerOptions("vlanclaim")).
 			Complete(r)
 }
 
@@ -90,20 +93,24 @@ type reconciler struct {
 	ClientProxy  clientproxy.Proxy[*vlanv1alpha1.VLANIndex, *vlanv1alpha1.VLANClaim]
 	pollInterval time.Duration
 	finalizer    *resource.APIFinalizer
-
-	l logr.Logger
 }
 
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	r.l = log.FromContext(ctx)
-	r.l.Info("reconcile", "req", req)
+	log := log.FromContext(ctx)
+	log.Info("reconcile", "req", req)
+
+	ctx, span := tracing.Start(ctx, "vlanclaim.Reconcile",
+		tracing.NamespaceKey.String(req.Namespace),
+		tracing.NameKey.String(req.Name),
+	)
+	defer span.End()
 
 	cr := &vlanv1alpha1.VLANClaim{}
 	if err := r.Get(ctx, req.NamespacedName, cr); err != nil {
 		// There's no need to requeue if we no longer exist. Otherwise we'll be
 		// requeued implicitly because we return an error.
 		if resource.IgnoreNotFound(err) != nil {
-			r.l.Error(err, "cannot get resource")
+			log.Error(err, "cannot get resource")
 			return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), "cannot get resource")
 		}
 		return reconcile.Result{}, nil
@@ -112,8 +119,9 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if meta.WasDeleted(cr) {
 		if cr.GetCondition(resourcev1alpha1.ConditionTypeReady).Status == metav1.ConditionTrue {
 			if err := r.ClientProxy.DeleteClaim(ctx, cr, nil); err != nil {
-				if !strings.Contains(err.Error(), "not ready") || !strings.Contains(err.Error(), "not found") {
-					r.l.Error(err, "cannot delete resource")
+				// when the index is not ready there is no claim to delete
+				if backend.GetErrorCode(err) != resourcepb.ErrorCode_IndexNotReady {
+					log.Error(err, "cannot delete resource")
 					cr.SetConditions(resourcev1alpha1.ReconcileError(err), resourcev1alpha1.Unknown())
 					return reconcile.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 				}
@@ -121,12 +129,12 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		if err := r.finalizer.RemoveFinalizer(ctx, cr); err != nil {
-			r.l.Error(err, "cannot remove finalizer")
+			log.Error(err, "cannot remove finalizer")
 			cr.SetConditions(resourcev1alpha1.ReconcileError(err), resourcev1alpha1.Unknown())
 			return reconcile.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 		}
 
-		r.l.Info("Successfully deleted resource")
+		log.Info("Successfully deleted resource")
 		return reconcile.Result{Requeue: false}, nil
 	}
 
@@ -134,7 +142,7 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// If this is the first time we encounter this issue we'll be requeued
 		// implicitly when we update our status with the new error condition. If
 		// not, we requeue explicitly, which will trigger backoff.
-		r.l.Error(err, "cannot add finalizer")
+		log.Error(err, "cannot add finalizer")
 		cr.SetConditions(resourcev1alpha1.ReconcileError(err), resourcev1alpha1.Unknown())
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
@@ -149,17 +157,17 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err := r.Get(ctx, idxName, idx); err != nil {
 		// There's no need to requeue if we no longer exist. Otherwise we'll be
 		// requeued implicitly because we return an error.
-		r.l.Info("cannot claim resource, index not found")
-		cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.Failed("index not found"))
-		return ctrl.Result{RequeueAfter: 5 * time.Second}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		log.Info("cannot claim resource, index not found")
+		cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.FailedWithReason(resourcev1alpha1.ConditionReasonIndexNotReady, "index not found"))
+		return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
 
 	// check the network instance existance, to ensure we update the condition in the cr
 	// when a network instance get deleted
 	if meta.WasDeleted(idx) {
-		r.l.Info("cannot claim prefix, index not ready")
-		cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.Failed("index not ready"))
-		return ctrl.Result{RequeueAfter: 5 * time.Second}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		log.Info("cannot claim prefix, index not ready")
+		cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.FailedWithReason(resourcev1alpha1.ConditionReasonIndexNotReady, "index not ready"))
+		return ctrl.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
 
 	// The spec got changed we check the existing claim against the status
@@ -171,8 +179,8 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// we set the prefix to "", to ensure the delete claim works
 		cr.Spec.VLANID = nil
 		if err := r.ClientProxy.DeleteClaim(ctx, cr, nil); err != nil {
-			if !strings.Contains(err.Error(), "not ready") || !strings.Contains(err.Error(), "not found") {
-				r.l.Error(err, "cannot delete resource")
+			if backend.GetErrorCode(err) != resourcepb.ErrorCode_IndexNotReady {
+				log.Error(err, "cannot delete resource")
 				cr.SetConditions(resourcev1alpha1.ReconcileError(err), resourcev1alpha1.Unknown())
 				return reconcile.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 			}
@@ -184,25 +192,33 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	claimResp, err := r.ClientProxy.Claim(ctx, cr, nil)
 	if err != nil {
-		r.l.Info("cannot claim resource", "err", err)
+		log.Info("cannot claim resource", "err", err)
+		tracing.SetError(span, err)
 
-		// TODO -> Depending on the error we should clear the prefix
-		// e.g. when the ni instance is not yet available we should not clear the error
-		cr.Status.VLANID = nil
-		cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.Failed(err.Error()))
-		return reconcile.Result{RequeueAfter: 5 * time.Second}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		// when the vlan index is not yet available we keep the vlan id
+		// such that the same vlan id is reclaimed when it becomes available
+		if backend.GetErrorCode(err) != resourcepb.ErrorCode_IndexNotReady {
+			cr.Status.VLANID = nil
+		}
+		cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.FailedWithReason(backend.GetConditionReason(err), err.Error()))
+		// only retriable errors are requeued, other errors require a change of the claim
+		if backend.IsRetriable(err) {
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		}
+		return reconcile.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 	}
 	// if the prefix is claimed in the spec, we need to ensure we get the same claim
 	if cr.Spec.VLANID != nil {
-		if claimResp.Status.VLANID != nil && claimResp.Status.VLANID != cr.Spec.VLANID {
-			// we got a different prefix than requested
-			r.l.Error(err, "resource claim failed", "requested", cr.Spec.VLANID, "claim Resp", claimResp.Status)
-			cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.Unknown())
-			return ctrl.Result{RequeueAfter: 5 * time.Second}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
+		if claimResp.Status.VLANID != nil && *claimResp.Status.VLANID != *cr.Spec.VLANID {
+			// we got a different vlan id than requested
+			log.Info("resource claim failed", "requested", cr.Spec.VLANID, "claim Resp", claimResp.Status)
+			cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.FailedWithReason(resourcev1alpha1.ConditionReasonConflict,
+				fmt.Sprintf("requested vlan id %d, got: %d", *cr.Spec.VLANID, *claimResp.Status.VLANID)))
+			return ctrl.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 		}
 	}
 	cr.Status.VLANID = claimResp.Status.VLANID
-	r.l.Info("Successfully reconciled resource", "claim", claimResp.Status)
+	log.Info("Successfully reconciled resource", "claim", claimResp.Status)
 	cr.SetConditions(resourcev1alpha1.ReconcileSuccess(), resourcev1alpha1.Ready())
 	return ctrl.Result{}, errors.Wrap(r.Status().Update(ctx, cr), errUpdateStatus)
 }
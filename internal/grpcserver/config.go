@@ -55,28 +55,20 @@ type Config struct {
 
 func (c *Config) setDefaults() {
 	if c.Address == "" {
-		c.Address = ":" + defaultAddress
+		c.Address = defaultAddress
 	}
 	if c.MaxRPC <= 0 {
 		c.MaxRPC = defaultMaxRPC
 	}
-
-	/*
-		if len(c.CertDir) == 0 {
-			c.CertDir = filepath.Join(os.TempDir(), "k8s-grpc-server", "serving-certs")
-		}
-
-		if len(c.CertName) == 0 {
-			c.CertName = "tls.crt"
-		}
-
-		if len(c.KeyName) == 0 {
-			c.KeyName = "tls.key"
-		}
-		if len(c.CaName) == 0 {
-			c.CaName = "ca.crt"
-		}
-	*/
+	if len(c.CertName) == 0 {
+		c.CertName = "tls.crt"
+	}
+	if len(c.KeyName) == 0 {
+		c.KeyName = "tls.key"
+	}
+	if len(c.CaName) == 0 {
+		c.CaName = "ca.crt"
+	}
 	if c.Timeout <= 0 {
 		c.Timeout = defaultTimeout
 	}
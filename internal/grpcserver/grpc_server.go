@@ -22,7 +22,10 @@ import (
 	"sync"
 
 	"github.com/go-logr/logr"
+	"github.com/nokia/k8s-ipam/pkg/election"
+	"github.com/nokia/k8s-ipam/pkg/grpcauth"
 	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc"
@@ -46,10 +49,19 @@ type GrpcServer struct {
 	claimHandler       ClaimHandler
 	deleteClaimHandler DeleteClaimHandler
 	watchClaimHandler  WatchClaimHandler
+	exportHandler      ExportHandler
+	importHandler      ImportHandler
+	getTreeHandler     GetTreeHandler
 
 	//health handlers
 	checkHandler CheckHandler
 	watchHandler WatchHandler
+
+	// authentication and authorization of the callers
+	authenticator grpcauth.Authenticator
+	authorizer    grpcauth.Authorizer
+	// only the leader serves the resource api when set
+	elector election.Elector
 	//
 	// cached certificate
 	cm *sync.Mutex
@@ -73,6 +85,12 @@ type DeleteClaimHandler func(context.Context, *resourcepb.ClaimRequest) (*resour
 
 type WatchClaimHandler func(*resourcepb.WatchRequest, resourcepb.Resource_WatchClaimServer) error
 
+type ExportHandler func(context.Context, *resourcepb.ExportRequest) (*resourcepb.ExportResponse, error)
+
+type ImportHandler func(context.Context, *resourcepb.ImportRequest) (*resourcepb.ImportResponse, error)
+
+type GetTreeHandler func(context.Context, *resourcepb.TreeRequest) (*resourcepb.TreeResponse, error)
+
 type Option func(*GrpcServer)
 
 func New(c Config, opts ...Option) *GrpcServer {
@@ -117,6 +135,13 @@ func (s *GrpcServer) Start(ctx context.Context) error {
 	healthpb.RegisterHealthServer(grpcServer, s)
 	s.l.Info("grpc server with health...")
 
+	// the server stops with the context, such that the clients fail over to
+	// another instance
+	go func() {
+		<-ctx.Done()
+		grpcServer.Stop()
+	}()
+
 	s.l.Info("starting grpc server...")
 	err = grpcServer.Serve(l)
 	if err != nil {
@@ -174,7 +199,51 @@ func WithWatchClaimHandler(h WatchClaimHandler) func(*GrpcServer) {
 	}
 }
 
-func (s *GrpcServer) acquireSem(ctx context.Context) error {
+func WithExportHandler(h ExportHandler) func(*GrpcServer) {
+	return func(s *GrpcServer) {
+		s.exportHandler = h
+	}
+}
+
+func WithImportHandler(h ImportHandler) func(*GrpcServer) {
+	return func(s *GrpcServer) {
+		s.importHandler = h
+	}
+}
+
+func WithGetTreeHandler(h GetTreeHandler) func(*GrpcServer) {
+	return func(s *GrpcServer) {
+		s.getTreeHandler = h
+	}
+}
+
+// WithAuthenticator authenticates the callers of every request, except for
+// the health service
+func WithAuthenticator(a grpcauth.Authenticator) func(*GrpcServer) {
+	return func(s *GrpcServer) {
+		s.authenticator = a
+	}
+}
+
+// WithAuthorizer authorizes every request of an authenticated caller
+func WithAuthorizer(a grpcauth.Authorizer) func(*GrpcServer) {
+	return func(s *GrpcServer) {
+		s.authorizer = a
+	}
+}
+
+// WithLeaderElector serves the resource api only while the instance is the
+// leader, the followers reject the requests with an unavailable status
+func WithLeaderElector(e election.Elector) func(*GrpcServer) {
+	return func(s *GrpcServer) {
+		s.elector = e
+	}
+}
+
+func (s *GrpcServer) acquireSem(ctx context.Context) (err error) {
+	// the span shows the time a request waits for the concurrency limit
+	_, span := tracing.Start(ctx, "grpcserver.acquireSemaphore")
+	defer func() { tracing.End(span, err) }()
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -24,6 +24,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/nokia/k8s-ipam/pkg/election"
+	"github.com/nokia/k8s-ipam/pkg/grpcauth"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -31,20 +34,33 @@ import (
 )
 
 func (s *GrpcServer) serverOpts(ctx context.Context) ([]grpc.ServerOption, error) {
+	// the tracing interceptors run first such that rejected calls are traced
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(tracing.StreamServerInterceptor()),
+	}
+	// followers reject the requests before they are authenticated
+	if s.elector != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(election.UnaryServerInterceptor(s.elector)),
+			grpc.ChainStreamInterceptor(election.StreamServerInterceptor(s.elector)),
+		)
+	}
+	if s.authenticator != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(grpcauth.UnaryServerInterceptor(s.authenticator, s.authorizer)),
+			grpc.ChainStreamInterceptor(grpcauth.StreamServerInterceptor(s.authenticator, s.authorizer)),
+		)
+	}
 	if s.config.Insecure {
-		return []grpc.ServerOption{
-			grpc.Creds(insecure.NewCredentials()),
-		}, nil
+		return append(opts, grpc.Creds(insecure.NewCredentials())), nil
 	}
 
 	tlsConfig, err := s.createTLSConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return []grpc.ServerOption{
-		grpc.Creds(credentials.NewTLS(tlsConfig)),
-	}, nil
-
+	return append(opts, grpc.Creds(credentials.NewTLS(tlsConfig))), nil
 }
 
 func (s *GrpcServer) createTLSConfig(ctx context.Context) (*tls.Config, error) {
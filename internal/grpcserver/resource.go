@@ -99,6 +99,48 @@ func (s *GrpcServer) DeleteClaim(ctx context.Context, req *resourcepb.ClaimReque
 	return resp, nil
 }
 
+func (s *GrpcServer) Export(ctx context.Context, req *resourcepb.ExportRequest) (*resourcepb.ExportResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+	err := s.acquireSem(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.sem.Release(1)
+	if s.exportHandler == nil {
+		return nil, status.Error(codes.Unimplemented, "")
+	}
+	return s.exportHandler(ctx, req)
+}
+
+func (s *GrpcServer) Import(ctx context.Context, req *resourcepb.ImportRequest) (*resourcepb.ImportResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+	err := s.acquireSem(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.sem.Release(1)
+	if s.importHandler == nil {
+		return nil, status.Error(codes.Unimplemented, "")
+	}
+	return s.importHandler(ctx, req)
+}
+
+func (s *GrpcServer) GetTree(ctx context.Context, req *resourcepb.TreeRequest) (*resourcepb.TreeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+	err := s.acquireSem(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.sem.Release(1)
+	if s.getTreeHandler == nil {
+		return nil, status.Error(codes.Unimplemented, "")
+	}
+	return s.getTreeHandler(ctx, req)
+}
+
 func (s *GrpcServer) WatchClaim(in *resourcepb.WatchRequest, stream resourcepb.Resource_WatchClaimServer) error {
 	err := s.acquireSem(stream.Context())
 	if err != nil {
@@ -17,22 +17,60 @@ package healthhandler
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+const (
+	defaultInterval = 5 * time.Second
 )
 
 type SubServer interface {
 	Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error)
 	Watch(in *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error
+	// Start runs the checks periodically and updates the serving status of
+	// the services till the context is cancelled
+	Start(ctx context.Context)
+	// Checker returns a healthz checker which mirrors the serving status of
+	// the service
+	Checker(service string) healthz.Checker
+}
+
+// CheckFn returns an error if the service cannot serve requests
+type CheckFn func() error
+
+type Config struct {
+	// Checks holds the check per service, the overall service "" is serving
+	// when all services are serving
+	Checks map[string]CheckFn
+	// Interval is the interval at which the checks are run, defaults to 5s
+	Interval time.Duration
 }
 
-func New() SubServer {
+func New(cfg Config) SubServer {
 	s := &subServer{
 		mu:        sync.RWMutex{},
-		statusMap: map[string]healthpb.HealthCheckResponse_ServingStatus{"": healthpb.HealthCheckResponse_SERVING},
+		checks:    cfg.Checks,
+		interval:  cfg.Interval,
+		statusMap: map[string]healthpb.HealthCheckResponse_ServingStatus{},
+		errs:      map[string]error{},
 		updates:   make(map[string]map[healthpb.Health_WatchServer]chan healthpb.HealthCheckResponse_ServingStatus),
+		l:         ctrl.Log.WithName("healthhandler"),
+	}
+	if s.interval <= 0 {
+		s.interval = defaultInterval
+	}
+	// the services are not serving till they are checked
+	s.statusMap[""] = healthpb.HealthCheckResponse_NOT_SERVING
+	for service := range s.checks {
+		s.statusMap[service] = healthpb.HealthCheckResponse_NOT_SERVING
 	}
 	return s
 }
@@ -40,6 +78,74 @@ func New() SubServer {
 type subServer struct {
 	l         logr.Logger
 	mu        sync.RWMutex
+	checks    map[string]CheckFn
+	interval  time.Duration
 	statusMap map[string]healthpb.HealthCheckResponse_ServingStatus
-	updates   map[string]map[healthpb.Health_WatchServer]chan healthpb.HealthCheckResponse_ServingStatus
+	// errs holds the error of the last check per service
+	errs    map[string]error
+	updates map[string]map[healthpb.Health_WatchServer]chan healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (s *subServer) Start(ctx context.Context) {
+	for {
+		s.runChecks()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+func (s *subServer) runChecks() {
+	overall := healthpb.HealthCheckResponse_SERVING
+	for service, check := range s.checks {
+		servingStatus := healthpb.HealthCheckResponse_SERVING
+		err := check()
+		if err != nil {
+			servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+			overall = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		s.setServingStatus(service, servingStatus, err)
+	}
+	s.setServingStatus("", overall, nil)
+}
+
+// setServingStatus updates the serving status of the service and informs the
+// watchers of the service when the status changed
+func (s *subServer) setServingStatus(service string, servingStatus healthpb.HealthCheckResponse_ServingStatus, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs[service] = err
+	if s.statusMap[service] == servingStatus {
+		return
+	}
+	s.l.Info("serving status changed", "service", service, "status", servingStatus.String(), "err", err)
+	s.statusMap[service] = servingStatus
+	for _, update := range s.updates[service] {
+		// replace the status that is not yet sent to the watcher
+		select {
+		case <-update:
+		default:
+		}
+		update <- servingStatus
+	}
+}
+
+func (s *subServer) Checker(service string) healthz.Checker {
+	return func(_ *http.Request) error {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		servingStatus, ok := s.statusMap[service]
+		if !ok {
+			return fmt.Errorf("unknown service %q", service)
+		}
+		if servingStatus != healthpb.HealthCheckResponse_SERVING {
+			if err := s.errs[service]; err != nil {
+				return fmt.Errorf("service %q is %s: %s", service, servingStatus.String(), err.Error())
+			}
+			return fmt.Errorf("service %q is %s", service, servingStatus.String())
+		}
+		return nil
+	}
 }
@@ -17,11 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
-	"strconv"
-	"strings"
+	"sort"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -35,29 +36,36 @@ import (
 	"github.com/henderiw-nephio/network-node-operator/pkg/node"
 	"github.com/henderiw-nephio/network-node-operator/pkg/node/srlinux"
 	"github.com/henderiw-nephio/network-node-operator/pkg/node/xserver"
+	_ "github.com/nokia/k8s-ipam/controllers/claimquota"
+	_ "github.com/nokia/k8s-ipam/controllers/interconnect-controller"
 	_ "github.com/nokia/k8s-ipam/controllers/ipclaim"
 	_ "github.com/nokia/k8s-ipam/controllers/ipnetworkinstance"
 	_ "github.com/nokia/k8s-ipam/controllers/ipprefix"
 	_ "github.com/nokia/k8s-ipam/controllers/link-controller"
 	_ "github.com/nokia/k8s-ipam/controllers/logicalinterconnect-controller"
 	//_ "github.com/nokia/k8s-ipam/controllers/node"
+	_ "github.com/nokia/k8s-ipam/controllers/nodepool"
 	_ "github.com/nokia/k8s-ipam/controllers/rawtopology"
+	_ "github.com/nokia/k8s-ipam/controllers/replicaset"
 	_ "github.com/nokia/k8s-ipam/controllers/vlanclaim"
 	_ "github.com/nokia/k8s-ipam/controllers/vlanindex"
 	_ "github.com/nokia/k8s-ipam/controllers/vlanvlan"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/nephio-project/nephio-controller-poc/pkg/porch"
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
 	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
 	"github.com/nokia/k8s-ipam/controllers"
 	"github.com/nokia/k8s-ipam/controllers/ctrlconfig"
 	"github.com/nokia/k8s-ipam/internal/grpcserver"
@@ -65,10 +73,20 @@ import (
 	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/backend/ipam"
 	"github.com/nokia/k8s-ipam/pkg/backend/vlan"
+	"github.com/nokia/k8s-ipam/pkg/backend/vxlan"
+	"github.com/nokia/k8s-ipam/pkg/config"
+	"github.com/nokia/k8s-ipam/pkg/election"
+	"github.com/nokia/k8s-ipam/pkg/grpcauth"
 	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
 	ipamcp "github.com/nokia/k8s-ipam/pkg/proxy/clientproxy/ipam"
 	vlancp "github.com/nokia/k8s-ipam/pkg/proxy/clientproxy/vlan"
+	vxlancp "github.com/nokia/k8s-ipam/pkg/proxy/clientproxy/vxlan"
 	"github.com/nokia/k8s-ipam/pkg/proxy/serverproxy"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
+	invwebhook "github.com/nokia/k8s-ipam/pkg/webhook/inv"
+	ipamwebhook "github.com/nokia/k8s-ipam/pkg/webhook/ipam"
+	topowebhook "github.com/nokia/k8s-ipam/pkg/webhook/topo"
+	vlanwebhook "github.com/nokia/k8s-ipam/pkg/webhook/vlan"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -80,11 +98,40 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var leaseDuration time.Duration
+	var leaseRenewInterval time.Duration
+	var backendDialTimeout time.Duration
+	var backendMaxMsgSize int
+	var backendReconnectMaxDelay time.Duration
+	var backendTokenFile string
+	var backendCAFile string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "lease-duration", 10*time.Second,
+		"The duration of the endpoint leases, a lease that is not renewed in time can be taken over.")
+	flag.DurationVar(&leaseRenewInterval, "lease-renew-interval", 2*time.Second,
+		"The interval at which a held endpoint lease is renewed, 0 disables the renewal.")
+	flag.DurationVar(&backendDialTimeout, "backend-dial-timeout", 30*time.Second,
+		"The timeout of a single connection attempt to the resource backend.")
+	flag.IntVar(&backendMaxMsgSize, "backend-max-msg-size", 512*1024*1024,
+		"The max size in bytes of a message sent to or received from the resource backend.")
+	flag.DurationVar(&backendReconnectMaxDelay, "backend-reconnect-max-delay", 2*time.Minute,
+		"The max delay between the attempts to reconnect to the resource backend.")
+	flag.StringVar(&backendTokenFile, "backend-token-file", "",
+		"The file with the bearer token sent to the resource backend, e.g. the service account token, requires tls.")
+	flag.StringVar(&backendCAFile, "backend-ca-file", "",
+		"The ca that verifies the certificate of the resource backend, defaults to the ca of the grpc cert dir.")
+	var cfgOpts config.Options
+	cfgOpts.BindFlags(flag.CommandLine)
+	var grpcAuthCfg grpcauth.Config
+	grpcAuthCfg.BindFlags(flag.CommandLine)
+	var tracingCfg tracing.Config
+	tracingCfg.BindFlags(flag.CommandLine)
+	var reconcilerOpts ctrlconfig.ReconcilerOptions
+	reconcilerOpts.BindFlags(flag.CommandLine)
 	opts := zap.Options{
 		Development: true,
 		TimeEncoder: zapcore.ISO8601TimeEncoder,
@@ -94,11 +141,39 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	reconcilerNames := make([]string, 0, len(controllers.Reconcilers))
+	for name := range controllers.Reconcilers {
+		reconcilerNames = append(reconcilerNames, name)
+	}
+	sort.Strings(reconcilerNames)
+	cfg, err := cfgOpts.Complete(flag.CommandLine, reconcilerNames)
+	if err != nil {
+		setupLog.Error(err, "cannot load configuration")
+		os.Exit(1)
+	}
+	setupLog.Info("effective configuration", "config", cfg.String())
+	for _, c := range cfg.Controllers {
+		if c.MaxConcurrentReconciles > 0 {
+			reconcilerOpts.SetMaxConcurrentReconciles(c.Name, c.MaxConcurrentReconciles)
+		}
+	}
+
+	tp, err := tracing.Setup(tracingCfg)
+	if err != nil {
+		setupLog.Error(err, "cannot set up tracing")
+		os.Exit(1)
+	}
+
 	scheme := runtime.NewScheme()
 	if err := clientgoscheme.AddToScheme(scheme); err != nil {
 		setupLog.Error(err, "cannot initializer schema")
 		os.Exit(1)
 	}
+	// the backends read the claim quotas independent of the claimquota controller
+	if err := quotav1alpha1.AddToScheme(scheme); err != nil {
+		setupLog.Error(err, "cannot initializer schema")
+		os.Exit(1)
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                     scheme,
@@ -114,69 +189,145 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("setup controller")
 	ctx := ctrl.SetupSignalHandler()
 
-	porchClient, err := porch.CreateClient()
-	if err != nil {
-		setupLog.Error(err, "unable to create porch client")
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
 
-	ctrlCfg := &ctrlconfig.ControllerConfig{
-		Address: os.Getenv("RESOURCE_BACKEND"),
-		IpamClientProxy: ipamcp.New(ctx, clientproxy.Config{
-			Address: os.Getenv("RESOURCE_BACKEND"),
-		}),
-		VlanClientProxy: vlancp.New(ctx, clientproxy.Config{
-			Address: os.Getenv("RESOURCE_BACKEND"),
-		}),
-		Noderegistry: registerSupportedNodeProviders(),
-		PorchClient:  porchClient,
-		Poll:         5 * time.Second,
-		Copts: controller.Options{
-			MaxConcurrentReconciles: 1,
-		},
+	var backends map[schema.GroupVersion]backend.Backend
+	if cfg.RunsBackend() {
+		setupLog.Info("setup backend")
+		backends, err = setupBackend(ctx, mgr, cfg, grpcAuthCfg)
+		if err != nil {
+			setupLog.Error(err, "cannot set up backend")
+			os.Exit(1)
+		}
 	}
 
-	gevents := map[schema.GroupVersionKind]chan event.GenericEvent{}
-	for name, reconciler := range controllers.Reconcilers {
-		setupLog.Info("reconciler", "name", name, "enabled", IsReconcilerEnabled(name))
-		if IsReconcilerEnabled(name) {
-			e, err := reconciler.Setup(ctx, mgr, ctrlCfg)
-			if err != nil {
-				setupLog.Error(err, "cannot add controllers to manager")
-				os.Exit(1)
-			}
-			for gvk, ech := range e {
-				gevents[gvk] = ech
-			}
+	if cfg.RunsControllers() {
+		setupLog.Info("setup controller")
+		backendTLS, backendCA := cfg.BackendTLS()
+		if backendCAFile != "" {
+			backendCA = backendCAFile
+		}
+		if backendTokenFile != "" && !backendTLS {
+			setupLog.Error(fmt.Errorf("the backend token is not sent without tls"), "cannot set up controller")
+			os.Exit(1)
+		}
+		ctrlCfg := &ctrlconfig.ControllerConfig{
+			Address:            cfg.Backends.Address,
+			Noderegistry:       registerSupportedNodeProviders(),
+			Poll:               5 * time.Second,
+			LeaseDuration:      leaseDuration,
+			LeaseRenewInterval: leaseRenewInterval,
+			Reconcilers:        reconcilerOpts,
+			// the backends are only available in process in the all mode
+			Ipam: backends[ipamv1alpha1.GroupVersion],
+			Vlan: backends[vlanv1alpha1.GroupVersion],
+		}
+		cpCfg := clientproxy.Config{
+			Address:           cfg.Backends.Address,
+			DialTimeout:       backendDialTimeout,
+			MaxMsgSize:        backendMaxMsgSize,
+			ReconnectMaxDelay: backendReconnectMaxDelay,
+			TLS:               backendTLS,
+			TLSCA:             backendCA,
+			TokenFile:         backendTokenFile,
+			Client:            mgr.GetAPIReader(),
+		}
+		if err := setupControllers(ctx, mgr, cfg, ctrlCfg, cpCfg); err != nil {
+			setupLog.Error(err, "cannot set up controllers")
+			os.Exit(1)
 		}
 	}
-	ctrlCfg.IpamClientProxy.AddEventChs(gevents)
 
-	ipambe, err := ipam.New(mgr.GetClient())
+	//+kubebuilder:scaffold:builder
+
+	setupLog.Info("starting manager")
+	err = mgr.Start(ctx)
+	// flush the spans that are not exported yet
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tp.Shutdown(shutdownCtx); err != nil {
+		setupLog.Error(err, "cannot shut down tracing")
+	}
 	if err != nil {
-		setupLog.Error(err, "cannot instantiate ipam backend")
+		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
-	vlanbe, err := vlan.New(mgr.GetClient())
+}
+
+// setupBackend starts the grpc server with the enabled backends, the backends
+// store their indices with the client of the manager
+func setupBackend(ctx context.Context, mgr ctrl.Manager, cfg *config.Config, grpcAuthCfg grpcauth.Config) (map[schema.GroupVersion]backend.Backend, error) {
+	backends := map[schema.GroupVersion]backend.Backend{}
+	for _, b := range []struct {
+		name string
+		gv   schema.GroupVersion
+		new  func(client.Client, backend.StorageConfig) (backend.Backend, error)
+	}{
+		{name: config.BackendIPAM, gv: ipamv1alpha1.GroupVersion, new: ipam.New},
+		{name: config.BackendVLAN, gv: vlanv1alpha1.GroupVersion, new: vlan.New},
+		{name: config.BackendVXLAN, gv: vxlanv1alpha1.GroupVersion, new: vxlan.New},
+	} {
+		if !cfg.IsBackendEnabled(b.name) {
+			continue
+		}
+		be, err := b.new(mgr.GetClient(), cfg.Backends.Storage)
+		if err != nil {
+			return nil, fmt.Errorf("cannot instantiate %s backend: %w", b.name, err)
+		}
+		backends[b.gv] = be
+	}
+
+	elector, err := setupLeaderElection(mgr, cfg.Backends.LeaderElection, backends)
 	if err != nil {
-		setupLog.Error(err, "cannot instantiate vlan backend")
-		os.Exit(1)
+		return nil, err
 	}
 
 	serverProxy := serverproxy.New(&serverproxy.Config{
-		Backends: map[schema.GroupVersion]backend.Backend{
-			ipamv1alpha1.GroupVersion: ipambe,
-			vlanv1alpha1.GroupVersion: vlanbe,
-		},
+		Backends: backends,
 	})
-	wh := healthhandler.New()
+	// the grpc health reports a service per backend groupVersion, which is
+	// serving when the backend restored its indices
+	checks := map[string]healthhandler.CheckFn{}
+	for gv, be := range backends {
+		checks[gv.String()] = be.Ready
+		if elector != nil {
+			// the followers are not serving, such that the clients only
+			// pick the leader
+			be := be
+			checks[gv.String()] = func() error {
+				if err := elector.Ready(); err != nil {
+					return err
+				}
+				return be.Ready()
+			}
+		}
+	}
+	wh := healthhandler.New(healthhandler.Config{Checks: checks})
+	go wh.Start(ctx)
+
+	authn, authz, err := grpcAuthCfg.Build(mgr.GetClient())
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up grpc authentication: %w", err)
+	}
 
 	s := grpcserver.New(grpcserver.Config{
-		Address:  ":" + strconv.Itoa(9999),
-		Insecure: true,
+		Address:  cfg.GRPC.Address,
+		Insecure: cfg.GRPC.Insecure,
+		MaxRPC:   cfg.GRPC.MaxRPC,
+		Timeout:  cfg.GRPC.Timeout.Duration,
+		CertDir:  cfg.GRPC.CertDir,
+		CertName: cfg.GRPC.CertName,
+		KeyName:  cfg.GRPC.KeyName,
+		CaName:   cfg.GRPC.CaName,
 	},
 		grpcserver.WithCreateIndexHandler(serverProxy.CreateIndex),
 		grpcserver.WithDeleteIndexHandler(serverProxy.DeleteIndex),
@@ -184,8 +335,14 @@ func main() {
 		grpcserver.WithClaimHandler(serverProxy.Claim),
 		grpcserver.WithDeleteClaimHandler(serverProxy.DeleteClaim),
 		grpcserver.WithWatchClaimHandler(serverProxy.Watch),
+		grpcserver.WithExportHandler(serverProxy.Export),
+		grpcserver.WithImportHandler(serverProxy.Import),
+		grpcserver.WithGetTreeHandler(serverProxy.GetTree),
 		grpcserver.WithWatchHandler(wh.Watch),
 		grpcserver.WithCheckHandler(wh.Check),
+		grpcserver.WithAuthenticator(authn),
+		grpcserver.WithAuthorizer(authz),
+		grpcserver.WithLeaderElector(elector),
 	)
 
 	go func() {
@@ -195,29 +352,109 @@ func main() {
 		}
 	}()
 
-	//+kubebuilder:scaffold:builder
+	for gv, be := range backends {
+		checker := wh.Checker(gv.String())
+		if elector != nil {
+			// the followers are ready to take over the leadership
+			be := be
+			checker = func(_ *http.Request) error { return be.Ready() }
+		}
+		if err := mgr.AddReadyzCheck("backend-"+gv.Group, checker); err != nil {
+			return nil, fmt.Errorf("unable to set up ready check for %s: %w", gv, err)
+		}
+	}
+	return backends, nil
+}
 
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up health check")
-		os.Exit(1)
+// setupLeaderElection returns the elector of the backend instance, nil when
+// the leader election is disabled. A new leader restores the indices of the
+// backends before serving.
+func setupLeaderElection(mgr ctrl.Manager, cfg config.LeaderElectionConfig, backends map[schema.GroupVersion]backend.Backend) (election.Elector, error) {
+	if !cfg.Enabled {
+		return nil, nil
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
-		os.Exit(1)
+	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("cannot create clientset: %w", err)
 	}
-
-	setupLog.Info("starting manager")
-	if err := mgr.Start(ctx); err != nil {
-		setupLog.Error(err, "problem running manager")
-		os.Exit(1)
+	e, err := election.New(cs, election.Config{
+		Namespace:     cfg.Namespace,
+		LeaseName:     cfg.LeaseName,
+		Identity:      cfg.Identity,
+		LeaseDuration: cfg.LeaseDuration.Duration,
+		RenewDeadline: cfg.RenewDeadline.Duration,
+		RetryPeriod:   cfg.RetryPeriod.Duration,
+	}, func(ctx context.Context) error {
+		for gv, be := range backends {
+			if err := be.Restore(ctx); err != nil {
+				return fmt.Errorf("cannot restore %s backend: %w", gv, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up leader election: %w", err)
+	}
+	if err := mgr.Add(e); err != nil {
+		return nil, fmt.Errorf("cannot add leader election: %w", err)
 	}
+	return e, nil
 }
 
-func IsReconcilerEnabled(reconcilerName string) bool {
-	if _, found := os.LookupEnv(fmt.Sprintf("ENABLE_%s", strings.ToUpper(reconcilerName))); found {
-		return true
+// setupControllers adds the enabled controllers and the webhooks to the
+// manager, the controllers claim the resources from the backend through the
+// client proxies
+func setupControllers(ctx context.Context, mgr ctrl.Manager, cfg *config.Config, ctrlCfg *ctrlconfig.ControllerConfig, cpCfg clientproxy.Config) error {
+	porchClient, err := porch.CreateClient()
+	if err != nil {
+		return fmt.Errorf("unable to create porch client: %w", err)
+	}
+	ctrlCfg.PorchClient = porchClient
+	ctrlCfg.IpamClientProxy = ipamcp.New(ctx, cpCfg)
+	ctrlCfg.VlanClientProxy = vlancp.New(ctx, cpCfg)
+	ctrlCfg.VxlanClientProxy = vxlancp.New(ctx, cpCfg)
+
+	gevents := map[schema.GroupVersionKind]chan event.GenericEvent{}
+	for name, reconciler := range controllers.Reconcilers {
+		setupLog.Info("reconciler", "name", name, "enabled", cfg.IsControllerEnabled(name))
+		if cfg.IsControllerEnabled(name) {
+			e, err := reconciler.Setup(ctx, mgr, ctrlCfg)
+			if err != nil {
+				return fmt.Errorf("cannot add controller %s to manager: %w", name, err)
+			}
+			for gvk, ech := range e {
+				gevents[gvk] = ech
+			}
+		}
+	}
+	ctrlCfg.IpamClientProxy.AddEventChs(gevents)
+
+	if _, found := os.LookupEnv("ENABLE_WEBHOOKS"); found {
+		if err := ipamwebhook.SetupWebhookWithManager(mgr); err != nil {
+			return fmt.Errorf("cannot add ipam webhooks to manager: %w", err)
+		}
+		if err := vlanwebhook.SetupWebhookWithManager(mgr); err != nil {
+			return fmt.Errorf("cannot add vlan webhooks to manager: %w", err)
+		}
+		if err := invwebhook.SetupWebhookWithManager(mgr); err != nil {
+			return fmt.Errorf("cannot add inventory webhooks to manager: %w", err)
+		}
+		if err := topowebhook.SetupWebhookWithManager(mgr); err != nil {
+			return fmt.Errorf("cannot add topology webhooks to manager: %w", err)
+		}
+	}
+
+	// the controllers are not ready when the resource backend is unreachable
+	for name, checker := range map[string]healthz.Checker{
+		"ipam-backend":  ctrlCfg.IpamClientProxy.Healthz,
+		"vlan-backend":  ctrlCfg.VlanClientProxy.Healthz,
+		"vxlan-backend": ctrlCfg.VxlanClientProxy.Healthz,
+	} {
+		if err := mgr.AddReadyzCheck(name, checker); err != nil {
+			return fmt.Errorf("unable to set up ready check %s: %w", name, err)
+		}
 	}
-	return false
+	return nil
 }
 
 func registerSupportedNodeProviders() node.NodeRegistry {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -36,12 +37,15 @@ type GetHandler[T1 client.Object] func(context.Context, T1) (T1, error)
 type ValidateHandler[T1 client.Object] func(context.Context, T1) (string, error)
 type ApplyHandler[T1 client.Object] func(context.Context, T1) (T1, error)
 type DeleteHandler[T1 client.Object] func(context.Context, T1) error
+type QuotaHandler[T1 client.Object] func(context.Context, T1) error
 
 type ApplogicConfig[T1 client.Object] struct {
 	GetHandler      GetHandler[T1]
 	ValidateHandler ValidateHandler[T1]
 	ApplyHandler    ApplyHandler[T1]
 	DeleteHandler   DeleteHandler[T1]
+	// QuotaHandler is optional and checks the quotas before the claim is applied
+	QuotaHandler QuotaHandler[T1]
 }
 
 func NewApplogic[T1 client.Object](cfg *ApplogicConfig[T1]) (AppLogic[T1], error) {
@@ -85,7 +89,17 @@ func (r *applogic[T1]) Apply(ctx context.Context, a T1) (T1, error) {
 	r.l = log.FromContext(ctx).WithValues("name", a.GetName())
 	r.l.Info("apply")
 
-	return r.cfg.ApplyHandler(ctx, a)
+	ctx, span := tracing.Start(ctx, "applogic.Apply", tracing.NameKey.String(a.GetName()))
+	defer span.End()
+	if r.cfg.QuotaHandler != nil {
+		if err := r.cfg.QuotaHandler(ctx, a); err != nil {
+			tracing.SetError(span, err)
+			return a, err
+		}
+	}
+	a, err := r.cfg.ApplyHandler(ctx, a)
+	tracing.SetError(span, err)
+	return a, err
 }
 
 func (r *applogic[T1]) Delete(ctx context.Context, a T1) error {
@@ -18,11 +18,16 @@ package backend
 
 import (
 	"context"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 type Backend interface {
 	// CreateIndex creates a backend index
 	CreateIndex(ctx context.Context, cr []byte) error
+	// Restore drops the indices in memory and restores the stored indices,
+	// since the indices might be changed by another backend instance
+	Restore(ctx context.Context) error
 	// DeleteIndex deletes a backend index
 	DeleteIndex(ctx context.Context, cr []byte) error
 	// List the data from the backend index
@@ -37,4 +42,31 @@ type Backend interface {
 	Claim(ctx context.Context, cr []byte) ([]byte, error)
 	// DeleteClaim delete a claim in the backend index
 	DeleteClaim(ctx context.Context, cr []byte) error
+	// Export returns the snapshot of the indices with their entries
+	Export(ctx context.Context) ([]IndexSnapshot, error)
+	// Import returns the entries of the snapshot that conflict with the
+	// entries in the backend, the snapshot is applied when there are no
+	// conflicts and dryRun is false
+	Import(ctx context.Context, indices []IndexSnapshot, dryRun bool) ([]string, error)
+	// Ready returns an error if the backend cannot serve claims, since the
+	// stored indices are restoring. The failures of a single index do not
+	// fail the readiness, the claims of the index are not ready instead.
+	Ready() error
+}
+
+// TreeBackend is a backend of which the entries of an index nest, such that
+// the index can be rendered as a tree
+type TreeBackend interface {
+	// Tree returns the entries of the index as a tree, which only contains
+	// the entries within the root entry when set and is limited to depth
+	// levels when depth > 0
+	Tree(ctx context.Context, ref corev1.ObjectReference, root string, depth int) (any, error)
+}
+
+// DryRunBackend is a backend that applies a claim on a copy of the index,
+// such that the would-be allocation of the claim is returned without
+// changing the index, storing it or firing watches
+type DryRunBackend interface {
+	// DryRunClaim returns the claim as it would be claimed in the index
+	DryRunClaim(ctx context.Context, cr []byte) ([]byte, error)
 }
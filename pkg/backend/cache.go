@@ -18,8 +18,10 @@ package backend
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -37,6 +39,8 @@ func newCacheContext[T1 any](i T1) *cacheContext[T1] {
 type cacheContext[T1 any] struct {
 	initialized bool
 	instance    T1
+	// index is the index resource of the instance
+	index []byte
 }
 
 func (r *cacheContext[T1]) Initialized() {
@@ -53,6 +57,20 @@ type Cache[T1 any] interface {
 	Get(corev1.ObjectReference, bool) (T1, error)
 	Create(corev1.ObjectReference, T1)
 	Delete(corev1.ObjectReference)
+	// Reset deletes all indices
+	Reset()
+	// SetIndex keeps the index resource of the instance, which is exported
+	// with the instance
+	SetIndex(corev1.ObjectReference, []byte) error
+	// List returns the initialized instances sorted by namespace and name
+	List() []CacheInstance[T1]
+}
+
+// CacheInstance is an initialized instance with its index resource
+type CacheInstance[T1 any] struct {
+	Ref      corev1.ObjectReference
+	Index    []byte
+	Instance T1
 }
 
 func NewCache[T1 any]() Cache[T1] {
@@ -81,6 +99,41 @@ func (r *caches[T1]) Delete(id corev1.ObjectReference) {
 	delete(r.db, id)
 }
 
+func (r *caches[T1]) Reset() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.db = map[corev1.ObjectReference]*cacheContext[T1]{}
+}
+
+func (r *caches[T1]) SetIndex(id corev1.ObjectReference, index []byte) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	dbCtx, ok := r.db[id]
+	if !ok {
+		return fmt.Errorf("db not initialized: %v", id)
+	}
+	dbCtx.index = index
+	return nil
+}
+
+func (r *caches[T1]) List() []CacheInstance[T1] {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	instances := make([]CacheInstance[T1], 0, len(r.db))
+	for id, dbCtx := range r.db {
+		if dbCtx.IsInitialized() {
+			instances = append(instances, CacheInstance[T1]{Ref: id, Index: dbCtx.index, Instance: dbCtx.instance})
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Ref.Namespace != instances[j].Ref.Namespace {
+			return instances[i].Ref.Namespace < instances[j].Ref.Namespace
+		}
+		return instances[i].Ref.Name < instances[j].Ref.Name
+	})
+	return instances
+}
+
 // init initializes the db
 // return true -> isInitialized
 // return false -> not initialized
@@ -113,10 +166,10 @@ func (r *caches[T1]) Get(id corev1.ObjectReference, ignoreInitializing bool) (T1
 	defer r.m.RUnlock()
 	i, ok := r.db[id]
 	if !ok {
-		return *new(T1), fmt.Errorf("db not initialized: %v", id)
+		return *new(T1), NewError(resourcepb.ErrorCode_IndexNotReady, "db not initialized: %v", id)
 	}
 	if !ignoreInitializing && !i.IsInitialized() {
-		return *new(T1), fmt.Errorf("db is initializing: %v", id)
+		return *new(T1), NewError(resourcepb.ErrorCode_IndexNotReady, "db is initializing: %v", id)
 	}
 	return i.instance, nil
 }
@@ -0,0 +1,83 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+)
+
+// Error is a backend error with an error code, the code is returned to the
+// client proxy in the resourcepb responses
+type Error struct {
+	Code    resourcepb.ErrorCode
+	Message string
+}
+
+func (r *Error) Error() string {
+	return r.Message
+}
+
+// NewError returns a backend error with the error code
+func NewError(code resourcepb.ErrorCode, format string, a ...any) error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+// GetErrorCode returns the error code of the error; errors that are not
+// backend errors e.g. transport errors are returned as internal errors
+func GetErrorCode(err error) resourcepb.ErrorCode {
+	if err == nil {
+		return resourcepb.ErrorCode_NoError
+	}
+	var berr *Error
+	if errors.As(err, &berr) {
+		return berr.Code
+	}
+	return resourcepb.ErrorCode_Internal
+}
+
+// IsRetriable returns true if the error is expected to resolve without a
+// change to the claim or the index, e.g. an index that is still initializing
+func IsRetriable(err error) bool {
+	switch GetErrorCode(err) {
+	case resourcepb.ErrorCode_IndexNotReady, resourcepb.ErrorCode_Internal:
+		return true
+	}
+	return false
+}
+
+// GetConditionReason returns the condition reason of the claim for the error
+func GetConditionReason(err error) resourcev1alpha1.ConditionReason {
+	switch GetErrorCode(err) {
+	case resourcepb.ErrorCode_IndexNotReady:
+		return resourcev1alpha1.ConditionReasonIndexNotReady
+	case resourcepb.ErrorCode_PoolExhausted:
+		return resourcev1alpha1.ConditionReasonPoolExhausted
+	case resourcepb.ErrorCode_SelectorNoMatch:
+		return resourcev1alpha1.ConditionReasonSelectorNoMatch
+	case resourcepb.ErrorCode_Conflict:
+		return resourcev1alpha1.ConditionReasonConflict
+	case resourcepb.ErrorCode_ValidationFailed:
+		return resourcev1alpha1.ConditionReasonValidationFailed
+	case resourcepb.ErrorCode_QuotaExceeded:
+		return resourcev1alpha1.ConditionReasonQuotaExceeded
+	}
+	return resourcev1alpha1.ConditionReasonFailed
+}
@@ -0,0 +1,86 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+)
+
+func TestErrorCode(t *testing.T) {
+	cases := map[string]struct {
+		err       error
+		code      resourcepb.ErrorCode
+		retriable bool
+		reason    resourcev1alpha1.ConditionReason
+	}{
+		"NoError": {
+			err:    nil,
+			code:   resourcepb.ErrorCode_NoError,
+			reason: resourcev1alpha1.ConditionReasonFailed,
+		},
+		"IndexNotReady": {
+			err:       NewError(resourcepb.ErrorCode_IndexNotReady, "db is initializing: %s", "a"),
+			code:      resourcepb.ErrorCode_IndexNotReady,
+			retriable: true,
+			reason:    resourcev1alpha1.ConditionReasonIndexNotReady,
+		},
+		"WrappedPoolExhausted": {
+			err:    fmt.Errorf("cannot claim: %w", NewError(resourcepb.ErrorCode_PoolExhausted, "no free prefix found")),
+			code:   resourcepb.ErrorCode_PoolExhausted,
+			reason: resourcev1alpha1.ConditionReasonPoolExhausted,
+		},
+		"Conflict": {
+			err:    NewError(resourcepb.ErrorCode_Conflict, "vlan ID %d already claimed", 10),
+			code:   resourcepb.ErrorCode_Conflict,
+			reason: resourcev1alpha1.ConditionReasonConflict,
+		},
+		"ValidationFailed": {
+			err:    NewError(resourcepb.ErrorCode_ValidationFailed, "validation failed"),
+			code:   resourcepb.ErrorCode_ValidationFailed,
+			reason: resourcev1alpha1.ConditionReasonValidationFailed,
+		},
+		"QuotaExceeded": {
+			err:    NewError(resourcepb.ErrorCode_QuotaExceeded, "quota exceeded"),
+			code:   resourcepb.ErrorCode_QuotaExceeded,
+			reason: resourcev1alpha1.ConditionReasonQuotaExceeded,
+		},
+		"Untyped": {
+			err:       errors.New("connection refused"),
+			code:      resourcepb.ErrorCode_Internal,
+			retriable: true,
+			reason:    resourcev1alpha1.ConditionReasonFailed,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := GetErrorCode(tc.err); got != tc.code {
+				t.Errorf("GetErrorCode: want %s, got %s", tc.code, got)
+			}
+			if got := IsRetriable(tc.err); got != tc.retriable {
+				t.Errorf("IsRetriable: want %t, got %t", tc.retriable, got)
+			}
+			if got := GetConditionReason(tc.err); got != tc.reason {
+				t.Errorf("GetConditionReason: want %s, got %s", tc.reason, got)
+			}
+		})
+	}
+}
@@ -24,8 +24,9 @@ import (
 	"github.com/hansthienpondt/nipam/pkg/table"
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/iputil"
-	"github.com/pkg/errors"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -74,7 +75,7 @@ func (r *applicator) ApplyDynamic(ctx context.Context) error {
 
 	routes = r.getRoutesByLabel()
 	if len(routes) == 0 {
-		return fmt.Errorf("dynamic claim: no available routes based on the selector labels %v", r.claim.GetSelectorLabels())
+		return backend.NewError(resourcepb.ErrorCode_SelectorNoMatch, "dynamic claim: no available routes based on the selector labels %v", r.claim.GetSelectorLabels())
 	}
 
 	// if the status indicated an claim prefix, the client suggests to reclaim this prefix if possible
@@ -112,13 +113,13 @@ func (r *applicator) ApplyDynamic(ctx context.Context) error {
 	prefixLength := r.getPrefixLengthFromRoute(routes[0])
 	selectedRoute := r.GetSelectedRouteWithPrefixLength(routes, uint8(prefixLength.Int()))
 	if selectedRoute == nil {
-		return fmt.Errorf("no route found with requested prefixLength: %d", prefixLength)
+		return backend.NewError(resourcepb.ErrorCode_SelectorNoMatch, "no route found with requested prefixLength: %d", prefixLength)
 	}
 	pi := iputil.NewPrefixInfo(selectedRoute.Prefix())
 	r.l.Info("dynamic claim new claim", "selectedRoute", selectedRoute)
 	p := r.rib.GetAvailablePrefixByBitLen(pi.GetIPPrefix(), uint8(prefixLength.Int()))
 	if !p.IsValid() {
-		return errors.New("no free prefix found")
+		return backend.NewError(resourcepb.ErrorCode_PoolExhausted, "no free prefix found")
 	}
 	r.l.Info("dynamic claim new claim",
 		"pi prefix", pi,
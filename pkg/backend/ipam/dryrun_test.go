@@ -0,0 +1,111 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestDryRunClaim(t *testing.T) {
+	ctx := context.Background()
+	be, err := New(nil, backend.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ni := ipamv1alpha1.BuildNetworkInstance(metav1.ObjectMeta{Namespace: "default", Name: "a"}, ipamv1alpha1.NetworkInstanceSpec{}, ipamv1alpha1.NetworkInstanceStatus{})
+	niBytes, err := json.Marshal(ni)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := be.CreateIndex(ctx, niBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	claim := func(name string, labels map[string]string, spec ipamv1alpha1.IPClaimSpec, dryRun bool) (*ipamv1alpha1.IPClaim, error) {
+		spec.NetworkInstance = corev1.ObjectReference{Namespace: ni.Namespace, Name: ni.Name}
+		req := ipamv1alpha1.BuildIPClaim(metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels}, spec, ipamv1alpha1.IPClaimStatus{})
+		req.AddOwnerLabelsToCR()
+		b, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		claimFn := be.Claim
+		if dryRun {
+			claimFn = be.(backend.DryRunBackend).DryRunClaim
+		}
+		b, err = claimFn(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		resp := &ipamv1alpha1.IPClaim{}
+		return resp, json.Unmarshal(b, resp)
+	}
+
+	if _, err := claim("aggregate", map[string]string{
+		resourcev1alpha1.NephioOwnerGvkKey: meta.GVKToString(ipamv1alpha1.NetworkInstanceGroupVersionKind),
+	}, ipamv1alpha1.IPClaimSpec{
+		Kind:         ipamv1alpha1.PrefixKindAggregate,
+		Prefix:       pointer.String("10.0.0.0/8"),
+		PrefixLength: util.PointerUint8(8),
+		CreatePrefix: pointer.Bool(true),
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	dynamic := ipamv1alpha1.IPClaimSpec{
+		Kind:         ipamv1alpha1.PrefixKindNetwork,
+		PrefixLength: util.PointerUint8(24),
+		CreatePrefix: pointer.Bool(true),
+	}
+	preview, err := claim("dynamic", nil, dynamic, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Status.Prefix == nil || *preview.Status.Prefix != "10.0.0.0/24" {
+		t.Fatalf("want the dry run to assign 10.0.0.0/24, got %v", preview.Status.Prefix)
+	}
+	if _, err := claim("static", nil, ipamv1alpha1.IPClaimSpec{
+		Kind:   ipamv1alpha1.PrefixKindNetwork,
+		Prefix: pointer.String("11.0.0.1/24"),
+	}, true); err == nil {
+		t.Errorf("want the dry run of a prefix without aggregate to fail")
+	}
+	if routes, err := be.List(ctx, niBytes); err != nil || len(routes.(table.Routes)) != 1 {
+		t.Fatalf("the dry run changed the network instance, got %v, %v", routes, err)
+	}
+
+	// the claim is assigned the prefix of the dry run
+	resp, err := claim("dynamic", nil, dynamic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status.Prefix == nil || *resp.Status.Prefix != *preview.Status.Prefix {
+		t.Errorf("want prefix %s, got %v", *preview.Status.Prefix, resp.Status.Prefix)
+	}
+}
@@ -16,7 +16,27 @@ limitations under the License.
 
 package ipam
 
+import (
+	"errors"
+	"fmt"
+)
+
 const (
 	errValidateDuplicatePrefix              = "cannot create prefix duplicate"
 	errValidateNetworkPrefixWoNetworkParent = "cannot create network prefix w/o parent network prefix"
 )
+
+var (
+	// errValidation is wrapped by the errors of the validators when the claim
+	// is not valid
+	errValidation = errors.New("validated failed")
+	// errDuplicatePrefix is wrapped by the errors of the validators when the
+	// prefix is claimed by another owner
+	errDuplicatePrefix = errors.New(errValidateDuplicatePrefix)
+)
+
+// newValidationError returns a validation error for the message of a
+// validator function
+func newValidationError(msg string) error {
+	return fmt.Errorf("%w: %s", errValidation, msg)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestClaimErrorCode(t *testing.T) {
+	ctx := context.Background()
+	be, err := New(nil, backend.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ni := ipamv1alpha1.BuildNetworkInstance(metav1.ObjectMeta{Namespace: "default", Name: "a"}, ipamv1alpha1.NetworkInstanceSpec{}, ipamv1alpha1.NetworkInstanceStatus{})
+	niBytes, err := json.Marshal(ni)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := be.CreateIndex(ctx, niBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	claim := func(name string, spec ipamv1alpha1.IPClaimSpec) error {
+		spec.NetworkInstance = corev1.ObjectReference{Namespace: ni.Namespace, Name: ni.Name}
+		labels := map[string]string{}
+		if spec.Kind == ipamv1alpha1.PrefixKindAggregate {
+			// aggregates are claimed on behalf of the network instance
+			labels[resourcev1alpha1.NephioOwnerGvkKey] = meta.GVKToString(ipamv1alpha1.NetworkInstanceGroupVersionKind)
+		}
+		req := ipamv1alpha1.BuildIPClaim(metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels}, spec, ipamv1alpha1.IPClaimStatus{})
+		req.AddOwnerLabelsToCR()
+		b, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		_, err = be.Claim(ctx, b)
+		return err
+	}
+	aggregate := ipamv1alpha1.IPClaimSpec{
+		Kind:         ipamv1alpha1.PrefixKindAggregate,
+		Prefix:       pointer.String("10.0.0.0/8"),
+		PrefixLength: util.PointerUint8(8),
+		CreatePrefix: pointer.Bool(true),
+	}
+	if err := claim("aggregate", aggregate); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		name     string
+		spec     ipamv1alpha1.IPClaimSpec
+		wantCode resourcepb.ErrorCode
+	}{
+		"SameOwner": {
+			name:     "aggregate",
+			spec:     aggregate,
+			wantCode: resourcepb.ErrorCode_NoError,
+		},
+		"DuplicatePrefix": {
+			name:     "other",
+			spec:     aggregate,
+			wantCode: resourcepb.ErrorCode_Conflict,
+		},
+		"NoParent": {
+			name: "network",
+			spec: ipamv1alpha1.IPClaimSpec{
+				Kind:   ipamv1alpha1.PrefixKindNetwork,
+				Prefix: pointer.String("11.0.0.1/24"),
+			},
+			wantCode: resourcepb.ErrorCode_ValidationFailed,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := claim(tc.name, tc.spec)
+			if code := backend.GetErrorCode(err); code != tc.wantCode {
+				t.Errorf("want error code %s, got %s: %v", tc.wantCode, code, err)
+			}
+		})
+	}
+}
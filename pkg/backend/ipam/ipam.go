@@ -19,32 +19,39 @@ package ipam
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"sync/atomic"
 
-	"github.com/go-logr/logr"
 	"github.com/hansthienpondt/nipam/pkg/table"
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func New(c client.Client) (backend.Backend, error) {
+// New returns the backend, the indices are kept in memory only when no client
+// is provided or the memory storage is selected
+func New(c client.Client, sc backend.StorageConfig) (backend.Backend, error) {
 	//ipamRib := newIpamRib()
 	cache := backend.NewCache[*table.RIB]()
 	watcher := newWatcher()
+	quotas := backend.NewQuotas(c)
 	runtimes := NewRuntimes(&RuntimeConfig{
 		cache:   cache,
 		watcher: watcher,
+		quotas:  quotas,
 	})
 
 	s := newNopCMStorage()
-	if c != nil {
+	if c != nil && sc.Type != backend.StorageTypeMemory {
 		var err error
 		s, err = newCMStorage(&storageConfig{
-			client:   c,
-			cache:    cache,
-			runtimes: runtimes,
+			client:    c,
+			namespace: sc.Namespace,
+			cache:     cache,
+			runtimes:  runtimes,
 		})
 		if err != nil {
 			return nil, err
@@ -56,6 +63,7 @@ func New(c client.Client) (backend.Backend, error) {
 		runtimes: runtimes,
 		store:    s,
 		watcher:  watcher,
+		quotas:   quotas,
 	}, nil
 }
 
@@ -64,8 +72,9 @@ type be struct {
 	cache    backend.Cache[*table.RIB]
 	runtimes Runtimes
 	store    Storage
-
-	l logr.Logger
+	quotas   backend.Quotas
+	// restoring is set while the stored indices are restored
+	restoring atomic.Bool
 }
 
 func (r *be) AddWatch(ownerGvkKey, ownerGvk string, fn backend.CallbackFn) {
@@ -75,6 +84,16 @@ func (r *be) DeleteWatch(ownerGvkKey, ownerGvk string) {
 	r.watcher.deleteWatch(ownerGvkKey, ownerGvk)
 }
 
+// Ready returns an error while the stored indices are restored, the restore
+// and save failures of a single index are reported by the index and the
+// storage metrics
+func (r *be) Ready() error {
+	if r.restoring.Load() {
+		return backend.NewError(resourcepb.ErrorCode_IndexNotReady, "restoring the indices")
+	}
+	return nil
+}
+
 // CreateIndex creates the instance from the cache
 func (r *be) CreateIndex(ctx context.Context, b []byte) error {
 	cr := &ipamv1alpha1.NetworkInstance{}
@@ -82,21 +101,49 @@ func (r *be) CreateIndex(ctx context.Context, b []byte) error {
 		return err
 	}
 	cacheID := cr.GetCacheID()
-	r.l = log.FromContext(ctx).WithValues("cache id", cacheID)
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
 
-	r.l.Info("create cache instance start", "isInitialized", r.cache.IsInitialized(cacheID))
+	log.Info("create cache instance start", "isInitialized", r.cache.IsInitialized(cacheID))
 	// if the Cache is not initialaized initialized
 	// this happens upon initialization or backend restart
 	r.cache.Create(cacheID, table.NewRIB())
 	if !r.cache.IsInitialized(cacheID) {
 		if err := r.store.Get().Restore(ctx, cacheID); err != nil {
-			r.l.Error(err, "backend cache restore error")
+			log.Error(err, "backend cache restore error")
+			return err
+		}
+		log.Info("create cache instance finished")
+		if err := r.cache.SetInitialized(cacheID); err != nil {
 			return err
 		}
-		r.l.Info("create cache instance finished")
-		return r.cache.SetInitialized(cacheID)
+	} else {
+		log.Info("create cache instance already initialized")
+	}
+	if err := r.cache.SetIndex(cacheID, b); err != nil {
+		return err
+	}
+	// the index is stored such that it is restored when another backend
+	// instance becomes the leader
+	return r.store.Get().SaveIndex(ctx, cacheID, b)
+}
+
+// Restore drops the indices in memory and restores the stored indices
+func (r *be) Restore(ctx context.Context) error {
+	r.restoring.Store(true)
+	defer r.restoring.Store(false)
+
+	r.cache.Reset()
+	indices, err := r.store.Get().ListIndices(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range indices {
+		// an index that fails to restore is not ready, the restore is
+		// retried when the index is created again
+		if err := r.CreateIndex(ctx, b); err != nil {
+			log.FromContext(ctx).Error(err, "cannot restore index")
+		}
 	}
-	r.l.Info("create cache instance already initialized")
 	return nil
 }
 
@@ -107,17 +154,17 @@ func (r *be) DeleteIndex(ctx context.Context, b []byte) error {
 		return err
 	}
 	cacheID := cr.GetCacheID()
-	r.l = log.FromContext(ctx).WithValues("cache id", cacheID)
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
 
-	r.l.Info("delete cache instance start")
+	log.Info("delete cache instance start")
 	r.cache.Delete(cacheID)
 
 	// delete the data from the backend
 	if err := r.store.Get().Destroy(ctx, cacheID); err != nil {
-		r.l.Error(err, "delete cache instance error")
+		log.Error(err, "delete cache instance error")
 		return err
 	}
-	r.l.Info("delete cache instance finished")
+	log.Info("delete cache instance finished")
 	return nil
 }
 
@@ -127,11 +174,11 @@ func (r *be) List(ctx context.Context, b []byte) (any, error) {
 		return nil, err
 	}
 	cacheID := cr.GetCacheID()
-	r.l = log.FromContext(ctx).WithValues("cache id", cacheID)
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
 
 	rib, err := r.cache.Get(cacheID, false)
 	if err != nil {
-		r.l.Error(err, "cannpt get cache instance")
+		log.Error(err, "cannpt get cache instance")
 		return []table.Route{}, err
 	}
 	return rib.GetTable(), nil
@@ -143,8 +190,8 @@ func (r *be) GetClaim(ctx context.Context, b []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	r.l = log.FromContext(ctx).WithValues("name", cr.GetName())
-	r.l.Info("get claim entry", "selectors", cr.GetSelectorLabels())
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+	log.Info("get claim entry", "selectors", cr.GetSelectorLabels())
 
 	// get the runtime based the following parameters
 	// prefixkind
@@ -159,43 +206,69 @@ func (r *be) GetClaim(ctx context.Context, b []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	r.l.Info("get claim entry done", "claimedPrefix", claimedPrefix)
+	log.Info("get claim entry done", "claimedPrefix", claimedPrefix)
 	return json.Marshal(claimedPrefix)
 }
 
 // Claim the prefix
 func (r *be) Claim(ctx context.Context, b []byte) ([]byte, error) {
+	return r.claim(ctx, b, false)
+}
+
+// DryRunClaim returns the claim with the prefix it would be assigned, the
+// claim is applied on a copy of the network instance and is not stored
+func (r *be) DryRunClaim(ctx context.Context, b []byte) ([]byte, error) {
+	return r.claim(ctx, b, true)
+}
+
+func (r *be) claim(ctx context.Context, b []byte, dryRun bool) ([]byte, error) {
 	cr := &ipamv1alpha1.IPClaim{}
 	if err := json.Unmarshal(b, cr); err != nil {
 		return nil, err
 	}
 
-	r.l = log.FromContext(ctx).WithValues("name", cr.GetName())
-	r.l.Info("claim entry", "prefix", cr.Spec.Prefix, "networkInstance", cr.Spec.NetworkInstance)
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+	log.Info("claim entry", "prefix", cr.Spec.Prefix, "networkInstance", cr.Spec.NetworkInstance, "dryRun", dryRun)
+
+	r.quotas.Lock(cr.GetCacheID())
+	defer r.quotas.Unlock(cr.GetCacheID())
 
 	// get the runtime based the following parameters
 	// prefixkind
 	// hasprefix -> if prefix parsing is nok we return an error
 	// networkinstance -> if not initialized we get an error
 	// initialized with claim, rib and prefix if present
-	op, err := r.runtimes.Get(cr, false)
-	if err != nil {
-		return nil, err
+	var op Runtime
+	var err error
+	if dryRun {
+		op, err = r.runtimes.DryRun(cr)
+	} else {
+		op, err = r.runtimes.Get(cr, false)
 	}
-	msg, err := op.Validate(ctx)
 	if err != nil {
-		r.l.Error(err, "validation failed")
 		return nil, err
 	}
-	if msg != "" {
-		r.l.Error(fmt.Errorf("%s", msg), "validation failed")
-		return nil, fmt.Errorf("validated failed: %s", msg)
+	if err := op.Validate(ctx); err != nil {
+		log.Error(err, "validation failed")
+		switch {
+		case errors.Is(err, errDuplicatePrefix):
+			// a duplicate prefix is claimed by another owner
+			return nil, backend.NewError(resourcepb.ErrorCode_Conflict, "%s", err.Error())
+		case errors.Is(err, errValidation):
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "%s", err.Error())
+		}
+		return nil, err
 	}
-	cr, err = op.Apply(ctx)
+	applyCtx, span := tracing.Start(ctx, "ipam.Apply", tracing.NameKey.String(cr.GetName()))
+	cr, err = op.Apply(applyCtx)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, err
 	}
-	r.l.Info("claim prefix done", "updated Claim", cr)
+	log.Info("claim prefix done", "updated Claim", cr, "dryRun", dryRun)
+	if dryRun {
+		return json.Marshal(cr)
+	}
 	if err := r.store.Get().SaveAll(ctx, cr.GetCacheID()); err != nil {
 		return nil, err
 	}
@@ -208,7 +281,10 @@ func (r *be) DeleteClaim(ctx context.Context, b []byte) error {
 		return err
 	}
 
-	r.l = log.FromContext(ctx).WithValues("name", cr.GetName())
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+
+	r.quotas.Lock(cr.GetCacheID())
+	defer r.quotas.Unlock(cr.GetCacheID())
 
 	// get the runtime based the following parameters
 	// prefixkind
@@ -217,12 +293,12 @@ func (r *be) DeleteClaim(ctx context.Context, b []byte) error {
 	// initialized with claim, rib and prefix if present
 	rt, err := r.runtimes.Get(cr, false)
 	if err != nil {
-		r.l.Error(err, "cannot get runtime")
+		log.Error(err, "cannot get runtime")
 		return err
 	}
 	// we trust the create prefix since it was already claimed
 	if err := rt.Delete(ctx); err != nil {
-		r.l.Error(err, "cannot delete claimed resource")
+		log.Error(err, "cannot delete claimed resource")
 		return err
 	}
 	return r.store.Get().SaveAll(ctx, cr.GetCacheID())
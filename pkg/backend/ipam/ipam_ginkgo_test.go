@@ -36,7 +36,7 @@ var _ = Describe("IPAM Testing", func() {
 			By("calling New() constructor for an ipam backend")
 			var err error
 			// create new index
-			be, err = New(nil)
+			be, err = New(nil, backend.StorageConfig{})
 			Ω(err).Should(Succeed(), "Failed to create ipam backend")
 
 			Ω(be).ShouldNot(BeNil(), "initializing ipam failed")
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/iputil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// checkQuota checks the address or prefix quotas of the namespace for a new
+// claim. Aggregates define the pools of the network instance and are not
+// subject to quotas.
+func checkQuota(ctx context.Context, quotas backend.Quotas, rib *table.RIB, cr *ipamv1alpha1.IPClaim) error {
+	if cr.Spec.Kind == ipamv1alpha1.PrefixKindAggregate {
+		return nil
+	}
+	ownerSelector, err := cr.GetOwnerSelector()
+	if err != nil {
+		return err
+	}
+	// a claim that holds its prefix already is not checked again
+	if len(rib.GetByLabel(ownerSelector)) > 0 {
+		return nil
+	}
+	return quotas.Check(ctx, backend.QuotaRequest{
+		Namespace: cr.GetNamespace(),
+		Labels:    cr.GetUserDefinedLabels(),
+		Index:     cr.GetCacheID(),
+		Resource:  getQuotaResource(cr),
+		Count:     1,
+	}, func(index corev1.ObjectReference, resource quotav1alpha1.QuotaResource, selector labels.Selector) (int64, error) {
+		return getQuotaUsage(rib, resource, selector), nil
+	})
+}
+
+// GetQuotaUsage returns the addresses or prefixes in the index held by the
+// claims that match the selector
+func (r *be) GetQuotaUsage(index corev1.ObjectReference, resource quotav1alpha1.QuotaResource, selector labels.Selector) (int64, error) {
+	rib, err := r.cache.Get(resourcev1alpha1.GetCacheID(index), false)
+	if err != nil {
+		return 0, err
+	}
+	return getQuotaUsage(rib, resource, selector), nil
+}
+
+// getQuotaResource returns if the claim requests an address or a prefix
+func getQuotaResource(cr *ipamv1alpha1.IPClaim) quotav1alpha1.QuotaResource {
+	if cr.Spec.Prefix != nil {
+		// a network prefix without create prefix claims the address in the subnet
+		if cr.Spec.Kind == ipamv1alpha1.PrefixKindNetwork && cr.Spec.CreatePrefix == nil {
+			return quotav1alpha1.QuotaResourceAddresses
+		}
+		pi, err := iputil.New(*cr.Spec.Prefix)
+		if err == nil && !pi.IsAddressPrefix() {
+			return quotav1alpha1.QuotaResourcePrefixes
+		}
+		return quotav1alpha1.QuotaResourceAddresses
+	}
+	if cr.Spec.PrefixLength != nil && *cr.Spec.PrefixLength != 32 && *cr.Spec.PrefixLength != 128 {
+		return quotav1alpha1.QuotaResourcePrefixes
+	}
+	return quotav1alpha1.QuotaResourceAddresses
+}
+
+// getQuotaUsage returns the addresses or prefixes held by the claims whose
+// routes match the selector. A claim can hold multiple routes, e.g. a network
+// prefix with create prefix, the claim holds a prefix if any route is a prefix.
+func getQuotaUsage(rib *table.RIB, resource quotav1alpha1.QuotaResource, selector labels.Selector) int64 {
+	claims := map[string]bool{}
+	for _, route := range rib.GetByLabel(selector) {
+		l := route.Labels()
+		if l[resourcev1alpha1.NephioPrefixKindKey] == string(ipamv1alpha1.PrefixKindAggregate) {
+			continue
+		}
+		owner := l[resourcev1alpha1.NephioOwnerGvkKey] + "/" + l[resourcev1alpha1.NephioNsnNamespaceKey] + "/" + l[resourcev1alpha1.NephioNsnNameKey]
+		isPrefix := route.Prefix().Bits() != route.Prefix().Addr().BitLen()
+		claims[owner] = claims[owner] || isPrefix
+	}
+	var used int64
+	for _, isPrefix := range claims {
+		if isPrefix == (resource == quotav1alpha1.QuotaResourcePrefixes) {
+			used++
+		}
+	}
+	return used
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	s := kruntime.NewScheme()
+	if err := quotav1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(&quotav1alpha1.ClaimQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "q"},
+		Spec: quotav1alpha1.ClaimQuotaSpec{
+			Limits: []quotav1alpha1.QuotaLimit{
+				{Index: corev1.ObjectReference{Namespace: "default", Name: "a"}, Resource: quotav1alpha1.QuotaResourcePrefixes, Max: 1},
+			},
+		},
+	}).Build()
+
+	be, err := New(c, backend.StorageConfig{Type: backend.StorageTypeMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ni := ipamv1alpha1.BuildNetworkInstance(metav1.ObjectMeta{Namespace: "default", Name: "a"}, ipamv1alpha1.NetworkInstanceSpec{}, ipamv1alpha1.NetworkInstanceStatus{})
+	niBytes, err := json.Marshal(ni)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := be.CreateIndex(ctx, niBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	claim := func(name string, spec ipamv1alpha1.IPClaimSpec, dryRun bool) error {
+		spec.NetworkInstance = corev1.ObjectReference{Namespace: ni.Namespace, Name: ni.Name}
+		labels := map[string]string{}
+		if spec.Kind == ipamv1alpha1.PrefixKindAggregate {
+			// aggregates are claimed on behalf of the network instance
+			labels[resourcev1alpha1.NephioOwnerGvkKey] = meta.GVKToString(ipamv1alpha1.NetworkInstanceGroupVersionKind)
+		}
+		req := ipamv1alpha1.BuildIPClaim(metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels}, spec, ipamv1alpha1.IPClaimStatus{})
+		req.AddOwnerLabelsToCR()
+		b, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			_, err = be.(backend.DryRunBackend).DryRunClaim(ctx, b)
+			return err
+		}
+		_, err = be.Claim(ctx, b)
+		return err
+	}
+	pool := func(prefix string) ipamv1alpha1.IPClaimSpec {
+		return ipamv1alpha1.IPClaimSpec{
+			Kind:         ipamv1alpha1.PrefixKindPool,
+			Prefix:       pointer.String(prefix),
+			CreatePrefix: pointer.Bool(true),
+		}
+	}
+
+	// aggregates are not subject to the quotas
+	if err := claim("aggregate", ipamv1alpha1.IPClaimSpec{
+		Kind:         ipamv1alpha1.PrefixKindAggregate,
+		Prefix:       pointer.String("10.0.0.0/8"),
+		PrefixLength: util.PointerUint8(8),
+		CreatePrefix: pointer.Bool(true),
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := claim("pool1", pool("10.1.0.0/16"), false); err != nil {
+		t.Fatalf("claim within quota failed: %v", err)
+	}
+	// a claim that holds its prefix already is not checked again
+	if err := claim("pool1", pool("10.1.0.0/16"), false); err != nil {
+		t.Errorf("refresh of an existing claim failed: %v", err)
+	}
+	for _, dryRun := range []bool{true, false} {
+		if err := claim("pool2", pool("10.2.0.0/16"), dryRun); backend.GetErrorCode(err) != resourcepb.ErrorCode_QuotaExceeded {
+			t.Errorf("dryRun %t: want QuotaExceeded, got %v", dryRun, err)
+		}
+	}
+}
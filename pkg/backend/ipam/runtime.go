@@ -27,11 +27,20 @@ import (
 
 type Runtimes interface {
 	Get(claim *ipamv1alpha1.IPClaim, initializing bool) (Runtime, error)
+	// DryRun returns the runtime of the claim on a copy of the rib, such
+	// that applying the claim does not change the rib or fire watches
+	DryRun(claim *ipamv1alpha1.IPClaim) (Runtime, error)
+	// Import returns the runtime of a prefix claim of a snapshot on the rib
+	// the snapshot is staged on, such that the entries of the snapshot are
+	// validated against the index and against each other
+	Import(claim *ipamv1alpha1.IPClaim, rib *table.RIB) (Runtime, error)
 }
 
 type RuntimeConfig struct {
 	cache   backend.Cache[*table.RIB]
 	watcher Watcher
+	// quotas are checked before a new claim is applied
+	quotas backend.Quotas
 }
 
 func NewRuntimes(c *RuntimeConfig) Runtimes {
@@ -42,7 +51,7 @@ func NewRuntimes(c *RuntimeConfig) Runtimes {
 }
 
 type runtimes struct {
-	prefixRuntime  runtime
+	prefixRuntime  *ipamPrefixRuntime
 	dynamicRuntime runtime
 }
 
@@ -53,13 +62,25 @@ func (r *runtimes) Get(claim *ipamv1alpha1.IPClaim, initializing bool) (Runtime,
 	return r.prefixRuntime.Get(claim, initializing)
 }
 
+func (r *runtimes) DryRun(claim *ipamv1alpha1.IPClaim) (Runtime, error) {
+	if claim.Spec.Prefix == nil {
+		return r.dynamicRuntime.DryRun(claim)
+	}
+	return r.prefixRuntime.DryRun(claim)
+}
+
+func (r *runtimes) Import(claim *ipamv1alpha1.IPClaim, rib *table.RIB) (Runtime, error) {
+	return r.prefixRuntime.Import(claim, rib)
+}
+
 type runtime interface {
 	Get(claim *ipamv1alpha1.IPClaim, initializing bool) (Runtime, error)
+	DryRun(claim *ipamv1alpha1.IPClaim) (Runtime, error)
 }
 
 type Runtime interface {
 	Get(ctx context.Context) (*ipamv1alpha1.IPClaim, error)
-	Validate(ctx context.Context) (string, error)
+	Validate(ctx context.Context) error
 	Apply(ctx context.Context) (*ipamv1alpha1.IPClaim, error)
 	Delete(ctx context.Context) error
 }
@@ -70,6 +91,7 @@ type PrefixRuntimeConfig struct {
 	rib          *table.RIB
 	fnc          *PrefixValidatorFunctionConfig
 	watcher      Watcher
+	quotas       backend.Quotas
 }
 
 type DynamicRuntimeConfig struct {
@@ -78,33 +100,35 @@ type DynamicRuntimeConfig struct {
 	rib          *table.RIB
 	fnc          *DynamicValidatorFunctionConfig
 	watcher      Watcher
+	quotas       backend.Quotas
 }
 
-func newPrefixRuntime(c *RuntimeConfig) Runtimes {
+func newPrefixRuntime(c *RuntimeConfig) *ipamPrefixRuntime {
 	return &ipamPrefixRuntime{
 		cache:   c.cache,
 		watcher: c.watcher,
+		quotas:  c.quotas,
 		oc: map[ipamv1alpha1.PrefixKind]*PrefixValidatorFunctionConfig{
 			ipamv1alpha1.PrefixKindNetwork: {
-				validateInputFn:         validateInput,
+				validateInputFn:         ValidateInput,
 				validateChildrenExistFn: validateChildrenExist,
 				validateNoParentExistFn: validateNoParentExist,
 				validateParentExistFn:   validateParentExist,
 			},
 			ipamv1alpha1.PrefixKindLoopback: {
-				validateInputFn:         validateInput,
+				validateInputFn:         ValidateInput,
 				validateChildrenExistFn: validateChildrenExist,
 				validateNoParentExistFn: validateNoParentExist,
 				validateParentExistFn:   validateParentExist,
 			},
 			ipamv1alpha1.PrefixKindPool: {
-				validateInputFn:         validateInput,
+				validateInputFn:         ValidateInput,
 				validateChildrenExistFn: validateChildrenExist,
 				validateNoParentExistFn: validateNoParentExist,
 				validateParentExistFn:   validateParentExist,
 			},
 			ipamv1alpha1.PrefixKindAggregate: {
-				validateInputFn:         validateInput,
+				validateInputFn:         ValidateInput,
 				validateChildrenExistFn: validateChildrenExist,
 				validateNoParentExistFn: validateNoParentExist,
 				validateParentExistFn:   validateParentExist,
@@ -116,6 +140,7 @@ func newPrefixRuntime(c *RuntimeConfig) Runtimes {
 type ipamPrefixRuntime struct {
 	cache   backend.Cache[*table.RIB]
 	watcher Watcher
+	quotas  backend.Quotas
 	m       sync.Mutex
 	oc      map[ipamv1alpha1.PrefixKind]*PrefixValidatorFunctionConfig
 }
@@ -135,27 +160,59 @@ func (r *ipamPrefixRuntime) Get(claim *ipamv1alpha1.IPClaim, initializing bool)
 		claim:        claim,
 		rib:          rib,
 		watcher:      r.watcher,
+		quotas:       r.quotas,
 		fnc:          r.oc[claim.Spec.Kind],
 	})
 
 }
 
-func newDynamicRuntime(c *RuntimeConfig) Runtimes {
+func (r *ipamPrefixRuntime) DryRun(claim *ipamv1alpha1.IPClaim) (Runtime, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	rib, err := r.cache.Get(claim.GetCacheID(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrefixRuntime(&PrefixRuntimeConfig{
+		claim: claim,
+		rib:   rib.Clone(),
+		// a watcher without watches, such that no watches are fired
+		watcher: newWatcher(),
+		quotas:  r.quotas,
+		fnc:     r.oc[claim.Spec.Kind],
+	})
+}
+
+func (r *ipamPrefixRuntime) Import(claim *ipamv1alpha1.IPClaim, rib *table.RIB) (Runtime, error) {
+	return NewPrefixRuntime(&PrefixRuntimeConfig{
+		claim: claim,
+		rib:   rib,
+		// a watcher without watches, the watches are fired when the snapshot
+		// is applied on the rib of the index
+		watcher: newWatcher(),
+		quotas:  r.quotas,
+		fnc:     r.oc[claim.Spec.Kind],
+	})
+}
+
+func newDynamicRuntime(c *RuntimeConfig) runtime {
 	return &ipamDynamicRuntime{
 		cache:   c.cache,
 		watcher: c.watcher,
+		quotas:  c.quotas,
 		oc: map[ipamv1alpha1.PrefixKind]*DynamicValidatorFunctionConfig{
 			ipamv1alpha1.PrefixKindNetwork: {
-				validateInputFn: validateInput,
+				validateInputFn: ValidateInput,
 			},
 			ipamv1alpha1.PrefixKindLoopback: {
-				validateInputFn: validateInput,
+				validateInputFn: ValidateInput,
 			},
 			ipamv1alpha1.PrefixKindPool: {
-				validateInputFn: validateInput,
+				validateInputFn: ValidateInput,
 			},
 			ipamv1alpha1.PrefixKindAggregate: {
-				validateInputFn: validateInput,
+				validateInputFn: ValidateInput,
 			},
 		},
 	}
@@ -164,6 +221,7 @@ func newDynamicRuntime(c *RuntimeConfig) Runtimes {
 type ipamDynamicRuntime struct {
 	cache   backend.Cache[*table.RIB]
 	watcher Watcher
+	quotas  backend.Quotas
 	m       sync.Mutex
 	oc      map[ipamv1alpha1.PrefixKind]*DynamicValidatorFunctionConfig
 }
@@ -182,6 +240,25 @@ func (r *ipamDynamicRuntime) Get(claim *ipamv1alpha1.IPClaim, initializing bool)
 		claim:        claim,
 		rib:          rib,
 		watcher:      r.watcher,
+		quotas:       r.quotas,
 		fnc:          r.oc[claim.Spec.Kind],
 	})
 }
+
+func (r *ipamDynamicRuntime) DryRun(claim *ipamv1alpha1.IPClaim) (Runtime, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	rib, err := r.cache.Get(claim.GetCacheID(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDynamicRuntime(&DynamicRuntimeConfig{
+		claim: claim,
+		rib:   rib.Clone(),
+		// a watcher without watches, such that no watches are fired
+		watcher: newWatcher(),
+		quotas:  r.quotas,
+		fnc:     r.oc[claim.Spec.Kind],
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/hansthienpondt/nipam/pkg/table"
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -37,6 +38,7 @@ func NewDynamicRuntime(cfg any) (Runtime, error) {
 		rib:          c.rib,
 		fnc:          c.fnc,
 		watcher:      c.watcher,
+		quotas:       c.quotas,
 	}, nil
 }
 
@@ -46,6 +48,7 @@ type claimRuntime struct {
 	rib          *table.RIB
 	fnc          *DynamicValidatorFunctionConfig
 	watcher      Watcher
+	quotas       backend.Quotas
 	l            logr.Logger
 }
 
@@ -64,7 +67,7 @@ func (r *claimRuntime) Get(ctx context.Context) (*ipamv1alpha1.IPClaim, error) {
 	return r.claim, nil
 }
 
-func (r *claimRuntime) Validate(ctx context.Context) (string, error) {
+func (r *claimRuntime) Validate(ctx context.Context) error {
 	r.l = log.FromContext(ctx).WithValues("name", r.claim.GetGenericNamespacedName(), "prefixkind", r.claim.Spec.Kind, "prefix", r.claim.Spec.Prefix)
 	r.l.Info("validate")
 	v := NewClaimValidator(&ClaimValidatorConfig{
@@ -79,6 +82,13 @@ func (r *claimRuntime) Apply(ctx context.Context) (*ipamv1alpha1.IPClaim, error)
 	r.l = log.FromContext(ctx).WithValues("name", r.claim.GetGenericNamespacedName(), "prefixkind", r.claim.Spec.Kind, "prefix", r.claim.Spec.Prefix)
 	r.l.Info("apply dynamic claim")
 
+	// the claims that are restored are not subject to the quotas
+	if !r.initializing {
+		if err := checkQuota(ctx, r.quotas, r.rib, r.claim); err != nil {
+			return nil, err
+		}
+	}
+
 	a := NewApplicator(&ApplicatorConfig{
 		initializing: r.initializing,
 		claim:        r.claim,
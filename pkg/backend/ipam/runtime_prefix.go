@@ -23,6 +23,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/hansthienpondt/nipam/pkg/table"
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/iputil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -43,6 +44,7 @@ func NewPrefixRuntime(cfg any) (Runtime, error) {
 		fnc:          c.fnc,
 		pi:           pi,
 		watcher:      c.watcher,
+		quotas:       c.quotas,
 	}, nil
 }
 
@@ -53,6 +55,7 @@ type prefixRuntime struct {
 	pi           *iputil.Prefix
 	fnc          *PrefixValidatorFunctionConfig
 	watcher      Watcher
+	quotas       backend.Quotas
 	l            logr.Logger
 }
 
@@ -71,7 +74,7 @@ func (r *prefixRuntime) Get(ctx context.Context) (*ipamv1alpha1.IPClaim, error)
 	return r.claim, nil
 }
 
-func (r *prefixRuntime) Validate(ctx context.Context) (string, error) {
+func (r *prefixRuntime) Validate(ctx context.Context) error {
 	r.l = log.FromContext(ctx).WithValues("name", r.claim.GetGenericNamespacedName(), "prefixkind", r.claim.Spec.Kind, "prefix", r.claim.Spec.Prefix)
 	r.l.Info("validate")
 	v := NewPrefixValidator(&PrefixValidatorConfig{
@@ -87,6 +90,12 @@ func (r *prefixRuntime) Apply(ctx context.Context) (*ipamv1alpha1.IPClaim, error
 	r.l = log.FromContext(ctx).WithValues("name", r.claim.GetName(), "prefixkind", r.claim.Spec.Kind, "prefix", r.claim.Spec.Prefix)
 	r.l.Info("apply")
 
+	// the claims that are restored are not subject to the quotas
+	if !r.initializing {
+		if err := checkQuota(ctx, r.quotas, r.rib, r.claim); err != nil {
+			return nil, err
+		}
+	}
 	pi, err := iputil.New(*r.claim.Spec.Prefix)
 	if err != nil {
 		return nil, err
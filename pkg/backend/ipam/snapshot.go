@@ -0,0 +1,192 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/iputil"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// Export returns the snapshot of the network instances with their prefixes
+func (r *be) Export(ctx context.Context) ([]backend.IndexSnapshot, error) {
+	return backend.ExportIndices(r.cache, func(rib *table.RIB) []backend.EntrySnapshot {
+		entries := []backend.EntrySnapshot{}
+		for _, route := range rib.GetTable() {
+			entries = append(entries, backend.EntrySnapshot{
+				ID:     route.Prefix().String(),
+				Labels: route.Labels(),
+			})
+		}
+		return entries
+	}), nil
+}
+
+// Import validates the prefixes of the snapshot against the prefixes in the
+// network instances and applies the snapshot when there are no conflicts. The
+// prefixes that are not claimed yet are validated like the prefix claims, the
+// owners of the applied prefixes are informed through their watches.
+func (r *be) Import(ctx context.Context, indices []backend.IndexSnapshot, dryRun bool) ([]string, error) {
+	defer backend.LockIndices(r.quotas, indices)()
+
+	conflicts := []string{}
+	for _, idx := range indices {
+		c, err := r.validateImport(ctx, idx)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, c...)
+	}
+	if len(conflicts) > 0 || dryRun {
+		return conflicts, nil
+	}
+
+	for _, idx := range indices {
+		if err := r.CreateIndex(ctx, idx.Index); err != nil {
+			return nil, err
+		}
+		cacheID := resourcev1alpha1.GetCacheID(corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name})
+		rib, err := r.cache.Get(cacheID, false)
+		if err != nil {
+			return nil, err
+		}
+		routes := table.Routes{}
+		for _, e := range idx.Entries {
+			pi := netip.MustParsePrefix(e.ID)
+			if _, ok := rib.Get(pi); ok {
+				continue
+			}
+			route := table.NewRoute(pi, e.Labels, map[string]any{})
+			if err := rib.Add(route); err != nil {
+				return nil, err
+			}
+			routes = append(routes, route)
+		}
+		if err := r.store.Get().SaveAll(ctx, cacheID); err != nil {
+			return nil, err
+		}
+		// the owners reconcile the claims of the imported prefixes
+		r.watcher.handleUpdate(ctx, routes, resourcepb.StatusCode_Unknown)
+	}
+	return conflicts, nil
+}
+
+// validateImport returns the prefixes of the index snapshot that are claimed
+// with other labels in the network instance or that are not valid claims
+func (r *be) validateImport(ctx context.Context, idx backend.IndexSnapshot) ([]string, error) {
+	cr := &ipamv1alpha1.NetworkInstance{}
+	if err := json.Unmarshal(idx.Index, cr); err != nil {
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid network instance %s/%s: %s", idx.Namespace, idx.Name, err.Error())
+	}
+	cacheID := resourcev1alpha1.GetCacheID(corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name})
+	if cr.GetCacheID() != cacheID {
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid network instance %s/%s: resource %v does not match", idx.Namespace, idx.Name, cr.GetCacheID())
+	}
+	rib, err := r.cache.Get(cacheID, false)
+	if err != nil {
+		rib = table.NewRIB()
+	}
+
+	prefixes := make([]netip.Prefix, len(idx.Entries))
+	for i, e := range idx.Entries {
+		pi, err := netip.ParsePrefix(e.ID)
+		if err != nil {
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid network instance %s/%s: invalid prefix %s", idx.Namespace, idx.Name, e.ID)
+		}
+		prefixes[i] = pi
+	}
+	// the new prefixes are staged on a copy of the rib with the parents
+	// first, such that a prefix is validated against the parents of the
+	// snapshot
+	order := make([]int, len(idx.Entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return prefixes[order[i]].Bits() < prefixes[order[j]].Bits() })
+	staged := rib.Clone()
+
+	conflicts := []string{}
+	for _, i := range order {
+		e := idx.Entries[i]
+		if route, ok := rib.Get(prefixes[i]); ok {
+			if c := backend.ImportConflict(idx, e, route.Labels()); c != "" {
+				conflicts = append(conflicts, c)
+			}
+			continue
+		}
+		if err := r.validateImportEntry(ctx, idx, staged, prefixes[i], e); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("index %s/%s: %s is invalid: %s", idx.Namespace, idx.Name, e.ID, err.Error()))
+			continue
+		}
+		if err := staged.Add(table.NewRoute(prefixes[i], e.Labels, map[string]any{})); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("index %s/%s: %s is invalid: %s", idx.Namespace, idx.Name, e.ID, err.Error()))
+		}
+	}
+	return conflicts, nil
+}
+
+// validateImportEntry validates the prefix of the snapshot as a prefix claim
+// of its owner on the staged rib. An address of a network prefix is validated
+// as an address in the subnet of its parent.
+func (r *be) validateImportEntry(ctx context.Context, idx backend.IndexSnapshot, rib *table.RIB, pi netip.Prefix, e backend.EntrySnapshot) error {
+	kind := ipamv1alpha1.GetPrefixKindFromString(e.Labels[resourcev1alpha1.NephioPrefixKindKey])
+	if kind == ipamv1alpha1.PrefixKindUnknown {
+		return fmt.Errorf("unknown prefix kind %q", e.Labels[resourcev1alpha1.NephioPrefixKindKey])
+	}
+	prefix := pi
+	var createPrefix *bool
+	if !iputil.NewPrefixInfo(pi).IsAddressPrefix() {
+		createPrefix = pointer.Bool(true)
+	} else if kind == ipamv1alpha1.PrefixKindNetwork {
+		if parents := rib.Parents(pi); len(parents) > 0 {
+			prefix = netip.PrefixFrom(pi.Addr(), findParent(parents).Prefix().Bits())
+		}
+	}
+	claim := ipamv1alpha1.BuildIPClaim(
+		metav1.ObjectMeta{
+			Namespace: e.Labels[resourcev1alpha1.NephioNsnNamespaceKey],
+			Name:      e.Labels[resourcev1alpha1.NephioNsnNameKey],
+		},
+		ipamv1alpha1.IPClaimSpec{
+			Kind:            kind,
+			NetworkInstance: corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name},
+			Prefix:          pointer.String(prefix.String()),
+			CreatePrefix:    createPrefix,
+			ClaimLabels: resourcev1alpha1.ClaimLabels{
+				UserDefinedLabels: resourcev1alpha1.UserDefinedLabels{Labels: e.Labels},
+			},
+		},
+		ipamv1alpha1.IPClaimStatus{},
+	)
+	rt, err := r.runtimes.Import(claim, rib)
+	if err != nil {
+		return err
+	}
+	return rt.Validate(ctx)
+}
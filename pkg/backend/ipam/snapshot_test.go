@@ -0,0 +1,165 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var niOwnerGvk = meta.GVKToString(ipamv1alpha1.NetworkInstanceGroupVersionKind)
+
+// newSnapshotBackend returns a backend with the network instance default/a
+// and the prefixes claimed in the network instance
+func newSnapshotBackend(t *testing.T, specs ...ipamv1alpha1.IPClaimSpec) backend.Backend {
+	ctx := context.Background()
+	be, err := New(nil, backend.StorageConfig{Type: backend.StorageTypeMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ni := ipamv1alpha1.BuildNetworkInstance(metav1.ObjectMeta{Namespace: "default", Name: "a"}, ipamv1alpha1.NetworkInstanceSpec{}, ipamv1alpha1.NetworkInstanceStatus{})
+	b, err := json.Marshal(ni)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := be.CreateIndex(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	for _, spec := range specs {
+		spec.NetworkInstance = corev1.ObjectReference{Namespace: "default", Name: "a"}
+		labels := map[string]string{}
+		if spec.Kind == ipamv1alpha1.PrefixKindAggregate {
+			// aggregates are claimed on behalf of the network instance
+			labels[resourcev1alpha1.NephioOwnerGvkKey] = niOwnerGvk
+		}
+		req := ipamv1alpha1.BuildIPClaim(metav1.ObjectMeta{Namespace: "default", Name: string(spec.Kind), Labels: labels}, spec, ipamv1alpha1.IPClaimStatus{})
+		req.AddOwnerLabelsToCR()
+		b, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := be.Claim(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return be
+}
+
+var (
+	aggregateSpec = ipamv1alpha1.IPClaimSpec{
+		Kind:         ipamv1alpha1.PrefixKindAggregate,
+		Prefix:       pointer.String("10.0.0.0/8"),
+		PrefixLength: util.PointerUint8(8),
+		CreatePrefix: pointer.Bool(true),
+	}
+	networkSpec = ipamv1alpha1.IPClaimSpec{
+		Kind:         ipamv1alpha1.PrefixKindNetwork,
+		Prefix:       pointer.String("10.0.0.1/24"),
+		CreatePrefix: pointer.Bool(true),
+	}
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	indices, err := newSnapshotBackend(t, aggregateSpec, networkSpec).Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 1 || len(indices[0].Entries) != 5 {
+		t.Fatalf("want the aggregate and the expanded network prefix, got %v", indices)
+	}
+
+	// the snapshot is imported in a backend without indices
+	restored, err := New(nil, backend.StorageConfig{Type: backend.StorageTypeMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var updated table.Routes
+	for _, ownerGvk := range []string{niOwnerGvk, ipamv1alpha1.IPClaimKindGVKString} {
+		restored.AddWatch(resourcev1alpha1.NephioOwnerGvkKey, ownerGvk, func(routes table.Routes, statusCode resourcepb.StatusCode) {
+			updated = append(updated, routes...)
+		})
+	}
+	for _, dryRun := range []bool{true, false} {
+		conflicts, err := restored.Import(ctx, indices, dryRun)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conflicts) != 0 {
+			t.Fatalf("dryRun %t: want no conflicts, got %v", dryRun, conflicts)
+		}
+	}
+	got, err := restored.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got[0].Entries, indices[0].Entries) {
+		t.Errorf("want the imported prefixes %v, got %v", indices[0].Entries, got[0].Entries)
+	}
+	if len(updated) != len(indices[0].Entries) {
+		t.Errorf("want the owners informed of %d imported prefixes, got %v", len(indices[0].Entries), updated)
+	}
+}
+
+func TestSnapshotInvalid(t *testing.T) {
+	ctx := context.Background()
+	indices, err := newSnapshotBackend(t, aggregateSpec, networkSpec).Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a network prefix requires an aggregate prefix
+	entries := []backend.EntrySnapshot{}
+	for _, e := range indices[0].Entries {
+		if e.Labels[resourcev1alpha1.NephioPrefixKindKey] != string(ipamv1alpha1.PrefixKindAggregate) {
+			entries = append(entries, e)
+		}
+	}
+	indices[0].Entries = entries
+
+	restored, err := New(nil, backend.StorageConfig{Type: backend.StorageTypeMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicts, err := restored.Import(ctx, indices, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) == 0 || !strings.Contains(conflicts[0], "10.0.0.0/24 is invalid") {
+		t.Errorf("want the network prefix without aggregate rejected, got %v", conflicts)
+	}
+	got, err := restored.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("want nothing applied, got %v", got)
+	}
+}
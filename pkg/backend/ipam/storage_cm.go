@@ -42,9 +42,10 @@ type Storage interface {
 }
 
 type storageConfig struct {
-	client   client.Client
-	cache    backend.Cache[*table.RIB]
-	runtimes Runtimes
+	client    client.Client
+	namespace string
+	cache     backend.Cache[*table.RIB]
+	runtimes  Runtimes
 }
 
 func newCMStorage(cfg *storageConfig) (Storage, error) {
@@ -59,6 +60,7 @@ func newCMStorage(cfg *storageConfig) (Storage, error) {
 		GetData:     r.GetData,
 		RestoreData: r.RestoreData,
 		Prefix:      "ipam",
+		Namespace:   cfg.namespace,
 	})
 	if err != nil {
 		return nil, err
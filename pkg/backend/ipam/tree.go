@@ -0,0 +1,178 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"math/big"
+	"net/netip"
+	"sort"
+
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Tree is the tree of the prefixes of a network instance
+type Tree struct {
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	Prefixes  []*TreeNode `json:"prefixes,omitempty"`
+}
+
+// TreeNode is a prefix with the prefixes nested in it; free is the number of
+// addresses of the prefix that are not covered by its children and the
+// utilization is the fraction of the prefix that is covered by its children
+type TreeNode struct {
+	Prefix      string                  `json:"prefix"`
+	Kind        string                  `json:"kind,omitempty"`
+	Owner       *corev1.ObjectReference `json:"owner,omitempty"`
+	Labels      map[string]string       `json:"labels,omitempty"`
+	Free        string                  `json:"free"`
+	Utilization float64                 `json:"utilization"`
+	Children    []*TreeNode             `json:"children,omitempty"`
+
+	prefix netip.Prefix
+}
+
+// Tree returns the prefixes of the network instance as a tree
+func (r *be) Tree(ctx context.Context, ref corev1.ObjectReference, root string, depth int) (any, error) {
+	cacheID := resourcev1alpha1.GetCacheID(ref)
+	rib, err := r.cache.Get(cacheID, false)
+	if err != nil {
+		return nil, err
+	}
+	return buildTree(cacheID, rib.GetTable(), root, depth)
+}
+
+// buildTree returns the tree of the routes, the parent of a prefix is the
+// most specific prefix that contains it
+func buildTree(ref corev1.ObjectReference, routes table.Routes, root string, depth int) (*Tree, error) {
+	nodes := make([]*TreeNode, 0, len(routes))
+	for _, route := range routes {
+		nodes = append(nodes, newTreeNode(route))
+	}
+	// a parent is sorted before the prefixes it contains
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].prefix.Addr() != nodes[j].prefix.Addr() {
+			return nodes[i].prefix.Addr().Less(nodes[j].prefix.Addr())
+		}
+		return nodes[i].prefix.Bits() < nodes[j].prefix.Bits()
+	})
+
+	t := &Tree{Namespace: ref.Namespace, Name: ref.Name}
+	// the stack holds the parents of the current prefix
+	stack := []*TreeNode{}
+	for _, n := range nodes {
+		for len(stack) > 0 && !contains(stack[len(stack)-1].prefix, n.prefix) {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			t.Prefixes = append(t.Prefixes, n)
+		} else {
+			p := stack[len(stack)-1]
+			p.Children = append(p.Children, n)
+		}
+		stack = append(stack, n)
+	}
+	for _, n := range t.Prefixes {
+		n.setUtilization()
+	}
+
+	if root != "" {
+		rp, err := netip.ParsePrefix(root)
+		if err != nil {
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid root prefix %s: %s", root, err.Error())
+		}
+		t.Prefixes = subTrees(t.Prefixes, rp.Masked())
+	}
+	if depth > 0 {
+		prune(t.Prefixes, depth)
+	}
+	return t, nil
+}
+
+func newTreeNode(route table.Route) *TreeNode {
+	l := route.Labels()
+	n := &TreeNode{
+		Prefix: route.Prefix().String(),
+		Kind:   l[resourcev1alpha1.NephioPrefixKindKey],
+		Labels: l,
+		prefix: route.Prefix(),
+	}
+	if l[resourcev1alpha1.NephioOwnerNsnNameKey] != "" {
+		gvk := meta.StringToGVK(l[resourcev1alpha1.NephioOwnerGvkKey])
+		n.Owner = &corev1.ObjectReference{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  l[resourcev1alpha1.NephioOwnerNsnNamespaceKey],
+			Name:       l[resourcev1alpha1.NephioOwnerNsnNameKey],
+		}
+	}
+	return n
+}
+
+// setUtilization sets the free addresses and the utilization of the prefix
+// and of the prefixes nested in it
+func (r *TreeNode) setUtilization() {
+	size := addresses(r.prefix)
+	used := new(big.Int)
+	for _, c := range r.Children {
+		c.setUtilization()
+		used.Add(used, addresses(c.prefix))
+	}
+	r.Free = new(big.Int).Sub(size, used).String()
+	r.Utilization, _ = new(big.Rat).SetFrac(used, size).Float64()
+}
+
+// subTrees returns the outermost prefixes within the root prefix
+func subTrees(nodes []*TreeNode, root netip.Prefix) []*TreeNode {
+	trees := []*TreeNode{}
+	for _, n := range nodes {
+		switch {
+		case contains(root, n.prefix) || root == n.prefix:
+			trees = append(trees, n)
+		case contains(n.prefix, root):
+			trees = append(trees, subTrees(n.Children, root)...)
+		}
+	}
+	return trees
+}
+
+// prune removes the prefixes that are nested deeper than depth levels
+func prune(nodes []*TreeNode, depth int) {
+	for _, n := range nodes {
+		if depth <= 1 {
+			n.Children = nil
+			continue
+		}
+		prune(n.Children, depth-1)
+	}
+}
+
+// contains returns true if the prefix p contains the more specific prefix c
+func contains(p, c netip.Prefix) bool {
+	return p.Bits() < c.Bits() && p.Contains(c.Addr())
+}
+
+// addresses returns the number of addresses of the prefix
+func addresses(p netip.Prefix) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(p.Addr().BitLen()-p.Bits()))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestBuildTree(t *testing.T) {
+	rib := table.NewRIB()
+	for p, kind := range map[string]string{
+		"10.0.0.0/8":      "aggregate",
+		"10.0.0.0/24":     "network",
+		"10.0.0.0/32":     "network",
+		"10.0.0.1/32":     "network",
+		"10.0.0.255/32":   "network",
+		"10.1.0.0/16":     "pool",
+		"192.168.0.0/16":  "aggregate",
+		"2001:db8::/32":   "aggregate",
+		"2001:db8:1::/48": "pool",
+	} {
+		l := labels.Set{resourcev1alpha1.NephioPrefixKindKey: kind}
+		if p == "10.0.0.1/32" {
+			l[resourcev1alpha1.NephioOwnerGvkKey] = "IPClaim.v1alpha1.ipam.resource.nephio.org"
+			l[resourcev1alpha1.NephioOwnerNsnNamespaceKey] = "default"
+			l[resourcev1alpha1.NephioOwnerNsnNameKey] = "gw"
+		}
+		if err := rib.Add(table.NewRoute(netip.MustParsePrefix(p), l, map[string]any{})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ref := corev1.ObjectReference{Namespace: "default", Name: "vpc"}
+
+	tree, err := buildTree(ref, rib.GetTable(), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prefixes(tree.Prefixes); got != "10.0.0.0/8[10.0.0.0/24[10.0.0.0/32 10.0.0.1/32 10.0.0.255/32] 10.1.0.0/16] 192.168.0.0/16 2001:db8::/32[2001:db8:1::/48]" {
+		t.Errorf("tree: got %s", got)
+	}
+	agg := tree.Prefixes[0]
+	if agg.Free != "16711424" {
+		t.Errorf("free of %s: want 16711424, got %s", agg.Prefix, agg.Free)
+	}
+	network := agg.Children[0]
+	if network.Free != "253" || network.Utilization != 3.0/256 {
+		t.Errorf("utilization of %s: got free %s utilization %f", network.Prefix, network.Free, network.Utilization)
+	}
+	if owner := network.Children[1].Owner; owner == nil || owner.Kind != "IPClaim" || owner.Name != "gw" {
+		t.Errorf("owner of %s: got %v", network.Children[1].Prefix, owner)
+	}
+	if v6 := tree.Prefixes[2]; v6.Free != "79226953588444722964369244160" {
+		t.Errorf("free of %s: got %s", v6.Prefix, v6.Free)
+	}
+
+	tree, err = buildTree(ref, rib.GetTable(), "10.0.0.0/16", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prefixes(tree.Prefixes); got != "10.0.0.0/24" {
+		t.Errorf("subtree: got %s", got)
+	}
+	if tree.Prefixes[0].Free != "253" {
+		t.Errorf("free of pruned %s: got %s", tree.Prefixes[0].Prefix, tree.Prefixes[0].Free)
+	}
+
+	if _, err := buildTree(ref, rib.GetTable(), "10.0.0.0", 0); backend.GetErrorCode(err) != resourcepb.ErrorCode_ValidationFailed {
+		t.Errorf("invalid root: want %v, got %v", resourcepb.ErrorCode_ValidationFailed, err)
+	}
+}
+
+func prefixes(nodes []*TreeNode) string {
+	s := ""
+	for i, n := range nodes {
+		if i > 0 {
+			s += " "
+		}
+		s += n.Prefix
+		if len(n.Children) > 0 {
+			s += "[" + prefixes(n.Children) + "]"
+		}
+	}
+	return s
+}
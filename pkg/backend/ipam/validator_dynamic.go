@@ -50,14 +50,14 @@ type claimvalidator struct {
 	l     logr.Logger
 }
 
-func (r *claimvalidator) Validate(ctx context.Context) (string, error) {
+func (r *claimvalidator) Validate(ctx context.Context) error {
 	r.l = log.FromContext(ctx).WithValues("prefixkind", r.claim.Spec.Kind, "cr", r.claim.GetGenericNamespacedName())
 	r.l.Info("validate claim without prefix")
 
 	// validate input
 	if msg := r.fnc.validateInputFn(r.claim, nil); msg != "" {
-		return msg, nil
+		return newValidationError(msg)
 	}
 
-	return "", nil
+	return nil
 }
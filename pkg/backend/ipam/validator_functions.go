@@ -25,7 +25,23 @@ import (
 	"github.com/nokia/k8s-ipam/pkg/iputil"
 )
 
-func validateInput(claim *ipamv1alpha1.IPClaim, pi *iputil.Prefix) string {
+// ValidateClaim validates the input of the claim without consulting the rib
+// e.g. it is used by the admission webhooks to reject invalid claims before
+// they reach the backend
+func ValidateClaim(claim *ipamv1alpha1.IPClaim) string {
+	var pi *iputil.Prefix
+	if claim.Spec.Prefix != nil {
+		var err error
+		pi, err = iputil.New(*claim.Spec.Prefix)
+		if err != nil {
+			return fmt.Sprintf("invalid prefix %s, err: %s", *claim.Spec.Prefix, err.Error())
+		}
+	}
+	return ValidateInput(claim, pi)
+}
+
+// ValidateInput validates the input of the claim, pi is nil for a dynamic claim
+func ValidateInput(claim *ipamv1alpha1.IPClaim, pi *iputil.Prefix) string {
 	if pi == nil {
 		if claim.Spec.Kind == ipamv1alpha1.PrefixKindAggregate {
 			return fmt.Sprintf("a dynamic prefix claim is not supported for: %s", claim.Spec.Kind)
@@ -74,14 +90,15 @@ func validateInput(claim *ipamv1alpha1.IPClaim, pi *iputil.Prefix) string {
 	return ""
 }
 
-func validatePrefixOwner(route table.Route, claim *ipamv1alpha1.IPClaim) string {
+func validatePrefixOwner(route table.Route, claim *ipamv1alpha1.IPClaim) error {
 	if route.Labels()[resourcev1alpha1.NephioNsnNamespaceKey] != claim.GetUserDefinedLabels()[resourcev1alpha1.NephioNsnNamespaceKey] ||
 		route.Labels()[resourcev1alpha1.NephioNsnNameKey] != claim.GetUserDefinedLabels()[resourcev1alpha1.NephioNsnNameKey] ||
 		route.Labels()[resourcev1alpha1.NephioOwnerNsnNamespaceKey] != claim.GetUserDefinedLabels()[resourcev1alpha1.NephioOwnerNsnNamespaceKey] ||
 		route.Labels()[resourcev1alpha1.NephioOwnerNsnNameKey] != claim.GetUserDefinedLabels()[resourcev1alpha1.NephioOwnerNsnNameKey] ||
 		route.Labels()[resourcev1alpha1.NephioOwnerGvkKey] != claim.GetUserDefinedLabels()[resourcev1alpha1.NephioOwnerGvkKey] {
-		return fmt.Sprintf("%s by owner gvk %s, owner nsn %s/%s with nsn %s/%s",
-			errValidateDuplicatePrefix,
+		return fmt.Errorf("%w: %w by owner gvk %s, owner nsn %s/%s with nsn %s/%s",
+			errValidation,
+			errDuplicatePrefix,
 			route.Labels()[resourcev1alpha1.NephioOwnerGvkKey],
 			route.Labels()[resourcev1alpha1.NephioOwnerNsnNamespaceKey],
 			route.Labels()[resourcev1alpha1.NephioOwnerNsnNameKey],
@@ -89,7 +106,7 @@ func validatePrefixOwner(route table.Route, claim *ipamv1alpha1.IPClaim) string
 			route.Labels()[resourcev1alpha1.NephioNsnNameKey])
 	}
 
-	return ""
+	return nil
 }
 
 func validateChildrenExist(route table.Route, prefixKind ipamv1alpha1.PrefixKind) string {
@@ -29,7 +29,7 @@ import (
 )
 
 type Validator interface {
-	Validate(ctx context.Context) (string, error)
+	Validate(ctx context.Context) error
 }
 
 type validateInputFn func(claim *ipamv1alpha1.IPClaim, pi *iputil.Prefix) string
@@ -68,7 +68,7 @@ type prefixvalidator struct {
 	l     logr.Logger
 }
 
-func (r *prefixvalidator) Validate(ctx context.Context) (string, error) {
+func (r *prefixvalidator) Validate(ctx context.Context) error {
 	r.l = log.FromContext(ctx).WithValues("prefixkind", r.claim.Spec.Kind, "name", r.claim.GetName(), "prefix", r.claim.Spec.Prefix)
 	r.l.Info("validate")
 
@@ -77,7 +77,7 @@ func (r *prefixvalidator) Validate(ctx context.Context) (string, error) {
 	// validate input
 	if r.fnc.validateInputFn != nil {
 		if msg := r.fnc.validateInputFn(r.claim, r.pi); msg != "" {
-			return msg, nil
+			return newValidationError(msg)
 		}
 	}
 
@@ -100,11 +100,11 @@ func (r *prefixvalidator) Validate(ctx context.Context) (string, error) {
 		if r.claim.Spec.CreatePrefix != nil {
 			// this is a create/claim prefix:
 			// ip prefix or a dynamic claim with a prefix
-			if msg := validatePrefixOwner(route, r.claim); msg != "" {
-				return msg, nil
+			if err := validatePrefixOwner(route, r.claim); err != nil {
+				return err
 			}
 			// all good prefix exists and has the proper attributes
-			return "", nil
+			return nil
 		}
 		// in case of a network prefix we need to turn this in an address and validate again
 		// since the initial validation is for the network subnet and not for the individual address
@@ -112,20 +112,20 @@ func (r *prefixvalidator) Validate(ctx context.Context) (string, error) {
 		if r.claim.Spec.Kind == ipamv1alpha1.PrefixKindNetwork {
 			// if parent is prefixkind network
 			if route.Labels()[resourcev1alpha1.NephioPrefixKindKey] != string(ipamv1alpha1.PrefixKindNetwork) {
-				return fmt.Sprintf("an address based prefix kind can only have parent prefix kind, got: %s", route.Labels()[resourcev1alpha1.NephioPrefixKindKey]), nil
+				return newValidationError(fmt.Sprintf("an address based prefix kind can only have parent prefix kind, got: %s", route.Labels()[resourcev1alpha1.NephioPrefixKindKey]))
 			}
 			route, ok = dryrunRib.Get(r.pi.GetIPAddressPrefix())
 			if !ok {
 				// we can return since we know there is a parent
 				// the child is a /32 or /128 which cannot have children
-				return "", nil
+				return nil
 			}
 		}
-		if msg := validatePrefixOwner(route, r.claim); msg != "" {
-			return msg, nil
+		if err := validatePrefixOwner(route, r.claim); err != nil {
+			return err
 		}
 		// finish all good
-		return "", nil
+		return nil
 	}
 	// Route does not exist
 	// add dummy route in dry run rib, this is the subnet route
@@ -139,20 +139,20 @@ func (r *prefixvalidator) Validate(ctx context.Context) (string, error) {
 
 	if err := dryrunRib.Add(route); err != nil {
 		r.l.Error(err, "cannot add route", "route", route)
-		return "", err
+		return err
 	}
 
 	// get the route again and check for children
 	route, ok = dryrunRib.Get(r.pi.GetIPSubnet())
 	if !ok {
-		return "route just added, but a new get does not find it", nil
+		return fmt.Errorf("route just added, but a new get does not find it")
 	}
 	// check for children
 	routes := route.Children(dryrunRib)
 	if len(routes) > 0 {
 		r.l.Info("got children", "routes", routes)
 		if msg := r.fnc.validateChildrenExistFn(routes[0], r.claim.Spec.Kind); msg != "" {
-			return msg, nil
+			return newValidationError(msg)
 		}
 	}
 	// get parents
@@ -160,16 +160,16 @@ func (r *prefixvalidator) Validate(ctx context.Context) (string, error) {
 	routes = route.Parents(dryrunRib)
 	if len(routes) == 0 {
 		if msg := r.fnc.validateNoParentExistFn(r.claim.Spec.Kind, r.claim.GetUserDefinedLabels()[resourcev1alpha1.NephioOwnerGvkKey]); msg != "" {
-			return msg, nil
+			return newValidationError(msg)
 		}
-		return "", nil
+		return nil
 	}
 
 	parentRoute := findParent(routes)
 	if msg := r.fnc.validateParentExistFn(parentRoute, r.claim, r.pi); msg != "" {
-		return msg, nil
+		return newValidationError(msg)
 	}
-	return "", nil
+	return nil
 }
 
 func findParent(routes table.Routes) table.Route {
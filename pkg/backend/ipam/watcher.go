@@ -20,7 +20,6 @@ import (
 	"context"
 	"sync"
 
-	"github.com/go-logr/logr"
 	"github.com/hansthienpondt/nipam/pkg/table"
 	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
@@ -50,7 +49,6 @@ type watcher struct {
 	m sync.RWMutex
 	// 1st key is ownerGvk key, 2nd key is ownerGVK
 	d map[string]map[string]backend.CallbackFn
-	l logr.Logger
 }
 
 func (r *watcher) addWatch(ownerGvkKey, ownerGvk string, fn backend.CallbackFn) {
@@ -76,9 +74,7 @@ func (r *watcher) deleteWatch(ownerGvkKey, ownerGvk string) {
 }
 
 func (r *watcher) handleUpdate(ctx context.Context, routes table.Routes, statusCode resourcepb.StatusCode) {
-	r.l = log.FromContext(ctx)
-	r.m.RLock()
-	defer r.m.RUnlock()
+	log := log.FromContext(ctx)
 
 	// build a new updatemap based on the values
 	// we receive routes but we have to build a map based on ownerGVK Values
@@ -87,6 +83,9 @@ func (r *watcher) handleUpdate(ctx context.Context, routes table.Routes, statusC
 	// walk through all the routes
 	// first check if the ownerGVK key is present
 	// if so check the value and map them to the proper output map
+	// the callbacks are called after releasing the lock since they take the
+	// locks of the watchers which in turn add and delete watches
+	r.m.RLock()
 	for _, route := range routes {
 		for ownerGvkKey, values := range r.d {
 			if ownerGvkValue, ok := route.Labels()[ownerGvkKey]; ok {
@@ -106,10 +105,11 @@ func (r *watcher) handleUpdate(ctx context.Context, routes table.Routes, statusC
 			}
 		}
 	}
+	r.m.RUnlock()
 
 	// call the callback fn using the routes and the original status code
 	for ownerGvk, updateContext := range updateMap {
-		r.l.Info("watch event", "ownerGvk", ownerGvk, "Routes", updateContext.routes)
+		log.Info("watch event", "ownerGvk", ownerGvk, "Routes", updateContext.routes)
 		updateContext.callBackFn(updateContext.routes, statusCode)
 	}
 }
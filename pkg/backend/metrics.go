@@ -0,0 +1,37 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	storageErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "backend_storage_errors_total",
+		Help: "Number of failed restores and saves of the backend indices",
+	}, []string{"storage", "operation"})
+	storageUnsaved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "backend_storage_unsaved_indices",
+		Help: "Number of backend indices with claims that are not yet saved, the save is retried in the background",
+	}, []string{"storage"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(storageErrors, storageUnsaved)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"sync"
+
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// QuotaUsageFn returns the amount of the resource held in the index by the
+// entries that match the selector
+type QuotaUsageFn func(index corev1.ObjectReference, resource quotav1alpha1.QuotaResource, selector labels.Selector) (int64, error)
+
+// QuotaUsageGetter is implemented by the backends that enforce quotas, it
+// reports the usage of a quota
+type QuotaUsageGetter interface {
+	GetQuotaUsage(index corev1.ObjectReference, resource quotav1alpha1.QuotaResource, selector labels.Selector) (int64, error)
+}
+
+// QuotaRequest is a new claim requesting an amount of a resource in an index
+type QuotaRequest struct {
+	Namespace string
+	Labels    map[string]string
+	Index     corev1.ObjectReference
+	Resource  quotav1alpha1.QuotaResource
+	Count     int64
+}
+
+// Quotas enforces the claim quotas of the namespaces
+type Quotas interface {
+	// Lock serializes the quota checks and the claims that follow them in
+	// the index, such that concurrent claims cannot exceed a quota together.
+	// The quotas limit the usage per index, the claims of other indices are
+	// not serialized.
+	Lock(index corev1.ObjectReference)
+	Unlock(index corev1.ObjectReference)
+	// Check returns a QuotaExceeded error if the request exceeds a quota
+	Check(ctx context.Context, req QuotaRequest, usage QuotaUsageFn) error
+}
+
+// NewQuotas returns the quotas of the ClaimQuota resources, no quotas are
+// enforced without a client
+func NewQuotas(c client.Client) Quotas {
+	return &quotas{
+		c:     c,
+		locks: map[corev1.ObjectReference]*indexLock{},
+	}
+}
+
+type quotas struct {
+	c client.Client
+
+	m sync.Mutex
+	// locks holds the lock per index, the lock of an index is deleted when
+	// it is not held or waited for
+	locks map[corev1.ObjectReference]*indexLock
+}
+
+type indexLock struct {
+	sync.Mutex
+	refs int
+}
+
+func (r *quotas) Lock(index corev1.ObjectReference) {
+	r.m.Lock()
+	l, ok := r.locks[index]
+	if !ok {
+		l = &indexLock{}
+		r.locks[index] = l
+	}
+	l.refs++
+	r.m.Unlock()
+	l.Lock()
+}
+
+func (r *quotas) Unlock(index corev1.ObjectReference) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	l := r.locks[index]
+	l.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(r.locks, index)
+	}
+}
+
+func (r *quotas) Check(ctx context.Context, req QuotaRequest, usage QuotaUsageFn) error {
+	if r.c == nil || req.Count <= 0 {
+		return nil
+	}
+	quotas := &quotav1alpha1.ClaimQuotaList{}
+	if err := r.c.List(ctx, quotas, client.InNamespace(req.Namespace)); err != nil {
+		// no quotas are enforced if the crd is not installed
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	for _, q := range quotas.Items {
+		ok, err := q.Selects(req.Namespace, req.Labels)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		for _, l := range q.GetLimits(req.Index, req.Resource) {
+			selector, err := q.GetUsageSelector()
+			if err != nil {
+				return err
+			}
+			used, err := usage(req.Index, req.Resource, selector)
+			if err != nil {
+				return err
+			}
+			if used+req.Count > l.Max {
+				return NewError(resourcepb.ErrorCode_QuotaExceeded, "quota %s exceeded: %d %s requested in %s, %d of %d in use",
+					q.GetName(), req.Count, req.Resource, req.Index.Name, used, l.Max)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestQuotaCheck(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := quotav1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	index := corev1.ObjectReference{Namespace: "default", Name: "vpc-1"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&quotav1alpha1.ClaimQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "gold"},
+		Spec: quotav1alpha1.ClaimQuotaSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}},
+			Limits: []quotav1alpha1.QuotaLimit{
+				{Index: index, Resource: quotav1alpha1.QuotaResourceVLANs, Max: 10},
+			},
+		},
+	}).Build()
+	q := NewQuotas(c)
+
+	// usage reports 8 vlans held by the claims selected by the quota
+	usage := func(i corev1.ObjectReference, resource quotav1alpha1.QuotaResource, selector labels.Selector) (int64, error) {
+		if !selector.Matches(labels.Set{"tier": "gold", resourcev1alpha1.NephioNsnNamespaceKey: "tenant-a"}) {
+			t.Errorf("unexpected usage selector %s", selector)
+		}
+		return 8, nil
+	}
+
+	cases := map[string]struct {
+		req  QuotaRequest
+		code resourcepb.ErrorCode
+	}{
+		"WithinQuota": {
+			req:  QuotaRequest{Namespace: "tenant-a", Labels: map[string]string{"tier": "gold"}, Index: index, Resource: quotav1alpha1.QuotaResourceVLANs, Count: 2},
+			code: resourcepb.ErrorCode_NoError,
+		},
+		"ExceedsQuota": {
+			req:  QuotaRequest{Namespace: "tenant-a", Labels: map[string]string{"tier": "gold"}, Index: index, Resource: quotav1alpha1.QuotaResourceVLANs, Count: 3},
+			code: resourcepb.ErrorCode_QuotaExceeded,
+		},
+		"NotSelected": {
+			req:  QuotaRequest{Namespace: "tenant-a", Labels: map[string]string{"tier": "silver"}, Index: index, Resource: quotav1alpha1.QuotaResourceVLANs, Count: 3},
+			code: resourcepb.ErrorCode_NoError,
+		},
+		"OtherNamespace": {
+			req:  QuotaRequest{Namespace: "tenant-b", Labels: map[string]string{"tier": "gold"}, Index: index, Resource: quotav1alpha1.QuotaResourceVLANs, Count: 3},
+			code: resourcepb.ErrorCode_NoError,
+		},
+		"OtherIndex": {
+			req:  QuotaRequest{Namespace: "tenant-a", Labels: map[string]string{"tier": "gold"}, Index: corev1.ObjectReference{Name: "vpc-2"}, Resource: quotav1alpha1.QuotaResourceVLANs, Count: 3},
+			code: resourcepb.ErrorCode_NoError,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := q.Check(context.Background(), tc.req, usage)
+			if GetErrorCode(err) != tc.code {
+				t.Errorf("want %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+func TestQuotaCheckWithoutClient(t *testing.T) {
+	q := NewQuotas(nil)
+	err := q.Check(context.Background(), QuotaRequest{Count: 1}, func(corev1.ObjectReference, quotav1alpha1.QuotaResource, labels.Selector) (int64, error) {
+		t.Errorf("usage is not expected without quotas")
+		return 0, nil
+	})
+	if err != nil {
+		t.Errorf("want no error, got %v", err)
+	}
+}
+
+func TestQuotaLock(t *testing.T) {
+	q := NewQuotas(nil)
+	a := corev1.ObjectReference{Namespace: "default", Name: "a"}
+	b := corev1.ObjectReference{Namespace: "default", Name: "b"}
+
+	q.Lock(a)
+	// the claims of another index are not serialized
+	locked := make(chan struct{})
+	go func() {
+		q.Lock(b)
+		q.Unlock(b)
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("lock of index b blocked by the lock of index a")
+	}
+
+	// the claims of the same index are serialized
+	locked = make(chan struct{})
+	go func() {
+		q.Lock(a)
+		q.Unlock(a)
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatalf("lock of index a not serialized")
+	case <-time.After(50 * time.Millisecond):
+	}
+	q.Unlock(a)
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("lock of index a not released")
+	}
+
+	qs := q.(*quotas)
+	qs.m.Lock()
+	defer qs.m.Unlock()
+	if n := len(qs.locks); n != 0 {
+		t.Errorf("want the locks of the indices deleted, got %d", n)
+	}
+}
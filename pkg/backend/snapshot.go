@@ -0,0 +1,157 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SnapshotAPIVersion is the version of the snapshot format
+	SnapshotAPIVersion = "snapshot.resource.nephio.org/v1alpha1"
+	// SnapshotKind is the kind of the snapshot format
+	SnapshotKind = "ResourceSnapshot"
+)
+
+// Snapshot is the state of the backends, it holds the indices with the
+// entries claimed in every index and the labels of the entries that identify
+// the owner of the claim
+type Snapshot struct {
+	APIVersion        string            `json:"apiVersion"`
+	Kind              string            `json:"kind"`
+	CreationTimestamp metav1.Time       `json:"creationTimestamp,omitempty"`
+	Backends          []BackendSnapshot `json:"backends,omitempty"`
+}
+
+// BackendSnapshot is the state of a backend identified by the group version
+// of its resources
+type BackendSnapshot struct {
+	GroupVersion string          `json:"groupVersion"`
+	Indices      []IndexSnapshot `json:"indices,omitempty"`
+}
+
+// IndexSnapshot is the state of an index, the index resource is used to
+// create the index upon import
+type IndexSnapshot struct {
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Index     json.RawMessage `json:"index"`
+	Entries   []EntrySnapshot `json:"entries,omitempty"`
+}
+
+// EntrySnapshot is an entry of an index, the id is the prefix, vlan or vxlan
+type EntrySnapshot struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// NewSnapshot returns a snapshot of the backends
+func NewSnapshot(backends []BackendSnapshot) *Snapshot {
+	return &Snapshot{
+		APIVersion:        SnapshotAPIVersion,
+		Kind:              SnapshotKind,
+		CreationTimestamp: metav1.Now(),
+		Backends:          backends,
+	}
+}
+
+// ParseSnapshot parses a snapshot in yaml or json and validates the version
+// and kind of the snapshot
+func ParseSnapshot(b []byte) (*Snapshot, error) {
+	s := &Snapshot{}
+	if err := yaml.Unmarshal(b, s); err != nil {
+		return nil, NewError(resourcepb.ErrorCode_ValidationFailed, "cannot parse snapshot: %s", err.Error())
+	}
+	if s.APIVersion != SnapshotAPIVersion || s.Kind != SnapshotKind {
+		return nil, NewError(resourcepb.ErrorCode_ValidationFailed, "unsupported snapshot %s %s, expecting %s %s",
+			s.APIVersion, s.Kind, SnapshotAPIVersion, SnapshotKind)
+	}
+	for _, b := range s.Backends {
+		for _, idx := range b.Indices {
+			if idx.Name == "" || len(idx.Index) == 0 {
+				return nil, NewError(resourcepb.ErrorCode_ValidationFailed, "invalid snapshot, index of %s without name or resource", b.GroupVersion)
+			}
+		}
+	}
+	return s, nil
+}
+
+// ExportIndices returns the snapshot of the initialized instances of the cache
+// where entries returns the entries of an instance
+func ExportIndices[T1 any](c Cache[T1], entries func(T1) []EntrySnapshot) []IndexSnapshot {
+	instances := c.List()
+	indices := make([]IndexSnapshot, 0, len(instances))
+	for _, ci := range instances {
+		e := entries(ci.Instance)
+		sort.SliceStable(e, func(i, j int) bool { return e[i].ID < e[j].ID })
+		indices = append(indices, IndexSnapshot{
+			Namespace: ci.Ref.Namespace,
+			Name:      ci.Ref.Name,
+			Index:     ci.Index,
+			Entries:   e,
+		})
+	}
+	return indices
+}
+
+// ImportConflict returns a conflict if the entry of the snapshot is claimed
+// with other labels in the index, an empty string is returned otherwise
+func ImportConflict(idx IndexSnapshot, e EntrySnapshot, l labels.Set) string {
+	if labels.Equals(l, labels.Set(e.Labels)) {
+		return ""
+	}
+	return fmt.Sprintf("index %s/%s: %s is claimed with labels %q, snapshot labels %q",
+		idx.Namespace, idx.Name, e.ID, l.String(), labels.Set(e.Labels).String())
+}
+
+// LockIndices locks the quotas of the indices of the snapshot in a stable
+// order, such that concurrent imports do not deadlock. The returned function
+// unlocks the indices.
+func LockIndices(q Quotas, indices []IndexSnapshot) func() {
+	refs := make([]corev1.ObjectReference, 0, len(indices))
+	seen := map[corev1.ObjectReference]bool{}
+	for _, idx := range indices {
+		ref := resourcev1alpha1.GetCacheID(corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name})
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Namespace != refs[j].Namespace {
+			return refs[i].Namespace < refs[j].Namespace
+		}
+		return refs[i].Name < refs[j].Name
+	})
+	for _, ref := range refs {
+		q.Lock(ref)
+	}
+	return func() {
+		for i := len(refs) - 1; i >= 0; i-- {
+			q.Unlock(refs[i])
+		}
+	}
+}
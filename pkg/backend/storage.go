@@ -22,12 +22,35 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+type StorageType string
+
+const (
+	// StorageTypeConfigMap stores the indices in configmaps
+	StorageTypeConfigMap StorageType = "configmap"
+	// StorageTypeMemory keeps the indices in memory only, they are lost on
+	// a restart
+	StorageTypeMemory StorageType = "memory"
+)
+
+// StorageConfig selects the storage of the backend indices
+type StorageConfig struct {
+	// Type defaults to configmap
+	Type StorageType `json:"type,omitempty"`
+	// Namespace of the configmaps, defaults to the namespace of the index
+	Namespace string `json:"namespace,omitempty"`
+}
+
 type Storage[T1, T2 any] interface {
 	Restore(ctx context.Context, ref corev1.ObjectReference) error
 	// only used in configmap
 	SaveAll(ctx context.Context, ref corev1.ObjectReference) error
 	// Destroy removes the store db
 	Destroy(ctx context.Context, ref corev1.ObjectReference) error
+	// SaveIndex stores the index itself, such that the index can be restored
+	// without being created again by a client
+	SaveIndex(ctx context.Context, ref corev1.ObjectReference, index []byte) error
+	// ListIndices returns the stored indices
+	ListIndices(ctx context.Context) ([][]byte, error)
 
 	Get(ctx context.Context, claim T1) ([]T2, error)
 	Set(ctx context.Context, claim T1) error
@@ -49,6 +72,12 @@ func (r *nopStorage[T1, T2]) SaveAll(ctx context.Context, ref corev1.ObjectRefer
 func (r *nopStorage[T1, T2]) Destroy(ctx context.Context, ref corev1.ObjectReference) error {
 	return nil
 }
+func (r *nopStorage[T1, T2]) SaveIndex(ctx context.Context, ref corev1.ObjectReference, index []byte) error {
+	return nil
+}
+func (r *nopStorage[T1, T2]) ListIndices(ctx context.Context) ([][]byte, error) {
+	return nil, nil
+}
 func (r *nopStorage[T1, T2]) Get(ctx context.Context, claim T1) ([]T2, error) { return nil, nil }
 func (r *nopStorage[T1, T2]) Set(ctx context.Context, claim T1) error         { return nil }
 func (r *nopStorage[T1, T2]) Delete(ctx context.Context, claim T1) error      { return nil }
@@ -1,11 +1,17 @@
 package backend
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
-	"github.com/go-logr/logr"
-	"github.com/pkg/errors"
+	"github.com/nokia/k8s-ipam/pkg/election"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
+	perrors "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,7 +20,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-const ConfigMapKey = "data"
+const (
+	ConfigMapKey = "data"
+	// IndexKey holds the index of the configmap, the stored indices are
+	// restored when a backend becomes the leader
+	IndexKey = "index"
+	// BackendLabelKey selects the configmaps of a backend
+	BackendLabelKey = "resource.nephio.org/backend"
+
+	// saveRetryBaseDelay is the delay before the first retry of a failed save
+	saveRetryBaseDelay = time.Second
+	// saveRetryMaxDelay caps the delay between the retries of a failed save
+	saveRetryMaxDelay = time.Minute
+)
+
+// errNotLeader is returned when the storage is written after the leadership
+// the request is served under is lost
+var errNotLeader = NewError(resourcepb.ErrorCode_IndexNotReady, "not leader")
 
 type GetDataFn func(ctx context.Context, ref corev1.ObjectReference) ([]byte, error)
 type RestoreDataFn func(ctx context.Context, ref corev1.ObjectReference, cm *corev1.ConfigMap) error
@@ -24,6 +46,8 @@ type CMConfig struct {
 	GetData     GetDataFn
 	RestoreData RestoreDataFn
 	Prefix      string
+	// Namespace of the configmaps, defaults to the namespace of the index
+	Namespace string
 }
 
 func NewCMBackend[claim, entry any](cfg *CMConfig) (Storage[claim, entry], error) {
@@ -35,9 +59,13 @@ func NewCMBackend[claim, entry any](cfg *CMConfig) (Storage[claim, entry], error
 	}
 
 	return &cm[claim, entry]{
-		c:      cfg.Client,
-		cfg:    cfg,
-		prefix: cfg.Prefix,
+		c:          cfg.Client,
+		cfg:        cfg,
+		prefix:     cfg.Prefix,
+		ns:         cfg.Namespace,
+		retryDelay: saveRetryBaseDelay,
+		unsaved:    map[corev1.ObjectReference]struct{}{},
+		indices:    map[corev1.ObjectReference][]byte{},
 	}, nil
 }
 
@@ -45,83 +73,274 @@ type cm[claim, entry any] struct {
 	c      client.Client
 	cfg    *CMConfig
 	prefix string
-	l      logr.Logger
+	ns     string
+
+	// retryDelay is the delay before the first retry of a failed save
+	retryDelay time.Duration
+	m          sync.Mutex
+	// unsaved holds the indices whose claims failed to save, the save is
+	// retried in the background till it succeeds or the index is destroyed
+	unsaved map[corev1.ObjectReference]struct{}
+	// indices holds the last saved index of each index, such that an
+	// unchanged index is not saved again
+	indices map[corev1.ObjectReference][]byte
 }
 
 func (r *cm[claim, entry]) Restore(ctx context.Context, ref corev1.ObjectReference) error {
-	r.l = log.FromContext(ctx)
-	r.l.Info("restore", "indexRef", ref)
+	log := log.FromContext(ctx)
+	log.Info("restore", "indexRef", ref)
+	// the index is saved again, another leader might have changed it
+	r.m.Lock()
+	delete(r.indices, ref)
+	r.m.Unlock()
 
 	// if no client provided dont try to restore
 	if r.c == nil {
 		return nil
 	}
 
-	cm := buildConfigMap(ref, r.prefix)
-	if err := r.c.Get(ctx, types.NamespacedName{Name: r.prefix + "-" + ref.Name, Namespace: ref.Namespace}, cm); err != nil {
+	cm := r.buildConfigMap(ref)
+	if err := r.c.Get(ctx, client.ObjectKeyFromObject(cm), cm); err != nil {
 		if kerrors.IsNotFound(err) {
-			return errors.Wrap(r.c.Create(ctx, cm), "cannot create configmap")
+			return perrors.Wrap(r.c.Create(ctx, cm), "cannot create configmap")
 		}
+		log.Error(err, "cannot get configmap", "ref", ref)
+		storageErrors.WithLabelValues(r.prefix, "restore").Inc()
+		return perrors.Wrap(err, "cannot get configmap")
 	}
 
 	// call the callback Fn
 	if err := r.cfg.RestoreData(ctx, ref, cm); err != nil {
-		r.l.Error(err, "cannot resore data")
+		log.Error(err, "cannot resore data")
+		storageErrors.WithLabelValues(r.prefix, "restore").Inc()
 		return err
 	}
-
 	return nil
 }
 
 // only used in configmap
-func (r *cm[claim, entry]) SaveAll(ctx context.Context, ref corev1.ObjectReference) error {
-	r.l = log.FromContext(ctx)
-
+func (r *cm[claim, entry]) SaveAll(ctx context.Context, ref corev1.ObjectReference) (err error) {
 	// if no client provided dont try to save
 	if r.c == nil {
 		return nil
 	}
+	ctx, span := tracing.Start(ctx, "storage.SaveAll",
+		tracing.NamespaceKey.String(ref.Namespace),
+		tracing.NameKey.String(ref.Name),
+	)
+	defer func() { tracing.End(span, err) }()
 
-	cm := buildConfigMap(ref, r.prefix)
-
-	if err := r.c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: r.prefix + "-" + ref.Name}, cm); err != nil {
-		if kerrors.IsNotFound(err) {
-			return errors.Wrap(r.c.Create(ctx, cm), "cannot create configmap")
-		}
-		r.l.Error(err, "cannot get configmap", "ref", ref)
+	// the claims are restored by the new leader, a former leader overwriting
+	// them would lose the claims of the new leader
+	if !election.IsLeading(ctx) {
+		return errNotLeader
 	}
+	if err := r.save(ctx, ref); err != nil {
+		log.FromContext(ctx).Error(err, "cannot save index, retrying in the background", "ref", ref)
+		tracing.SetError(span, err)
+		storageErrors.WithLabelValues(r.prefix, "save").Inc()
+		// the claims are kept in memory and served while the save is retried
+		r.retrySave(ctx, ref)
+		return nil
+	}
+	r.setSaved(ref)
+	return nil
+}
 
+// save stores the claims of the index in the configmap
+func (r *cm[claim, entry]) save(ctx context.Context, ref corev1.ObjectReference) error {
 	// call the callback Fn
 	b, err := r.cfg.GetData(ctx, ref)
 	if err != nil {
-		r.l.Error(err, "cannot marshal data")
-		return err
+		return perrors.Wrap(err, "cannot marshal data")
 	}
 
-	cm.Data = map[string]string{}
+	cm := r.buildConfigMap(ref)
+	if err := r.c.Get(ctx, client.ObjectKeyFromObject(cm), cm); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return perrors.Wrap(err, "cannot get configmap")
+		}
+		cm.Data = map[string]string{ConfigMapKey: string(b)}
+		return perrors.Wrap(r.c.Create(ctx, cm), "cannot create configmap")
+	}
+
+	// the stored index is kept
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
 	cm.Data[ConfigMapKey] = string(b)
+	return perrors.Wrap(r.c.Update(ctx, cm), "cannot update configmap")
+}
 
-	if err := r.c.Update(ctx, cm); err != nil {
-		r.l.Error(err, "cannot update configmap")
+// retrySave retries the save of the index in the background till it succeeds
+// or the index is destroyed, a single retry runs per index
+func (r *cm[claim, entry]) retrySave(ctx context.Context, ref corev1.ObjectReference) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if _, ok := r.unsaved[ref]; ok {
+		return
 	}
-	return nil
+	r.unsaved[ref] = struct{}{}
+	storageUnsaved.WithLabelValues(r.prefix).Set(float64(len(r.unsaved)))
+
+	// the retry outlives the request of the failed save, but not the
+	// leadership the request was served under
+	l := log.FromContext(ctx).WithValues("ref", ref)
+	ctx = election.NewContext(log.IntoContext(context.Background(), l), election.FromContext(ctx))
+	go func() {
+		delay := r.retryDelay
+		for {
+			time.Sleep(delay)
+			if !r.isUnsaved(ref) {
+				return
+			}
+			if !election.IsLeading(ctx) {
+				l.Info("stop saving index, not leader")
+				r.setSaved(ref)
+				return
+			}
+			if err := r.save(ctx, ref); err != nil {
+				l.Error(err, "cannot save index, retrying in the background")
+				storageErrors.WithLabelValues(r.prefix, "save").Inc()
+				delay *= 2
+				if delay > saveRetryMaxDelay {
+					delay = saveRetryMaxDelay
+				}
+				continue
+			}
+			l.Info("saved index")
+			r.setSaved(ref)
+			return
+		}
+	}()
+}
+
+func (r *cm[claim, entry]) isUnsaved(ref corev1.ObjectReference) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	_, ok := r.unsaved[ref]
+	return ok
+}
+
+// setSaved stops the retry of the save of the index
+func (r *cm[claim, entry]) setSaved(ref corev1.ObjectReference) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	delete(r.unsaved, ref)
+	storageUnsaved.WithLabelValues(r.prefix).Set(float64(len(r.unsaved)))
 }
 
 func (r *cm[claim, entry]) Destroy(ctx context.Context, ref corev1.ObjectReference) error {
-	r.l = log.FromContext(ctx)
+	log := log.FromContext(ctx)
 	// if no client provided dont try to save
 	if r.c == nil {
 		return nil
 	}
-	cm := buildConfigMap(ref, r.prefix)
+	r.setSaved(ref)
+	r.m.Lock()
+	delete(r.indices, ref)
+	r.m.Unlock()
+	cm := r.buildConfigMap(ref)
 	if err := r.c.Delete(ctx, cm); err != nil {
 		if !kerrors.IsNotFound(err) {
-			r.l.Error(err, "ipam delete instance cm", "name", ref.Name)
+			log.Error(err, "ipam delete instance cm", "name", ref.Name)
 		}
 	}
 	return nil
 }
 
+func (r *cm[claim, entry]) SaveIndex(ctx context.Context, ref corev1.ObjectReference, index []byte) error {
+	// if no client provided dont try to save
+	if r.c == nil {
+		return nil
+	}
+	if !election.IsLeading(ctx) {
+		return errNotLeader
+	}
+	// the index is created again on every reconcile of the index resource,
+	// it is only saved when it is new or changed
+	normalized, err := normalizeIndex(index)
+	if err != nil {
+		return err
+	}
+	r.m.Lock()
+	saved, ok := r.indices[ref]
+	r.m.Unlock()
+	if ok && bytes.Equal(saved, normalized) {
+		return nil
+	}
+	if err := r.saveIndex(ctx, ref, index); err != nil {
+		return err
+	}
+	r.m.Lock()
+	r.indices[ref] = normalized
+	r.m.Unlock()
+	return nil
+}
+
+func (r *cm[claim, entry]) saveIndex(ctx context.Context, ref corev1.ObjectReference, index []byte) error {
+	cm := r.buildConfigMap(ref)
+	cm.Labels = map[string]string{BackendLabelKey: r.prefix}
+	cm.Data = map[string]string{IndexKey: string(index)}
+	// the configmap is patched, such that the claims of the index are kept
+	// without reading the configmap from the cache
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"labels": cm.Labels},
+		"data":     cm.Data,
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.c.Patch(ctx, cm, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		if kerrors.IsNotFound(err) {
+			return perrors.Wrap(r.c.Create(ctx, cm), "cannot create configmap")
+		}
+		return perrors.Wrap(err, "cannot patch configmap")
+	}
+	return nil
+}
+
+// normalizeIndex strips the status and the metadata that changes without a
+// change of the index, e.g. the resource version
+func normalizeIndex(index []byte) ([]byte, error) {
+	o := map[string]any{}
+	if err := json.Unmarshal(index, &o); err != nil {
+		return nil, perrors.Wrap(err, "cannot unmarshal index")
+	}
+	delete(o, "status")
+	if meta, ok := o["metadata"].(map[string]any); ok {
+		for _, k := range []string{"resourceVersion", "generation", "managedFields"} {
+			delete(meta, k)
+		}
+	}
+	// the keys of the maps are marshalled sorted
+	return json.Marshal(o)
+}
+
+func (r *cm[claim, entry]) ListIndices(ctx context.Context) ([][]byte, error) {
+	// if no client provided there are no stored indices
+	if r.c == nil {
+		return nil, nil
+	}
+	cms := &corev1.ConfigMapList{}
+	opts := []client.ListOption{client.MatchingLabels{BackendLabelKey: r.prefix}}
+	if r.ns != "" {
+		opts = append(opts, client.InNamespace(r.ns))
+	}
+	if err := r.c.List(ctx, cms, opts...); err != nil {
+		return nil, perrors.Wrap(err, "cannot list configmaps")
+	}
+	indices := make([][]byte, 0, len(cms.Items))
+	for _, cm := range cms.Items {
+		// configmaps stored before the index was saved are restored when the
+		// index is created again by a client
+		if index, ok := cm.Data[IndexKey]; ok {
+			indices = append(indices, []byte(index))
+		}
+	}
+	return indices, nil
+}
+
 // Get is a getall actually
 func (r *cm[claim, entry]) Get(ctx context.Context, a claim) ([]entry, error) {
 	return nil, nil
@@ -135,15 +354,24 @@ func (r *cm[claim, entry]) Delete(ctx context.Context, a claim) error {
 	return nil
 }
 
-func buildConfigMap(indexRef corev1.ObjectReference, prefix string) *corev1.ConfigMap {
+// buildConfigMap returns the configmap of the index. When a storage namespace
+// is configured the namespace of the index is part of the name, such that the
+// indices of different namespaces do not collide.
+func (r *cm[claim, entry]) buildConfigMap(indexRef corev1.ObjectReference) *corev1.ConfigMap {
+	namespace := indexRef.Namespace
+	name := r.prefix + "-" + indexRef.Name
+	if r.ns != "" {
+		namespace = r.ns
+		name = r.prefix + "-" + indexRef.Namespace + "-" + indexRef.Name
+	}
 	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
 			Kind:       "ConfigMap",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: indexRef.Namespace,
-			Name:      prefix + "-" + indexRef.Name,
+			Namespace: namespace,
+			Name:      name,
 		},
 	}
 }
@@ -0,0 +1,277 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nokia/k8s-ipam/pkg/election"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCMStorageNamespace(t *testing.T) {
+	index := corev1.ObjectReference{Namespace: "tenant-a", Name: "vpc-1"}
+	cases := map[string]struct {
+		namespace string
+		want      types.NamespacedName
+	}{
+		"IndexNamespace": {
+			want: types.NamespacedName{Namespace: "tenant-a", Name: "ipam-vpc-1"},
+		},
+		"StorageNamespace": {
+			namespace: "backend-system",
+			want:      types.NamespacedName{Namespace: "backend-system", Name: "ipam-tenant-a-vpc-1"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().Build()
+			s, err := NewCMBackend[any, any](&CMConfig{
+				Client:      c,
+				GetData:     func(ctx context.Context, ref corev1.ObjectReference) ([]byte, error) { return []byte("{}"), nil },
+				RestoreData: func(ctx context.Context, ref corev1.ObjectReference, cm *corev1.ConfigMap) error { return nil },
+				Prefix:      "ipam",
+				Namespace:   tc.namespace,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if err := s.Restore(ctx, index); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.SaveAll(ctx, index); err != nil {
+				t.Fatal(err)
+			}
+			cm := &corev1.ConfigMap{}
+			if err := c.Get(ctx, tc.want, cm); err != nil {
+				t.Fatalf("want configmap %s, got %v", tc.want, err)
+			}
+			if cm.Data[ConfigMapKey] != "{}" {
+				t.Errorf("want saved data, got %v", cm.Data)
+			}
+		})
+	}
+}
+
+func TestCMStorageIndices(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	s, err := NewCMBackend[any, any](&CMConfig{
+		Client:      c,
+		GetData:     func(ctx context.Context, ref corev1.ObjectReference) ([]byte, error) { return []byte("{}"), nil },
+		RestoreData: func(ctx context.Context, ref corev1.ObjectReference, cm *corev1.ConfigMap) error { return nil },
+		Prefix:      "vlan",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	index := corev1.ObjectReference{Namespace: "tenant-a", Name: "vpc-1"}
+	if err := s.Restore(ctx, index); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveIndex(ctx, index, []byte(`{"name":"vpc-1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	// the stored index is kept when the claims are saved
+	if err := s.SaveAll(ctx, index); err != nil {
+		t.Fatal(err)
+	}
+	// configmaps without a stored index or of another backend are not listed
+	for _, cm := range []*corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "vlan-vpc-2"}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "ipam-vpc-1", Labels: map[string]string{BackendLabelKey: "ipam"}},
+			Data:       map[string]string{IndexKey: `{"name":"vpc-1"}`},
+		},
+	} {
+		if err := c.Create(ctx, cm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	indices, err := s.ListIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 1 || string(indices[0]) != `{"name":"vpc-1"}` {
+		t.Errorf("want the stored index, got %q", indices)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "tenant-a", Name: "vlan-vpc-1"}, cm); err != nil {
+		t.Fatal(err)
+	}
+	if cm.Data[ConfigMapKey] != "{}" {
+		t.Errorf("want saved data, got %v", cm.Data)
+	}
+}
+
+// failingClient fails the updates till the failures are reset
+type failingClient struct {
+	client.Client
+	m    sync.Mutex
+	fail bool
+}
+
+func (r *failingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.fail {
+		return fmt.Errorf("update failed")
+	}
+	return r.Client.Update(ctx, obj, opts...)
+}
+
+func (r *failingClient) setFail(fail bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.fail = fail
+}
+
+func TestCMStorageSaveRetry(t *testing.T) {
+	c := &failingClient{Client: fake.NewClientBuilder().Build()}
+	s, err := NewCMBackend[any, any](&CMConfig{
+		Client:      c,
+		GetData:     func(ctx context.Context, ref corev1.ObjectReference) ([]byte, error) { return []byte("{}"), nil },
+		RestoreData: func(ctx context.Context, ref corev1.ObjectReference, cm *corev1.ConfigMap) error { return nil },
+		Prefix:      "vlan",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.(*cm[any, any]).retryDelay = 10 * time.Millisecond
+	ctx := context.Background()
+	index := corev1.ObjectReference{Namespace: "tenant-a", Name: "vpc-1"}
+	if err := s.Restore(ctx, index); err != nil {
+		t.Fatal(err)
+	}
+
+	// a failed save does not fail the claim, the save is retried in the
+	// background
+	c.setFail(true)
+	if err := s.SaveAll(ctx, index); err != nil {
+		t.Fatalf("want no error when the save fails, got %v", err)
+	}
+	if !s.(*cm[any, any]).isUnsaved(index) {
+		t.Fatalf("want index unsaved")
+	}
+	c.setFail(false)
+
+	key := types.NamespacedName{Namespace: "tenant-a", Name: "vlan-vpc-1"}
+	deadline := time.Now().Add(5 * time.Second)
+	for s.(*cm[any, any]).isUnsaved(index) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for the save retry")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, cm); err != nil {
+		t.Fatal(err)
+	}
+	if cm.Data[ConfigMapKey] != "{}" {
+		t.Errorf("want saved data, got %v", cm.Data)
+	}
+}
+
+// countingClient counts the patches of the configmaps
+type countingClient struct {
+	client.Client
+	patches int
+}
+
+func (r *countingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	r.patches++
+	return r.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestCMStorageSaveIndexUnchanged(t *testing.T) {
+	c := &countingClient{Client: fake.NewClientBuilder().Build()}
+	s, err := NewCMBackend[any, any](&CMConfig{
+		Client:      c,
+		GetData:     func(ctx context.Context, ref corev1.ObjectReference) ([]byte, error) { return []byte("{}"), nil },
+		RestoreData: func(ctx context.Context, ref corev1.ObjectReference, cm *corev1.ConfigMap) error { return nil },
+		Prefix:      "vlan",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	index := corev1.ObjectReference{Namespace: "tenant-a", Name: "vpc-1"}
+	if err := s.Restore(ctx, index); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []string{
+		`{"metadata":{"name":"vpc-1","resourceVersion":"1"},"spec":{"start":1}}`,
+		// the status and the resource version do not change the index
+		`{"metadata":{"name":"vpc-1","resourceVersion":"2"},"spec":{"start":1},"status":{"ready":true}}`,
+		`{"metadata":{"name":"vpc-1","resourceVersion":"3"},"spec":{"start":2}}`,
+	} {
+		if err := s.SaveIndex(ctx, index, []byte(b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c.patches != 2 {
+		t.Errorf("want the new and the changed index saved, got %d patches", c.patches)
+	}
+
+	// the index is saved again once it is destroyed and created again
+	if err := s.Destroy(ctx, index); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveIndex(ctx, index, []byte(`{"metadata":{"name":"vpc-1"},"spec":{"start":2}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if c.patches != 3 {
+		t.Errorf("want the created index saved, got %d patches", c.patches)
+	}
+}
+
+func TestCMStorageNotLeader(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	s, err := NewCMBackend[any, any](&CMConfig{
+		Client:      c,
+		GetData:     func(ctx context.Context, ref corev1.ObjectReference) ([]byte, error) { return []byte("{}"), nil },
+		RestoreData: func(ctx context.Context, ref corev1.ObjectReference, cm *corev1.ConfigMap) error { return nil },
+		Prefix:      "vlan",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx := election.NewContext(context.Background(), leaderCtx)
+	index := corev1.ObjectReference{Namespace: "tenant-a", Name: "vpc-1"}
+	if err := s.SaveAll(ctx, index); !IsRetriable(err) {
+		t.Errorf("want a retriable error when the leadership is lost, got %v", err)
+	}
+	if err := s.SaveIndex(ctx, index, []byte(`{}`)); !IsRetriable(err) {
+		t.Errorf("want a retriable error when the leadership is lost, got %v", err)
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "tenant-a", Name: "vlan-vpc-1"}, cm); err == nil {
+		t.Errorf("want nothing saved when the leadership is lost, got %v", cm.Data)
+	}
+}
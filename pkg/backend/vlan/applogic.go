@@ -3,32 +3,51 @@ package vlan
 import (
 	"context"
 
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
 	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/db"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func (r *be) newApplogic(cr *vlanv1alpha1.VLANClaim, initializing bool) (backend.AppLogic[*vlanv1alpha1.VLANClaim], error) {
+func (r *be) newApplogic(ctx context.Context, cr *vlanv1alpha1.VLANClaim, initializing bool) (backend.AppLogic[*vlanv1alpha1.VLANClaim], error) {
 	// we assume right now 1 database ID
 	t, err := r.cache.Get(cr.GetCacheID(), initializing)
 	if err != nil {
 		return nil, err
 	}
+	return r.newTableApplogic(ctx, t, cr)
+}
 
-	vlanClaimCtx, err := cr.GetVLANClaimCtx()
+// newDryRunApplogic returns the applogic of the claim on a copy of the
+// table, such that applying the claim does not change the table
+func (r *be) newDryRunApplogic(ctx context.Context, cr *vlanv1alpha1.VLANClaim) (backend.AppLogic[*vlanv1alpha1.VLANClaim], error) {
+	t, err := r.cache.Get(cr.GetCacheID(), false)
 	if err != nil {
 		return nil, err
 	}
-	r.l.Info("newApplogic", "vlanClaimCtx", vlanClaimCtx)
+	return r.newTableApplogic(ctx, t.Clone(), cr)
+}
 
-	return newVLANApplogic(t, vlanClaimCtx, false)
+func (r *be) newTableApplogic(ctx context.Context, t db.DB[uint16], cr *vlanv1alpha1.VLANClaim) (backend.AppLogic[*vlanv1alpha1.VLANClaim], error) {
+	vlanClaimCtx, err := cr.GetVLANClaimCtx()
+	if err != nil {
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid VLAN claim: %s", err.Error())
+	}
+	log.FromContext(ctx).Info("newApplogic", "vlanClaimCtx", vlanClaimCtx)
+
+	return newVLANApplogic(t, vlanClaimCtx, r.quotas)
 
 }
 
-func newVLANApplogic(t db.DB[uint16], vctx *vlanv1alpha1.VLANClaimCtx, init bool) (backend.AppLogic[*vlanv1alpha1.VLANClaim], error) {
+func newVLANApplogic(t db.DB[uint16], vctx *vlanv1alpha1.VLANClaimCtx, quotas backend.Quotas) (backend.AppLogic[*vlanv1alpha1.VLANClaim], error) {
 	r := &applogic{
-		table: t,
-		vctx:  vctx,
+		table:  t,
+		vctx:   vctx,
+		quotas: quotas,
 		fnc: map[vlanv1alpha1.VLANClaimType]*applogicFunctionConfig{
 			vlanv1alpha1.VLANClaimTypeDynamic: {
 				getHandler:        getHandlerSingleVlan,
@@ -58,13 +77,15 @@ func newVLANApplogic(t db.DB[uint16], vctx *vlanv1alpha1.VLANClaimCtx, init bool
 		ValidateHandler: r.ValidateHandler,
 		ApplyHandler:    r.ApplyHandler,
 		DeleteHandler:   r.DeleteHandler,
+		QuotaHandler:    r.QuotaHandler,
 	})
 }
 
 type applogic struct {
-	table db.DB[uint16]
-	vctx  *vlanv1alpha1.VLANClaimCtx
-	fnc   map[vlanv1alpha1.VLANClaimType]*applogicFunctionConfig
+	table  db.DB[uint16]
+	vctx   *vlanv1alpha1.VLANClaimCtx
+	quotas backend.Quotas
+	fnc    map[vlanv1alpha1.VLANClaimType]*applogicFunctionConfig
 }
 
 type applogicFunctionConfig struct {
@@ -91,8 +112,7 @@ func (r *applogic) GetHandler(ctx context.Context, a *vlanv1alpha1.VLANClaim) (*
 }
 
 func (r *applogic) ValidateHandler(ctx context.Context, a *vlanv1alpha1.VLANClaim) (string, error) {
-	// no validatopn required so far
-	return "", nil
+	return ValidateClaimCtx(r.vctx), nil
 }
 
 func (r *applogic) ApplyHandler(ctx context.Context, a *vlanv1alpha1.VLANClaim) (*vlanv1alpha1.VLANClaim, error) {
@@ -120,6 +140,33 @@ func (r *applogic) ApplyHandler(ctx context.Context, a *vlanv1alpha1.VLANClaim)
 	return claim, nil
 }
 
+// QuotaHandler checks the vlan quotas of the namespace for a new claim, a
+// claim that holds its vlans already is not checked again
+func (r *applogic) QuotaHandler(ctx context.Context, a *vlanv1alpha1.VLANClaim) error {
+	entries, err := r.getEntriesByOwner(r.table, a)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return nil
+	}
+	count := int64(1)
+	if r.vctx.Kind == vlanv1alpha1.VLANClaimTypeRange || r.vctx.Kind == vlanv1alpha1.VLANClaimTypeSize {
+		count = int64(r.vctx.Size)
+	}
+	return r.quotas.Check(ctx, backend.QuotaRequest{
+		Namespace: a.GetNamespace(),
+		Labels:    a.GetUserDefinedLabels(),
+		Index:     a.GetCacheID(),
+		Resource:  quotav1alpha1.QuotaResourceVLANs,
+		Count:     count,
+	}, r.quotaUsage)
+}
+
+func (r *applogic) quotaUsage(index corev1.ObjectReference, resource quotav1alpha1.QuotaResource, selector labels.Selector) (int64, error) {
+	return int64(len(r.table.GetByLabel(selector))), nil
+}
+
 func (r *applogic) DeleteHandler(ctx context.Context, a *vlanv1alpha1.VLANClaim) error {
 	// get the entries in the cache based on the owner references
 	entries, err := r.getEntriesByOwner(r.table, a)
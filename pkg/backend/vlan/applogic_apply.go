@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/db"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
 	"k8s.io/utils/ptr"
 )
 
@@ -26,7 +28,7 @@ func applyHandlerStaticVlan(entries db.Entries[uint16], claim *vlanv1alpha1.VLAN
 	if claim.Status.VLANID == nil || *claim.Status.VLANID == entries[0].ID() {
 		claim.Status.VLANID = ptr.To[uint16](entries[0].ID())
 	} else {
-		return fmt.Errorf("vlan claim with a different vlan ID")
+		return backend.NewError(resourcepb.ErrorCode_Conflict, "vlan claim with a different vlan ID, claimed: %d, got: %d", entries[0].ID(), *claim.Status.VLANID)
 	}
 	return nil
 }
@@ -40,7 +42,7 @@ func applyHandlerMultipleVlan(entries db.Entries[uint16], claim *vlanv1alpha1.VL
 func applyHandlerNewDynamicVlan(table db.DB[uint16], vctx *vlanv1alpha1.VLANClaimCtx, claim *vlanv1alpha1.VLANClaim) error {
 	e, err := table.FindFree()
 	if err != nil {
-		return err
+		return backend.NewError(resourcepb.ErrorCode_PoolExhausted, "%s", err.Error())
 	}
 	e = db.NewEntry(e.ID(), claim.GetUserDefinedLabels())
 	if err := table.Set(e); err != nil {
@@ -51,6 +53,11 @@ func applyHandlerNewDynamicVlan(table db.DB[uint16], vctx *vlanv1alpha1.VLANClai
 }
 
 func applyHandlerNewStaticVlan(table db.DB[uint16], vctx *vlanv1alpha1.VLANClaimCtx, claim *vlanv1alpha1.VLANClaim) error {
+	// FindFreeID returns the existing entry when the id is in use, since the
+	// owner did not match it is claimed by somebody else
+	if table.Has(vctx.Start) {
+		return backend.NewError(resourcepb.ErrorCode_Conflict, "vlan ID %d already claimed", vctx.Start)
+	}
 	e, err := table.FindFreeID(vctx.Start)
 	if err != nil {
 		return err
@@ -66,7 +73,7 @@ func applyHandlerNewStaticVlan(table db.DB[uint16], vctx *vlanv1alpha1.VLANClaim
 func applyHandlerNewVlanRange(table db.DB[uint16], vctx *vlanv1alpha1.VLANClaimCtx, claim *vlanv1alpha1.VLANClaim) error {
 	_, err := table.FindFreeRange(vctx.Start, vctx.Size)
 	if err != nil {
-		return err
+		return backend.NewError(resourcepb.ErrorCode_Conflict, "%s", err.Error())
 	}
 	claim.Status.VLANRange = ptr.To[string](fmt.Sprintf("%d:%d", vctx.Start, vctx.Start+vctx.Size-1))
 	return nil
@@ -75,7 +82,7 @@ func applyHandlerNewVlanRange(table db.DB[uint16], vctx *vlanv1alpha1.VLANClaimC
 func applyHandlerNewVlanSize(table db.DB[uint16], vctx *vlanv1alpha1.VLANClaimCtx, claim *vlanv1alpha1.VLANClaim) error {
 	_, err := table.FindFreeSize(vctx.Size)
 	if err != nil {
-		return err
+		return backend.NewError(resourcepb.ErrorCode_PoolExhausted, "%s", err.Error())
 	}
 	claim.Status.VLANRange = ptr.To[string]("TBD update status ")
 	return nil
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDryRunClaim(t *testing.T) {
+	ctx := context.Background()
+	be := newSnapshotBackend(t, map[string]*uint16{"static": util.PointerUint16(100)})
+	before, err := be.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claim := func(name string, vlanID *uint16, dryRun bool) (*vlanv1alpha1.VLANClaim, error) {
+		req := vlanv1alpha1.BuildVLANClaim(
+			metav1.ObjectMeta{Namespace: "default", Name: name},
+			vlanv1alpha1.VLANClaimSpec{
+				VLANIndex: corev1.ObjectReference{Namespace: "default", Name: "a"},
+				VLANID:    vlanID,
+			},
+			vlanv1alpha1.VLANClaimStatus{},
+		)
+		req.AddOwnerLabelsToCR()
+		b, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		claimFn := be.Claim
+		if dryRun {
+			claimFn = be.(backend.DryRunBackend).DryRunClaim
+		}
+		b, err = claimFn(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		resp := &vlanv1alpha1.VLANClaim{}
+		return resp, json.Unmarshal(b, resp)
+	}
+
+	preview, err := claim("dynamic", nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Status.VLANID == nil || *preview.Status.VLANID != 2 {
+		t.Fatalf("want the dry run to assign VLAN 2, got %v", preview.Status.VLANID)
+	}
+	if _, err := claim("other", util.PointerUint16(100), true); err == nil {
+		t.Errorf("want the dry run of a claimed VLAN to fail")
+	}
+	after, err := be.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before, after) {
+		t.Fatalf("the dry run changed the index, want %v, got %v", before, after)
+	}
+
+	// the claim is assigned the VLAN of the dry run
+	resp, err := claim("dynamic", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resp.Status.VLANID, preview.Status.VLANID) {
+		t.Errorf("want VLAN %d, got %v", *preview.Status.VLANID, resp.Status.VLANID)
+	}
+}
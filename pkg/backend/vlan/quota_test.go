@@ -0,0 +1,135 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	s := scheme.Scheme
+	if err := quotav1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	index := vlanv1alpha1.BuildVLANIndex(metav1.ObjectMeta{Namespace: "default", Name: "a"}, vlanv1alpha1.VLANIndexSpec{}, vlanv1alpha1.VLANIndexStatus{})
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(&quotav1alpha1.ClaimQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "q"},
+		Spec: quotav1alpha1.ClaimQuotaSpec{
+			Limits: []quotav1alpha1.QuotaLimit{
+				{Index: corev1.ObjectReference{Namespace: "default", Name: "a"}, Resource: quotav1alpha1.QuotaResourceVLANs, Max: 2},
+			},
+		},
+	}).Build()
+
+	be, err := New(c, backend.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := be.CreateIndex(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	claim := func(name string, vlanRange *string) error {
+		req := vlanv1alpha1.BuildVLANClaim(
+			metav1.ObjectMeta{Namespace: "default", Name: name},
+			vlanv1alpha1.VLANClaimSpec{
+				VLANIndex: corev1.ObjectReference{Namespace: "default", Name: "a"},
+				VLANRange: vlanRange,
+			},
+			vlanv1alpha1.VLANClaimStatus{},
+		)
+		req.AddOwnerLabelsToCR()
+		b, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		_, err = be.Claim(ctx, b)
+		return err
+	}
+	size := func(s int) *string {
+		r := fmt.Sprintf("%d", s)
+		return &r
+	}
+
+	if err := claim("dynamic1", nil); err != nil {
+		t.Fatalf("claim within quota failed: %v", err)
+	}
+	if err := claim("range1", size(3)); backend.GetErrorCode(err) != resourcepb.ErrorCode_QuotaExceeded {
+		t.Errorf("want QuotaExceeded, got %v", err)
+	}
+	if err := claim("dynamic2", nil); err != nil {
+		t.Fatalf("claim within quota failed: %v", err)
+	}
+	// a claim that holds its vlan already is not checked again
+	if err := claim("dynamic1", nil); err != nil {
+		t.Errorf("refresh of an existing claim failed: %v", err)
+	}
+	if err := claim("dynamic3", nil); backend.GetErrorCode(err) != resourcepb.ErrorCode_QuotaExceeded {
+		t.Errorf("want QuotaExceeded, got %v", err)
+	}
+}
+
+func TestDeleteClaimLock(t *testing.T) {
+	ctx := context.Background()
+	r := newSnapshotBackend(t, nil)
+	req := vlanv1alpha1.BuildVLANClaim(
+		metav1.ObjectMeta{Namespace: "default", Name: "claim"},
+		vlanv1alpha1.VLANClaimSpec{VLANIndex: corev1.ObjectReference{Namespace: "default", Name: "a"}},
+		vlanv1alpha1.VLANClaimStatus{},
+	)
+	req.AddOwnerLabelsToCR()
+	b, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Claim(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	// the delete waits for the quota check of a concurrent claim in the index
+	quotas := r.(*be).quotas
+	quotas.Lock(req.GetCacheID())
+	done := make(chan error)
+	go func() { done <- r.DeleteClaim(ctx, b) }()
+	select {
+	case err := <-done:
+		t.Fatalf("delete done while the index is locked: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	quotas.Unlock(req.GetCacheID())
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/db"
+	"github.com/nokia/k8s-ipam/pkg/db/vlandb"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Export returns the snapshot of the indices with their entries
+func (r *be) Export(ctx context.Context) ([]backend.IndexSnapshot, error) {
+	return backend.ExportIndices(r.cache, func(d db.DB[uint16]) []backend.EntrySnapshot {
+		entries := []backend.EntrySnapshot{}
+		for _, e := range d.GetAll() {
+			entries = append(entries, backend.EntrySnapshot{
+				ID:     strconv.FormatUint(uint64(e.ID()), 10),
+				Labels: e.Labels(),
+			})
+		}
+		return entries
+	}), nil
+}
+
+// Import validates the entries of the snapshot against the entries in the
+// indices and applies the snapshot when there are no conflicts
+func (r *be) Import(ctx context.Context, indices []backend.IndexSnapshot, dryRun bool) ([]string, error) {
+	defer backend.LockIndices(r.quotas, indices)()
+	conflicts := []string{}
+	for _, idx := range indices {
+		c, err := r.validateImport(idx)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, c...)
+	}
+	if len(conflicts) > 0 || dryRun {
+		return conflicts, nil
+	}
+
+	for _, idx := range indices {
+		if err := r.CreateIndex(ctx, idx.Index); err != nil {
+			return nil, err
+		}
+		cacheID := resourcev1alpha1.GetCacheID(corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name})
+		d, err := r.cache.Get(cacheID, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range idx.Entries {
+			id, _ := strconv.ParseUint(e.ID, 10, 16)
+			if d.Has(uint16(id)) {
+				continue
+			}
+			if err := d.Set(db.NewEntry(uint16(id), e.Labels)); err != nil {
+				return nil, err
+			}
+		}
+		if err := r.store.Get().SaveAll(ctx, cacheID); err != nil {
+			return nil, err
+		}
+	}
+	return conflicts, nil
+}
+
+// validateImport returns the entries of the index snapshot that are claimed
+// with other labels in the index and validates the vlans that are not claimed
+func (r *be) validateImport(idx backend.IndexSnapshot) ([]string, error) {
+	cr := &vlanv1alpha1.VLANIndex{}
+	if err := json.Unmarshal(idx.Index, cr); err != nil {
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid index %s/%s: %s", idx.Namespace, idx.Name, err.Error())
+	}
+	cacheID := resourcev1alpha1.GetCacheID(corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name})
+	if cr.GetCacheID() != cacheID {
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid index %s/%s: resource %v does not match", idx.Namespace, idx.Name, cr.GetCacheID())
+	}
+	// the reserved vlans of a new db are part of the snapshot
+	newDB := vlandb.New()
+	d, err := r.cache.Get(cacheID, false)
+	if err != nil {
+		d = newDB
+	}
+
+	conflicts := []string{}
+	for _, e := range idx.Entries {
+		id, err := strconv.ParseUint(e.ID, 10, 16)
+		if err != nil {
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid index %s/%s: invalid id %s", idx.Namespace, idx.Name, e.ID)
+		}
+		if d.Has(uint16(id)) {
+			existing, err := d.Get(uint16(id))
+			if err != nil {
+				return nil, err
+			}
+			if c := backend.ImportConflict(idx, e, existing.Labels()); c != "" {
+				conflicts = append(conflicts, c)
+			}
+			continue
+		}
+		if newDB.Has(uint16(id)) {
+			continue
+		}
+		if err := vlandb.ValidateVLANID(uint16(id)); err != nil {
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid index %s/%s: %s", idx.Namespace, idx.Name, err.Error())
+		}
+	}
+	return conflicts, nil
+}
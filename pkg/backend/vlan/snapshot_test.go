@@ -0,0 +1,138 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newSnapshotBackend(t *testing.T, claims map[string]*uint16) backend.Backend {
+	ctx := context.Background()
+	be, err := New(nil, backend.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := vlanv1alpha1.BuildVLANIndex(metav1.ObjectMeta{Namespace: "default", Name: "a"}, vlanv1alpha1.VLANIndexSpec{}, vlanv1alpha1.VLANIndexStatus{})
+	b, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := be.CreateIndex(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	for name, vlanID := range claims {
+		req := vlanv1alpha1.BuildVLANClaim(
+			metav1.ObjectMeta{Namespace: "default", Name: name},
+			vlanv1alpha1.VLANClaimSpec{
+				VLANIndex: corev1.ObjectReference{Namespace: "default", Name: "a"},
+				VLANID:    vlanID,
+			},
+			vlanv1alpha1.VLANClaimStatus{},
+		)
+		req.AddOwnerLabelsToCR()
+		b, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := be.Claim(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return be
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	be := newSnapshotBackend(t, map[string]*uint16{"static": util.PointerUint16(100), "dynamic": nil})
+	indices, err := be.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 1 || indices[0].Namespace != "default" || indices[0].Name != "a" {
+		t.Fatalf("want index default/a, got %v", indices)
+	}
+
+	// the snapshot is imported in a backend without indices
+	restored, err := New(nil, backend.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicts, err := restored.Import(ctx, indices, true)
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("want no conflicts, got %v, %v", conflicts, err)
+	}
+	if got, _ := restored.Export(ctx); len(got) != 0 {
+		t.Fatalf("dry run applied the snapshot: %v", got)
+	}
+	if _, err := restored.Import(ctx, indices, false); err != nil {
+		t.Fatal(err)
+	}
+	got, err := restored.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, indices) {
+		t.Errorf("want %v, got %v", indices, got)
+	}
+	// the snapshot is imported again without conflicts
+	if conflicts, err := restored.Import(ctx, indices, false); err != nil || len(conflicts) != 0 {
+		t.Errorf("want no conflicts, got %v, %v", conflicts, err)
+	}
+}
+
+func TestSnapshotConflict(t *testing.T) {
+	ctx := context.Background()
+	indices, err := newSnapshotBackend(t, map[string]*uint16{"static": util.PointerUint16(100), "other": util.PointerUint16(200)}).Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// vlan 100 is claimed by another owner
+	be := newSnapshotBackend(t, map[string]*uint16{"conflict": util.PointerUint16(100)})
+	conflicts, err := be.Import(ctx, indices, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("want 1 conflict, got %v", conflicts)
+	}
+	got, err := be.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range got[0].Entries {
+		if e.ID == "200" {
+			t.Errorf("snapshot with conflicts is applied")
+		}
+	}
+
+	// an out of range vlan cannot be imported
+	indices[0].Entries = append(indices[0].Entries, backend.EntrySnapshot{ID: "4096"})
+	if _, err := be.Import(ctx, indices, true); backend.GetErrorCode(err) != resourcepb.ErrorCode_ValidationFailed {
+		t.Errorf("want ValidationFailed, got %v", err)
+	}
+}
@@ -39,8 +39,9 @@ type Storage interface {
 }
 
 type storageConfig struct {
-	client client.Client
-	cache  backend.Cache[db.DB[uint16]]
+	client    client.Client
+	namespace string
+	cache     backend.Cache[db.DB[uint16]]
 }
 
 func newCMStorage(cfg *storageConfig) (Storage, error) {
@@ -54,6 +55,7 @@ func newCMStorage(cfg *storageConfig) (Storage, error) {
 		GetData:     r.GetData,
 		RestoreData: r.RestoreData,
 		Prefix:      "vlan",
+		Namespace:   cfg.namespace,
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,55 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlan
+
+import (
+	"fmt"
+
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/db/vlandb"
+)
+
+// ValidateClaim validates the input of the claim without consulting the
+// vlan database e.g. it is used by the admission webhooks to reject
+// invalid claims before they reach the backend
+func ValidateClaim(claim *vlanv1alpha1.VLANClaim) string {
+	if claim.Spec.VLANID != nil && claim.Spec.VLANRange != nil {
+		return "a VLAN claim can either have a vlanID or a range, not both"
+	}
+	vctx, err := claim.GetVLANClaimCtx()
+	if err != nil {
+		return fmt.Sprintf("invalid VLAN range %s, err: %s", *claim.Spec.VLANRange, err.Error())
+	}
+	return ValidateClaimCtx(vctx)
+}
+
+// ValidateClaimCtx validates the VLAN IDs, start and size of the claim context
+func ValidateClaimCtx(vctx *vlanv1alpha1.VLANClaimCtx) string {
+	var err error
+	switch vctx.Kind {
+	case vlanv1alpha1.VLANClaimTypeStatic:
+		err = vlandb.ValidateVLANID(vctx.Start)
+	case vlanv1alpha1.VLANClaimTypeSize:
+		err = vlandb.ValidateVLANSize(vctx.Size)
+	case vlanv1alpha1.VLANClaimTypeRange:
+		err = vlandb.ValidateVLANRange(vctx.Start, vctx.Size)
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
@@ -20,26 +20,36 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/netip"
+	"sync/atomic"
 
-	"github.com/go-logr/logr"
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
 	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/db"
 	"github.com/nokia/k8s-ipam/pkg/db/vlandb"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func New(c client.Client) (backend.Backend, error) {
+// New returns the backend, the indices are kept in memory only when no client
+// is provided or the memory storage is selected
+func New(c client.Client, sc backend.StorageConfig) (backend.Backend, error) {
 
 	ca := backend.NewCache[db.DB[uint16]]()
 
 	s := newNopCMStorage()
-	if c != nil {
+	if c != nil && sc.Type != backend.StorageTypeMemory {
 		var err error
 		s, err = newCMStorage(&storageConfig{
-			client: c,
-			cache:  ca,
+			client:    c,
+			namespace: sc.Namespace,
+			cache:     ca,
 		})
 		if err != nil {
 			return nil, err
@@ -47,8 +57,10 @@ func New(c client.Client) (backend.Backend, error) {
 	}
 
 	return &be{
-		cache: ca,
-		store: s,
+		watcher: newWatcher(),
+		cache:   ca,
+		store:   s,
+		quotas:  backend.NewQuotas(c),
 	}, nil
 }
 
@@ -56,7 +68,9 @@ type be struct {
 	watcher Watcher
 	cache   backend.Cache[db.DB[uint16]]
 	store   Storage
-	l       logr.Logger
+	quotas  backend.Quotas
+	// restoring is set while the stored indices are restored
+	restoring atomic.Bool
 }
 
 func (r *be) AddWatch(ownerGvkKey, ownerGvk string, fn backend.CallbackFn) {
@@ -66,6 +80,26 @@ func (r *be) DeleteWatch(ownerGvkKey, ownerGvk string) {
 	r.watcher.deleteWatch(ownerGvkKey, ownerGvk)
 }
 
+// Ready returns an error while the stored indices are restored, the restore
+// and save failures of a single index are reported by the index and the
+// storage metrics
+func (r *be) Ready() error {
+	if r.restoring.Load() {
+		return backend.NewError(resourcepb.ErrorCode_IndexNotReady, "restoring the indices")
+	}
+	return nil
+}
+
+// GetQuotaUsage returns the vlans in the index held by the claims that match
+// the selector
+func (r *be) GetQuotaUsage(index corev1.ObjectReference, resource quotav1alpha1.QuotaResource, selector labels.Selector) (int64, error) {
+	t, err := r.cache.Get(resourcev1alpha1.GetCacheID(index), false)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(t.GetByLabel(selector))), nil
+}
+
 // Create the cache instance and/or restore the cache instance
 func (r *be) CreateIndex(ctx context.Context, b []byte) error {
 	cr := &vlanv1alpha1.VLANIndex{}
@@ -73,22 +107,50 @@ func (r *be) CreateIndex(ctx context.Context, b []byte) error {
 		return err
 	}
 	cacheID := cr.GetCacheID()
-	r.l = log.FromContext(ctx).WithValues("cache id", cacheID)
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
 
-	r.l.Info("create cache instance start", "isInitialized", r.cache.IsInitialized(cacheID))
+	log.Info("create cache instance start", "isInitialized", r.cache.IsInitialized(cacheID))
 	// if the Cache is not initialaized initialized
 	// this happens upon initialization or backend restart
 	r.cache.Create(cacheID, vlandb.New())
 	if !r.cache.IsInitialized(cacheID) {
 		if err := r.store.Get().Restore(ctx, cacheID); err != nil {
-			r.l.Error(err, "backend cache restore error")
+			log.Error(err, "backend cache restore error")
+			return err
+		}
+
+		log.Info("create cache instance finished")
+		if err := r.cache.SetInitialized(cacheID); err != nil {
 			return err
 		}
+	} else {
+		log.Info("create cache instance already initialized")
+	}
+	if err := r.cache.SetIndex(cacheID, b); err != nil {
+		return err
+	}
+	// the index is stored such that it is restored when another backend
+	// instance becomes the leader
+	return r.store.Get().SaveIndex(ctx, cacheID, b)
+}
 
-		r.l.Info("create cache instance finished")
-		return r.cache.SetInitialized(cacheID)
+// Restore drops the indices in memory and restores the stored indices
+func (r *be) Restore(ctx context.Context) error {
+	r.restoring.Store(true)
+	defer r.restoring.Store(false)
+
+	r.cache.Reset()
+	indices, err := r.store.Get().ListIndices(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range indices {
+		// an index that fails to restore is not ready, the restore is
+		// retried when the index is created again
+		if err := r.CreateIndex(ctx, b); err != nil {
+			log.FromContext(ctx).Error(err, "cannot restore index")
+		}
 	}
-	r.l.Info("create cache instance already initialized")
 	return nil
 }
 
@@ -99,17 +161,17 @@ func (r *be) DeleteIndex(ctx context.Context, b []byte) error {
 		return err
 	}
 	cacheID := cr.GetCacheID()
-	r.l = log.FromContext(ctx).WithValues("cache id", cacheID)
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
 
-	r.l.Info("delete cache instance start")
+	log.Info("delete cache instance start")
 	r.cache.Delete(cacheID)
 
 	// delete the data from the backend
 	if err := r.store.Get().Destroy(ctx, cacheID); err != nil {
-		r.l.Error(err, "delete cache instance error")
+		log.Error(err, "delete cache instance error")
 		return err
 	}
-	r.l.Info("delete cache instance finished")
+	log.Info("delete cache instance finished")
 	return nil
 }
 
@@ -120,11 +182,11 @@ func (r *be) List(ctx context.Context, b []byte) (any, error) {
 		return nil, err
 	}
 	cacheID := cr.GetCacheID()
-	r.l = log.FromContext(ctx).WithValues("cache id", cacheID)
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
 
 	d, err := r.cache.Get(cacheID, false)
 	if err != nil {
-		r.l.Error(err, "cannpt get cache instance")
+		log.Error(err, "cannpt get cache instance")
 		return nil, err
 	}
 	return d.GetAll(), err
@@ -136,10 +198,10 @@ func (r *be) GetClaim(ctx context.Context, b []byte) ([]byte, error) {
 	if err := json.Unmarshal(b, cr); err != nil {
 		return nil, err
 	}
-	r.l = log.FromContext(ctx).WithValues("name", cr.GetName())
-	r.l.Info("get claimed entry", "selectors", cr.GetSelectorLabels())
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+	log.Info("get claimed entry", "selectors", cr.GetSelectorLabels())
 
-	al, err := r.newApplogic(cr, false)
+	al, err := r.newApplogic(ctx, cr, false)
 	if err != nil {
 		return nil, err
 	}
@@ -148,19 +210,45 @@ func (r *be) GetClaim(ctx context.Context, b []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	r.l.Info("get claimed entry done", "claimedVLAN", cr.Status)
+	log.Info("get claimed entry done", "claimedVLAN", cr.Status)
 	return json.Marshal(cr)
 }
 
 func (r *be) Claim(ctx context.Context, b []byte) ([]byte, error) {
+	return r.claim(ctx, b, false)
+}
+
+// DryRunClaim returns the claim with the VLANs it would be assigned, the
+// claim is applied on a copy of the index and is not stored
+func (r *be) DryRunClaim(ctx context.Context, b []byte) ([]byte, error) {
+	return r.claim(ctx, b, true)
+}
+
+func (r *be) claim(ctx context.Context, b []byte, dryRun bool) ([]byte, error) {
 	cr := &vlanv1alpha1.VLANClaim{}
 	if err := json.Unmarshal(b, cr); err != nil {
 		return nil, err
 	}
-	r.l = log.FromContext(ctx).WithValues("name", cr.GetName())
-	r.l.Info("claim", "cr spec", cr.Spec)
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+	log.Info("claim", "cr spec", cr.Spec, "dryRun", dryRun)
 
-	al, err := r.newApplogic(cr, false)
+	// the watches of the owner are informed when the claim changed its vlans,
+	// after the index is unlocked
+	var updated table.Routes
+	defer func() { r.watcher.handleUpdate(ctx, updated, resourcepb.StatusCode_Valid) }()
+	r.quotas.Lock(cr.GetCacheID())
+	defer r.quotas.Unlock(cr.GetCacheID())
+	var entries db.Entries[uint16]
+	if !dryRun {
+		entries = r.getEntriesByOwner(cr)
+	}
+	var al backend.AppLogic[*vlanv1alpha1.VLANClaim]
+	var err error
+	if dryRun {
+		al, err = r.newDryRunApplogic(ctx, cr)
+	} else {
+		al, err = r.newApplogic(ctx, cr, false)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -169,18 +257,24 @@ func (r *be) Claim(ctx context.Context, b []byte) ([]byte, error) {
 		return nil, err
 	}
 	if msg != "" {
-		r.l.Error(fmt.Errorf("%s", msg), "validation failed")
-		return nil, fmt.Errorf("validation failed: %s", msg)
+		log.Error(fmt.Errorf("%s", msg), "validation failed")
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "validation failed: %s", msg)
 	}
 	cr, err = al.Apply(ctx, cr)
 	if err != nil {
 		return nil, err
 	}
 
-	r.l.Info("claim done", "updated Claim", cr)
+	log.Info("claim done", "updated Claim", cr, "dryRun", dryRun)
+	if dryRun {
+		return json.Marshal(cr)
+	}
 	if err := r.store.Get().SaveAll(ctx, cr.GetCacheID()); err != nil {
 		return nil, err
 	}
+	if claimed := r.getEntriesByOwner(cr); !sameEntries(entries, claimed) {
+		updated = entryRoutes(claimed)
+	}
 	return json.Marshal(cr)
 }
 
@@ -190,17 +284,68 @@ func (r *be) DeleteClaim(ctx context.Context, b []byte) error {
 	if err := json.Unmarshal(b, cr); err != nil {
 		return err
 	}
-	r.l = log.FromContext(ctx).WithValues("name", cr.GetName())
-	r.l.Info("delete claim")
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+	log.Info("delete claim")
 
-	al, err := r.newApplogic(cr, false)
+	// the watches of the owner are informed of the released vlans, after the
+	// index is unlocked
+	var released table.Routes
+	defer func() { r.watcher.handleUpdate(ctx, released, resourcepb.StatusCode_Unknown) }()
+	r.quotas.Lock(cr.GetCacheID())
+	defer r.quotas.Unlock(cr.GetCacheID())
+	al, err := r.newApplogic(ctx, cr, false)
 	if err != nil {
 		return err
 	}
+	entries := r.getEntriesByOwner(cr)
 	if err := al.Delete(ctx, cr); err != nil {
-		r.l.Error(err, "cannot delete claimed resource")
+		log.Error(err, "cannot delete claimed resource")
+		return err
+	}
+
+	if err := r.store.Get().SaveAll(ctx, cr.GetCacheID()); err != nil {
 		return err
 	}
+	released = entryRoutes(entries)
+	return nil
+}
 
-	return r.store.Get().SaveAll(ctx, cr.GetCacheID())
+// getEntriesByOwner returns the entries of the owner of the claim in the index
+func (r *be) getEntriesByOwner(cr *vlanv1alpha1.VLANClaim) db.Entries[uint16] {
+	t, err := r.cache.Get(cr.GetCacheID(), false)
+	if err != nil {
+		return nil
+	}
+	ownerSelector, err := cr.GetOwnerSelector()
+	if err != nil {
+		return nil
+	}
+	return t.GetByLabel(ownerSelector)
+}
+
+// sameEntries returns true if both lists hold the same vlans
+func sameEntries(a, b db.Entries[uint16]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ids := map[uint16]bool{}
+	for _, e := range a {
+		ids[e.ID()] = true
+	}
+	for _, e := range b {
+		if !ids[e.ID()] {
+			return false
+		}
+	}
+	return true
+}
+
+// entryRoutes returns the entries as routes for the watches, the vlans have
+// no prefix and the watches only use the labels of the routes
+func entryRoutes(entries db.Entries[uint16]) table.Routes {
+	routes := make(table.Routes, 0, len(entries))
+	for _, e := range entries {
+		routes = append(routes, table.NewRoute(netip.Prefix{}, e.Labels(), map[string]any{}))
+	}
+	return routes
 }
@@ -34,7 +34,7 @@ var _ = Describe("VLAN Backend Testing", func() {
 			By("calling New() constructor for an ipam backend")
 			var err error
 			// create new backend
-			be, err = New(nil)
+			be, err = New(nil, backend.StorageConfig{})
 			Ω(err).Should(Succeed(), "Failed to create backend")
 			Ω(be).ShouldNot(BeNil(), "initializing backend failed")
 
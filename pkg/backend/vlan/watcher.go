@@ -18,6 +18,7 @@ package vlan
 
 import (
 	"context"
+	"sync"
 
 	"github.com/hansthienpondt/nipam/pkg/table"
 	"github.com/nokia/k8s-ipam/pkg/backend"
@@ -31,3 +32,62 @@ type Watcher interface {
 	deleteWatch(ownerGvkKey, ownerGvk string)
 	handleUpdate(ctx context.Context, routes table.Routes, statusCode resourcepb.StatusCode)
 }
+
+func newWatcher() Watcher {
+	return &watcher{
+		d: map[string]map[string]backend.CallbackFn{},
+	}
+}
+
+type watcher struct {
+	m sync.RWMutex
+	// 1st key is ownerGvk key, 2nd key is ownerGVK
+	d map[string]map[string]backend.CallbackFn
+}
+
+func (r *watcher) addWatch(ownerGvkKey, ownerGvk string, fn backend.CallbackFn) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.d[ownerGvkKey]; !ok {
+		r.d[ownerGvkKey] = map[string]backend.CallbackFn{}
+	}
+	r.d[ownerGvkKey][ownerGvk] = fn
+}
+
+func (r *watcher) deleteWatch(ownerGvkKey, ownerGvk string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.d[ownerGvkKey]; ok {
+		delete(r.d[ownerGvkKey], ownerGvk)
+	}
+	if len(r.d[ownerGvkKey]) == 0 {
+		delete(r.d, ownerGvkKey)
+	}
+}
+
+func (r *watcher) handleUpdate(ctx context.Context, routes table.Routes, statusCode resourcepb.StatusCode) {
+	// group the routes per ownerGVK value and call the matching callback once
+	// after releasing the lock, the callbacks take locks of their own
+	r.m.RLock()
+	updateMap := map[string][]table.Route{}
+	fns := map[string]backend.CallbackFn{}
+	for _, route := range routes {
+		for ownerGvkKey, values := range r.d {
+			ownerGvkValue, ok := route.Labels()[ownerGvkKey]
+			if !ok {
+				continue
+			}
+			if fn, ok := values[ownerGvkValue]; ok {
+				updateMap[ownerGvkValue] = append(updateMap[ownerGvkValue], route)
+				fns[ownerGvkValue] = fn
+			}
+		}
+	}
+	r.m.RUnlock()
+
+	for ownerGvk, routes := range updateMap {
+		fns[ownerGvk](routes, statusCode)
+	}
+}
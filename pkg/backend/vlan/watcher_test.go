@@ -0,0 +1,91 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	be := newSnapshotBackend(t, nil)
+	type event struct {
+		claim      string
+		statusCode resourcepb.StatusCode
+	}
+	events := []event{}
+	be.AddWatch(resourcev1alpha1.NephioOwnerGvkKey, meta.GVKToString(vlanv1alpha1.VLANClaimGroupVersionKind), func(routes table.Routes, statusCode resourcepb.StatusCode) {
+		for _, route := range routes {
+			events = append(events, event{claim: route.Labels()[resourcev1alpha1.NephioNsnNameKey], statusCode: statusCode})
+		}
+	})
+
+	claim := func(vlanID uint16) []byte {
+		req := vlanv1alpha1.BuildVLANClaim(
+			metav1.ObjectMeta{Namespace: "default", Name: "claim"},
+			vlanv1alpha1.VLANClaimSpec{
+				VLANIndex: corev1.ObjectReference{Namespace: "default", Name: "a"},
+				VLANID:    util.PointerUint16(vlanID),
+			},
+			vlanv1alpha1.VLANClaimStatus{},
+		)
+		req.AddOwnerLabelsToCR()
+		b, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	for _, vlanID := range []uint16{100, 100} {
+		if _, err := be.Claim(ctx, claim(vlanID)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a dry run does not inform the watches
+	if _, err := be.(backend.DryRunBackend).DryRunClaim(ctx, claim(300)); err != nil {
+		t.Fatal(err)
+	}
+	if err := be.DeleteClaim(ctx, claim(100)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the claim of an unchanged vlan does not inform the watches
+	want := []event{
+		{claim: "claim", statusCode: resourcepb.StatusCode_Valid},
+		{claim: "claim", statusCode: resourcepb.StatusCode_Unknown},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("want events %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d: want %v, got %v", i, want[i], events[i])
+		}
+	}
+}
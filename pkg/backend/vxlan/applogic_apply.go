@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vxlan
+
+import (
+	"fmt"
+
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/db"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"k8s.io/utils/ptr"
+)
+
+func applyHandlerDynamicVXLAN(entries db.Entries[uint32], claim *vxlanv1alpha1.VXLANClaim) error {
+	if len(entries) > 1 {
+		return fmt.Errorf("claim for single entry returned multiple: %v", entries)
+	}
+	// update the status
+	claim.Status.VXLANID = ptr.To[uint32](entries[0].ID())
+	return nil
+}
+
+func applyHandlerStaticVXLAN(entries db.Entries[uint32], claim *vxlanv1alpha1.VXLANClaim) error {
+	if len(entries) > 1 {
+		return fmt.Errorf("claim for single entry returned multiple: %v", entries)
+	}
+	// the owner already holds an entry, it should match the requested id
+	if *claim.Spec.VXLANID != entries[0].ID() {
+		return backend.NewError(resourcepb.ErrorCode_Conflict, "vxlan claim with a different vxlan ID, claimed: %d, requested: %d", entries[0].ID(), *claim.Spec.VXLANID)
+	}
+	claim.Status.VXLANID = ptr.To[uint32](entries[0].ID())
+	return nil
+}
+
+func applyHandlerNewDynamicVXLAN(table db.DB[uint32], vctx *vxlanv1alpha1.VXLANClaimCtx, claim *vxlanv1alpha1.VXLANClaim) error {
+	e, err := table.FindFree()
+	if err != nil {
+		return err
+	}
+	e = db.NewEntry(e.ID(), claim.GetUserDefinedLabels())
+	if err := table.Set(e); err != nil {
+		return err
+	}
+	claim.Status.VXLANID = ptr.To[uint32](e.ID())
+	return nil
+}
+
+func applyHandlerNewStaticVXLAN(table db.DB[uint32], vctx *vxlanv1alpha1.VXLANClaimCtx, claim *vxlanv1alpha1.VXLANClaim) error {
+	// FindFreeID returns the existing entry when the id is in use, since the
+	// owner did not match it is claimed by somebody else
+	if table.Has(vctx.Start) {
+		return backend.NewError(resourcepb.ErrorCode_Conflict, "vxlan ID %d already claimed", vctx.Start)
+	}
+	e, err := table.FindFreeID(vctx.Start)
+	if err != nil {
+		return err
+	}
+	e = db.NewEntry(e.ID(), claim.GetUserDefinedLabels())
+	if err := table.Set(e); err != nil {
+		return err
+	}
+	claim.Status.VXLANID = ptr.To[uint32](e.ID())
+	return nil
+}
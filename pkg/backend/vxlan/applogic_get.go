@@ -0,0 +1,34 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vxlan
+
+import (
+	"fmt"
+
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/db"
+	"k8s.io/utils/ptr"
+)
+
+func getHandlerSingleVXLAN(entries db.Entries[uint32], claim *vxlanv1alpha1.VXLANClaim) error {
+	if len(entries) > 1 {
+		return fmt.Errorf("get for single entry returned multiple: %v", entries)
+	}
+	// update the status
+	claim.Status.VXLANID = ptr.To[uint32](entries[0].ID())
+	return nil
+}
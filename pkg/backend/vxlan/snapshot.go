@@ -0,0 +1,133 @@
+/*
+Copyright 2022 Nokia.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vxlan
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/db"
+	"github.com/nokia/k8s-ipam/pkg/db/vxlandb"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Export returns the snapshot of the indices with their entries
+func (r *be) Export(ctx context.Context) ([]backend.IndexSnapshot, error) {
+	return backend.ExportIndices(r.cache, func(d db.DB[uint32]) []backend.EntrySnapshot {
+		entries := []backend.EntrySnapshot{}
+		for _, e := range d.GetAll() {
+			entries = append(entries, backend.EntrySnapshot{
+				ID:     strconv.FormatUint(uint64(e.ID()), 10),
+				Labels: e.Labels(),
+			})
+		}
+		return entries
+	}), nil
+}
+
+// Import validates the entries of the snapshot against the entries in the
+// indices and applies the snapshot when there are no conflicts
+func (r *be) Import(ctx context.Context, indices []backend.IndexSnapshot, dryRun bool) ([]string, error) {
+	conflicts := []string{}
+	for _, idx := range indices {
+		c, err := r.validateImport(idx)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, c...)
+	}
+	if len(conflicts) > 0 || dryRun {
+		return conflicts, nil
+	}
+
+	for _, idx := range indices {
+		if err := r.CreateIndex(ctx, idx.Index); err != nil {
+			return nil, err
+		}
+		cacheID := resourcev1alpha1.GetCacheID(corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name})
+		d, err := r.cache.Get(cacheID, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range idx.Entries {
+			id, _ := strconv.ParseUint(e.ID, 10, 32)
+			if d.Has(uint32(id)) {
+				continue
+			}
+			if err := d.Set(db.NewEntry(uint32(id), e.Labels)); err != nil {
+				return nil, err
+			}
+		}
+		if err := r.store.Get().SaveAll(ctx, cacheID); err != nil {
+			return nil, err
+		}
+	}
+	return conflicts, nil
+}
+
+// validateImport returns the entries of the index snapshot that are claimed
+// with other labels in the index and validates the entries that are not
+// claimed against a new db of the index
+func (r *be) validateImport(idx backend.IndexSnapshot) ([]string, error) {
+	cr := &vxlanv1alpha1.VXLANIndex{}
+	if err := json.Unmarshal(idx.Index, cr); err != nil {
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid index %s/%s: %s", idx.Namespace, idx.Name, err.Error())
+	}
+	cacheID := resourcev1alpha1.GetCacheID(corev1.ObjectReference{Namespace: idx.Namespace, Name: idx.Name})
+	if cr.GetCacheID() != cacheID {
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid index %s/%s: resource %v does not match", idx.Namespace, idx.Name, cr.GetCacheID())
+	}
+	// the new db validates the entries the same way as the index does
+	newDB := vxlandb.New(&vxlandb.Config[uint32]{
+		Offset:     cr.Spec.Offset,
+		MaxEntryID: cr.Spec.MaxEntryID,
+	})
+	d, err := r.cache.Get(cacheID, false)
+	if err != nil {
+		d = newDB
+	}
+
+	conflicts := []string{}
+	for _, e := range idx.Entries {
+		id, err := strconv.ParseUint(e.ID, 10, 32)
+		if err != nil {
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid index %s/%s: invalid id %s", idx.Namespace, idx.Name, e.ID)
+		}
+		if d.Has(uint32(id)) {
+			existing, err := d.Get(uint32(id))
+			if err != nil {
+				return nil, err
+			}
+			if c := backend.ImportConflict(idx, e, existing.Labels()); c != "" {
+				conflicts = append(conflicts, c)
+			}
+			continue
+		}
+		if newDB.Has(uint32(id)) {
+			continue
+		}
+		if err := newDB.Set(db.NewEntry(uint32(id), e.Labels)); err != nil {
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid index %s/%s: %s", idx.Namespace, idx.Name, err.Error())
+		}
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vxlan
+
+import (
+	"context"
+
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/db"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+type Storage interface {
+	Get() backend.Storage[*vxlanv1alpha1.VXLANClaim, map[string]labels.Set]
+}
+
+type storageConfig struct {
+	client    client.Client
+	namespace string
+	cache     backend.Cache[db.DB[uint32]]
+}
+
+func newCMStorage(cfg *storageConfig) (Storage, error) {
+	r := &cm{
+		c:     cfg.client,
+		cache: cfg.cache,
+	}
+
+	be, err := backend.NewCMBackend[*vxlanv1alpha1.VXLANClaim, map[string]labels.Set](&backend.CMConfig{
+		Client:      cfg.client,
+		GetData:     r.GetData,
+		RestoreData: r.RestoreData,
+		Prefix:      "vxlan",
+		Namespace:   cfg.namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.be = be
+
+	return r, nil
+}
+
+type cm struct {
+	c     client.Client
+	be    backend.Storage[*vxlanv1alpha1.VXLANClaim, map[string]labels.Set]
+	cache backend.Cache[db.DB[uint32]]
+}
+
+func (r *cm) Get() backend.Storage[*vxlanv1alpha1.VXLANClaim, map[string]labels.Set] {
+	return r.be
+}
+
+func (r *cm) GetData(ctx context.Context, ref corev1.ObjectReference) ([]byte, error) {
+	log := log.FromContext(ctx)
+	ca, err := r.cache.Get(ref, false)
+	if err != nil {
+		log.Error(err, "cannot get db info")
+		return nil, err
+	}
+
+	data := map[uint32]labels.Set{}
+	for _, entry := range ca.GetAll() {
+		data[entry.ID()] = entry.Labels()
+	}
+	b, err := yaml.Marshal(data)
+	if err != nil {
+		log.Error(err, "cannot marshal data")
+	}
+	return b, nil
+}
+
+// RestoreData restores all the entries stored in the configmap.
+// Unlike vlan, vxlan IDs are mostly claimed by controllers on behalf of other
+// resources (e.g. ESI/LAG IDs for logical endpoints) which have no VXLANClaim CR
+// to validate against, so the stored entries are the source of truth.
+func (r *cm) RestoreData(ctx context.Context, ref corev1.ObjectReference, cm *corev1.ConfigMap) error {
+	log := log.FromContext(ctx)
+	claims := map[uint32]labels.Set{}
+	if err := yaml.Unmarshal([]byte(cm.Data[backend.ConfigMapKey]), &claims); err != nil {
+		log.Error(err, "unmarshal error from configmap data")
+		return err
+	}
+	log.Info("restore data", "ref", ref, "claims", claims)
+
+	ca, err := r.cache.Get(ref, true)
+	if err != nil {
+		return err
+	}
+	for vxlanID, labels := range claims {
+		if err := ca.Set(db.NewEntry(vxlanID, labels)); err != nil {
+			log.Error(err, "cannot restore entry", "vxlanID", vxlanID)
+		}
+	}
+	return nil
+}
+
+func newNopCMStorage() Storage {
+	return &nopcm{
+		be: backend.NewNopStorage[*vxlanv1alpha1.VXLANClaim, map[string]labels.Set](),
+	}
+}
+
+type nopcm struct {
+	be backend.Storage[*vxlanv1alpha1.VXLANClaim, map[string]labels.Set]
+}
+
+func (r *nopcm) Get() backend.Storage[*vxlanv1alpha1.VXLANClaim, map[string]labels.Set] {
+	return r.be
+}
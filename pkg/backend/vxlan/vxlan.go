@@ -0,0 +1,261 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vxlan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/db"
+	"github.com/nokia/k8s-ipam/pkg/db/vxlandb"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// New returns the backend, the indices are kept in memory only when no client
+// is provided or the memory storage is selected
+func New(c client.Client, sc backend.StorageConfig) (backend.Backend, error) {
+
+	ca := backend.NewCache[db.DB[uint32]]()
+
+	s := newNopCMStorage()
+	if c != nil && sc.Type != backend.StorageTypeMemory {
+		var err error
+		s, err = newCMStorage(&storageConfig{
+			client:    c,
+			namespace: sc.Namespace,
+			cache:     ca,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &be{
+		watcher: newWatcher(),
+		cache:   ca,
+		store:   s,
+		quotas:  backend.NewQuotas(nil),
+	}, nil
+}
+
+type be struct {
+	watcher Watcher
+	cache   backend.Cache[db.DB[uint32]]
+	store   Storage
+	// quotas serializes the claims per index, no quotas are enforced on the
+	// vxlan claims
+	quotas backend.Quotas
+	// restoring is set while the stored indices are restored
+	restoring atomic.Bool
+}
+
+func (r *be) AddWatch(ownerGvkKey, ownerGvk string, fn backend.CallbackFn) {
+	r.watcher.addWatch(ownerGvkKey, ownerGvk, fn)
+}
+func (r *be) DeleteWatch(ownerGvkKey, ownerGvk string) {
+	r.watcher.deleteWatch(ownerGvkKey, ownerGvk)
+}
+
+// Ready returns an error while the stored indices are restored, the restore
+// and save failures of a single index are reported by the index and the
+// storage metrics
+func (r *be) Ready() error {
+	if r.restoring.Load() {
+		return backend.NewError(resourcepb.ErrorCode_IndexNotReady, "restoring the indices")
+	}
+	return nil
+}
+
+// Create the cache instance and/or restore the cache instance
+func (r *be) CreateIndex(ctx context.Context, b []byte) error {
+	cr := &vxlanv1alpha1.VXLANIndex{}
+	if err := json.Unmarshal(b, cr); err != nil {
+		return err
+	}
+	cacheID := cr.GetCacheID()
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
+
+	log.Info("create cache instance start", "isInitialized", r.cache.IsInitialized(cacheID))
+	// if the Cache is not initialaized initialized
+	// this happens upon initialization or backend restart
+	r.cache.Create(cacheID, vxlandb.New(&vxlandb.Config[uint32]{
+		Offset:     cr.Spec.Offset,
+		MaxEntryID: cr.Spec.MaxEntryID,
+	}))
+	if !r.cache.IsInitialized(cacheID) {
+		if err := r.store.Get().Restore(ctx, cacheID); err != nil {
+			log.Error(err, "backend cache restore error")
+			return err
+		}
+
+		log.Info("create cache instance finished")
+		if err := r.cache.SetInitialized(cacheID); err != nil {
+			return err
+		}
+	} else {
+		log.Info("create cache instance already initialized")
+	}
+	if err := r.cache.SetIndex(cacheID, b); err != nil {
+		return err
+	}
+	// the index is stored such that it is restored when another backend
+	// instance becomes the leader
+	return r.store.Get().SaveIndex(ctx, cacheID, b)
+}
+
+// Restore drops the indices in memory and restores the stored indices
+func (r *be) Restore(ctx context.Context) error {
+	r.restoring.Store(true)
+	defer r.restoring.Store(false)
+
+	r.cache.Reset()
+	indices, err := r.store.Get().ListIndices(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range indices {
+		// an index that fails to restore is not ready, the restore is
+		// retried when the index is created again
+		if err := r.CreateIndex(ctx, b); err != nil {
+			log.FromContext(ctx).Error(err, "cannot restore index")
+		}
+	}
+	return nil
+}
+
+// Delete the cache instance
+func (r *be) DeleteIndex(ctx context.Context, b []byte) error {
+	cr := &vxlanv1alpha1.VXLANIndex{}
+	if err := json.Unmarshal(b, cr); err != nil {
+		return err
+	}
+	cacheID := cr.GetCacheID()
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
+
+	log.Info("delete cache instance start")
+	r.cache.Delete(cacheID)
+
+	// delete the data from the backend
+	if err := r.store.Get().Destroy(ctx, cacheID); err != nil {
+		log.Error(err, "delete cache instance error")
+		return err
+	}
+	log.Info("delete cache instance finished")
+	return nil
+}
+
+// List entries in the db instance
+func (r *be) List(ctx context.Context, b []byte) (any, error) {
+	cr := &vxlanv1alpha1.VXLANIndex{}
+	if err := json.Unmarshal(b, cr); err != nil {
+		return nil, err
+	}
+	cacheID := cr.GetCacheID()
+	log := log.FromContext(ctx).WithValues("cache id", cacheID)
+
+	d, err := r.cache.Get(cacheID, false)
+	if err != nil {
+		log.Error(err, "cannot get cache instance")
+		return nil, err
+	}
+	return d.GetAll(), err
+}
+
+// GetClaim return the claimed entry if found
+func (r *be) GetClaim(ctx context.Context, b []byte) ([]byte, error) {
+	cr := &vxlanv1alpha1.VXLANClaim{}
+	if err := json.Unmarshal(b, cr); err != nil {
+		return nil, err
+	}
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+	log.Info("get claimed entry", "selectors", cr.GetSelectorLabels())
+
+	al, err := r.newApplogic(ctx, cr, false)
+	if err != nil {
+		return nil, err
+	}
+	cr, err = al.Get(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("get claimed entry done", "claimedVXLAN", cr.Status)
+	return json.Marshal(cr)
+}
+
+func (r *be) Claim(ctx context.Context, b []byte) ([]byte, error) {
+	cr := &vxlanv1alpha1.VXLANClaim{}
+	if err := json.Unmarshal(b, cr); err != nil {
+		return nil, err
+	}
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+	log.Info("claim", "cr spec", cr.Spec)
+
+	r.quotas.Lock(cr.GetCacheID())
+	defer r.quotas.Unlock(cr.GetCacheID())
+	al, err := r.newApplogic(ctx, cr, false)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := al.Validate(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if msg != "" {
+		log.Error(fmt.Errorf("%s", msg), "validation failed")
+		return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "validation failed: %s", msg)
+	}
+	cr, err = al.Apply(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("claim done", "updated Claim", cr)
+	if err := r.store.Get().SaveAll(ctx, cr.GetCacheID()); err != nil {
+		return nil, err
+	}
+	return json.Marshal(cr)
+}
+
+// DeleteClaim deletes the claim based on owner selection. No errors are returned if no claim was found
+func (r *be) DeleteClaim(ctx context.Context, b []byte) error {
+	cr := &vxlanv1alpha1.VXLANClaim{}
+	if err := json.Unmarshal(b, cr); err != nil {
+		return err
+	}
+	log := log.FromContext(ctx).WithValues("name", cr.GetName())
+	log.Info("delete claim")
+
+	r.quotas.Lock(cr.GetCacheID())
+	defer r.quotas.Unlock(cr.GetCacheID())
+	al, err := r.newApplogic(ctx, cr, false)
+	if err != nil {
+		return err
+	}
+	if err := al.Delete(ctx, cr); err != nil {
+		log.Error(err, "cannot delete claimed resource")
+		return err
+	}
+
+	return r.store.Get().SaveAll(ctx, cr.GetCacheID())
+}
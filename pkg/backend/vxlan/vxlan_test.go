@@ -0,0 +1,174 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vxlan
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func buildVXLANIndex(name string) *vxlanv1alpha1.VXLANIndex {
+	return vxlanv1alpha1.BuildVXLANIndex(
+		metav1.ObjectMeta{Namespace: "default", Name: name},
+		vxlanv1alpha1.VXLANIndexSpec{Offset: 10, MaxEntryID: 20},
+		vxlanv1alpha1.VXLANIndexStatus{},
+	)
+}
+
+func buildVXLANClaim(name string, id *uint32) *vxlanv1alpha1.VXLANClaim {
+	claim := vxlanv1alpha1.BuildVXLANClaim(
+		metav1.ObjectMeta{Namespace: "default", Name: name},
+		vxlanv1alpha1.VXLANClaimSpec{
+			VXLANIndex: corev1.ObjectReference{Namespace: "default", Name: "test"},
+			VXLANID:    id,
+		},
+		vxlanv1alpha1.VXLANClaimStatus{},
+	)
+	claim.AddOwnerLabelsToCR()
+	return claim
+}
+
+func claim(ctx context.Context, t *testing.T, r *be, cr *vxlanv1alpha1.VXLANClaim) (*vxlanv1alpha1.VXLANClaim, error) {
+	t.Helper()
+	b, err := json.Marshal(cr)
+	if err != nil {
+		t.Fatalf("cannot marshal claim: %s", err)
+	}
+	b, err = r.Claim(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	resp := &vxlanv1alpha1.VXLANClaim{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		t.Fatalf("cannot unmarshal claim: %s", err)
+	}
+	return resp, nil
+}
+
+func TestClaim(t *testing.T) {
+	cases := map[string]struct {
+		existing    []*vxlanv1alpha1.VXLANClaim
+		claim       *vxlanv1alpha1.VXLANClaim
+		expectedID  uint32
+		expectedErr bool
+	}{
+		"Dynamic": {
+			claim:      buildVXLANClaim("a", nil),
+			expectedID: 10,
+		},
+		"DynamicNext": {
+			existing:   []*vxlanv1alpha1.VXLANClaim{buildVXLANClaim("a", nil)},
+			claim:      buildVXLANClaim("b", nil),
+			expectedID: 11,
+		},
+		"DynamicIdempotent": {
+			existing:   []*vxlanv1alpha1.VXLANClaim{buildVXLANClaim("a", nil)},
+			claim:      buildVXLANClaim("a", nil),
+			expectedID: 10,
+		},
+		"Static": {
+			claim:      buildVXLANClaim("a", ptr.To[uint32](15)),
+			expectedID: 15,
+		},
+		"StaticIdempotent": {
+			existing:   []*vxlanv1alpha1.VXLANClaim{buildVXLANClaim("a", ptr.To[uint32](15))},
+			claim:      buildVXLANClaim("a", ptr.To[uint32](15)),
+			expectedID: 15,
+		},
+		"StaticClaimedByOther": {
+			existing:    []*vxlanv1alpha1.VXLANClaim{buildVXLANClaim("a", ptr.To[uint32](15))},
+			claim:       buildVXLANClaim("b", ptr.To[uint32](15)),
+			expectedErr: true,
+		},
+		"StaticOutOfRange": {
+			claim:       buildVXLANClaim("a", ptr.To[uint32](30)),
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			b, err := New(nil, backend.StorageConfig{})
+			if err != nil {
+				t.Fatalf("cannot initialize vxlan backend: %s", err)
+			}
+			r := b.(*be)
+			idx, err := json.Marshal(buildVXLANIndex("test"))
+			if err != nil {
+				t.Fatalf("cannot marshal index: %s", err)
+			}
+			if err := r.CreateIndex(ctx, idx); err != nil {
+				t.Fatalf("cannot create index: %s", err)
+			}
+			for _, e := range tc.existing {
+				if _, err := claim(ctx, t, r, e); err != nil {
+					t.Fatalf("cannot claim existing entry: %s", err)
+				}
+			}
+
+			resp, err := claim(ctx, t, r, tc.claim)
+			if tc.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, got vxlanID: %v", resp.Status.VXLANID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if resp.Status.VXLANID == nil || *resp.Status.VXLANID != tc.expectedID {
+				t.Errorf("want vxlanID %d, got %v", tc.expectedID, resp.Status.VXLANID)
+			}
+		})
+	}
+}
+
+func TestDeleteClaim(t *testing.T) {
+	ctx := context.Background()
+	b, err := New(nil, backend.StorageConfig{})
+	if err != nil {
+		t.Fatalf("cannot initialize vxlan backend: %s", err)
+	}
+	r := b.(*be)
+	idx, _ := json.Marshal(buildVXLANIndex("test"))
+	if err := r.CreateIndex(ctx, idx); err != nil {
+		t.Fatalf("cannot create index: %s", err)
+	}
+	if _, err := claim(ctx, t, r, buildVXLANClaim("a", ptr.To[uint32](15))); err != nil {
+		t.Fatalf("cannot claim: %s", err)
+	}
+	d, _ := json.Marshal(buildVXLANClaim("a", nil))
+	if err := r.DeleteClaim(ctx, d); err != nil {
+		t.Fatalf("cannot delete claim: %s", err)
+	}
+	// once released the id can be claimed by another owner
+	resp, err := claim(ctx, t, r, buildVXLANClaim("b", ptr.To[uint32](15)))
+	if err != nil {
+		t.Fatalf("cannot claim released id: %s", err)
+	}
+	if *resp.Status.VXLANID != 15 {
+		t.Errorf("want vxlanID 15, got %d", *resp.Status.VXLANID)
+	}
+}
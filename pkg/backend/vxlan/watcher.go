@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vxlan
+
+import (
+	"sync"
+
+	"github.com/nokia/k8s-ipam/pkg/backend"
+)
+
+type Watcher interface {
+	addWatch(ownerGvkKey, ownerGvk string, fn backend.CallbackFn)
+	deleteWatch(ownerGvkKey, ownerGvk string)
+}
+
+func newWatcher() Watcher {
+	return &watcher{
+		d: map[string]map[string]backend.CallbackFn{},
+	}
+}
+
+type watcher struct {
+	m sync.RWMutex
+	// 1st key is ownerGvk key, 2nd key is ownerGVK
+	d map[string]map[string]backend.CallbackFn
+}
+
+func (r *watcher) addWatch(ownerGvkKey, ownerGvk string, fn backend.CallbackFn) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.d[ownerGvkKey]; !ok {
+		r.d[ownerGvkKey] = map[string]backend.CallbackFn{}
+	}
+	r.d[ownerGvkKey][ownerGvk] = fn
+}
+
+func (r *watcher) deleteWatch(ownerGvkKey, ownerGvk string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.d[ownerGvkKey]; ok {
+		delete(r.d[ownerGvkKey], ownerGvk)
+	}
+	if len(r.d[ownerGvkKey]) == 0 {
+		delete(r.d, ownerGvkKey)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backoff
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// defaultJitter is the fraction of the delay that is randomly removed
+	// to spread the retries of items that failed at the same time
+	defaultJitter = 0.2
+)
+
+// NewRateLimiter returns a workqueue rate limiter which delays the retries of
+// an item exponentially, starting at baseDelay and capped at maxDelay, with a
+// random jitter applied to every delay
+func NewRateLimiter(baseDelay, maxDelay time.Duration) workqueue.RateLimiter {
+	if maxDelay < baseDelay {
+		maxDelay = baseDelay
+	}
+	return &rateLimiter{
+		failures:  map[any]int{},
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+		jitter:    defaultJitter,
+		rand:      rand.Float64,
+	}
+}
+
+type rateLimiter struct {
+	m         sync.Mutex
+	failures  map[any]int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	jitter    float64
+	rand      func() float64
+}
+
+func (r *rateLimiter) When(item any) time.Duration {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	exp := r.failures[item]
+	r.failures[item]++
+
+	delay := r.maxDelay
+	// avoid an overflow of the shift and the multiplication
+	if exp < 62 {
+		if d := float64(r.baseDelay) * float64(int64(1)<<exp); d < float64(r.maxDelay) {
+			delay = time.Duration(d)
+		}
+	}
+	// the jitter shortens the delay such that it never exceeds the cap
+	return delay - time.Duration(r.jitter*r.rand()*float64(delay))
+}
+
+func (r *rateLimiter) Forget(item any) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	delete(r.failures, item)
+}
+
+func (r *rateLimiter) NumRequeues(item any) int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.failures[item]
+}
@@ -0,0 +1,82 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	cases := map[string]struct {
+		baseDelay time.Duration
+		maxDelay  time.Duration
+		rand      float64
+		want      []time.Duration
+	}{
+		"NoJitter": {
+			baseDelay: time.Second,
+			maxDelay:  10 * time.Second,
+			rand:      0,
+			want:      []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second},
+		},
+		"MaxJitter": {
+			baseDelay: time.Second,
+			maxDelay:  10 * time.Second,
+			rand:      1,
+			want:      []time.Duration{800 * time.Millisecond, 1600 * time.Millisecond, 3200 * time.Millisecond, 6400 * time.Millisecond, 8 * time.Second},
+		},
+		"MaxBelowBase": {
+			baseDelay: 5 * time.Second,
+			maxDelay:  time.Second,
+			rand:      0,
+			want:      []time.Duration{5 * time.Second, 5 * time.Second},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRateLimiter(tc.baseDelay, tc.maxDelay).(*rateLimiter)
+			r.rand = func() float64 { return tc.rand }
+			for i, want := range tc.want {
+				if got := r.When("item"); got != want {
+					t.Errorf("failure %d: want %s, got %s", i, want, got)
+				}
+			}
+			if got := r.NumRequeues("item"); got != len(tc.want) {
+				t.Errorf("NumRequeues: want %d, got %d", len(tc.want), got)
+			}
+			r.Forget("item")
+			if got := r.NumRequeues("item"); got != 0 {
+				t.Errorf("NumRequeues after Forget: want 0, got %d", got)
+			}
+			if got := r.When("item"); got != tc.want[0] {
+				t.Errorf("after Forget: want %s, got %s", tc.want[0], got)
+			}
+		})
+	}
+}
+
+func TestRateLimiterOverflow(t *testing.T) {
+	r := NewRateLimiter(time.Second, time.Minute).(*rateLimiter)
+	r.rand = func() float64 { return 0 }
+	for i := 0; i < 100; i++ {
+		if got := r.When("item"); got <= 0 || got > time.Minute {
+			t.Fatalf("failure %d: delay %s out of range", i, got)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package config provides the versioned configuration of the resource backend
+// binary.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	APIVersion = "config.resource.nephio.org/v1alpha1"
+	Kind       = "ResourceBackendConfig"
+
+	// ModeAll runs the backends and the controllers in a single process
+	ModeAll = "all"
+	// ModeBackend runs the grpc server and the backends without controllers
+	ModeBackend = "backend"
+	// ModeControllers runs the controllers against a remote backend
+	ModeControllers = "controllers"
+
+	BackendIPAM  = "ipam"
+	BackendVLAN  = "vlan"
+	BackendVXLAN = "vxlan"
+
+	defaultGRPCAddress    = ":9999"
+	defaultBackendAddress = "127.0.0.1:9999"
+	defaultMaxRPC         = 600
+	defaultTimeout        = time.Minute
+	defaultLeaseName      = "resource-backend"
+	defaultLeaseDuration  = 15 * time.Second
+	defaultRenewDeadline  = 10 * time.Second
+	defaultRetryPeriod    = 2 * time.Second
+	defaultCaName         = "ca.crt"
+)
+
+var backends = []string{BackendIPAM, BackendVLAN, BackendVXLAN}
+
+// Config is the configuration of the resource backend binary
+type Config struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Mode selects the components that run: all, backend or controllers
+	Mode string `json:"mode"`
+	// GRPC is the grpc server of the resource api
+	GRPC GRPCConfig `json:"grpc"`
+	// Backends are the backends served by the grpc server
+	Backends BackendsConfig `json:"backends"`
+	// Controllers are the controllers to enable, they only run in the all and
+	// controllers modes. When unset the deprecated ENABLE_<NAME> environment
+	// variables select the controllers.
+	Controllers []ControllerConfig `json:"controllers,omitempty"`
+}
+
+// GRPCConfig is the grpc server of the resource api
+type GRPCConfig struct {
+	// Address the grpc server listens on
+	Address string `json:"address"`
+	// Insecure serves the grpc api without tls, the controllers connect to the
+	// backend without tls as well
+	Insecure bool `json:"insecure"`
+	// CertDir is the directory with the server certificate, key and ca, the
+	// controllers verify the backend with the ca
+	CertDir  string `json:"certDir,omitempty"`
+	CertName string `json:"certName,omitempty"`
+	KeyName  string `json:"keyName,omitempty"`
+	CaName   string `json:"caName,omitempty"`
+	// MaxRPC is the max number of concurrent requests
+	MaxRPC int64 `json:"maxRPC"`
+	// Timeout of a request
+	Timeout metav1.Duration `json:"timeout"`
+}
+
+// BackendsConfig selects the backends and their storage, the backends are
+// only served in the all and backend modes
+type BackendsConfig struct {
+	// Address of the resource backend the controllers connect to, defaults
+	// to the deprecated RESOURCE_BACKEND environment variable
+	Address string `json:"address,omitempty"`
+	// Enabled are the backends served by the grpc server: ipam, vlan or vxlan
+	Enabled []string `json:"enabled"`
+	// Storage of the backend indices
+	Storage backend.StorageConfig `json:"storage"`
+	// LeaderElection runs the backend instances active/standby
+	LeaderElection LeaderElectionConfig `json:"leaderElection"`
+}
+
+// LeaderElectionConfig elects the backend instance that serves the resource
+// api. The followers reject the requests, a new leader restores the indices
+// from the storage before serving.
+type LeaderElectionConfig struct {
+	Enabled bool `json:"enabled"`
+	// Namespace of the lease, defaults to the POD_NAMESPACE environment
+	// variable
+	Namespace string `json:"namespace,omitempty"`
+	// LeaseName is the lease shared by the backend instances
+	LeaseName string `json:"leaseName"`
+	// Identity is the address the controllers reach the grpc server of the
+	// instance on, defaults to the POD_IP environment variable and the port
+	// of the grpc address
+	Identity string `json:"identity,omitempty"`
+	// LeaseDuration is the time the followers wait before taking over the
+	// lease of a failed leader
+	LeaseDuration metav1.Duration `json:"leaseDuration"`
+	// RenewDeadline is the time the leader retries renewing the lease before
+	// it stops leading
+	RenewDeadline metav1.Duration `json:"renewDeadline"`
+	// RetryPeriod is the time between the attempts to acquire or renew the
+	// lease
+	RetryPeriod metav1.Duration `json:"retryPeriod"`
+}
+
+// ControllerConfig enables a controller
+type ControllerConfig struct {
+	Name string `json:"name"`
+	// MaxConcurrentReconciles overrides the default number of concurrent
+	// workers of the controller when set
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+}
+
+// Default returns the configuration used when no configuration file is
+// provided
+func Default() *Config {
+	return &Config{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Mode:       ModeAll,
+		GRPC: GRPCConfig{
+			Address:  defaultGRPCAddress,
+			Insecure: true,
+			MaxRPC:   defaultMaxRPC,
+			Timeout:  metav1.Duration{Duration: defaultTimeout},
+		},
+		Backends: BackendsConfig{
+			Enabled: append([]string{}, backends...),
+			Storage: backend.StorageConfig{Type: backend.StorageTypeConfigMap},
+			LeaderElection: LeaderElectionConfig{
+				LeaseName:     defaultLeaseName,
+				LeaseDuration: metav1.Duration{Duration: defaultLeaseDuration},
+				RenewDeadline: metav1.Duration{Duration: defaultRenewDeadline},
+				RetryPeriod:   metav1.Duration{Duration: defaultRetryPeriod},
+			},
+		},
+	}
+}
+
+// Load reads the configuration file, the settings that are not in the file
+// keep their default
+func Load(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := Default()
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if c.APIVersion != APIVersion || c.Kind != Kind {
+		return nil, fmt.Errorf("invalid config file %s: unsupported apiVersion %q and kind %q, expecting %s %s",
+			path, c.APIVersion, c.Kind, APIVersion, Kind)
+	}
+	return c, nil
+}
+
+// Validate validates the configuration against the names of the registered
+// controllers
+func (r *Config) Validate(controllers []string) error {
+	var errs []error
+	switch r.Mode {
+	case ModeAll, ModeBackend, ModeControllers:
+	default:
+		errs = append(errs, fmt.Errorf("unknown mode %q, expecting %s, %s or %s", r.Mode, ModeAll, ModeBackend, ModeControllers))
+	}
+	if r.Mode == ModeBackend && len(r.Controllers) != 0 {
+		errs = append(errs, fmt.Errorf("controllers are not run in the %s mode", ModeBackend))
+	}
+	if _, _, err := net.SplitHostPort(r.GRPC.Address); err != nil {
+		errs = append(errs, fmt.Errorf("invalid grpc address %q: %w", r.GRPC.Address, err))
+	}
+	if !r.GRPC.Insecure && r.GRPC.CertDir == "" {
+		errs = append(errs, fmt.Errorf("grpc certDir is required when the grpc server is not insecure"))
+	}
+	if r.GRPC.MaxRPC <= 0 {
+		errs = append(errs, fmt.Errorf("grpc maxRPC must be positive, got %d", r.GRPC.MaxRPC))
+	}
+	if r.GRPC.Timeout.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("grpc timeout must be positive, got %s", r.GRPC.Timeout.Duration))
+	}
+
+	if r.Backends.Address == "" {
+		errs = append(errs, fmt.Errorf("backend address is required"))
+	}
+	errs = append(errs, validateNames("backend", r.Backends.Enabled, backends)...)
+	switch r.Backends.Storage.Type {
+	case backend.StorageTypeConfigMap, backend.StorageTypeMemory:
+	default:
+		errs = append(errs, fmt.Errorf("unknown storage type %q, expecting %s or %s",
+			r.Backends.Storage.Type, backend.StorageTypeConfigMap, backend.StorageTypeMemory))
+	}
+	if r.Backends.LeaderElection.Enabled {
+		errs = append(errs, r.validateLeaderElection()...)
+	}
+
+	names := make([]string, 0, len(r.Controllers))
+	for _, c := range r.Controllers {
+		names = append(names, c.Name)
+		if c.MaxConcurrentReconciles < 0 {
+			errs = append(errs, fmt.Errorf("controller %s maxConcurrentReconciles must not be negative, got %d",
+				c.Name, c.MaxConcurrentReconciles))
+		}
+	}
+	errs = append(errs, validateNames("controller", names, controllers)...)
+	return errors.Join(errs...)
+}
+
+func (r *Config) validateLeaderElection() []error {
+	var errs []error
+	le := r.Backends.LeaderElection
+	if !r.RunsBackend() {
+		errs = append(errs, fmt.Errorf("leader election requires the %s or %s mode", ModeAll, ModeBackend))
+	}
+	// the state of a new leader is restored from the configmaps
+	if r.Backends.Storage.Type != backend.StorageTypeConfigMap {
+		errs = append(errs, fmt.Errorf("leader election requires the %s storage", backend.StorageTypeConfigMap))
+	}
+	if le.Namespace == "" {
+		errs = append(errs, fmt.Errorf("leader election namespace is required"))
+	}
+	if le.LeaseName == "" {
+		errs = append(errs, fmt.Errorf("leader election leaseName is required"))
+	}
+	if le.Identity == "" {
+		errs = append(errs, fmt.Errorf("leader election identity is required"))
+	}
+	if le.RetryPeriod.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("leader election retryPeriod must be positive, got %s", le.RetryPeriod.Duration))
+	}
+	if le.RenewDeadline.Duration <= time.Duration(leaderelection.JitterFactor*float64(le.RetryPeriod.Duration)) {
+		errs = append(errs, fmt.Errorf("leader election renewDeadline %s must be greater than %.1f times the retryPeriod",
+			le.RenewDeadline.Duration, leaderelection.JitterFactor))
+	}
+	if le.LeaseDuration.Duration <= le.RenewDeadline.Duration {
+		errs = append(errs, fmt.Errorf("leader election leaseDuration %s must be greater than the renewDeadline",
+			le.LeaseDuration.Duration))
+	}
+	return errs
+}
+
+func validateNames(kind string, names, known []string) []error {
+	var errs []error
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			errs = append(errs, fmt.Errorf("duplicate %s %q", kind, name))
+		}
+		seen[name] = true
+		if !contains(known, name) {
+			errs = append(errs, fmt.Errorf("unknown %s %q", kind, name))
+		}
+	}
+	return errs
+}
+
+// RunsBackend returns true if the mode runs the grpc server and the backends
+func (r *Config) RunsBackend() bool {
+	return r.Mode == ModeAll || r.Mode == ModeBackend
+}
+
+// RunsControllers returns true if the mode runs the controllers
+func (r *Config) RunsControllers() bool {
+	return r.Mode == ModeAll || r.Mode == ModeControllers
+}
+
+// BackendTLS returns true if the controllers connect to the backend with tls
+// and the ca that verifies the certificate of the backend. The backend serves
+// with tls unless the grpc api is insecure, the ca is the one of the certDir.
+func (r *Config) BackendTLS() (bool, string) {
+	if r.GRPC.Insecure {
+		return false, ""
+	}
+	caName := r.GRPC.CaName
+	if caName == "" {
+		caName = defaultCaName
+	}
+	return true, filepath.Join(r.GRPC.CertDir, caName)
+}
+
+// IsBackendEnabled returns true if the backend is served by the grpc server
+func (r *Config) IsBackendEnabled(name string) bool {
+	return r.RunsBackend() && contains(r.Backends.Enabled, name)
+}
+
+// IsControllerEnabled returns true if the controller is enabled
+func (r *Config) IsControllerEnabled(name string) bool {
+	if !r.RunsControllers() {
+		return false
+	}
+	for _, c := range r.Controllers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the configuration as yaml
+func (r *Config) String() string {
+	b, err := yaml.Marshal(r)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
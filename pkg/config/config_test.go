@@ -0,0 +1,382 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nokia/k8s-ipam/pkg/backend"
+)
+
+var testControllers = []string{"ipclaim", "vlanclaim"}
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cases := map[string]struct {
+		data string
+		err  string
+	}{
+		"Valid": {
+			data: `
+apiVersion: config.resource.nephio.org/v1alpha1
+kind: ResourceBackendConfig
+grpc:
+  address: ":9090"
+backends:
+  enabled: [ipam]
+`,
+		},
+		"UnknownField": {
+			data: `
+apiVersion: config.resource.nephio.org/v1alpha1
+kind: ResourceBackendConfig
+grpc:
+  port: 9090
+`,
+			err: "unknown field",
+		},
+		"UnsupportedVersion": {
+			data: `
+apiVersion: config.resource.nephio.org/v1
+kind: ResourceBackendConfig
+`,
+			err: "unsupported apiVersion",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := Load(writeConfig(t, tc.data))
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("want error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.GRPC.Address != ":9090" {
+				t.Errorf("want grpc address :9090, got %s", c.GRPC.Address)
+			}
+			// the settings that are not in the file keep their default
+			if c.GRPC.MaxRPC != defaultMaxRPC || !c.GRPC.Insecure {
+				t.Errorf("want default grpc settings, got %+v", c.GRPC)
+			}
+			if !c.IsBackendEnabled(BackendIPAM) || c.IsBackendEnabled(BackendVLAN) {
+				t.Errorf("want only the ipam backend enabled, got %v", c.Backends.Enabled)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		mutate func(c *Config)
+		err    string
+	}{
+		"Default": {
+			mutate: func(c *Config) {},
+		},
+		"UnknownMode": {
+			mutate: func(c *Config) { c.Mode = "standalone" },
+			err:    "unknown mode",
+		},
+		"ControllersInBackendMode": {
+			mutate: func(c *Config) {
+				c.Mode = ModeBackend
+				c.Controllers = []ControllerConfig{{Name: "ipclaim"}}
+			},
+			err: "controllers are not run in the backend mode",
+		},
+		"InvalidAddress": {
+			mutate: func(c *Config) { c.GRPC.Address = "9999" },
+			err:    "invalid grpc address",
+		},
+		"SecureWithoutCertDir": {
+			mutate: func(c *Config) { c.GRPC.Insecure = false },
+			err:    "certDir is required",
+		},
+		"MaxRPC": {
+			mutate: func(c *Config) { c.GRPC.MaxRPC = 0 },
+			err:    "maxRPC must be positive",
+		},
+		"UnknownBackend": {
+			mutate: func(c *Config) { c.Backends.Enabled = []string{"ipam", "vrf"} },
+			err:    "unknown backend \"vrf\"",
+		},
+		"UnknownStorage": {
+			mutate: func(c *Config) { c.Backends.Storage.Type = "etcd" },
+			err:    "unknown storage type",
+		},
+		"UnknownController": {
+			mutate: func(c *Config) { c.Controllers = []ControllerConfig{{Name: "vxlanclaim"}} },
+			err:    "unknown controller \"vxlanclaim\"",
+		},
+		"DuplicateController": {
+			mutate: func(c *Config) { c.Controllers = []ControllerConfig{{Name: "ipclaim"}, {Name: "ipclaim"}} },
+			err:    "duplicate controller \"ipclaim\"",
+		},
+		"LeaderElection": {
+			mutate: func(c *Config) { enableLeaderElection(c) },
+		},
+		"LeaderElectionMemoryStorage": {
+			mutate: func(c *Config) {
+				enableLeaderElection(c)
+				c.Backends.Storage.Type = backend.StorageTypeMemory
+			},
+			err: "leader election requires the configmap storage",
+		},
+		"LeaderElectionControllersMode": {
+			mutate: func(c *Config) {
+				enableLeaderElection(c)
+				c.Mode = ModeControllers
+			},
+			err: "leader election requires the all or backend mode",
+		},
+		"LeaderElectionIdentity": {
+			mutate: func(c *Config) {
+				enableLeaderElection(c)
+				c.Backends.LeaderElection.Identity = ""
+			},
+			err: "leader election identity is required",
+		},
+		"LeaderElectionDurations": {
+			mutate: func(c *Config) {
+				enableLeaderElection(c)
+				c.Backends.LeaderElection.LeaseDuration.Duration = c.Backends.LeaderElection.RenewDeadline.Duration
+			},
+			err: "leaseDuration 10s must be greater than the renewDeadline",
+		},
+		"NegativeConcurrency": {
+			mutate: func(c *Config) { c.Controllers = []ControllerConfig{{Name: "ipclaim", MaxConcurrentReconciles: -1}} },
+			err:    "must not be negative",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := Default()
+			c.Backends.Address = defaultBackendAddress
+			tc.mutate(c)
+			err := c.Validate(testControllers)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("want error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func enableLeaderElection(c *Config) {
+	c.Backends.LeaderElection.Enabled = true
+	c.Backends.LeaderElection.Namespace = "backend-system"
+	c.Backends.LeaderElection.Identity = "10.0.0.1:9999"
+}
+
+func TestComplete(t *testing.T) {
+	path := writeConfig(t, `
+apiVersion: config.resource.nephio.org/v1alpha1
+kind: ResourceBackendConfig
+grpc:
+  address: ":9090"
+  maxRPC: 100
+  timeout: 30s
+backends:
+  address: backend:9999
+  storage:
+    type: memory
+controllers:
+- name: ipclaim
+  maxConcurrentReconciles: 4
+`)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	o := &Options{}
+	o.BindFlags(fs)
+	if err := fs.Parse([]string{
+		"--config", path,
+		"--grpc-max-rpc", "200",
+		"--controllers", "ipclaim,vlanclaim",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := o.Complete(fs, testControllers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the flags that are set override the file
+	if c.GRPC.MaxRPC != 200 {
+		t.Errorf("want maxRPC 200, got %d", c.GRPC.MaxRPC)
+	}
+	// the flags that are not set keep the settings of the file
+	if c.GRPC.Address != ":9090" || c.GRPC.Timeout.Duration != 30*time.Second {
+		t.Errorf("want grpc settings of the file, got %+v", c.GRPC)
+	}
+	if c.Backends.Address != "backend:9999" || c.Backends.Storage.Type != backend.StorageTypeMemory {
+		t.Errorf("want backend settings of the file, got %+v", c.Backends)
+	}
+	want := []ControllerConfig{{Name: "ipclaim", MaxConcurrentReconciles: 4}, {Name: "vlanclaim"}}
+	if len(c.Controllers) != len(want) || c.Controllers[0] != want[0] || c.Controllers[1] != want[1] {
+		t.Errorf("want controllers %v, got %v", want, c.Controllers)
+	}
+}
+
+func TestCompleteEnv(t *testing.T) {
+	t.Setenv(resourceBackendEnv, "env:9999")
+	t.Setenv("ENABLE_VLANCLAIM", "true")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	o := &Options{}
+	o.BindFlags(fs)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	c, err := o.Complete(fs, testControllers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Backends.Address != "env:9999" {
+		t.Errorf("want backend address env:9999, got %s", c.Backends.Address)
+	}
+	if c.IsControllerEnabled("ipclaim") || !c.IsControllerEnabled("vlanclaim") {
+		t.Errorf("want only vlanclaim enabled, got %v", c.Controllers)
+	}
+}
+
+func TestCompleteLeaderElection(t *testing.T) {
+	t.Setenv(podNamespaceEnv, "backend-system")
+	t.Setenv(podIPEnv, "10.0.0.1")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	o := &Options{}
+	o.BindFlags(fs)
+	if err := fs.Parse([]string{"--mode", ModeBackend, "--grpc-address", ":9090", "--backend-leader-elect"}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := o.Complete(fs, testControllers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	le := c.Backends.LeaderElection
+	if !le.Enabled || le.LeaseName != defaultLeaseName {
+		t.Errorf("want leader election with the default lease, got %+v", le)
+	}
+	// the pod namespace and ip are the defaults of the downward api
+	if le.Namespace != "backend-system" || le.Identity != "10.0.0.1:9090" {
+		t.Errorf("want namespace backend-system and identity 10.0.0.1:9090, got %s %s", le.Namespace, le.Identity)
+	}
+}
+
+func TestBackendTLS(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		wantTLS bool
+		wantCA  string
+	}{
+		"Insecure": {
+			args: []string{"--mode", ModeControllers},
+		},
+		"Controllers": {
+			args:    []string{"--mode", ModeControllers, "--grpc-insecure=false", "--grpc-cert-dir", "/certs"},
+			wantTLS: true,
+			wantCA:  "/certs/ca.crt",
+		},
+		"All": {
+			args:    []string{"--mode", ModeAll, "--grpc-insecure=false", "--grpc-cert-dir", "/certs"},
+			wantTLS: true,
+			wantCA:  "/certs/ca.crt",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			o := &Options{}
+			o.BindFlags(fs)
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			c, err := o.Complete(fs, testControllers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tls, ca := c.BackendTLS()
+			if tls != tc.wantTLS || ca != tc.wantCA {
+				t.Errorf("want tls %t with ca %q, got %t with ca %q", tc.wantTLS, tc.wantCA, tls, ca)
+			}
+		})
+	}
+
+	// the ca name of the grpc server is used to verify the backend
+	c := Default()
+	c.GRPC.Insecure = false
+	c.GRPC.CertDir = "/certs"
+	c.GRPC.CaName = "root.crt"
+	if _, ca := c.BackendTLS(); ca != "/certs/root.crt" {
+		t.Errorf("want ca /certs/root.crt, got %s", ca)
+	}
+}
+
+func TestModes(t *testing.T) {
+	t.Setenv("ENABLE_IPCLAIM", "true")
+
+	cases := map[string]struct {
+		backend     bool
+		controllers bool
+	}{
+		ModeAll:         {backend: true, controllers: true},
+		ModeBackend:     {backend: true},
+		ModeControllers: {controllers: true},
+	}
+	for mode, tc := range cases {
+		t.Run(mode, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			o := &Options{}
+			o.BindFlags(fs)
+			if err := fs.Parse([]string{"--mode", mode}); err != nil {
+				t.Fatal(err)
+			}
+			c, err := o.Complete(fs, testControllers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.RunsBackend() != tc.backend || c.IsBackendEnabled(BackendIPAM) != tc.backend {
+				t.Errorf("want backend %t, got %t", tc.backend, c.RunsBackend())
+			}
+			// the deprecated environment variables only enable controllers
+			// in the modes that run them
+			if c.RunsControllers() != tc.controllers || c.IsControllerEnabled("ipclaim") != tc.controllers {
+				t.Errorf("want controllers %t, got %t", tc.controllers, c.RunsControllers())
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/nokia/k8s-ipam/pkg/backend"
+)
+
+const (
+	// deprecated environment variables, used when neither the configuration
+	// file nor the flags set the backend address or the controllers
+	resourceBackendEnv = "RESOURCE_BACKEND"
+	enableEnvPrefix    = "ENABLE_"
+	// downward api environment variables, used as the defaults of the leader
+	// election
+	podNamespaceEnv = "POD_NAMESPACE"
+	podIPEnv        = "POD_IP"
+)
+
+// Options are the flags of the configuration. A flag that is set overrides the
+// setting of the configuration file.
+type Options struct {
+	// File is the configuration file
+	File string
+
+	flags       Config
+	controllers []string
+}
+
+// BindFlags registers the flags of the configuration
+func (r *Options) BindFlags(fs *flag.FlagSet) {
+	d := Default()
+	fs.StringVar(&r.File, "config", "",
+		"The configuration file, flags that are set override the settings of the file.")
+	fs.StringVar(&r.flags.Mode, "mode", d.Mode,
+		"The components to run: all, backend (grpc server and backends) or controllers (against a remote backend).")
+	fs.StringVar(&r.flags.GRPC.Address, "grpc-address", d.GRPC.Address,
+		"The address the resource grpc server listens on.")
+	fs.BoolVar(&r.flags.GRPC.Insecure, "grpc-insecure", d.GRPC.Insecure,
+		"Serve the resource grpc api without tls, the controllers connect to the resource backend without tls as well.")
+	fs.StringVar(&r.flags.GRPC.CertDir, "grpc-cert-dir", "",
+		"The directory with the tls.crt, tls.key and ca.crt of the resource grpc server.")
+	fs.Int64Var(&r.flags.GRPC.MaxRPC, "grpc-max-rpc", d.GRPC.MaxRPC,
+		"The max number of concurrent requests of the resource grpc server.")
+	fs.StringVar(&r.flags.Backends.Address, "backend-address", "",
+		"The address of the resource backend the controllers connect to.")
+	fs.Func("backends", "Comma separated backends served by the grpc server: ipam, vlan and vxlan.", func(s string) error {
+		r.flags.Backends.Enabled = splitList(s)
+		return nil
+	})
+	fs.Func("storage-type", "The storage of the backend indices: configmap or memory.", func(s string) error {
+		r.flags.Backends.Storage.Type = backend.StorageType(s)
+		return nil
+	})
+	fs.StringVar(&r.flags.Backends.Storage.Namespace, "storage-namespace", "",
+		"The namespace of the configmaps of the backend indices, defaults to the namespace of the index.")
+	fs.BoolVar(&r.flags.Backends.LeaderElection.Enabled, "backend-leader-elect", false,
+		"Run the backend instances active/standby, only the elected leader serves the resource grpc api.")
+	fs.StringVar(&r.flags.Backends.LeaderElection.Namespace, "backend-leader-elect-namespace", "",
+		"The namespace of the backend lease, defaults to the POD_NAMESPACE environment variable.")
+	fs.StringVar(&r.flags.Backends.LeaderElection.Identity, "backend-leader-elect-identity", "",
+		"The address the controllers reach this backend instance on, defaults to the POD_IP environment variable and the grpc port.")
+	fs.Func("controllers", "Comma separated controllers to enable.", func(s string) error {
+		r.controllers = splitList(s)
+		return nil
+	})
+}
+
+// Complete returns the validated configuration of the configuration file, the
+// flags that are set and the defaults. The controllers are the names of the
+// registered controllers.
+func (r *Options) Complete(fs *flag.FlagSet, controllers []string) (*Config, error) {
+	c := Default()
+	if r.File != "" {
+		var err error
+		c, err = Load(r.File)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "mode":
+			c.Mode = r.flags.Mode
+		case "grpc-address":
+			c.GRPC.Address = r.flags.GRPC.Address
+		case "grpc-insecure":
+			c.GRPC.Insecure = r.flags.GRPC.Insecure
+		case "grpc-cert-dir":
+			c.GRPC.CertDir = r.flags.GRPC.CertDir
+		case "grpc-max-rpc":
+			c.GRPC.MaxRPC = r.flags.GRPC.MaxRPC
+		case "backend-address":
+			c.Backends.Address = r.flags.Backends.Address
+		case "backends":
+			c.Backends.Enabled = r.flags.Backends.Enabled
+		case "storage-type":
+			c.Backends.Storage.Type = r.flags.Backends.Storage.Type
+		case "storage-namespace":
+			c.Backends.Storage.Namespace = r.flags.Backends.Storage.Namespace
+		case "backend-leader-elect":
+			c.Backends.LeaderElection.Enabled = r.flags.Backends.LeaderElection.Enabled
+		case "backend-leader-elect-namespace":
+			c.Backends.LeaderElection.Namespace = r.flags.Backends.LeaderElection.Namespace
+		case "backend-leader-elect-identity":
+			c.Backends.LeaderElection.Identity = r.flags.Backends.LeaderElection.Identity
+		case "controllers":
+			c.Controllers = mergeControllers(c.Controllers, r.controllers)
+		}
+	})
+
+	if c.Backends.Address == "" {
+		c.Backends.Address = os.Getenv(resourceBackendEnv)
+	}
+	if c.Backends.Address == "" {
+		c.Backends.Address = defaultBackendAddress
+	}
+	if le := &c.Backends.LeaderElection; le.Enabled {
+		if le.Namespace == "" {
+			le.Namespace = os.Getenv(podNamespaceEnv)
+		}
+		if le.Identity == "" {
+			le.Identity = defaultIdentity(c.GRPC.Address)
+		}
+	}
+	if c.Controllers == nil && c.RunsControllers() {
+		for _, name := range controllers {
+			if _, ok := os.LookupEnv(enableEnvPrefix + strings.ToUpper(name)); ok {
+				c.Controllers = append(c.Controllers, ControllerConfig{Name: name})
+			}
+		}
+	}
+
+	if err := c.Validate(controllers); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return c, nil
+}
+
+// mergeControllers returns the controllers of the flag, keeping the settings
+// of the controllers in the configuration file
+func mergeControllers(cfg []ControllerConfig, names []string) []ControllerConfig {
+	controllers := make([]ControllerConfig, 0, len(names))
+	for _, name := range names {
+		c := ControllerConfig{Name: name}
+		for _, cc := range cfg {
+			if cc.Name == name {
+				c = cc
+			}
+		}
+		controllers = append(controllers, c)
+	}
+	return controllers
+}
+
+// defaultIdentity returns the pod ip with the port of the grpc address, empty
+// when the pod ip is unknown
+func defaultIdentity(grpcAddress string) string {
+	ip := os.Getenv(podIPEnv)
+	_, port, err := net.SplitHostPort(grpcAddress)
+	if ip == "" || err != nil {
+		return ""
+	}
+	return net.JoinHostPort(ip, port)
+}
+
+func splitList(s string) []string {
+	l := []string{}
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			l = append(l, e)
+		}
+	}
+	return l
+}
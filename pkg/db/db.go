@@ -18,6 +18,9 @@ type DB[T constraints.Integer] interface {
 	Has(id T) bool
 	Delete(id T) error
 	Count() int
+	// Clone returns a copy of the db, setting or deleting entries in the
+	// copy does not change the db
+	Clone() DB[T]
 	Iterate() *Iterator[T]
 	IterateFree() *Iterator[T]
 
@@ -143,6 +146,21 @@ func (r *db[T]) Count() int {
 	return len(r.store)
 }
 
+func (r *db[T]) Clone() DB[T] {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	store := make(map[T]Entry[T], len(r.store))
+	for id, e := range r.store {
+		store[id] = e
+	}
+	return &db[T]{
+		m:     &sync.RWMutex{},
+		store: store,
+		cfg:   r.cfg,
+	}
+}
+
 func (r *db[T]) Iterate() *Iterator[T] {
 	r.m.RLock()
 	defer r.m.RUnlock()
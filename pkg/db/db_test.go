@@ -404,6 +404,28 @@ func TestCount(t *testing.T) {
 	}
 }
 
+func TestClone(t *testing.T) {
+	d := NewDB(&DBConfig[uint16]{
+		InitEntries: Entries[uint16]{
+			NewEntry(uint16(10), map[string]string{"a": "b"}),
+			NewEntry(uint16(11), map[string]string{"a": "b"}),
+		},
+	})
+	c := d.Clone()
+	if err := c.Set(NewEntry(uint16(12), map[string]string{"a": "b"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(uint16(10)); err != nil {
+		t.Fatal(err)
+	}
+	if d.Count() != 2 || !d.Has(10) || d.Has(12) {
+		t.Errorf("TestClone: the db changed with the clone, got %v", d.GetAll())
+	}
+	if c.Count() != 2 || c.Has(10) || !c.Has(12) {
+		t.Errorf("TestClone: unexpected clone, got %v", c.GetAll())
+	}
+}
+
 func TestIterate(t *testing.T) {
 	initEntries := Entries[uint16]{
 		NewEntry(uint16(0), map[string]string{"a": "b", "x": "y"}),
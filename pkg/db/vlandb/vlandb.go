@@ -19,6 +19,47 @@ func New[T uint16]() db.DB[T] {
 	})
 }
 
+const (
+	// maxVLANID is the highest VLAN ID of the VLAN database
+	maxVLANID = 4095
+	// maxVLANSize is the number of VLAN IDs that can be claimed,
+	// VLAN 0, 1 and 4095 are reserved
+	maxVLANSize = maxVLANID - 2
+)
+
+// ValidateVLANID returns an error if the VLAN ID is out of range or reserved
+func ValidateVLANID(id uint16) error {
+	if id > maxVLANID {
+		return fmt.Errorf("VLAN %d is out of range, max VLAN is %d", id, maxVLANID)
+	}
+	return setVLANValidation(id)
+}
+
+// ValidateVLANSize returns an error if the amount of VLANs cannot be claimed
+func ValidateVLANSize(size uint16) error {
+	if size == 0 || size > maxVLANSize {
+		return fmt.Errorf("VLAN size %d is out of range, expecting 1..%d", size, maxVLANSize)
+	}
+	return nil
+}
+
+// ValidateVLANRange returns an error if the range starting at start with
+// the given size contains VLAN IDs that are out of range or reserved
+func ValidateVLANRange(start, size uint16) error {
+	if err := ValidateVLANSize(size); err != nil {
+		return err
+	}
+	if err := ValidateVLANID(start); err != nil {
+		return err
+	}
+	// avoid an overflow of the uint16
+	end := uint32(start) + uint32(size) - 1
+	if end > maxVLANID {
+		return fmt.Errorf("VLAN range end %d is out of range, max VLAN is %d", end, maxVLANID)
+	}
+	return ValidateVLANID(uint16(end))
+}
+
 func setVLANValidation[T uint16](id T) error {
 	// TODO validate max entries
 	switch id {
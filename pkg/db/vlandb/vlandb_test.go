@@ -47,3 +47,57 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateVLANRange(t *testing.T) {
+	cases := map[string]struct {
+		start       uint16
+		size        uint16
+		expectedErr bool
+	}{
+		"Valid": {
+			start:       100,
+			size:        10,
+			expectedErr: false,
+		},
+		"Single": {
+			start:       4094,
+			size:        1,
+			expectedErr: false,
+		},
+		"Full": {
+			start:       2,
+			size:        4093,
+			expectedErr: false,
+		},
+		"ZeroSize": {
+			start:       100,
+			size:        0,
+			expectedErr: true,
+		},
+		"StartReserved": {
+			start:       1,
+			size:        10,
+			expectedErr: true,
+		},
+		"EndReserved": {
+			start:       4090,
+			size:        6,
+			expectedErr: true,
+		},
+		"EndOutOfRange": {
+			start:       4000,
+			size:        1000,
+			expectedErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateVLANRange(tc.start, tc.size)
+			if !tc.expectedErr {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package election
+
+import "context"
+
+type leaderKey struct{}
+
+// NewContext returns a context that carries the context of the leadership
+// the request is served under
+func NewContext(ctx, leaderCtx context.Context) context.Context {
+	return context.WithValue(ctx, leaderKey{}, leaderCtx)
+}
+
+// FromContext returns the context of the leadership carried by the context,
+// nil when the request is not served under a leadership
+func FromContext(ctx context.Context) context.Context {
+	leaderCtx, _ := ctx.Value(leaderKey{}).(context.Context)
+	return leaderCtx
+}
+
+// IsLeading returns false when the leadership the request is served under is
+// lost. A request that is not served under a leadership, e.g. when the leader
+// election is disabled or the state is restored, is leading.
+func IsLeading(ctx context.Context) bool {
+	leaderCtx := FromContext(ctx)
+	return leaderCtx == nil || leaderCtx.Err() == nil
+}
@@ -0,0 +1,236 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package election runs the resource backend active/standby. The backend
+// instances elect a leader with a lease, only the leader serves the resource
+// api.
+package election
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+)
+
+type Config struct {
+	// Namespace of the lease
+	Namespace string
+	// LeaseName is the name of the lease shared by the backend instances
+	LeaseName string
+	// Identity is the address the clients reach the grpc server of the
+	// instance on, it is returned to the clients as the leader address
+	Identity string
+	// LeaseDuration is the time the followers wait before taking over the
+	// lease of a leader that stopped renewing it, defaults to 15s
+	LeaseDuration time.Duration
+	// RenewDeadline is the time the leader retries renewing the lease before
+	// it stops leading, defaults to 10s
+	RenewDeadline time.Duration
+	// RetryPeriod is the time between the attempts to acquire or renew the
+	// lease, defaults to 2s
+	RetryPeriod time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.LeaseDuration <= 0 {
+		c.LeaseDuration = defaultLeaseDuration
+	}
+	if c.RenewDeadline <= 0 {
+		c.RenewDeadline = defaultRenewDeadline
+	}
+	if c.RetryPeriod <= 0 {
+		c.RetryPeriod = defaultRetryPeriod
+	}
+}
+
+// RestoreFn restores the state of the backends, a new leader only serves
+// once the state is restored
+type RestoreFn func(ctx context.Context) error
+
+type Elector interface {
+	// Start runs the election till the context is cancelled, the lease is
+	// released when the instance is leading
+	Start(ctx context.Context) error
+	// NeedLeaderElection returns false, since every instance takes part in
+	// the election independent of the leader election of the manager
+	NeedLeaderElection() bool
+	// IsLeader returns true when the instance is leading and the state is
+	// restored
+	IsLeader() bool
+	// Leader returns the identity of the current leader, empty when no leader
+	// is elected
+	Leader() string
+	// LeaderContext returns a context which is cancelled when the instance
+	// stops leading, nil when the instance is not the serving leader
+	LeaderContext() context.Context
+	// Ready returns an error when the instance is not the serving leader
+	Ready() error
+}
+
+func New(c kubernetes.Interface, cfg Config, restore RestoreFn) (Elector, error) {
+	cfg.setDefaults()
+	if cfg.Namespace == "" || cfg.LeaseName == "" {
+		return nil, fmt.Errorf("the namespace and name of the lease are required")
+	}
+	if cfg.Identity == "" {
+		return nil, fmt.Errorf("the identity is required")
+	}
+	if restore == nil {
+		return nil, fmt.Errorf("restore callback fn is required")
+	}
+	return &elector{
+		cfg:     cfg,
+		restore: restore,
+		lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Namespace: cfg.Namespace,
+				Name:      cfg.LeaseName,
+			},
+			Client: c.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: cfg.Identity,
+			},
+		},
+		l: ctrl.Log.WithName("election").WithValues("identity", cfg.Identity),
+	}, nil
+}
+
+type elector struct {
+	cfg     Config
+	restore RestoreFn
+	lock    resourcelock.Interface
+	l       logr.Logger
+
+	m sync.RWMutex
+	// leading is the context of the leadership, set once the state is
+	// restored
+	leading context.Context
+	leader  string
+}
+
+func (r *elector) Start(ctx context.Context) error {
+	r.l.Info("start", "lease", r.cfg.Namespace+"/"+r.cfg.LeaseName)
+	for {
+		le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:            r.lock,
+			Name:            r.cfg.LeaseName,
+			LeaseDuration:   r.cfg.LeaseDuration,
+			RenewDeadline:   r.cfg.RenewDeadline,
+			RetryPeriod:     r.cfg.RetryPeriod,
+			ReleaseOnCancel: true,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: r.lead,
+				OnStoppedLeading: r.stopLeading,
+				OnNewLeader:      r.setLeader,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		// run returns when the leadership is lost, the instance continues
+		// as a follower
+		le.Run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (r *elector) NeedLeaderElection() bool {
+	return false
+}
+
+// lead restores the state before serving, since the previous leader changed
+// the state. The context is cancelled when the leadership is lost.
+func (r *elector) lead(ctx context.Context) {
+	r.l.Info("started leading, restoring the state")
+	if err := wait.PollUntilContextCancel(ctx, r.cfg.RetryPeriod, true, func(ctx context.Context) (bool, error) {
+		if err := r.restore(ctx); err != nil {
+			r.l.Error(err, "cannot restore the state")
+			return false, nil
+		}
+		return true, nil
+	}); err != nil {
+		// the leadership is lost before the state is restored
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	r.leading = ctx
+	r.l.Info("serving as leader")
+}
+
+func (r *elector) stopLeading() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.leading = nil
+	r.l.Info("stopped leading")
+}
+
+func (r *elector) setLeader(identity string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.leader = identity
+	r.l.Info("new leader", "leader", identity)
+}
+
+func (r *elector) IsLeader() bool {
+	return r.LeaderContext() != nil
+}
+
+func (r *elector) Leader() string {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	return r.leader
+}
+
+func (r *elector) LeaderContext() context.Context {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	if r.leading == nil || r.leading.Err() != nil {
+		return nil
+	}
+	return r.leading
+}
+
+func (r *elector) Ready() error {
+	if r.IsLeader() {
+		return nil
+	}
+	leader := r.Leader()
+	if leader == "" || leader == r.cfg.Identity {
+		return fmt.Errorf("not leader, no leader is serving")
+	}
+	return fmt.Errorf("not leader, the leader is %s", leader)
+}
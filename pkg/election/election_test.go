@@ -0,0 +1,333 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package election_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	quotav1alpha1 "github.com/nokia/k8s-ipam/apis/resource/quota/v1alpha1"
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/internal/grpcserver"
+	"github.com/nokia/k8s-ipam/internal/healthhandler"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/backend/vlan"
+	"github.com/nokia/k8s-ipam/pkg/election"
+	"github.com/nokia/k8s-ipam/pkg/proto/resource"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
+	"github.com/nokia/k8s-ipam/pkg/proxy/serverproxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+const (
+	testNamespace = "default"
+	testTimeout   = 30 * time.Second
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		vlanv1alpha1.AddToScheme,
+		quotav1alpha1.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("cannot add scheme: %s", err.Error())
+		}
+	}
+	return scheme
+}
+
+// TestFailover runs two backend instances sharing a fake api server
+func TestFailover(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+	testFailover(t, c, k8sfake.NewSimpleClientset())
+}
+
+// TestFailoverEnvtest runs two backend instances sharing an api server, it
+// requires the envtest binaries, e.g. KUBEBUILDER_ASSETS as set by make test
+func TestFailoverEnvtest(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS not set, skipping envtest")
+	}
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("cannot start envtest: %s", err.Error())
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("cannot stop envtest: %s", err.Error())
+		}
+	}()
+	c, err := client.New(cfg, client.Options{Scheme: newScheme(t)})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err.Error())
+	}
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		t.Fatalf("cannot create clientset: %s", err.Error())
+	}
+	testFailover(t, c, cs)
+}
+
+func testFailover(t *testing.T, c client.Client, cs kubernetes.Interface) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	instances := []*instance{startInstance(t, c, cs), startInstance(t, c, cs)}
+	leader, follower := waitForLeader(ctx, t, instances)
+
+	// the follower rejects the requests with the address of the leader
+	conn, err := grpc.Dial(follower.address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var trailer metadata.MD
+	_, err = resourcepb.NewResourceClient(conn).CreateIndex(ctx, indexRequest(t), grpc.Trailer(&trailer))
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("want unavailable from the follower, got %v", err)
+	}
+	if got := trailer.Get(election.LeaderMetadataKey); len(got) != 1 || got[0] != leader.address {
+		t.Errorf("want leader %s in the trailer, got %v", leader.address, got)
+	}
+
+	// the client sends the requests to the serving leader
+	rc, err := resource.New(&resource.Config{
+		Address:            instances[0].address + "," + instances[1].address,
+		Insecure:           true,
+		HealthService:      vlanv1alpha1.GroupVersion.String(),
+		ReconnectBaseDelay: 50 * time.Millisecond,
+		ReconnectMaxDelay:  time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Delete()
+
+	retry(ctx, t, func() error {
+		_, err := rc.Get().CreateIndex(ctx, indexRequest(t))
+		return err
+	})
+	vlanID := claim(ctx, t, rc, c, "claim-1")
+
+	// the new leader restores the index and the claims before serving
+	leader.stop()
+	if got := claim(ctx, t, rc, c, "claim-1"); got != vlanID {
+		t.Errorf("want vlan %d of claim-1 restored, got %d", vlanID, got)
+	}
+	if follower.elector.Leader() != follower.address || !follower.elector.IsLeader() {
+		t.Errorf("want %s leading, got %s", follower.address, follower.elector.Leader())
+	}
+	if got := claim(ctx, t, rc, c, "claim-2"); got == vlanID {
+		t.Errorf("want claim-2 to get another vlan than %d", vlanID)
+	}
+}
+
+type instance struct {
+	address string
+	elector election.Elector
+	stop    context.CancelFunc
+}
+
+func startInstance(t *testing.T, c client.Client, cs kubernetes.Interface) *instance {
+	t.Helper()
+	be, err := vlan.New(c, backend.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := freeAddress(t)
+	e, err := election.New(cs, election.Config{
+		Namespace:     testNamespace,
+		LeaseName:     "resource-backend",
+		Identity:      address,
+		LeaseDuration: 2 * time.Second,
+		RenewDeadline: time.Second,
+		RetryPeriod:   100 * time.Millisecond,
+	}, be.Restore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wh := healthhandler.New(healthhandler.Config{
+		Checks: map[string]healthhandler.CheckFn{
+			vlanv1alpha1.GroupVersion.String(): func() error {
+				if err := e.Ready(); err != nil {
+					return err
+				}
+				return be.Ready()
+			},
+		},
+		Interval: 50 * time.Millisecond,
+	})
+	sp := serverproxy.New(&serverproxy.Config{
+		Backends: map[schema.GroupVersion]backend.Backend{vlanv1alpha1.GroupVersion: be},
+	})
+	s := grpcserver.New(grpcserver.Config{Address: address, Insecure: true},
+		grpcserver.WithCreateIndexHandler(sp.CreateIndex),
+		grpcserver.WithDeleteIndexHandler(sp.DeleteIndex),
+		grpcserver.WithGetClaimHandler(sp.GetClaim),
+		grpcserver.WithClaimHandler(sp.Claim),
+		grpcserver.WithDeleteClaimHandler(sp.DeleteClaim),
+		grpcserver.WithWatchClaimHandler(sp.Watch),
+		grpcserver.WithWatchHandler(wh.Watch),
+		grpcserver.WithCheckHandler(wh.Check),
+		grpcserver.WithLeaderElector(e),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go wh.Start(ctx)
+	go func() {
+		if err := e.Start(ctx); err != nil {
+			t.Errorf("cannot start election: %s", err.Error())
+		}
+	}()
+	go func() {
+		if err := s.Start(ctx); err != nil {
+			t.Errorf("cannot start grpc server: %s", err.Error())
+		}
+	}()
+	return &instance{address: address, elector: e, stop: cancel}
+}
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// waitForLeader returns the leader and the follower once the leader serves
+func waitForLeader(ctx context.Context, t *testing.T, instances []*instance) (*instance, *instance) {
+	t.Helper()
+	for {
+		for i, inst := range instances {
+			if inst.elector.IsLeader() {
+				return inst, instances[1-i]
+			}
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("no leader elected")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// retry retries the request till it succeeds, the requests fail while the
+// leader fails over
+func retry(ctx context.Context, t *testing.T, fn func() error) {
+	t.Helper()
+	for {
+		err := fn()
+		if err == nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("request failed: %s", err.Error())
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func indexRequest(t *testing.T) *resourcepb.ClaimRequest {
+	t.Helper()
+	index := vlanv1alpha1.BuildVLANIndex(
+		metav1.ObjectMeta{Name: "vpc-1", Namespace: testNamespace},
+		vlanv1alpha1.VLANIndexSpec{},
+		vlanv1alpha1.VLANIndexStatus{},
+	)
+	b, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clientproxy.BuildResourcePb(index, index.GetName(), string(b), "never", vlanv1alpha1.VLANIndexGroupVersionKind)
+}
+
+// claim claims a dynamic vlan, the claim is stored in the api server such
+// that it is restored by a new leader
+func claim(ctx context.Context, t *testing.T, rc resource.Client, c client.Client, name string) uint16 {
+	t.Helper()
+	cr := vlanv1alpha1.BuildVLANClaim(
+		metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		vlanv1alpha1.VLANClaimSpec{
+			VLANIndex: corev1.ObjectReference{Name: "vpc-1", Namespace: testNamespace},
+		},
+		vlanv1alpha1.VLANClaimStatus{},
+	)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cr), &vlanv1alpha1.VLANClaim{}); err != nil {
+		if err := c.Create(ctx, cr.DeepCopy()); err != nil {
+			t.Fatalf("cannot create claim: %s", err.Error())
+		}
+	}
+	cr.AddOwnerLabelsToCR()
+	b, err := json.Marshal(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := clientproxy.BuildResourcePb(cr, cr.GetName(), string(b), "never", vlanv1alpha1.VLANClaimGroupVersionKind)
+
+	var rsp *resourcepb.ClaimResponse
+	retry(ctx, t, func() error {
+		rsp, err = rc.Get().Claim(ctx, req)
+		if err != nil {
+			return err
+		}
+		if rsp.StatusCode != resourcepb.StatusCode_Valid {
+			return fmt.Errorf("claim %s: %s", name, rsp.ErrorMessage)
+		}
+		return nil
+	})
+	claimed := &vlanv1alpha1.VLANClaim{}
+	if err := json.Unmarshal([]byte(rsp.Status), claimed); err != nil {
+		t.Fatal(err)
+	}
+	if claimed.Status.VLANID == nil {
+		t.Fatalf("claim %s: no vlan claimed", name)
+	}
+	return *claimed.Status.VLANID
+}
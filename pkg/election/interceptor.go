@@ -0,0 +1,119 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package election
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// LeaderMetadataKey is the trailer with the address of the leader, which
+	// is returned when a follower rejects a request
+	LeaderMetadataKey = "resource-leader"
+	// only the resource service is served by the leader, the health service
+	// reports the followers as not serving
+	resourceServicePrefix = "/resource.Resource/"
+)
+
+// UnaryServerInterceptor rejects the resource requests with a retriable
+// unavailable status when the instance is not the serving leader. The request
+// is cancelled when the instance stops leading while it is handled, such that
+// the client retries the request on the new leader.
+func UnaryServerInterceptor(e Elector) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, resourceServicePrefix) {
+			return handler(ctx, req)
+		}
+		leaderCtx := e.LeaderContext()
+		if leaderCtx == nil {
+			return nil, notLeader(e, func(md metadata.MD) { grpc.SetTrailer(ctx, md) })
+		}
+		ctx, cancel := cancelOnLeaderLost(ctx, leaderCtx)
+		defer cancel()
+		resp, err := handler(NewContext(ctx, leaderCtx), req)
+		if leaderCtx.Err() != nil {
+			// the changes of the request are not stored, the new leader
+			// restores the state without them
+			return nil, notLeader(e, func(md metadata.MD) { grpc.SetTrailer(ctx, md) })
+		}
+		return resp, err
+	}
+}
+
+// cancelOnLeaderLost returns a context which is cancelled when the instance
+// stops leading
+func cancelOnLeaderLost(ctx, leaderCtx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-leaderCtx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// StreamServerInterceptor rejects the resource streams when the instance is
+// not the serving leader and ends the streams when the instance stops leading
+func StreamServerInterceptor(e Elector) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, resourceServicePrefix) {
+			return handler(srv, ss)
+		}
+		leaderCtx := e.LeaderContext()
+		if leaderCtx == nil {
+			return notLeader(e, ss.SetTrailer)
+		}
+		ctx, cancel := cancelOnLeaderLost(ss.Context(), leaderCtx)
+		defer cancel()
+		if err := handler(srv, &leaderStream{ServerStream: ss, ctx: NewContext(ctx, leaderCtx)}); err != nil {
+			return err
+		}
+		if leaderCtx.Err() != nil {
+			// the client watches again, such that it is served by the new
+			// leader
+			return notLeader(e, ss.SetTrailer)
+		}
+		return nil
+	}
+}
+
+type leaderStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (r *leaderStream) Context() context.Context {
+	return r.ctx
+}
+
+func notLeader(e Elector, setTrailer func(metadata.MD)) error {
+	leader := e.Leader()
+	setTrailer(metadata.Pairs(LeaderMetadataKey, leader))
+	// the instance might have become the leader in the meantime
+	if err := e.Ready(); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.Unavailable, "not leader")
+}
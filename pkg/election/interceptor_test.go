@@ -0,0 +1,75 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package election_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nokia/k8s-ipam/pkg/election"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// leaderElector is the serving leader till the leader context is cancelled
+type leaderElector struct {
+	election.Elector
+	ctx context.Context
+}
+
+func (r *leaderElector) LeaderContext() context.Context {
+	if r.ctx.Err() != nil {
+		return nil
+	}
+	return r.ctx
+}
+
+func (r *leaderElector) Leader() string { return "other" }
+
+func (r *leaderElector) Ready() error { return nil }
+
+func TestUnaryServerInterceptorLeaderLost(t *testing.T) {
+	leaderCtx, lose := context.WithCancel(context.Background())
+	interceptor := election.UnaryServerInterceptor(&leaderElector{ctx: leaderCtx})
+	info := &grpc.UnaryServerInfo{FullMethod: "/resource.Resource/Claim"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		if !election.IsLeading(ctx) {
+			t.Errorf("want leading before the leadership is lost")
+		}
+		lose()
+		// the request is cancelled when the leadership is lost
+		<-ctx.Done()
+		if election.IsLeading(ctx) {
+			t.Errorf("want not leading after the leadership is lost")
+		}
+		return "done", nil
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("want unavailable when the leadership is lost, got %v", err)
+	}
+
+	// the instance is no longer the serving leader
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		t.Errorf("want the request rejected")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("want unavailable when not leader, got %v", err)
+	}
+}
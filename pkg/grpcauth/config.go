@@ -0,0 +1,112 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	AuthenticationNone        = "none"
+	AuthenticationTokenReview = "tokenreview"
+
+	AuthorizationNone                = "none"
+	AuthorizationSubjectAccessReview = "subjectaccessreview"
+	AuthorizationPolicy              = "policy"
+)
+
+// Config selects the authentication and authorization of the resource api
+type Config struct {
+	// Authentication is none or tokenreview
+	Authentication string
+	// Authorization is none, subjectaccessreview or policy, it defaults to
+	// subjectaccessreview when the callers are authenticated
+	Authorization string
+	// PolicyFile is the static policy used by the policy authorization
+	PolicyFile string
+	// Audiences the tokens are validated against, defaults to the audiences
+	// of the api server
+	Audiences []string
+	// CacheTTL is the time a token review is cached
+	CacheTTL time.Duration
+}
+
+// BindFlags registers the flags of the grpc authentication and authorization
+func (r *Config) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&r.Authentication, "grpc-authentication", AuthenticationNone,
+		"The authentication of the resource grpc api callers: none or tokenreview.")
+	fs.StringVar(&r.Authorization, "grpc-authorization", "",
+		"The authorization of the resource grpc api requests: none, subjectaccessreview or policy, defaults to subjectaccessreview with authentication and none without.")
+	fs.StringVar(&r.PolicyFile, "grpc-authorization-policy-file", "",
+		"The static policy file used by the policy authorization.")
+	fs.Func("grpc-token-audiences", "Comma separated audiences the tokens are validated against.", func(s string) error {
+		r.Audiences = strings.Split(s, ",")
+		return nil
+	})
+	fs.DurationVar(&r.CacheTTL, "grpc-token-cache-ttl", defaultCacheTTL,
+		"The time a token review result is cached.")
+}
+
+// Build returns the authenticator and authorizer selected by the config. The
+// authenticator and authorizer are nil when authentication is disabled, the
+// authentication is rejected without an authorization.
+func (r *Config) Build(c client.Client) (Authenticator, Authorizer, error) {
+	var authn Authenticator
+	switch r.Authentication {
+	case "", AuthenticationNone:
+	case AuthenticationTokenReview:
+		authn = NewTokenReviewAuthenticator(c, r.Audiences, r.CacheTTL)
+	default:
+		return nil, nil, fmt.Errorf("unknown grpc authentication %q", r.Authentication)
+	}
+
+	authorization := r.Authorization
+	if authorization == "" {
+		authorization = AuthorizationNone
+		if authn != nil {
+			authorization = AuthorizationSubjectAccessReview
+		}
+	}
+
+	var authz Authorizer
+	switch authorization {
+	case AuthorizationNone:
+		if authn != nil {
+			return nil, nil, fmt.Errorf("grpc authentication %s requires authorization", r.Authentication)
+		}
+		return nil, nil, nil
+	case AuthorizationSubjectAccessReview:
+		authz = NewSubjectAccessReviewAuthorizer(c)
+	case AuthorizationPolicy:
+		p, err := LoadPolicy(r.PolicyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		authz = p
+	default:
+		return nil, nil, fmt.Errorf("unknown grpc authorization %q", authorization)
+	}
+	if authn == nil {
+		return nil, nil, fmt.Errorf("grpc authorization %s requires authentication", authorization)
+	}
+	return authn, authz, nil
+}
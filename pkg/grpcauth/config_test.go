@@ -0,0 +1,69 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"testing"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuild(t *testing.T) {
+	cases := map[string]struct {
+		cfg       Config
+		wantAuthn bool
+		wantAuthz bool
+		wantErr   bool
+	}{
+		"Disabled": {
+			cfg: Config{},
+		},
+		"DefaultAuthorization": {
+			cfg:       Config{Authentication: AuthenticationTokenReview},
+			wantAuthn: true,
+			wantAuthz: true,
+		},
+		"AuthenticationWithoutAuthorization": {
+			cfg:     Config{Authentication: AuthenticationTokenReview, Authorization: AuthorizationNone},
+			wantErr: true,
+		},
+		"AuthorizationWithoutAuthentication": {
+			cfg:     Config{Authorization: AuthorizationSubjectAccessReview},
+			wantErr: true,
+		},
+		"UnknownAuthorization": {
+			cfg:     Config{Authentication: AuthenticationTokenReview, Authorization: "unknown"},
+			wantErr: true,
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			authn, authz, err := tc.cfg.Build(c)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error %t, got %v", tc.wantErr, err)
+			}
+			if (authn != nil) != tc.wantAuthn {
+				t.Errorf("want authenticator %t, got %v", tc.wantAuthn, authn)
+			}
+			if (authz != nil) != tc.wantAuthz {
+				t.Errorf("want authorizer %t, got %v", tc.wantAuthz, authz)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// UserInfo is the identity of an authenticated caller
+type UserInfo struct {
+	Username string
+	UID      string
+	Groups   []string
+	Extra    map[string][]string
+}
+
+// Attributes describe the action a caller performs on the resource api
+type Attributes struct {
+	User      *UserInfo
+	Verb      string
+	Namespace string
+	Group     string
+	Resource  string
+	Name      string
+}
+
+// Authenticator validates a bearer token and returns the identity of the caller
+type Authenticator interface {
+	// Authenticate returns nil and no error when the token is not valid
+	Authenticate(ctx context.Context, token string) (*UserInfo, error)
+}
+
+// Authorizer decides if a caller may perform the action described by the attributes
+type Authorizer interface {
+	// Authorize returns if the action is allowed and a reason why it is not
+	Authorize(ctx context.Context, attrs Attributes) (bool, string, error)
+}
+
+// methodVerbs maps the methods of the resource service to the verbs that
+// are checked by the authorizer
+var methodVerbs = map[string]string{
+	"CreateIndex": "create",
+	"DeleteIndex": "delete",
+	"GetClaim":    "get",
+	"Claim":       "create",
+	"DeleteClaim": "delete",
+	"WatchClaim":  "watch",
+	"Export":      "get",
+	"Import":      "create",
+	"GetTree":     "get",
+}
+
+// snapshotResource is the resource that is checked by the authorizer for the
+// cluster wide export and import of the backend snapshots
+var snapshotResource = schema.GroupResource{Group: "resource.nephio.org", Resource: "snapshots"}
+
+type headerGetter interface {
+	GetHeader() *resourcepb.Header
+}
+
+type specGetter interface {
+	GetSpec() string
+}
+
+// GetAttributes returns the attributes of a resource request; the namespace
+// and name are taken from the spec the backend acts on, the group and
+// resource are derived from the gvk in the header. Requests without a spec
+// use the namespace of the owner in the header. A request whose header does
+// not match its spec is rejected, since the header is supplied by the client.
+func GetAttributes(fullMethod string, req any) (Attributes, error) {
+	attrs := Attributes{
+		Verb: methodVerbs[fullMethod[strings.LastIndex(fullMethod, "/")+1:]],
+	}
+	switch req.(type) {
+	case *resourcepb.ExportRequest, *resourcepb.ImportRequest:
+		attrs.Group = snapshotResource.Group
+		attrs.Resource = snapshotResource.Resource
+		return attrs, nil
+	}
+	hg, ok := req.(headerGetter)
+	if !ok {
+		return attrs, nil
+	}
+	header := hg.GetHeader()
+	attrs.Namespace = header.GetOwnerNsn().GetNamespace()
+	if attrs.Namespace == "" {
+		attrs.Namespace = header.GetNsn().GetNamespace()
+	}
+	attrs.Name = header.GetNsn().GetName()
+	if sg, ok := req.(specGetter); ok && sg.GetSpec() != "" {
+		spec := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal([]byte(sg.GetSpec()), spec); err != nil {
+			return attrs, fmt.Errorf("cannot decode spec: %w", err)
+		}
+		if err := validateHeader(header, spec); err != nil {
+			return attrs, err
+		}
+		attrs.Namespace = spec.GetNamespace()
+		attrs.Name = spec.GetName()
+	}
+	if gvk := header.GetGvk(); gvk.GetKind() != "" {
+		plural, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{
+			Group:   gvk.GetGroup(),
+			Version: gvk.GetVersion(),
+			Kind:    gvk.GetKind(),
+		})
+		attrs.Group = plural.Group
+		attrs.Resource = plural.Resource
+	}
+	return attrs, nil
+}
+
+// validateHeader returns an error if the namespace and name in the header
+// differ from the spec
+func validateHeader(header *resourcepb.Header, spec *metav1.PartialObjectMetadata) error {
+	if nsn := header.GetNsn(); nsn != nil && (nsn.GetNamespace() != spec.GetNamespace() || nsn.GetName() != spec.GetName()) {
+		return fmt.Errorf("header %s/%s does not match the spec %s/%s",
+			nsn.GetNamespace(), nsn.GetName(), spec.GetNamespace(), spec.GetName())
+	}
+	if ns := header.GetOwnerNsn().GetNamespace(); ns != "" && ns != spec.GetNamespace() {
+		return fmt.Errorf("owner namespace %s in the header does not match the spec namespace %s",
+			ns, spec.GetNamespace())
+	}
+	return nil
+}
+
+type userKey struct{}
+
+// NewContext returns a context that carries the authenticated caller
+func NewContext(ctx context.Context, u *UserInfo) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// FromContext returns the authenticated caller carried by the context
+func FromContext(ctx context.Context) (*UserInfo, bool) {
+	u, ok := ctx.Value(userKey{}).(*UserInfo)
+	return u, ok
+}
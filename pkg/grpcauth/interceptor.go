@@ -0,0 +1,145 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationHeader = "authorization"
+	bearerPrefix        = "bearer "
+	// the health service is used by probes that carry no token
+	healthServicePrefix = "/grpc.health.v1.Health/"
+)
+
+// UnaryServerInterceptor authenticates the caller of every unary call and
+// authorizes the request, the requests are denied without an authorizer.
+func UnaryServerInterceptor(authn Authenticator, authz Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+		u, err := authenticate(ctx, authn)
+		if err != nil {
+			return nil, err
+		}
+		ctx = NewContext(ctx, u)
+		if err := authorize(ctx, authz, u, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates the caller of every stream and
+// authorizes the first message received on the stream, which carries the
+// header of the request.
+func StreamServerInterceptor(authn Authenticator, authz Authorizer) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(srv, ss)
+		}
+		u, err := authenticate(ss.Context(), authn)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{
+			ServerStream: ss,
+			ctx:          NewContext(ss.Context(), u),
+			authz:        authz,
+			user:         u,
+			fullMethod:   info.FullMethod,
+		})
+	}
+}
+
+type authStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	authz      Authorizer
+	user       *UserInfo
+	fullMethod string
+}
+
+func (r *authStream) Context() context.Context {
+	return r.ctx
+}
+
+func (r *authStream) RecvMsg(m any) error {
+	if err := r.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	// every message is authorized, since a client stream could otherwise
+	// change the header after the first message
+	return authorize(r.ctx, r.authz, r.user, r.fullMethod, m)
+}
+
+func authenticate(ctx context.Context, authn Authenticator) (*UserInfo, error) {
+	token, err := getBearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, err := authn.Authenticate(ctx, token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "cannot authenticate: %v", err)
+	}
+	if u == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return u, nil
+}
+
+func authorize(ctx context.Context, authz Authorizer, u *UserInfo, fullMethod string, req any) error {
+	// an authenticated caller is not trusted without an authorizer
+	if authz == nil {
+		return status.Error(codes.PermissionDenied, "no authorizer")
+	}
+	attrs, err := GetAttributes(fullMethod, req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	attrs.User = u
+	allowed, reason, err := authz.Authorize(ctx, attrs)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "cannot authorize: %v", err)
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "user %q cannot %s %s.%s in namespace %q: %s",
+			u.Username, attrs.Verb, attrs.Resource, attrs.Group, attrs.Namespace, reason)
+	}
+	return nil
+}
+
+func getBearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	for _, v := range md.Get(authorizationHeader) {
+		if len(v) > len(bearerPrefix) && strings.EqualFold(v[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(v[len(bearerPrefix):]), nil
+		}
+	}
+	return "", status.Error(codes.Unauthenticated, "missing bearer token")
+}
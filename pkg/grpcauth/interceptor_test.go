@@ -0,0 +1,254 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type tokenAuthenticator map[string]*UserInfo
+
+func (r tokenAuthenticator) Authenticate(ctx context.Context, token string) (*UserInfo, error) {
+	return r[token], nil
+}
+
+var testAuthn = tokenAuthenticator{
+	"ipam": {Username: "system:serviceaccount:default:ipam"},
+}
+
+var testAuthz = &Policy{Rules: []PolicyRule{{
+	Users:      []string{"system:serviceaccount:default:ipam"},
+	Namespaces: []string{"default"},
+}}}
+
+func newClaimRequest(namespace string) *resourcepb.ClaimRequest {
+	return newSpoofedClaimRequest(namespace, namespace)
+}
+
+// newSpoofedClaimRequest returns a claim request whose header claims another
+// namespace than its spec
+func newSpoofedClaimRequest(headerNamespace, specNamespace string) *resourcepb.ClaimRequest {
+	return &resourcepb.ClaimRequest{
+		Header: &resourcepb.Header{
+			Gvk:      &resourcepb.GVK{Group: "ipam.resource.nephio.org", Version: "v1alpha1", Kind: "IPClaim"},
+			Nsn:      &resourcepb.NSN{Namespace: headerNamespace, Name: "claim"},
+			OwnerNsn: &resourcepb.NSN{Namespace: headerNamespace, Name: "owner"},
+		},
+		Spec: fmt.Sprintf(`{"metadata":{"namespace":%q,"name":"claim"}}`, specNamespace),
+	}
+}
+
+func TestGetAttributes(t *testing.T) {
+	attrs, err := GetAttributes("/resource.Resource/DeleteClaim", newClaimRequest("default"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Attributes{
+		Verb:      "delete",
+		Namespace: "default",
+		Group:     "ipam.resource.nephio.org",
+		Resource:  "ipclaims",
+		Name:      "claim",
+	}
+	if attrs != want {
+		t.Errorf("want %v, got %v", want, attrs)
+	}
+}
+
+func TestGetAttributesHeaderMismatch(t *testing.T) {
+	cases := map[string]*resourcepb.ClaimRequest{
+		"Namespace": newSpoofedClaimRequest("default", "other"),
+		"Name": {
+			Header: &resourcepb.Header{Nsn: &resourcepb.NSN{Namespace: "default", Name: "claim"}},
+			Spec:   `{"metadata":{"namespace":"default","name":"other"}}`,
+		},
+		"OwnerNamespace": {
+			Header: &resourcepb.Header{
+				Nsn:      &resourcepb.NSN{Namespace: "other", Name: "claim"},
+				OwnerNsn: &resourcepb.NSN{Namespace: "default", Name: "owner"},
+			},
+			Spec: `{"metadata":{"namespace":"other","name":"claim"}}`,
+		},
+		"InvalidSpec": {
+			Header: &resourcepb.Header{Nsn: &resourcepb.NSN{Namespace: "default", Name: "claim"}},
+			Spec:   `{`,
+		},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := GetAttributes("/resource.Resource/Claim", req); err == nil {
+				t.Errorf("want error for a header that does not match the spec")
+			}
+		})
+	}
+}
+
+func TestGetAttributesSnapshot(t *testing.T) {
+	attrs, err := GetAttributes("/resource.Resource/Import", &resourcepb.ImportRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Attributes{
+		Verb:     "create",
+		Group:    "resource.nephio.org",
+		Resource: "snapshots",
+	}
+	if attrs != want {
+		t.Errorf("want %v, got %v", want, attrs)
+	}
+}
+
+func TestGetAttributesTree(t *testing.T) {
+	attrs, err := GetAttributes("/resource.Resource/GetTree", &resourcepb.TreeRequest{
+		Header: &resourcepb.Header{
+			Gvk: &resourcepb.GVK{Group: "ipam.resource.nephio.org", Version: "v1alpha1", Kind: "NetworkInstance"},
+			Nsn: &resourcepb.NSN{Namespace: "default", Name: "vpc"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Attributes{
+		Verb:      "get",
+		Namespace: "default",
+		Group:     "ipam.resource.nephio.org",
+		Resource:  "networkinstances",
+		Name:      "vpc",
+	}
+	if attrs != want {
+		t.Errorf("want %v, got %v", want, attrs)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	cases := map[string]struct {
+		method string
+		token  string
+		req    any
+		code   codes.Code
+	}{
+		"Allowed": {
+			method: "/resource.Resource/Claim",
+			token:  "ipam",
+			req:    newClaimRequest("default"),
+			code:   codes.OK,
+		},
+		"NoToken": {
+			method: "/resource.Resource/Claim",
+			req:    newClaimRequest("default"),
+			code:   codes.Unauthenticated,
+		},
+		"InvalidToken": {
+			method: "/resource.Resource/Claim",
+			token:  "invalid",
+			req:    newClaimRequest("default"),
+			code:   codes.Unauthenticated,
+		},
+		"OtherNamespace": {
+			method: "/resource.Resource/Claim",
+			token:  "ipam",
+			req:    newClaimRequest("other"),
+			code:   codes.PermissionDenied,
+		},
+		"SpoofedHeader": {
+			method: "/resource.Resource/Claim",
+			token:  "ipam",
+			req:    newSpoofedClaimRequest("default", "other"),
+			code:   codes.InvalidArgument,
+		},
+		"Health": {
+			method: "/grpc.health.v1.Health/Check",
+			code:   codes.OK,
+		},
+	}
+	interceptor := UnaryServerInterceptor(testAuthn, testAuthz)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tc.token))
+			}
+			_, err := interceptor(ctx, tc.req, &grpc.UnaryServerInfo{FullMethod: tc.method}, func(ctx context.Context, req any) (any, error) {
+				if _, ok := FromContext(ctx); !ok && tc.token != "" {
+					t.Errorf("want user in context")
+				}
+				return nil, nil
+			})
+			if status.Code(err) != tc.code {
+				t.Errorf("want code %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptorNoAuthorizer(t *testing.T) {
+	interceptor := UnaryServerInterceptor(testAuthn, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer ipam"))
+	_, err := interceptor(ctx, newClaimRequest("default"), &grpc.UnaryServerInfo{FullMethod: "/resource.Resource/Claim"}, func(ctx context.Context, req any) (any, error) {
+		t.Errorf("handler called without an authorizer")
+		return nil, nil
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("want code %s, got %v", codes.PermissionDenied, err)
+	}
+}
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req *resourcepb.WatchRequest
+}
+
+func (r *testStream) Context() context.Context { return r.ctx }
+
+func (r *testStream) RecvMsg(m any) error {
+	m.(*resourcepb.WatchRequest).Header = r.req.Header
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	cases := map[string]struct {
+		namespace string
+		code      codes.Code
+	}{
+		"Allowed":        {namespace: "default", code: codes.OK},
+		"OtherNamespace": {namespace: "other", code: codes.PermissionDenied},
+	}
+	interceptor := StreamServerInterceptor(testAuthn, testAuthz)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss := &testStream{
+				ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer ipam")),
+				req: &resourcepb.WatchRequest{Header: newClaimRequest(tc.namespace).Header},
+			}
+			err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/resource.Resource/WatchClaim"}, func(srv any, stream grpc.ServerStream) error {
+				return stream.RecvMsg(&resourcepb.WatchRequest{})
+			})
+			if status.Code(err) != tc.code {
+				t.Errorf("want code %s, got %v", tc.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+const wildcard = "*"
+
+// Policy is a static authorization policy; a request is allowed when any of
+// the rules matches.
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule allows the users and groups to perform the verbs on the resources
+// in the namespaces. An empty namespaces, verbs or resources list or a "*" entry
+// matches all.
+type PolicyRule struct {
+	Users      []string `json:"users,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Verbs      []string `json:"verbs,omitempty"`
+	Resources  []string `json:"resources,omitempty"`
+}
+
+// LoadPolicy reads a static authorization policy from a yaml file
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policy{}
+	if err := yaml.UnmarshalStrict(b, p); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return p, nil
+}
+
+func (r *Policy) Authorize(ctx context.Context, attrs Attributes) (bool, string, error) {
+	for _, rule := range r.Rules {
+		if rule.matches(attrs) {
+			return true, "", nil
+		}
+	}
+	return false, "no policy rule matches", nil
+}
+
+func (r PolicyRule) matches(attrs Attributes) bool {
+	if attrs.User == nil {
+		return false
+	}
+	subject := contains(r.Users, attrs.User.Username)
+	for _, g := range attrs.User.Groups {
+		subject = subject || contains(r.Groups, g)
+	}
+	return subject &&
+		matchesAll(r.Namespaces, attrs.Namespace) &&
+		matchesAll(r.Verbs, attrs.Verb) &&
+		matchesAll(r.Resources, attrs.Resource)
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s || e == wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(l []string, s string) bool {
+	return len(l) == 0 || contains(l, s)
+}
@@ -0,0 +1,103 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	p := &Policy{Rules: []PolicyRule{
+		{
+			Users:      []string{"system:serviceaccount:default:ipam"},
+			Namespaces: []string{"default"},
+		},
+		{
+			Groups:    []string{"readers"},
+			Verbs:     []string{"get", "watch"},
+			Resources: []string{"ipclaims"},
+		},
+	}}
+	cases := map[string]struct {
+		attrs   Attributes
+		allowed bool
+	}{
+		"UserInNamespace": {
+			attrs:   Attributes{User: &UserInfo{Username: "system:serviceaccount:default:ipam"}, Verb: "create", Namespace: "default", Resource: "ipclaims"},
+			allowed: true,
+		},
+		"UserOtherNamespace": {
+			attrs:   Attributes{User: &UserInfo{Username: "system:serviceaccount:default:ipam"}, Verb: "create", Namespace: "other", Resource: "ipclaims"},
+			allowed: false,
+		},
+		"GroupAllowedVerb": {
+			attrs:   Attributes{User: &UserInfo{Username: "bob", Groups: []string{"readers"}}, Verb: "get", Namespace: "other", Resource: "ipclaims"},
+			allowed: true,
+		},
+		"GroupOtherVerb": {
+			attrs:   Attributes{User: &UserInfo{Username: "bob", Groups: []string{"readers"}}, Verb: "delete", Namespace: "other", Resource: "ipclaims"},
+			allowed: false,
+		},
+		"GroupOtherResource": {
+			attrs:   Attributes{User: &UserInfo{Username: "bob", Groups: []string{"readers"}}, Verb: "get", Namespace: "other", Resource: "vlanclaims"},
+			allowed: false,
+		},
+		"UnknownUser": {
+			attrs:   Attributes{User: &UserInfo{Username: "alice"}, Verb: "get", Namespace: "default", Resource: "ipclaims"},
+			allowed: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			allowed, _, err := p.Authorize(context.Background(), tc.attrs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowed != tc.allowed {
+				t.Errorf("want allowed %t, got %t", tc.allowed, allowed)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(`rules:
+- users: ["*"]
+  namespaces: ["default"]
+  verbs: ["get"]
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rules) != 1 || p.Rules[0].Namespaces[0] != "default" {
+		t.Errorf("unexpected policy %v", p)
+	}
+
+	if err := os.WriteFile(path, []byte("rules:\n- user: [\"*\"]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(path); err == nil {
+		t.Errorf("want error for an unknown field")
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewSubjectAccessReviewAuthorizer returns an authorizer that delegates the
+// decision to the kubernetes RBAC with a SubjectAccessReview, such that a
+// caller needs the same permissions on the claims as it would need on the api.
+func NewSubjectAccessReviewAuthorizer(c client.Client) Authorizer {
+	return &subjectAccessReviewAuthorizer{c: c}
+}
+
+type subjectAccessReviewAuthorizer struct {
+	c client.Client
+}
+
+func (r *subjectAccessReviewAuthorizer) Authorize(ctx context.Context, attrs Attributes) (bool, string, error) {
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   attrs.User.Username,
+			UID:    attrs.User.UID,
+			Groups: attrs.User.Groups,
+			Extra:  map[string]authorizationv1.ExtraValue{},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: attrs.Namespace,
+				Verb:      attrs.Verb,
+				Group:     attrs.Group,
+				Resource:  attrs.Resource,
+				Name:      attrs.Name,
+			},
+		},
+	}
+	for k, v := range attrs.User.Extra {
+		sar.Spec.Extra[k] = v
+	}
+	if err := r.c.Create(ctx, sar); err != nil {
+		return false, "", err
+	}
+	return sar.Status.Allowed, sar.Status.Reason, nil
+}
@@ -0,0 +1,122 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultCacheTTL        = time.Minute
+	defaultMaxCacheEntries = 1000
+)
+
+// NewTokenReviewAuthenticator returns an authenticator that validates
+// kubernetes service account tokens with a TokenReview. The result of a
+// review is cached for the ttl to avoid a review per request.
+func NewTokenReviewAuthenticator(c client.Client, audiences []string, ttl time.Duration) Authenticator {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	return &tokenReviewAuthenticator{
+		c:         c,
+		audiences: audiences,
+		ttl:       ttl,
+		cache:     map[[sha256.Size]byte]tokenCacheEntry{},
+		now:       time.Now,
+	}
+}
+
+type tokenReviewAuthenticator struct {
+	c         client.Client
+	audiences []string
+	ttl       time.Duration
+
+	m     sync.Mutex
+	cache map[[sha256.Size]byte]tokenCacheEntry
+	// now is used to test the expiry of the cache
+	now func() time.Time
+}
+
+type tokenCacheEntry struct {
+	user    *UserInfo
+	expires time.Time
+}
+
+func (r *tokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (*UserInfo, error) {
+	key := sha256.Sum256([]byte(token))
+	if u, ok := r.get(key); ok {
+		return u, nil
+	}
+
+	tr := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: r.audiences,
+		},
+	}
+	if err := r.c.Create(ctx, tr); err != nil {
+		return nil, err
+	}
+	var u *UserInfo
+	if tr.Status.Authenticated {
+		u = &UserInfo{
+			Username: tr.Status.User.Username,
+			UID:      tr.Status.User.UID,
+			Groups:   tr.Status.User.Groups,
+			Extra:    map[string][]string{},
+		}
+		for k, v := range tr.Status.User.Extra {
+			u.Extra[k] = v
+		}
+	}
+	r.add(key, u)
+	return u, nil
+}
+
+func (r *tokenReviewAuthenticator) get(key [sha256.Size]byte) (*UserInfo, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	e, ok := r.cache[key]
+	if !ok || r.now().After(e.expires) {
+		return nil, false
+	}
+	return e.user, true
+}
+
+func (r *tokenReviewAuthenticator) add(key [sha256.Size]byte, u *UserInfo) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	now := r.now()
+	if len(r.cache) >= defaultMaxCacheEntries {
+		for k, e := range r.cache {
+			if now.After(e.expires) {
+				delete(r.cache, k)
+			}
+		}
+		if len(r.cache) >= defaultMaxCacheEntries {
+			r.cache = map[[sha256.Size]byte]tokenCacheEntry{}
+		}
+	}
+	r.cache[key] = tokenCacheEntry{user: u, expires: now.Add(r.ttl)}
+}
@@ -0,0 +1,86 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestTokenReviewAuthenticator(t *testing.T) {
+	reviews := 0
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			reviews++
+			tr := obj.(*authenticationv1.TokenReview)
+			if tr.Spec.Token == "valid" {
+				tr.Status.Authenticated = true
+				tr.Status.User = authenticationv1.UserInfo{
+					Username: "system:serviceaccount:default:ipam",
+					Groups:   []string{"system:serviceaccounts"},
+				}
+			}
+			return nil
+		},
+	}).Build()
+
+	authn := NewTokenReviewAuthenticator(c, nil, time.Minute).(*tokenReviewAuthenticator)
+	now := time.Now()
+	authn.now = func() time.Time { return now }
+
+	u, err := authn.Authenticate(context.Background(), "valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u == nil || u.Username != "system:serviceaccount:default:ipam" {
+		t.Errorf("want authenticated serviceaccount, got %v", u)
+	}
+	u, err = authn.Authenticate(context.Background(), "invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u != nil {
+		t.Errorf("want unauthenticated, got %v", u)
+	}
+
+	// the results are cached
+	if _, err := authn.Authenticate(context.Background(), "valid"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authn.Authenticate(context.Background(), "invalid"); err != nil {
+		t.Fatal(err)
+	}
+	if reviews != 2 {
+		t.Errorf("want 2 reviews, got %d", reviews)
+	}
+
+	// the results expire after the ttl
+	now = now.Add(2 * time.Minute)
+	if _, err := authn.Authenticate(context.Background(), "valid"); err != nil {
+		t.Fatal(err)
+	}
+	if reviews != 3 {
+		t.Errorf("want 3 reviews, got %d", reviews)
+	}
+}
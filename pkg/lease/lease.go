@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -38,15 +39,48 @@ const (
 )
 
 type Lease interface {
+	// AcquireLease acquires the lease on behalf of the cr, the lease is renewed
+	// in the background when a renew interval is configured
 	AcquireLease(ctx context.Context, cr client.Object) error
+	// ReleaseLease releases the lease if it is held by the cr such that
+	// other holders can acquire it without waiting for the lease to expire
+	ReleaseLease(ctx context.Context, cr client.Object) error
 }
 
-func New(c client.Client, leaseNSN types.NamespacedName) Lease {
-	return &lease{
+type Option func(*lease)
+
+// WithDuration sets the duration for which the lease is valid without renewal,
+// a lease that is not renewed in time, e.g. because the holder crashed, can be
+// taken over by another holder
+func WithDuration(d time.Duration) Option {
+	return func(r *lease) {
+		if d > 0 {
+			r.duration = d
+		}
+	}
+}
+
+// WithRenewInterval sets the interval at which a held lease is renewed until
+// it is released, the interval should be smaller than the duration
+func WithRenewInterval(d time.Duration) Option {
+	return func(r *lease) {
+		r.renewInterval = d
+	}
+}
+
+func New(c client.Client, leaseNSN types.NamespacedName, opts ...Option) Lease {
+	r := &lease{
 		Client:         c,
 		leasName:       leaseNSN.Name,
 		leaseNamespace: leaseNSN.Namespace,
+		duration:       defaultLeaseInterval,
+		now:            time.Now,
+		renewers:       map[string]context.CancelFunc{},
+	}
+	for _, o := range opts {
+		o(r)
 	}
+	return r
 }
 
 type lease struct {
@@ -54,8 +88,13 @@ type lease struct {
 
 	leasName       string
 	leaseNamespace string
+	duration       time.Duration
+	renewInterval  time.Duration
+	now            func() time.Time
 
-	l logr.Logger
+	m sync.Mutex
+	// renewers keeps track of the background renewals, key = holder identity
+	renewers map[string]context.CancelFunc
 }
 
 func getHolderIdentity(cr client.Object) string {
@@ -65,8 +104,15 @@ func getHolderIdentity(cr client.Object) string {
 		strings.ToLower(cr.GetName()))
 }
 
+func (r *lease) getLeaseNSN() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      r.leasName,
+		Namespace: r.leaseNamespace,
+	}
+}
+
 func (r *lease) getLease(cr client.Object) *coordinationv1.Lease {
-	now := metav1.NowMicro()
+	now := metav1.NewMicroTime(r.now())
 	return &coordinationv1.Lease{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      r.leasName,
@@ -74,61 +120,155 @@ func (r *lease) getLease(cr client.Object) *coordinationv1.Lease {
 		},
 		Spec: coordinationv1.LeaseSpec{
 			HolderIdentity:       pointer.String(getHolderIdentity(cr)),
-			LeaseDurationSeconds: pointer.Int32(int32(defaultLeaseInterval / time.Second)),
+			LeaseDurationSeconds: pointer.Int32(r.getDurationSeconds()),
 			AcquireTime:          &now,
 			RenewTime:            &now,
 		},
 	}
 }
 
-func (r *lease) AcquireLease(ctx context.Context, cr client.Object) error {
-	r.l = log.FromContext(ctx)
-	r.l.Info("attempting to acquire lease to update the resource", "lease", r.leasName)
-	interconnectLeaseNSN := types.NamespacedName{
-		Name:      r.leasName,
-		Namespace: r.leaseNamespace,
+// getDurationSeconds returns the duration in seconds, the lease api supports a
+// minimum of 1 second
+func (r *lease) getDurationSeconds() int32 {
+	d := int32(r.duration / time.Second)
+	if d < 1 {
+		return 1
+	}
+	return d
+}
+
+// isExpired returns true if the lease is not held or not renewed in time
+func (r *lease) isExpired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return true
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
 	}
+	expectedRenewTime := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return expectedRenewTime.Before(r.now())
+}
+
+func (r *lease) AcquireLease(ctx context.Context, cr client.Object) error {
+	log := log.FromContext(ctx)
+	log.Info("attempting to acquire lease to update the resource", "lease", r.leasName)
 
 	lease := &coordinationv1.Lease{}
-	if err := r.Get(ctx, interconnectLeaseNSN, lease); err != nil {
+	if err := r.Get(ctx, r.getLeaseNSN(), lease); err != nil {
 		if resource.IgnoreNotFound(err) != nil {
 			return err
 		}
-		r.l.Info("lease not found, creating it", "lease", r.leasName)
+		log.Info("lease not found, creating it", "lease", r.leasName)
 
 		lease = r.getLease(cr)
 		if err := r.Create(ctx, lease); err != nil {
 			return err
 		}
+		r.startRenewal(cr, log)
+		log.Info("successfully acquired lease")
+		return nil
 	}
-	// get the lease again
-	if err := r.Get(ctx, interconnectLeaseNSN, lease); err != nil {
+
+	holderIdentity := getHolderIdentity(cr)
+	newLease := r.getLease(cr)
+	if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity == holderIdentity {
+		// the lease is renewed, the acquire time remains
+		newLease.Spec.AcquireTime = lease.Spec.AcquireTime
+		newLease.Spec.LeaseTransitions = lease.Spec.LeaseTransitions
+	} else {
+		if !r.isExpired(lease) {
+			log.Info("cannot acquire lease, lease held by another identity", "identity", *lease.Spec.HolderIdentity)
+			return fmt.Errorf("cannot acquire lease, lease held by another identity: %s", *lease.Spec.HolderIdentity)
+		}
+		// take over the lease that was released or expired
+		// e.g. because the holder crashed
+		log.Info("taking over lease", "previous identity", pointer.StringDeref(lease.Spec.HolderIdentity, ""))
+		newLease.Spec.LeaseTransitions = pointer.Int32(pointer.Int32Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	}
+	// the resource version ensures we fail when another holder updated the
+	// lease concurrently
+	newLease.SetResourceVersion(lease.ResourceVersion)
+	if err := r.Update(ctx, newLease); err != nil {
 		return err
 	}
+	r.startRenewal(cr, log)
+	log.Info("successfully acquired lease")
+	return nil
+}
+
+func (r *lease) ReleaseLease(ctx context.Context, cr client.Object) error {
+	log := log.FromContext(ctx)
+	holderIdentity := getHolderIdentity(cr)
+	r.stopRenewal(holderIdentity)
+
+	lease := &coordinationv1.Lease{}
+	if err := r.Get(ctx, r.getLeaseNSN(), lease); err != nil {
+		return resource.IgnoreNotFound(err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holderIdentity {
+		// the lease is not held by the cr, nothing to release
+		return nil
+	}
+	log.Info("releasing lease", "lease", r.leasName)
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	lease.Spec.RenewTime = nil
+	return r.Update(ctx, lease)
+}
 
-	if lease == nil || lease.Spec.HolderIdentity == nil {
-		return fmt.Errorf("lease nil or holderidentity nil")
+// startRenewal renews the lease of the holder in the background until the
+// lease is released or taken over
+func (r *lease) startRenewal(cr client.Object, log logr.Logger) {
+	if r.renewInterval <= 0 {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	holderIdentity := getHolderIdentity(cr)
+	if _, ok := r.renewers[holderIdentity]; ok {
+		// renewal is already running
+		return
 	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.renewers[holderIdentity] = cancel
 
-	now := metav1.NowMicro()
-	if *lease.Spec.HolderIdentity != getHolderIdentity(cr) {
-		// lease is held by another
-		r.l.Info("lease held by another identity", "identity", *lease.Spec.HolderIdentity)
-		if lease.Spec.RenewTime != nil {
-			expectedRenewTime := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
-			if !expectedRenewTime.Before(now.Time) {
-				r.l.Info("cannot acquire lease, lease held by another identity", "identity", *lease.Spec.HolderIdentity)
-				return fmt.Errorf("cannot acquire lease, lease held by another identity: %s", *lease.Spec.HolderIdentity)
+	go func() {
+		ticker := time.NewTicker(r.renewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.renew(ctx, holderIdentity); err != nil {
+					log.Info("lease renewal stopped", "lease", r.leasName, "identity", holderIdentity, "reason", err.Error())
+					r.stopRenewal(holderIdentity)
+					return
+				}
 			}
 		}
+	}()
+}
+
+func (r *lease) stopRenewal(holderIdentity string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if cancel, ok := r.renewers[holderIdentity]; ok {
+		cancel()
+		delete(r.renewers, holderIdentity)
 	}
+}
 
-	// take over the lease or update the lease
-	r.l.Info("successfully acquired lease")
-	newLease := r.getLease(cr)
-	newLease.SetResourceVersion(lease.ResourceVersion)
-	if err := r.Update(ctx, newLease); err != nil {
+// renew updates the renew time of the lease if it is still held by the holder
+func (r *lease) renew(ctx context.Context, holderIdentity string) error {
+	lease := &coordinationv1.Lease{}
+	if err := r.Get(ctx, r.getLeaseNSN(), lease); err != nil {
 		return err
 	}
-	return nil
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holderIdentity {
+		return fmt.Errorf("lease no longer held by %s", holderIdentity)
+	}
+	now := metav1.NewMicroTime(r.now())
+	lease.Spec.RenewTime = &now
+	return r.Update(ctx, lease)
 }
@@ -0,0 +1,194 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeClient(t *testing.T) client.Client {
+	scheme := runtime.NewScheme()
+	if err := coordinationv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot add scheme: %s", err.Error())
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).Build()
+}
+
+func newCr(name string) client.Object {
+	cr := &invv1alpha1.Endpoint{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	cr.SetGroupVersionKind(invv1alpha1.EndpointGroupVersionKind)
+	return cr
+}
+
+func getLease(t *testing.T, c client.Client, name string) *coordinationv1.Lease {
+	l := &coordinationv1.Lease{}
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, l); err != nil {
+		t.Fatalf("cannot get lease %s: %s", name, err.Error())
+	}
+	return l
+}
+
+func TestAcquireRelease(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient(t)
+	now := time.Now()
+	l := New(c, types.NamespacedName{Namespace: "default", Name: "test"}, WithDuration(10*time.Second))
+	l.(*lease).now = func() time.Time { return now }
+
+	cr1 := newCr("a")
+	cr2 := newCr("b")
+
+	if err := l.AcquireLease(ctx, cr1); err != nil {
+		t.Fatalf("cannot acquire lease: %s", err.Error())
+	}
+	// the same holder can acquire the lease again
+	if err := l.AcquireLease(ctx, cr1); err != nil {
+		t.Errorf("cannot reacquire lease: %s", err.Error())
+	}
+	// another holder cannot acquire the lease
+	if err := l.AcquireLease(ctx, cr2); err == nil {
+		t.Errorf("expected error acquiring lease held by another identity, got nil")
+	}
+	// releasing a lease that is not held is a noop
+	if err := l.ReleaseLease(ctx, cr2); err != nil {
+		t.Errorf("cannot release lease: %s", err.Error())
+	}
+	if err := l.ReleaseLease(ctx, cr1); err != nil {
+		t.Errorf("cannot release lease: %s", err.Error())
+	}
+	// after release another holder can acquire the lease
+	if err := l.AcquireLease(ctx, cr2); err != nil {
+		t.Errorf("cannot acquire released lease: %s", err.Error())
+	}
+	lease := getLease(t, c, "test")
+	if got := *lease.Spec.HolderIdentity; got != getHolderIdentity(cr2) {
+		t.Errorf("want holder %s, got %s", getHolderIdentity(cr2), got)
+	}
+	if got := *lease.Spec.LeaseTransitions; got != 1 {
+		t.Errorf("want 1 lease transition, got %d", got)
+	}
+	if got := *lease.Spec.LeaseDurationSeconds; got != 10 {
+		t.Errorf("want lease duration 10, got %d", got)
+	}
+}
+
+func TestAcquireExpired(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient(t)
+	now := time.Now()
+	l := New(c, types.NamespacedName{Namespace: "default", Name: "test"}, WithDuration(5*time.Second))
+	l.(*lease).now = func() time.Time { return now }
+
+	// the first holder crashes without releasing the lease
+	if err := l.AcquireLease(ctx, newCr("a")); err != nil {
+		t.Fatalf("cannot acquire lease: %s", err.Error())
+	}
+	now = now.Add(3 * time.Second)
+	if err := l.AcquireLease(ctx, newCr("b")); err == nil {
+		t.Errorf("expected error acquiring lease that did not expire, got nil")
+	}
+	now = now.Add(3 * time.Second)
+	if err := l.AcquireLease(ctx, newCr("b")); err != nil {
+		t.Errorf("cannot acquire expired lease: %s", err.Error())
+	}
+}
+
+func TestRenewal(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient(t)
+	l := New(c, types.NamespacedName{Namespace: "default", Name: "test"},
+		WithDuration(time.Second),
+		WithRenewInterval(10*time.Millisecond),
+	)
+	cr := newCr("a")
+	if err := l.AcquireLease(ctx, cr); err != nil {
+		t.Fatalf("cannot acquire lease: %s", err.Error())
+	}
+	acquired := getLease(t, c, "test").Spec.RenewTime.Time
+
+	renewed := false
+	for i := 0; i < 100 && !renewed; i++ {
+		time.Sleep(10 * time.Millisecond)
+		renewed = getLease(t, c, "test").Spec.RenewTime.After(acquired)
+	}
+	if !renewed {
+		t.Errorf("lease was not renewed")
+	}
+	if err := l.ReleaseLease(ctx, cr); err != nil {
+		t.Errorf("cannot release lease: %s", err.Error())
+	}
+	if len(l.(*lease).renewers) != 0 {
+		t.Errorf("renewal not stopped after release")
+	}
+	if lease := getLease(t, c, "test"); lease.Spec.HolderIdentity != nil {
+		t.Errorf("want no holder after release, got %s", *lease.Spec.HolderIdentity)
+	}
+}
+
+func TestSet(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient(t)
+	s := NewSet(c, "default", "endpoint", WithDuration(10*time.Second))
+
+	cr1 := newCr("a")
+	cr2 := newCr("b")
+
+	// disjoint scopes can be acquired concurrently
+	if err := s.AcquireLeases(ctx, cr1, []string{"topo1", "topo2"}); err != nil {
+		t.Fatalf("cannot acquire leases: %s", err.Error())
+	}
+	if err := s.AcquireLeases(ctx, cr2, []string{"topo3"}); err != nil {
+		t.Errorf("cannot acquire leases for disjoint scope: %s", err.Error())
+	}
+	// overlapping scopes cannot be acquired, the acquired leases are released
+	if err := s.AcquireLeases(ctx, cr2, []string{"topo0", "topo2"}); err == nil {
+		t.Errorf("expected error acquiring overlapping scope, got nil")
+	}
+	if lease := getLease(t, c, "endpoint-topo0"); lease.Spec.HolderIdentity != nil {
+		t.Errorf("want lease endpoint-topo0 released, got holder %s", *lease.Spec.HolderIdentity)
+	}
+	if err := s.ReleaseLeases(ctx, cr1, []string{"topo1", "topo2"}); err != nil {
+		t.Errorf("cannot release leases: %s", err.Error())
+	}
+	if err := s.AcquireLeases(ctx, cr2, []string{"topo0", "topo2"}); err != nil {
+		t.Errorf("cannot acquire released leases: %s", err.Error())
+	}
+}
+
+func TestGetLeaseName(t *testing.T) {
+	s := &set{prefix: "endpoint"}
+	cases := map[string]string{
+		"topo1":      "endpoint-topo1",
+		"Topo_A/b":   "endpoint-topo-a-b",
+		"default.x.": "endpoint-default.x",
+	}
+	for scope, want := range cases {
+		if got := s.getLeaseName(scope); got != want {
+			t.Errorf("scope %s want %s, got %s", scope, want, got)
+		}
+	}
+}
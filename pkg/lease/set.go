@@ -0,0 +1,128 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Set manages a set of leases keyed by a caller provided scope, e.g. a topology.
+// This allows holders that operate on disjoint scopes to proceed concurrently.
+type Set interface {
+	// AcquireLeases acquires the leases of all scopes on behalf of the cr. The
+	// leases are acquired in a deterministic order and when one of them cannot be
+	// acquired the leases that were acquired are released again.
+	AcquireLeases(ctx context.Context, cr client.Object, scopes []string) error
+	// ReleaseLeases releases the leases of the scopes that are held by the cr
+	ReleaseLeases(ctx context.Context, cr client.Object, scopes []string) error
+}
+
+// NewSet returns a lease set, the leases are named <prefix>-<scope> and are
+// created in the namespace
+func NewSet(c client.Client, namespace, prefix string, opts ...Option) Set {
+	return &set{
+		Client:    c,
+		namespace: namespace,
+		prefix:    prefix,
+		opts:      opts,
+		leases:    map[string]Lease{},
+	}
+}
+
+type set struct {
+	client.Client
+	namespace string
+	prefix    string
+	opts      []Option
+
+	m      sync.Mutex
+	leases map[string]Lease
+}
+
+// getLeaseName returns a valid lease name for the scope
+func (r *set) getLeaseName(scope string) string {
+	name := strings.ToLower(fmt.Sprintf("%s-%s", r.prefix, scope))
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return '-'
+	}, name)
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = name[:validation.DNS1123SubdomainMaxLength]
+	}
+	return strings.Trim(name, "-.")
+}
+
+func (r *set) getLease(scope string) Lease {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if l, ok := r.leases[scope]; ok {
+		return l
+	}
+	l := New(r.Client, types.NamespacedName{Namespace: r.namespace, Name: r.getLeaseName(scope)}, r.opts...)
+	r.leases[scope] = l
+	return l
+}
+
+// getScopes returns the unique scopes in a deterministic order to avoid
+// holders waiting on each other
+func getScopes(scopes []string) []string {
+	m := map[string]struct{}{}
+	for _, scope := range scopes {
+		m[scope] = struct{}{}
+	}
+	l := make([]string, 0, len(m))
+	for scope := range m {
+		l = append(l, scope)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func (r *set) AcquireLeases(ctx context.Context, cr client.Object, scopes []string) error {
+	acquired := []string{}
+	for _, scope := range getScopes(scopes) {
+		if err := r.getLease(scope).AcquireLease(ctx, cr); err != nil {
+			if errr := r.ReleaseLeases(ctx, cr, acquired); errr != nil {
+				err = errors.Join(err, errr)
+			}
+			return fmt.Errorf("scope %s: %w", scope, err)
+		}
+		acquired = append(acquired, scope)
+	}
+	return nil
+}
+
+func (r *set) ReleaseLeases(ctx context.Context, cr client.Object, scopes []string) error {
+	var errs error
+	for _, scope := range getScopes(scopes) {
+		if err := r.getLease(scope).ReleaseLease(ctx, cr); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
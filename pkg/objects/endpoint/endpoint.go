@@ -24,7 +24,9 @@ import (
 
 	"github.com/go-logr/logr"
 	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/objects"
+	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -39,15 +41,30 @@ type Endpoint interface {
 	DeleteClaim(ctx context.Context, eps []invv1alpha1.Endpoint) error
 }
 
-func New(c client.Client) Endpoint {
-	return &endpoint{
+type Option func(*endpoint)
+
+// WithVtepClientProxy enables the claim of a vtep id per claimed endpoint
+// from a per topology index in the resource backend
+func WithVtepClientProxy(p clientproxy.Proxy[*vxlanv1alpha1.VXLANIndex, *vxlanv1alpha1.VXLANClaim]) Option {
+	return func(r *endpoint) {
+		r.vtepClientProxy = p
+	}
+}
+
+func New(c client.Client, opts ...Option) Endpoint {
+	r := &endpoint{
 		Client: c,
 	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
 }
 
 type endpoint struct {
 	client.Client
-	l logr.Logger
+	vtepClientProxy clientproxy.Proxy[*vxlanv1alpha1.VXLANIndex, *vxlanv1alpha1.VXLANClaim]
+	l               logr.Logger
 
 	inventory map[string]objects.Objects
 }
@@ -122,6 +139,16 @@ func (r *endpoint) Claim(ctx context.Context, o client.Object, eps []invv1alpha1
 	r.l = log.FromContext(ctx)
 	for _, ep := range eps {
 		ep.Status.ClaimRef = getCoreRef(o)
+		if isOverlay(o, &ep) {
+			if err := r.claimVtepID(ctx, &ep); err != nil {
+				return err
+			}
+		} else if ep.Status.VtepID != "" {
+			// the endpoint is no longer used for overlay wiring
+			if err := r.deleteVtepID(ctx, &ep); err != nil {
+				return err
+			}
+		}
 		if err := r.Status().Update(ctx, &ep); err != nil {
 			return err
 		}
@@ -133,6 +160,11 @@ func (r *endpoint) DeleteClaim(ctx context.Context, eps []invv1alpha1.Endpoint)
 	r.l = log.FromContext(ctx)
 	for _, ep := range eps {
 		ep.Status.ClaimRef = nil
+		if ep.Status.VtepID != "" {
+			if err := r.deleteVtepID(ctx, &ep); err != nil {
+				return err
+			}
+		}
 		if err := r.Status().Update(ctx, &ep); err != nil {
 			return err
 		}
@@ -0,0 +1,265 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Selector selects endpoints for the links of a logical interconnect spec
+type Selector struct {
+	endpoint Endpoint
+	links    uint16
+	//leps     []invv1alpha1.LogicalEndpoint
+	result *Result
+	// excluded keeps track of the endpoints that cannot be selected
+	// e.g. endpoints selected in a previous selection of the same owner
+	excluded map[types.NamespacedName]struct{}
+
+	l logr.Logger
+}
+
+// Result holds the outcome of the endpoint selection
+type Result struct {
+	// selectedEndpoints keeps track of the selected endpoints
+	// -> labeled after the selection completes
+	endpoints []invv1alpha1.Endpoint
+	// logicalEndpoints keeps track of the selected endpoints
+	// logical links are per endpoint, the per node/topology
+	// actuation happens by the logicalendpoint controller
+	// -> applied after selection
+	logicalEndpoints []invv1alpha1.LogicalEndpointSpec
+	// links keeps track of the links
+	// -> applied after selection
+	linkSpecs []invv1alpha1.LinkSpec
+}
+
+// NewSelector returns a selector for the amount of links. The excluded endpoints
+// are not considered during selection.
+func NewSelector(links uint16, endpoint Endpoint, excluded []invv1alpha1.Endpoint) *Selector {
+	r := &Selector{
+		endpoint: endpoint,
+		links:    links,
+		excluded: map[types.NamespacedName]struct{}{},
+		result: &Result{
+			endpoints:        make([]invv1alpha1.Endpoint, 0, links*2),
+			logicalEndpoints: make([]invv1alpha1.LogicalEndpointSpec, 2),
+			linkSpecs:        make([]invv1alpha1.LinkSpec, links),
+		},
+	}
+	for _, ep := range excluded {
+		r.excluded[types.NamespacedName{Namespace: ep.GetNamespace(), Name: ep.GetName()}] = struct{}{}
+	}
+	return r
+}
+
+func (r *Selector) isExcluded(ep invv1alpha1.Endpoint) bool {
+	_, ok := r.excluded[types.NamespacedName{Namespace: ep.GetNamespace(), Name: ep.GetName()}]
+	return ok
+}
+
+func (r *Selector) validateIndices(linkIdx, epIdx int) error {
+	if linkIdx < 0 || linkIdx >= int(r.links) {
+		return fmt.Errorf("invalid linkIndex, got: %d, want linkidx > 0 and < %d", linkIdx, r.links)
+	}
+	if epIdx < 0 || epIdx >= 2 {
+		return fmt.Errorf("invalid endpointIndex, got: %d, want endpointIndex > 0 and < 2", epIdx)
+	}
+	return nil
+}
+
+func (r *Selector) addEndpoint(topology string, linkIdx, epIdx int, ep invv1alpha1.Endpoint, lacp *bool, name *string) error {
+	if err := r.validateIndices(linkIdx, epIdx); err != nil {
+		return err
+	}
+	// add selected endpoint
+	r.result.endpoints = append(r.result.endpoints, ep)
+	// add linkSpec
+	if len(r.result.linkSpecs[linkIdx].Endpoints) == 0 {
+		r.result.linkSpecs[linkIdx].Endpoints = make([]invv1alpha1.LinkEndpointSpec, 2)
+	}
+	r.result.linkSpecs[linkIdx].Endpoints[epIdx] = invv1alpha1.LinkEndpointSpec{
+		Topology: topology,
+		EndpointSpec: invv1alpha1.EndpointSpec{
+			InterfaceName: ep.Spec.InterfaceName,
+			NodeName:      ep.Spec.NodeName,
+		},
+	}
+	// add logical endpoint
+	r.result.logicalEndpoints[epIdx].Lacp = lacp
+	r.result.logicalEndpoints[epIdx].Name = name
+	if len(r.result.logicalEndpoints[epIdx].Endpoints) == 0 {
+		r.result.logicalEndpoints[epIdx].Endpoints = []invv1alpha1.EndpointSpec{}
+	}
+	r.result.logicalEndpoints[epIdx].Endpoints = append(r.result.logicalEndpoints[epIdx].Endpoints,
+		invv1alpha1.EndpointSpec{
+			//Topology:      topology,
+			InterfaceName: ep.Spec.InterfaceName,
+			NodeName:      ep.Spec.NodeName,
+		},
+	)
+	return nil
+}
+
+// SelectEndpoints selects the endpoints for the links of the spec on behalf of the owner cr
+func (r *Selector) SelectEndpoints(ctx context.Context, cr client.Object, spec *topov1alpha1.LogicalInterconnectSpec) (*Result, error) {
+	r.l = log.FromContext(ctx)
+	// initialize the endpoint inventory based on the spec topology information
+	if err := r.endpoint.Init(ctx, spec.GetTopologies()); err != nil {
+		return nil, err
+	}
+
+	// selection is run per link endpoint to ensure we take into account topology and node diversity
+	for epIdx, ep := range spec.Endpoints {
+		// nodeSlots defines the amount of nodes the links are spread over within a topology
+		// without a diversity policy the links alternate between 2 nodes, which can be the same
+		// node; with a diversity policy the nodes are diverse
+		nodeSlots := 2
+		diverse := false
+		if len(ep.Topologies) == 1 && ep.SelectorPolicy.IsDiverse() {
+			diverse = true
+			if n := ep.SelectorPolicy.GetNodeDiversity(); n > 0 {
+				if n > int(spec.Links) {
+					return nil, fmt.Errorf("endpoint %d cannot satisfy %s with %d links", epIdx, ep.SelectorPolicy.String(), spec.Links)
+				}
+				nodeSlots = n
+			}
+		}
+		diversityKeys := ep.SelectorPolicy.GetDiversityLabelKeys()
+
+		// for node diversity we keep track of the endpoint per node slot and topology
+		// that have been selected - key = topology
+		selectedNodes := map[string][]*invv1alpha1.Endpoint{}
+		// retrieve endpoints per topology - key = topology
+		topoEndpoints := map[string][]invv1alpha1.Endpoint{}
+		for _, topology := range ep.Topologies {
+			eps, err := r.endpoint.GetTopologyEndpointsWithSelector(topology, ep.Selector)
+			if err != nil {
+				return nil, err
+			}
+			topoEndpoints[topology] = []invv1alpha1.Endpoint{}
+			for _, tep := range eps {
+				if !r.isExcluded(tep) {
+					topoEndpoints[topology] = append(topoEndpoints[topology], tep)
+				}
+			}
+			selectedNodes[topology] = make([]*invv1alpha1.Endpoint, nodeSlots)
+		}
+
+		// allocate endpoint per link
+		for linkIdx := 0; linkIdx < int(spec.Links); linkIdx++ {
+			// topoIdx is used to find the topology we should use for the
+			// endpoint selection
+			// Current strategy we walk one by one through each topology
+			topoIdx := linkIdx % len(ep.Topologies)
+			topology := ep.Topologies[topoIdx]
+			// selectedNodeIdx is used for node selection, the links are spread
+			// round robin over the node slots
+			selectedNodeIdx := 0
+			// for a multi topology environment node selection is not applicable
+			// (we ignore it)
+			if len(ep.Topologies) == 1 {
+				selectedNodeIdx = linkIdx % nodeSlots
+			}
+			selectedNode := selectedNodes[topology][selectedNodeIdx]
+
+			// select an endpoint within a topology
+			found := false
+			for idx, tep := range topoEndpoints[topology] {
+				tep := tep
+				//r.l.Info("selector topoEndpoints", "gvk", fmt.Sprintf("%s.%s.%s", tep.APIVersion, tep.Kind, tep.Name))
+				if tep.WasAllocated(cr) {
+					// an endpoint that was allocated before is selected again when
+					// it fits the node selection, its node is recorded to take it
+					// into account for the node selection of the next links
+					switch {
+					case selectedNode != nil:
+						found = tep.Spec.NodeName == selectedNode.Spec.NodeName
+					case diverse:
+						found = tep.IsDiverse(selectedNodeIdx, selectedNodes[topology], diversityKeys)
+					default:
+						found = true
+					}
+					if found && selectedNode == nil {
+						selectedNodes[topology][selectedNodeIdx] = &tep
+					}
+				} else if selectedNode != nil {
+					// node selection was already done, we need to select an endpoint
+					// on the same node that was not already allocated
+					found = tep.Spec.NodeName == selectedNode.Spec.NodeName && !tep.IsAllocated(cr)
+				} else if diverse {
+					// we need to select a node - ensure the node we select is
+					// diverse from the previous selected nodes and not allocated
+					if tep.IsDiverse(selectedNodeIdx, selectedNodes[topology], diversityKeys) && !tep.IsAllocated(cr) {
+						found = true
+						selectedNodes[topology][selectedNodeIdx] = &tep
+					}
+				} else if !tep.IsAllocated(cr) {
+					found = true
+					selectedNodes[topology][selectedNodeIdx] = &tep
+				}
+				if found {
+					if err := r.addEndpoint(topology, linkIdx, epIdx, tep, spec.Lacp, ep.Name); err != nil {
+						return nil, err
+					}
+					// delete the endpoint from the list to avoid reselection
+					topoEndpoints[topology] = append(topoEndpoints[topology][:idx], topoEndpoints[topology][idx+1:]...)
+					break
+				}
+			}
+			if !found {
+				switch {
+				case selectedNode != nil:
+					return nil, fmt.Errorf("no endpoints available for link %d on node %s in topology %s", linkIdx, selectedNode.Spec.NodeName, topology)
+				case diverse:
+					return nil, fmt.Errorf("no endpoints available for link %d in topology %s that satisfy %s", linkIdx, topology, ep.SelectorPolicy.String())
+				default:
+					return nil, fmt.Errorf("no endpoints available for link %d in topology %s", linkIdx, topology)
+				}
+			}
+		}
+	}
+
+	return r.result, nil
+}
+
+func (r *Result) GetLinkSpecs() []invv1alpha1.LinkSpec {
+	return r.linkSpecs
+}
+
+func (r *Result) GetSelectedEndpoints() []invv1alpha1.Endpoint {
+	return r.endpoints
+}
+
+func (r *Result) GetLogicalEndpoints() []invv1alpha1.LogicalEndpointSpec {
+	return r.logicalEndpoints
+}
+
+// option 1:
+// give the endpoints back -> issue is reallocation
+
+// option 2:
+// list the allocated endpoints based on links or endpoints
+// check if the selection
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeEndpoint is an endpoint inventory that returns all endpoints of a topology
+type fakeEndpoint struct {
+	Endpoint
+	eps map[string][]invv1alpha1.Endpoint
+}
+
+func (r *fakeEndpoint) Init(ctx context.Context, topologies []string) error { return nil }
+
+func (r *fakeEndpoint) GetTopologyEndpointsWithSelector(topology string, s *metav1.LabelSelector) ([]invv1alpha1.Endpoint, error) {
+	return r.eps[topology], nil
+}
+
+// buildEndpoints returns the endpoints for the nodes, the endpoints are
+// ordered by interface name like the endpoint inventory does
+func buildEndpoints(topology string, itfces int, nodes map[string]map[string]string) []invv1alpha1.Endpoint {
+	eps := []invv1alpha1.Endpoint{}
+	for i := 0; i < itfces; i++ {
+		for _, nodeName := range []string{"a", "b", "c", "d"} {
+			labels, ok := nodes[nodeName]
+			if !ok {
+				continue
+			}
+			itfceName := fmt.Sprintf("e1-%d", i)
+			eps = append(eps, *invv1alpha1.BuildEndpoint(
+				metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%s", nodeName, itfceName),
+					Namespace: topology,
+					Labels:    labels,
+				},
+				invv1alpha1.EndpointSpec{NodeName: nodeName, InterfaceName: itfceName},
+				invv1alpha1.EndpointStatus{},
+			))
+		}
+	}
+	return eps
+}
+
+func TestSelectEndpoints(t *testing.T) {
+	rack := func(r string) map[string]string { return map[string]string{invv1alpha1.NephioInventoryRack: r} }
+	cases := map[string]struct {
+		links     uint16
+		nodes     map[string]map[string]string
+		policy    *topov1alpha1.SelectorPolicy
+		allocated []string
+		wantNodes []string
+		expectErr bool
+	}{
+		"NoPolicy": {
+			links:     4,
+			nodes:     map[string]map[string]string{"a": nil, "b": nil},
+			wantNodes: []string{"a", "b", "a", "b"},
+		},
+		"NodeDiversity2": {
+			links:     2,
+			nodes:     map[string]map[string]string{"a": nil, "b": nil, "c": nil},
+			policy:    &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](2)},
+			wantNodes: []string{"a", "b"},
+		},
+		"NodeDiversity3": {
+			links:     6,
+			nodes:     map[string]map[string]string{"a": nil, "b": nil, "c": nil},
+			policy:    &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](3)},
+			wantNodes: []string{"a", "b", "c", "a", "b", "c"},
+		},
+		"NodeDiversity3NotEnoughNodes": {
+			links:     3,
+			nodes:     map[string]map[string]string{"a": nil, "b": nil},
+			policy:    &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](3)},
+			expectErr: true,
+		},
+		"NodeDiversityExceedsLinks": {
+			links:     2,
+			nodes:     map[string]map[string]string{"a": nil, "b": nil, "c": nil},
+			policy:    &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](3)},
+			expectErr: true,
+		},
+		"NodeDiversityAllocated": {
+			links:     4,
+			nodes:     map[string]map[string]string{"a": nil, "b": nil, "c": nil},
+			policy:    &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](2)},
+			allocated: []string{"a-e1-0", "c-e1-0"},
+			wantNodes: []string{"a", "b", "a", "b"},
+		},
+		"RackDiversity": {
+			links:     2,
+			nodes:     map[string]map[string]string{"a": rack("r1"), "b": rack("r1"), "c": rack("r2")},
+			policy:    &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](2), RackDiversity: ptr.To(true)},
+			wantNodes: []string{"a", "c"},
+		},
+		"RackDiversityNotEnoughRacks": {
+			links:     2,
+			nodes:     map[string]map[string]string{"a": rack("r1"), "b": rack("r1")},
+			policy:    &topov1alpha1.SelectorPolicy{NodeDiversity: ptr.To[uint16](2), RackDiversity: ptr.To(true)},
+			expectErr: true,
+		},
+		"RackDiversityMissingLabel": {
+			links:     2,
+			nodes:     map[string]map[string]string{"a": rack("r1"), "b": nil, "c": rack("r2")},
+			policy:    &topov1alpha1.SelectorPolicy{RackDiversity: ptr.To(true)},
+			wantNodes: []string{"a", "c"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var cr client.Object = &topov1alpha1.LogicalInterconnect{
+				TypeMeta:   metav1.TypeMeta{APIVersion: topov1alpha1.GroupVersion.String(), Kind: topov1alpha1.LogicalInterconnectKind},
+				ObjectMeta: metav1.ObjectMeta{Name: "li", Namespace: "default"},
+			}
+			t1eps := buildEndpoints("t1", 4, tc.nodes)
+			for i := range t1eps {
+				for _, name := range tc.allocated {
+					if t1eps[i].GetName() == name {
+						t1eps[i].Status.ClaimRef = getCoreRef(cr)
+					}
+				}
+			}
+			fe := &fakeEndpoint{eps: map[string][]invv1alpha1.Endpoint{
+				"t1": t1eps,
+				"t2": buildEndpoints("t2", 8, map[string]map[string]string{"d": nil}),
+			}}
+			spec := &topov1alpha1.LogicalInterconnectSpec{
+				Links: tc.links,
+				Endpoints: []topov1alpha1.LogicalInterconnectEndpoint{
+					{Topologies: []string{"t1"}, SelectorPolicy: tc.policy},
+					{Topologies: []string{"t2"}},
+				},
+			}
+			res, err := NewSelector(tc.links, fe, nil).SelectEndpoints(context.Background(), cr, spec)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("%s expected error, got nil", name)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+				return
+			}
+			for linkIdx, l := range res.GetLinkSpecs() {
+				if l.Endpoints[0].NodeName != tc.wantNodes[linkIdx] {
+					t.Errorf("%s link %d want node %s, got %s", name, linkIdx, tc.wantNodes[linkIdx], l.Endpoints[0].NodeName)
+				}
+			}
+		})
+	}
+}
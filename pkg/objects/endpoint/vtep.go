@@ -0,0 +1,155 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	vtepOffset     = 1
+	vtepMaxEntryID = 65535
+)
+
+// getVtepIndexName returns the vtep index name, vtep ids are unique per topology
+func getVtepIndexName(topology string) string {
+	return fmt.Sprintf("%s-vtep", topology)
+}
+
+func buildVtepIndex(ep *invv1alpha1.Endpoint, topology string) *vxlanv1alpha1.VXLANIndex {
+	return vxlanv1alpha1.BuildVXLANIndex(
+		metav1.ObjectMeta{
+			Name:      getVtepIndexName(topology),
+			Namespace: ep.GetNamespace(),
+		},
+		vxlanv1alpha1.VXLANIndexSpec{
+			Offset:     vtepOffset,
+			MaxEntryID: vtepMaxEntryID,
+		},
+		vxlanv1alpha1.VXLANIndexStatus{},
+	)
+}
+
+// buildVtepClaim returns a vtep claim owned by the endpoint
+func buildVtepClaim(ep *invv1alpha1.Endpoint, topology string, id *uint32) *vxlanv1alpha1.VXLANClaim {
+	return vxlanv1alpha1.BuildVXLANClaim(
+		metav1.ObjectMeta{
+			Name:      ep.GetName(),
+			Namespace: ep.GetNamespace(),
+			Labels: map[string]string{
+				resourcev1alpha1.NephioOwnerGvkKey:          invv1alpha1.EndpointKindGVKString,
+				resourcev1alpha1.NephioOwnerNsnNameKey:      ep.GetName(),
+				resourcev1alpha1.NephioOwnerNsnNamespaceKey: ep.GetNamespace(),
+			},
+		},
+		vxlanv1alpha1.VXLANClaimSpec{
+			VXLANIndex: corev1.ObjectReference{
+				Name:      getVtepIndexName(topology),
+				Namespace: ep.GetNamespace(),
+			},
+			VXLANID: id,
+		},
+		vxlanv1alpha1.VXLANClaimStatus{},
+	)
+}
+
+// isOverlay returns true if the endpoint or the object claiming the endpoint
+// is labeled for overlay wiring
+func isOverlay(o client.Object, ep *invv1alpha1.Endpoint) bool {
+	if o != nil && o.GetLabels()[invv1alpha1.NephioWiringKey] == invv1alpha1.NephioWiringOverlay {
+		return true
+	}
+	return ep.GetLabels()[invv1alpha1.NephioWiringKey] == invv1alpha1.NephioWiringOverlay
+}
+
+func getTopology(ep *invv1alpha1.Endpoint) (string, error) {
+	topology, ok := ep.GetLabels()[invv1alpha1.NephioTopologyKey]
+	if !ok || topology == "" {
+		return "", fmt.Errorf("endpoint %s has no topology label", ep.GetName())
+	}
+	return topology, nil
+}
+
+// claimVtepID claims the vtep id of the endpoint from the topology vtep index
+// and updates the status of the endpoint. A vtep id that was claimed before is
+// claimed again statically to keep it stable. When the static id is held by
+// another owner a new vtep id is claimed dynamically.
+func (r *endpoint) claimVtepID(ctx context.Context, ep *invv1alpha1.Endpoint) error {
+	if r.vtepClientProxy == nil {
+		return nil
+	}
+	topology, err := getTopology(ep)
+	if err != nil {
+		return err
+	}
+	var id *uint32
+	if ep.Status.VtepID != "" {
+		vtepID, err := strconv.ParseUint(ep.Status.VtepID, 10, 32)
+		if err == nil {
+			id = ptr.To[uint32](uint32(vtepID))
+		}
+	}
+	// creating the index is idempotent in the backend
+	if err := r.vtepClientProxy.CreateIndex(ctx, buildVtepIndex(ep, topology)); err != nil {
+		return err
+	}
+	claim, err := r.vtepClientProxy.Claim(ctx, buildVtepClaim(ep, topology, id), nil)
+	if id != nil && backend.GetErrorCode(err) == resourcepb.ErrorCode_Conflict {
+		claim, err = r.vtepClientProxy.Claim(ctx, buildVtepClaim(ep, topology, nil), nil)
+	}
+	if err != nil {
+		return err
+	}
+	if claim == nil || claim.Status.VXLANID == nil {
+		return fmt.Errorf("no vtep id claimed for endpoint %s", ep.GetName())
+	}
+	ep.Status.VtepID = strconv.FormatUint(uint64(*claim.Status.VXLANID), 10)
+	return nil
+}
+
+// deleteVtepID releases the vtep id of the endpoint
+func (r *endpoint) deleteVtepID(ctx context.Context, ep *invv1alpha1.Endpoint) error {
+	if r.vtepClientProxy == nil {
+		return nil
+	}
+	topology, err := getTopology(ep)
+	if err != nil {
+		return err
+	}
+	// the index is created to ensure the backend is initialized
+	// e.g. after a restart
+	if err := r.vtepClientProxy.CreateIndex(ctx, buildVtepIndex(ep, topology)); err != nil {
+		return err
+	}
+	if err := r.vtepClientProxy.DeleteClaim(ctx, buildVtepClaim(ep, topology, nil), nil); err != nil {
+		return err
+	}
+	ep.Status.VtepID = ""
+	return nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"context"
+	"testing"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeVtepProxy is a vxlan client proxy that hands out vtep ids from a map
+type fakeVtepProxy struct {
+	clientproxy.Proxy[*vxlanv1alpha1.VXLANIndex, *vxlanv1alpha1.VXLANClaim]
+	// owners of the claimed vtep ids
+	owners map[uint32]string
+	claims int
+}
+
+func newFakeVtepProxy() *fakeVtepProxy {
+	return &fakeVtepProxy{owners: map[uint32]string{}}
+}
+
+func (r *fakeVtepProxy) CreateIndex(ctx context.Context, cr *vxlanv1alpha1.VXLANIndex) error {
+	return nil
+}
+
+func (r *fakeVtepProxy) Claim(ctx context.Context, o client.Object, d any) (*vxlanv1alpha1.VXLANClaim, error) {
+	r.claims++
+	cr := o.(*vxlanv1alpha1.VXLANClaim)
+	if cr.Spec.VXLANID != nil {
+		id := *cr.Spec.VXLANID
+		if owner, ok := r.owners[id]; ok && owner != cr.GetName() {
+			return nil, backend.NewError(resourcepb.ErrorCode_Conflict, "vxlan ID %d already claimed", id)
+		}
+		r.owners[id] = cr.GetName()
+		cr.Status.VXLANID = ptr.To[uint32](id)
+		return cr, nil
+	}
+	for id := uint32(vtepOffset); id <= vtepMaxEntryID; id++ {
+		if owner, ok := r.owners[id]; !ok || owner == cr.GetName() {
+			r.owners[id] = cr.GetName()
+			cr.Status.VXLANID = ptr.To[uint32](id)
+			return cr, nil
+		}
+	}
+	return nil, backend.NewError(resourcepb.ErrorCode_PoolExhausted, "no free vtep id")
+}
+
+func (r *fakeVtepProxy) DeleteClaim(ctx context.Context, o client.Object, d any) error {
+	for id, owner := range r.owners {
+		if owner == o.GetName() {
+			delete(r.owners, id)
+		}
+	}
+	return nil
+}
+
+func buildVtepEndpoint(name, wiring, vtepID string) *invv1alpha1.Endpoint {
+	labels := map[string]string{invv1alpha1.NephioTopologyKey: "topo"}
+	if wiring != "" {
+		labels[invv1alpha1.NephioWiringKey] = wiring
+	}
+	ep := invv1alpha1.BuildEndpoint(metav1.ObjectMeta{
+		Name:      name,
+		Namespace: "default",
+		Labels:    labels,
+	}, invv1alpha1.EndpointSpec{}, invv1alpha1.EndpointStatus{})
+	ep.Status.VtepID = vtepID
+	return ep
+}
+
+func TestClaimVtepID(t *testing.T) {
+	cases := map[string]struct {
+		owners     map[uint32]string
+		ep         *invv1alpha1.Endpoint
+		expectedID string
+	}{
+		"Dynamic": {
+			ep:         buildVtepEndpoint("ep1", invv1alpha1.NephioWiringOverlay, ""),
+			expectedID: "1",
+		},
+		"StaticStable": {
+			owners:     map[uint32]string{1: "ep2", 5: "ep1"},
+			ep:         buildVtepEndpoint("ep1", invv1alpha1.NephioWiringOverlay, "5"),
+			expectedID: "5",
+		},
+		"StaticConflictFallsBackToDynamic": {
+			owners:     map[uint32]string{1: "ep2", 5: "ep3"},
+			ep:         buildVtepEndpoint("ep1", invv1alpha1.NephioWiringOverlay, "5"),
+			expectedID: "2",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := newFakeVtepProxy()
+			for id, owner := range tc.owners {
+				p.owners[id] = owner
+			}
+			r := &endpoint{vtepClientProxy: p}
+			if err := r.claimVtepID(context.Background(), tc.ep); err != nil {
+				t.Fatalf("claimVtepID: %v", err)
+			}
+			if tc.ep.Status.VtepID != tc.expectedID {
+				t.Errorf("want vtep id %s, got %s", tc.expectedID, tc.ep.Status.VtepID)
+			}
+		})
+	}
+}
+
+func TestClaimOverlayOnly(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := invv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	eps := []*invv1alpha1.Endpoint{
+		buildVtepEndpoint("underlay", "", ""),
+		buildVtepEndpoint("overlay", invv1alpha1.NephioWiringOverlay, ""),
+		// an endpoint that was used for overlay wiring before
+		buildVtepEndpoint("former", "", "7"),
+	}
+	c := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(eps[0], eps[1], eps[2]).
+		WithStatusSubresource(&invv1alpha1.Endpoint{}).
+		Build()
+
+	p := newFakeVtepProxy()
+	p.owners[7] = "former"
+	r := New(c, WithVtepClientProxy(p))
+
+	link := invv1alpha1.BuildEndpoint(metav1.ObjectMeta{Name: "link", Namespace: "default"},
+		invv1alpha1.EndpointSpec{}, invv1alpha1.EndpointStatus{})
+	claimEps := []invv1alpha1.Endpoint{}
+	for _, ep := range eps {
+		claimEps = append(claimEps, *ep)
+	}
+	if err := r.Claim(context.Background(), link, claimEps); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if p.claims != 1 {
+		t.Errorf("want 1 vtep claim, got %d", p.claims)
+	}
+
+	expected := map[string]string{"underlay": "", "overlay": "1", "former": ""}
+	for name, vtepID := range expected {
+		ep := &invv1alpha1.Endpoint{}
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, ep); err != nil {
+			t.Fatal(err)
+		}
+		if ep.Status.VtepID != vtepID {
+			t.Errorf("endpoint %s: want vtep id %q, got %q", name, vtepID, ep.Status.VtepID)
+		}
+		if ep.Status.ClaimRef == nil {
+			t.Errorf("endpoint %s: no claim ref", name)
+		}
+	}
+	if _, ok := p.owners[7]; ok {
+		t.Errorf("vtep id of the former overlay endpoint is not released")
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// SelfVariable references the object the pipeline is evaluated against
+	SelfVariable = "self"
+	// ReplicaVariable references the index of the replica
+	ReplicaVariable = "replica"
+)
+
+// Variables hold the values an expression can reference, e.g. the selected
+// objects by their variable name and the replica index
+type Variables map[string]any
+
+// Mutate applies a mutator expression on the object. The expression has the
+// form "<path> = <value>", where path is a dot separated field path of the
+// object and value is either a json literal, a quoted string in which
+// ${<variable>.<path>} references are interpolated or a single reference which
+// keeps the type of the referenced value.
+func Mutate(obj map[string]any, expr string, vars Variables) error {
+	lhs, rhs, ok := splitOperator(expr, "=")
+	if !ok {
+		return fmt.Errorf("invalid mutator expression %q, expected <path> = <value>", expr)
+	}
+	path := splitPath(lhs)
+	if len(path) == 0 {
+		return fmt.Errorf("invalid mutator expression %q, empty path", expr)
+	}
+	v, err := evaluate(rhs, withSelf(vars, obj))
+	if err != nil {
+		return fmt.Errorf("invalid mutator expression %q, err: %s", expr, err.Error())
+	}
+	return setField(obj, path, toUnstructuredValue(v))
+}
+
+// Validate evaluates a validator expression against the object and returns
+// true when the object passes the validation. The expression has the form
+// "<value> == <value>" or "<value> != <value>", values follow the same rules
+// as the mutator values. The object is referenced through the self variable.
+func Validate(obj map[string]any, expr string, vars Variables) (bool, error) {
+	vars = withSelf(vars, obj)
+	for _, op := range []string{"==", "!="} {
+		lhs, rhs, ok := splitOperator(expr, op)
+		if !ok {
+			continue
+		}
+		l, err := evaluate(lhs, vars)
+		if err != nil {
+			return false, fmt.Errorf("invalid validator expression %q, err: %s", expr, err.Error())
+		}
+		r, err := evaluate(rhs, vars)
+		if err != nil {
+			return false, fmt.Errorf("invalid validator expression %q, err: %s", expr, err.Error())
+		}
+		equal := reflect.DeepEqual(normalize(l), normalize(r))
+		if op == "==" {
+			return equal, nil
+		}
+		return !equal, nil
+	}
+	return false, fmt.Errorf("invalid validator expression %q, expected <value> == <value> or <value> != <value>", expr)
+}
+
+func withSelf(vars Variables, obj map[string]any) Variables {
+	newVars := make(Variables, len(vars)+1)
+	for k, v := range vars {
+		newVars[k] = v
+	}
+	newVars[SelfVariable] = obj
+	return newVars
+}
+
+// splitOperator splits the expression on the first occurance of the operator
+// that is not part of a quoted string
+func splitOperator(expr, op string) (string, string, bool) {
+	inQuote := false
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case expr[i] == '\\' && inQuote:
+			i++
+		case expr[i] == '"':
+			inQuote = !inQuote
+		case !inQuote && strings.HasPrefix(expr[i:], op):
+			// the assignment operator should not match a comparison operator
+			if op == "=" && ((i > 0 && (expr[i-1] == '=' || expr[i-1] == '!')) || strings.HasPrefix(expr[i:], "==")) {
+				continue
+			}
+			return strings.TrimSpace(expr[:i]), strings.TrimSpace(expr[i+len(op):]), true
+		}
+	}
+	return "", "", false
+}
+
+func splitPath(s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ".")
+}
+
+// evaluate returns the value of an operand
+func evaluate(s string, vars Variables) (any, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty value")
+	}
+	// single reference, keeps the type of the referenced value
+	if strings.HasPrefix(s, "${") && strings.HasSuffix(s, "}") && strings.Count(s, "${") == 1 {
+		return lookup(s[2:len(s)-1], vars)
+	}
+	// quoted string with interpolation
+	if strings.HasPrefix(s, "\"") {
+		var str string
+		if err := json.Unmarshal([]byte(s), &str); err != nil {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		return interpolate(str, vars)
+	}
+	// json literal
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("invalid value %s", s)
+	}
+	return v, nil
+}
+
+func interpolate(s string, vars Variables) (string, error) {
+	var sb strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated reference in %q", s)
+		}
+		v, err := lookup(s[start+2:start+end], vars)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(s[:start])
+		if v != nil {
+			sb.WriteString(fmt.Sprintf("%v", v))
+		}
+		s = s[start+end+1:]
+	}
+}
+
+// lookup returns the value of the reference, a reference to a field that does
+// not exist returns nil, a reference to an unknown variable returns an error
+func lookup(ref string, vars Variables) (any, error) {
+	path := splitPath(ref)
+	if len(path) == 0 {
+		return nil, fmt.Errorf("empty reference")
+	}
+	v, ok := vars[path[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable %s", path[0])
+	}
+	for _, field := range path[1:] {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		v = m[field]
+	}
+	return v, nil
+}
+
+func setField(obj map[string]any, path []string, v any) error {
+	m := obj
+	for i, field := range path[:len(path)-1] {
+		next, ok := m[field]
+		if !ok || next == nil {
+			next = map[string]any{}
+			m[field] = next
+		}
+		nm, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("field %s is not an object", strings.Join(path[:i+1], "."))
+		}
+		m = nm
+	}
+	m[path[len(path)-1]] = v
+	return nil
+}
+
+// normalize converts the numeric values to float64 such that values from
+// unstructured objects and json literals can be compared
+func normalize(v any) any {
+	switch x := v.(type) {
+	case int:
+		return float64(x)
+	case int32:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint32:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case map[string]any:
+		m := make(map[string]any, len(x))
+		for k, v := range x {
+			m[k] = normalize(v)
+		}
+		return m
+	case []any:
+		l := make([]any, 0, len(x))
+		for _, v := range x {
+			l = append(l, normalize(v))
+		}
+		return l
+	default:
+		return v
+	}
+}
+
+// toUnstructuredValue converts the numeric values to the types supported by
+// unstructured objects, integral numbers become int64
+func toUnstructuredValue(v any) any {
+	switch x := normalize(v).(type) {
+	case float64:
+		if x == float64(int64(x)) {
+			return int64(x)
+		}
+		return x
+	case map[string]any:
+		for k, v := range x {
+			x[k] = toUnstructuredValue(v)
+		}
+		return x
+	case []any:
+		for i, v := range x {
+			x[i] = toUnstructuredValue(v)
+		}
+		return x
+	default:
+		return x
+	}
+}
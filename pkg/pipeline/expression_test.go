@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMutate(t *testing.T) {
+	cases := map[string]struct {
+		obj       map[string]any
+		expr      string
+		vars      Variables
+		want      map[string]any
+		expectErr bool
+	}{
+		"Literal": {
+			obj:  map[string]any{},
+			expr: "spec.vlanID = 10",
+			want: map[string]any{"spec": map[string]any{"vlanID": int64(10)}},
+		},
+		"Interpolation": {
+			obj:  map[string]any{"metadata": map[string]any{"name": "a"}},
+			expr: `metadata.name = "${node.metadata.name}-${replica}"`,
+			vars: Variables{
+				"node":    map[string]any{"metadata": map[string]any{"name": "srl1"}},
+				"replica": int64(1),
+			},
+			want: map[string]any{"metadata": map[string]any{"name": "srl1-1"}},
+		},
+		"Reference": {
+			obj:  map[string]any{},
+			expr: "spec.labels = ${node.metadata.labels}",
+			vars: Variables{
+				"node": map[string]any{"metadata": map[string]any{"labels": map[string]any{"a": "b"}}},
+			},
+			want: map[string]any{"spec": map[string]any{"labels": map[string]any{"a": "b"}}},
+		},
+		"Self": {
+			obj:  map[string]any{"spec": map[string]any{"a": "x"}},
+			expr: `spec.b = "${self.spec.a}-y"`,
+			want: map[string]any{"spec": map[string]any{"a": "x", "b": "x-y"}},
+		},
+		"UnknownVariable": {
+			obj:       map[string]any{},
+			expr:      "spec.a = ${unknown.a}",
+			expectErr: true,
+		},
+		"NoAssignment": {
+			obj:       map[string]any{},
+			expr:      "spec.a == 1",
+			expectErr: true,
+		},
+		"NotAnObject": {
+			obj:       map[string]any{"spec": "a"},
+			expr:      "spec.a = 1",
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Mutate(tc.obj, tc.expr, tc.vars)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("%s expected error, got nil", name)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+				return
+			}
+			if diff := cmp.Diff(tc.want, tc.obj); diff != "" {
+				t.Errorf("%s -want, +got:\n%s", name, diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	obj := map[string]any{
+		"metadata": map[string]any{"name": "a"},
+		"spec":     map[string]any{"vlanID": int64(10)},
+	}
+	cases := map[string]struct {
+		expr      string
+		want      bool
+		expectErr bool
+	}{
+		"Equal": {
+			expr: "${self.spec.vlanID} == 10",
+			want: true,
+		},
+		"NotEqual": {
+			expr: `${self.metadata.name} != "a"`,
+			want: false,
+		},
+		"Missing": {
+			expr: "${self.spec.prefix} == null",
+			want: true,
+		},
+		"OperatorInString": {
+			expr: `"${self.metadata.name}==" == "a=="`,
+			want: true,
+		},
+		"NoOperator": {
+			expr:      "${self.spec.vlanID}",
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Validate(obj, tc.expr, nil)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("%s expected error, got nil", name)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+				return
+			}
+			if got != tc.want {
+				t.Errorf("%s want %t, got %t", name, tc.want, got)
+			}
+		})
+	}
+}
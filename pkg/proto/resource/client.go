@@ -19,25 +19,47 @@ package resource
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	// enables the client side health check
+	_ "google.golang.org/grpc/health"
 )
 
 const (
-	defaultTimeout = 30 * time.Second
-	maxMsgSize     = 512 * 1024 * 1024
+	defaultTimeout            = 30 * time.Second
+	defaultMaxMsgSize         = 512 * 1024 * 1024
+	defaultReconnectBaseDelay = time.Second
+	defaultReconnectMaxDelay  = 2 * time.Minute
+	// resolverScheme resolves a comma separated list of backend addresses
+	resolverScheme = "resource"
 )
 
 type Client interface {
 	Delete() error
 	Get() resourcepb.ResourceClient
+	// IsReady returns true if the connection to the backend is established
+	IsReady() bool
+	// OnReconnect registers a handler which is called every time the
+	// connection is re-established after it was lost
+	OnReconnect(func())
 }
 
+// New creates a client that connects to the backend in the background. A lost
+// connection is re-established with an exponential backoff.
 func New(cfg *Config) (Client, error) {
 	c := &client{
 		cfg: cfg,
@@ -49,6 +71,9 @@ type client struct {
 	cfg              *Config
 	conn             *grpc.ClientConn
 	resourcePbClient resourcepb.ResourceClient
+
+	m           sync.RWMutex
+	onReconnect []func()
 }
 
 func (r *client) create() error {
@@ -56,7 +81,9 @@ func (r *client) create() error {
 		return fmt.Errorf("must provide non-nil Configw")
 	}
 	var opts []grpc.DialOption
-	fmt.Printf("grpc client config: %v\n", r.cfg)
+	// the config is printed on stderr, such that it does not mix with the
+	// output of the cli
+	fmt.Fprintf(os.Stderr, "grpc client config: %v\n", r.cfg)
 	if r.cfg.Insecure {
 		//opts = append(opts, grpc.WithInsecure())
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -67,20 +94,125 @@ func (r *client) create() error {
 		}
 		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	}
-	timeoutCtx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
+	maxMsgSize := r.cfg.MaxMsgSize
+	if maxMsgSize <= 0 {
+		maxMsgSize = defaultMaxMsgSize
+	}
+	opts = append(opts, grpc.WithDefaultCallOptions(
+		grpc.MaxCallRecvMsgSize(maxMsgSize),
+		grpc.MaxCallSendMsgSize(maxMsgSize),
+	))
+	opts = append(opts, grpc.WithConnectParams(r.getConnectParams()))
+	// the trace context of the caller is propagated to the backend
+	opts = append(opts,
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tracing.StreamClientInterceptor()),
+	)
+	if r.cfg.TokenFile != "" {
+		// the token is not sent in plaintext
+		if r.cfg.Insecure {
+			return fmt.Errorf("a token file requires tls")
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(&tokenCredentials{path: r.cfg.TokenFile}))
+	}
+	if r.cfg.HealthService != "" {
+		// the connection is not ready while the backend is restoring its
+		// indices, such that the requests wait for the backend to be serving.
+		// The health check requires the round robin balancer, which only
+		// picks the serving leader of an active/standby backend.
+		opts = append(opts, grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig":[{"round_robin":{}}],"healthCheckConfig":{"serviceName":%q}}`, r.cfg.HealthService)))
+	}
+	target := r.cfg.Address
+	if addresses := strings.Split(r.cfg.Address, ","); len(addresses) > 1 {
+		// the backend instances are resolved statically
+		res := manual.NewBuilderWithScheme(resolverScheme)
+		state := resolver.State{}
+		for _, address := range addresses {
+			state.Addresses = append(state.Addresses, resolver.Address{Addr: strings.TrimSpace(address)})
+		}
+		res.InitialState(state)
+		opts = append(opts, grpc.WithResolvers(res))
+		target = resolverScheme + ":///backend"
+	}
 
+	// the dial does not block, the connection is established in the background
+	// such that the controllers can start while the backend is unavailable
 	var err error
-	r.conn, err = grpc.DialContext(timeoutCtx, r.cfg.Address, opts...)
+	r.conn, err = grpc.Dial(target, opts...)
 	if err != nil {
 		return err
 	}
-	//defer conn.Close()
 	r.resourcePbClient = resourcepb.NewResourceClient(r.conn)
+	r.conn.Connect()
+	go r.watchState()
 
 	return nil
 }
 
+func (r *client) getConnectParams() grpc.ConnectParams {
+	p := grpc.ConnectParams{
+		Backoff:           backoff.DefaultConfig,
+		MinConnectTimeout: r.cfg.DialTimeout,
+	}
+	p.Backoff.BaseDelay = r.cfg.ReconnectBaseDelay
+	p.Backoff.MaxDelay = r.cfg.ReconnectMaxDelay
+	if p.MinConnectTimeout <= 0 {
+		p.MinConnectTimeout = defaultTimeout
+	}
+	if p.Backoff.BaseDelay <= 0 {
+		p.Backoff.BaseDelay = defaultReconnectBaseDelay
+	}
+	if p.Backoff.MaxDelay < p.Backoff.BaseDelay {
+		p.Backoff.MaxDelay = defaultReconnectMaxDelay
+	}
+	return p
+}
+
+// watchState keeps the connection connected and calls the reconnect handlers
+// when a lost connection is re-established
+func (r *client) watchState() {
+	connected := false
+	lost := false
+	state := r.conn.GetState()
+	for {
+		switch state {
+		case connectivity.Ready:
+			if lost {
+				r.m.RLock()
+				for _, fn := range r.onReconnect {
+					go fn()
+				}
+				r.m.RUnlock()
+			}
+			connected = true
+			lost = false
+		case connectivity.Idle:
+			// an idle connection only reconnects on the next rpc, since the
+			// watches need to be re-established we reconnect immediately
+			r.conn.Connect()
+			lost = connected
+		case connectivity.TransientFailure:
+			lost = connected
+		case connectivity.Shutdown:
+			return
+		}
+		if !r.conn.WaitForStateChange(context.Background(), state) {
+			return
+		}
+		state = r.conn.GetState()
+	}
+}
+
+func (r *client) IsReady() bool {
+	return r.conn != nil && r.conn.GetState() == connectivity.Ready
+}
+
+func (r *client) OnReconnect(fn func()) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.onReconnect = append(r.onReconnect, fn)
+}
+
 func (r *client) Get() resourcepb.ResourceClient {
 	return r.resourcePbClient
 }
@@ -97,34 +229,24 @@ func (r *client) newTLS() (*tls.Config, error) {
 		Renegotiation:      tls.RenegotiateNever,
 		InsecureSkipVerify: r.cfg.SkipVerify,
 	}
-	//err := loadCerts(tlsConfig)
-	//if err != nil {
-	//	return nil, err
-	//}
-	return tlsConfig, nil
-}
-
-/*
-func loadCerts(tlscfg *tls.Config) error {
-	if c.TLSCert != "" && c.TLSKey != "" {
-		certificate, err := tls.LoadX509KeyPair(*c.TLSCert, *c.TLSKey)
+	if r.cfg.TLSCert != "" && r.cfg.TLSKey != "" {
+		certificate, err := tls.LoadX509KeyPair(r.cfg.TLSCert, r.cfg.TLSKey)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("cannot load client certificate: %w", err)
 		}
-		tlscfg.Certificates = []tls.Certificate{certificate}
-		tlscfg.BuildNameToCertificate()
+		tlsConfig.Certificates = []tls.Certificate{certificate}
 	}
-	if c.TLSCA != nil && *c.TLSCA != "" {
-		certPool := x509.NewCertPool()
-		caFile, err := ioutil.ReadFile(*c.TLSCA)
+	// the system roots verify the backend certificate when no ca is provided
+	if r.cfg.TLSCA != "" {
+		ca, err := os.ReadFile(r.cfg.TLSCA)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("cannot read ca certificate: %w", err)
 		}
-		if ok := certPool.AppendCertsFromPEM(caFile); !ok {
-			return errors.New("failed to append certificate")
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("cannot append ca certificate %s", r.cfg.TLSCA)
 		}
-		tlscfg.RootCAs = certPool
+		tlsConfig.RootCAs = certPool
 	}
-	return nil
+	return tlsConfig, nil
 }
-*/
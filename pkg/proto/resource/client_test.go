@@ -0,0 +1,105 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resource
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"google.golang.org/grpc"
+)
+
+func startServer(t *testing.T, address string) *grpc.Server {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatalf("cannot listen: %s", err.Error())
+	}
+	s := grpc.NewServer()
+	resourcepb.RegisterResourceServer(s, &resourcepb.UnimplementedResourceServer{})
+	go s.Serve(l)
+	return s
+}
+
+func waitFor(t *testing.T, msg string, fn func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !fn() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	// reserve a free port that is reused when the server restarts
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %s", err.Error())
+	}
+	address := l.Addr().String()
+	l.Close()
+
+	// the client is created while the server is unavailable
+	c, err := New(&Config{
+		Address:            address,
+		Insecure:           true,
+		DialTimeout:        time.Second,
+		ReconnectBaseDelay: 10 * time.Millisecond,
+		ReconnectMaxDelay:  100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err.Error())
+	}
+	defer c.Delete()
+	reconnected := make(chan struct{}, 1)
+	c.OnReconnect(func() { reconnected <- struct{}{} })
+
+	if c.IsReady() {
+		t.Errorf("client ready without a server")
+	}
+
+	s := startServer(t, address)
+	waitFor(t, "the initial connection", c.IsReady)
+	select {
+	case <-reconnected:
+		t.Errorf("reconnect handler called on the initial connection")
+	default:
+	}
+
+	// restart the server
+	s.Stop()
+	waitFor(t, "the lost connection", func() bool { return !c.IsReady() })
+	s = startServer(t, address)
+	defer s.Stop()
+	waitFor(t, "the reconnect", c.IsReady)
+	select {
+	case <-reconnected:
+	case <-time.After(10 * time.Second):
+		t.Errorf("reconnect handler not called")
+	}
+}
+
+func TestTokenRequiresTLS(t *testing.T) {
+	if !(&tokenCredentials{}).RequireTransportSecurity() {
+		t.Errorf("token sent without transport security")
+	}
+	if _, err := New(&Config{Address: "127.0.0.1:0", Insecure: true, TokenFile: "token"}); err == nil {
+		t.Errorf("want error for a token without tls")
+	}
+}
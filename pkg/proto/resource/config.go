@@ -16,7 +16,12 @@ limitations under the License.
 
 package resource
 
+import "time"
+
 type Config struct {
+	// Address of the backend. The instances of an active/standby backend are
+	// a comma separated list of addresses or a dns:/// target of a headless
+	// service, the requests are sent to the serving leader.
 	Address    string
 	Username   string
 	Password   string
@@ -27,5 +32,20 @@ type Config struct {
 	TLSKey     string
 	SkipVerify bool
 	Insecure   bool
+	// MaxMsgSize is the max size of a message sent or received, defaults to 512MiB
 	MaxMsgSize int
+	// DialTimeout is the timeout of a single connection attempt, defaults to 30s
+	DialTimeout time.Duration
+	// ReconnectBaseDelay is the delay after the first failed connection
+	// attempt, the delay is increased exponentially on every subsequent
+	// failure, defaults to 1s
+	ReconnectBaseDelay time.Duration
+	// ReconnectMaxDelay caps the delay between connection attempts, defaults to 2m
+	ReconnectMaxDelay time.Duration
+	// HealthService is the grpc health service of the backend, the connection
+	// is only ready while the service is serving. Empty disables the health check.
+	HealthService string
+	// TokenFile is the file with the bearer token sent with every request,
+	// typically the projected service account token. Empty sends no token.
+	TokenFile string
 }
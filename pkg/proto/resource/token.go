@@ -0,0 +1,46 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tokenCredentials sends the token in the file as bearer token. The file is
+// read on every request since the kubelet rotates the projected token.
+type tokenCredentials struct {
+	path string
+}
+
+func (r *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read token: %w", err)
+	}
+	return map[string]string{
+		"authorization": "Bearer " + strings.TrimSpace(string(b)),
+	}, nil
+}
+
+// RequireTransportSecurity is true such that the token is never sent over a
+// plaintext connection.
+func (r *tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -50,6 +50,49 @@ func (StatusCode) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_20916bbff21c491c, []int{0}
 }
 
+type ErrorCode int32
+
+const (
+	ErrorCode_NoError          ErrorCode = 0
+	ErrorCode_IndexNotReady    ErrorCode = 1
+	ErrorCode_PoolExhausted    ErrorCode = 2
+	ErrorCode_SelectorNoMatch  ErrorCode = 3
+	ErrorCode_Conflict         ErrorCode = 4
+	ErrorCode_ValidationFailed ErrorCode = 5
+	ErrorCode_Internal         ErrorCode = 6
+	ErrorCode_QuotaExceeded    ErrorCode = 7
+)
+
+var ErrorCode_name = map[int32]string{
+	0: "NoError",
+	1: "IndexNotReady",
+	2: "PoolExhausted",
+	3: "SelectorNoMatch",
+	4: "Conflict",
+	5: "ValidationFailed",
+	6: "Internal",
+	7: "QuotaExceeded",
+}
+
+var ErrorCode_value = map[string]int32{
+	"NoError":          0,
+	"IndexNotReady":    1,
+	"PoolExhausted":    2,
+	"SelectorNoMatch":  3,
+	"Conflict":         4,
+	"ValidationFailed": 5,
+	"Internal":         6,
+	"QuotaExceeded":    7,
+}
+
+func (x ErrorCode) String() string {
+	return proto.EnumName(ErrorCode_name, int32(x))
+}
+
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{1}
+}
+
 type Instance struct {
 	Nsn                  *NSN     `protobuf:"bytes,1,opt,name=nsn,proto3" json:"nsn,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -98,9 +141,12 @@ func (m *Instance) GetNsn() *NSN {
 }
 
 type ClaimRequest struct {
-	Header               *Header  `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	Spec                 string   `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
-	ExpiryTime           string   `protobuf:"bytes,3,opt,name=expiryTime,proto3" json:"expiryTime,omitempty"`
+	Header     *Header `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Spec       string  `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+	ExpiryTime string  `protobuf:"bytes,3,opt,name=expiryTime,proto3" json:"expiryTime,omitempty"`
+	// the claim is validated and applied on a copy of the index, such that the
+	// response holds the would-be allocation without claiming it
+	DryRun               bool     `protobuf:"varint,4,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -160,10 +206,19 @@ func (m *ClaimRequest) GetExpiryTime() string {
 	return ""
 }
 
+func (m *ClaimRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type EmptyResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ErrorCode            ErrorCode `protobuf:"varint,1,opt,name=errorCode,proto3,enum=resource.ErrorCode" json:"errorCode,omitempty"`
+	ErrorMessage         string    `protobuf:"bytes,2,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *EmptyResponse) Reset()         { *m = EmptyResponse{} }
@@ -199,12 +254,28 @@ func (m *EmptyResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_EmptyResponse proto.InternalMessageInfo
 
+func (m *EmptyResponse) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_NoError
+}
+
+func (m *EmptyResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
 type ClaimResponse struct {
 	Header               *Header    `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	Spec                 string     `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
 	Status               string     `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
 	StatusCode           StatusCode `protobuf:"varint,4,opt,name=statusCode,proto3,enum=resource.StatusCode" json:"statusCode,omitempty"`
 	ExpiryTime           string     `protobuf:"bytes,5,opt,name=expiryTime,proto3" json:"expiryTime,omitempty"`
+	ErrorCode            ErrorCode  `protobuf:"varint,6,opt,name=errorCode,proto3,enum=resource.ErrorCode" json:"errorCode,omitempty"`
+	ErrorMessage         string     `protobuf:"bytes,7,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
 	XXX_unrecognized     []byte     `json:"-"`
 	XXX_sizecache        int32      `json:"-"`
@@ -278,14 +349,31 @@ func (m *ClaimResponse) GetExpiryTime() string {
 	return ""
 }
 
+func (m *ClaimResponse) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_NoError
+}
+
+func (m *ClaimResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
 type WatchResponse struct {
 	Header *Header `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	// string spec = 2;
-	// string status = 3;
-	StatusCode           StatusCode `protobuf:"varint,2,opt,name=statusCode,proto3,enum=resource.StatusCode" json:"statusCode,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	//string spec = 2;
+	//string status = 3;
+	StatusCode StatusCode `protobuf:"varint,2,opt,name=statusCode,proto3,enum=resource.StatusCode" json:"statusCode,omitempty"`
+	//string expiryTime = 3;
+	// sequence number of the update within the watch of the client
+	Sequence             uint64   `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
@@ -335,8 +423,21 @@ func (m *WatchResponse) GetStatusCode() StatusCode {
 	return StatusCode_Valid
 }
 
+func (m *WatchResponse) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
 type WatchRequest struct {
-	Header               *Header  `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Header *Header `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// stable identity of the client, a watch of a client that reconnects
+	// replaces the previous watch of the same client
+	ClientId string `protobuf:"bytes,2,opt,name=clientId,proto3" json:"clientId,omitempty"`
+	// sequence number of the last update received by the client, the updates
+	// after it are replayed. 0 starts a new watch without replay.
+	Sequence             uint64   `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -382,28 +483,40 @@ func (m *WatchRequest) GetHeader() *Header {
 	return nil
 }
 
-type Header struct {
-	Gvk                  *GVK     `protobuf:"bytes,1,opt,name=gvk,proto3" json:"gvk,omitempty"`
-	Nsn                  *NSN     `protobuf:"bytes,2,opt,name=nsn,proto3" json:"nsn,omitempty"`
-	OwnerGvk             *GVK     `protobuf:"bytes,3,opt,name=ownerGvk,proto3" json:"ownerGvk,omitempty"`
-	OwnerNsn             *NSN     `protobuf:"bytes,4,opt,name=ownerNsn,proto3" json:"ownerNsn,omitempty"`
+func (m *WatchRequest) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+type ExportRequest struct {
+	// groupVersions of the backends to export, all backends when empty
+	GroupVersions        []string `protobuf:"bytes,1,rep,name=groupVersions,proto3" json:"groupVersions,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Header) Reset()         { *m = Header{} }
-func (m *Header) String() string { return proto.CompactTextString(m) }
-func (*Header) ProtoMessage()    {}
-func (*Header) Descriptor() ([]byte, []int) {
+func (m *ExportRequest) Reset()         { *m = ExportRequest{} }
+func (m *ExportRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportRequest) ProtoMessage()    {}
+func (*ExportRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_20916bbff21c491c, []int{6}
 }
-func (m *Header) XXX_Unmarshal(b []byte) error {
+func (m *ExportRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ExportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Header.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ExportRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -413,67 +526,111 @@ func (m *Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Header) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Header.Merge(m, src)
+func (m *ExportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportRequest.Merge(m, src)
 }
-func (m *Header) XXX_Size() int {
+func (m *ExportRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *Header) XXX_DiscardUnknown() {
-	xxx_messageInfo_Header.DiscardUnknown(m)
+func (m *ExportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Header proto.InternalMessageInfo
+var xxx_messageInfo_ExportRequest proto.InternalMessageInfo
 
-func (m *Header) GetGvk() *GVK {
+func (m *ExportRequest) GetGroupVersions() []string {
 	if m != nil {
-		return m.Gvk
+		return m.GroupVersions
 	}
 	return nil
 }
 
-func (m *Header) GetNsn() *NSN {
+type ExportResponse struct {
+	// versioned snapshot document in json
+	Snapshot             string    `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	ErrorCode            ErrorCode `protobuf:"varint,2,opt,name=errorCode,proto3,enum=resource.ErrorCode" json:"errorCode,omitempty"`
+	ErrorMessage         string    `protobuf:"bytes,3,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ExportResponse) Reset()         { *m = ExportResponse{} }
+func (m *ExportResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportResponse) ProtoMessage()    {}
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{7}
+}
+func (m *ExportResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExportResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportResponse.Merge(m, src)
+}
+func (m *ExportResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportResponse proto.InternalMessageInfo
+
+func (m *ExportResponse) GetSnapshot() string {
 	if m != nil {
-		return m.Nsn
+		return m.Snapshot
 	}
-	return nil
+	return ""
 }
 
-func (m *Header) GetOwnerGvk() *GVK {
+func (m *ExportResponse) GetErrorCode() ErrorCode {
 	if m != nil {
-		return m.OwnerGvk
+		return m.ErrorCode
 	}
-	return nil
+	return ErrorCode_NoError
 }
 
-func (m *Header) GetOwnerNsn() *NSN {
+func (m *ExportResponse) GetErrorMessage() string {
 	if m != nil {
-		return m.OwnerNsn
+		return m.ErrorMessage
 	}
-	return nil
+	return ""
 }
 
-type GVK struct {
-	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
-	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	Kind                 string   `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+type ImportRequest struct {
+	// versioned snapshot document in json
+	Snapshot string `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// only validates the snapshot against the state of the backends
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GVK) Reset()         { *m = GVK{} }
-func (m *GVK) String() string { return proto.CompactTextString(m) }
-func (*GVK) ProtoMessage()    {}
-func (*GVK) Descriptor() ([]byte, []int) {
-	return fileDescriptor_20916bbff21c491c, []int{7}
+func (m *ImportRequest) Reset()         { *m = ImportRequest{} }
+func (m *ImportRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportRequest) ProtoMessage()    {}
+func (*ImportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{8}
 }
-func (m *GVK) XXX_Unmarshal(b []byte) error {
+func (m *ImportRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *GVK) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ImportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_GVK.Marshal(b, m, deterministic)
+		return xxx_messageInfo_ImportRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -483,59 +640,132 @@ func (m *GVK) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *GVK) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GVK.Merge(m, src)
+func (m *ImportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportRequest.Merge(m, src)
 }
-func (m *GVK) XXX_Size() int {
+func (m *ImportRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *GVK) XXX_DiscardUnknown() {
-	xxx_messageInfo_GVK.DiscardUnknown(m)
+func (m *ImportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GVK proto.InternalMessageInfo
+var xxx_messageInfo_ImportRequest proto.InternalMessageInfo
 
-func (m *GVK) GetGroup() string {
+func (m *ImportRequest) GetSnapshot() string {
 	if m != nil {
-		return m.Group
+		return m.Snapshot
 	}
 	return ""
 }
 
-func (m *GVK) GetVersion() string {
+func (m *ImportRequest) GetDryRun() bool {
 	if m != nil {
-		return m.Version
+		return m.DryRun
 	}
-	return ""
+	return false
 }
 
-func (m *GVK) GetKind() string {
+type ImportResponse struct {
+	// conflicts of the snapshot with the state of the backends, the snapshot
+	// is not applied when it has conflicts. A backend that conflicts while it
+	// is applied is not applied, the error message reports the backends
+	// applied before it.
+	Conflicts            []string  `protobuf:"bytes,1,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	Applied              bool      `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	ErrorCode            ErrorCode `protobuf:"varint,3,opt,name=errorCode,proto3,enum=resource.ErrorCode" json:"errorCode,omitempty"`
+	ErrorMessage         string    `protobuf:"bytes,4,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ImportResponse) Reset()         { *m = ImportResponse{} }
+func (m *ImportResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportResponse) ProtoMessage()    {}
+func (*ImportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{9}
+}
+func (m *ImportResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ImportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ImportResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ImportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportResponse.Merge(m, src)
+}
+func (m *ImportResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ImportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportResponse proto.InternalMessageInfo
+
+func (m *ImportResponse) GetConflicts() []string {
 	if m != nil {
-		return m.Kind
+		return m.Conflicts
+	}
+	return nil
+}
+
+func (m *ImportResponse) GetApplied() bool {
+	if m != nil {
+		return m.Applied
+	}
+	return false
+}
+
+func (m *ImportResponse) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_NoError
+}
+
+func (m *ImportResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
 	}
 	return ""
 }
 
-type NSN struct {
-	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+type TreeRequest struct {
+	// gvk and nsn of the index
+	Header *Header `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// the tree only contains the entries within the root entry, all entries
+	// of the index when empty
+	Root string `protobuf:"bytes,2,opt,name=root,proto3" json:"root,omitempty"`
+	// levels of the tree that are returned, all levels when 0
+	Depth                uint32   `protobuf:"varint,3,opt,name=depth,proto3" json:"depth,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *NSN) Reset()         { *m = NSN{} }
-func (m *NSN) String() string { return proto.CompactTextString(m) }
-func (*NSN) ProtoMessage()    {}
-func (*NSN) Descriptor() ([]byte, []int) {
-	return fileDescriptor_20916bbff21c491c, []int{8}
+func (m *TreeRequest) Reset()         { *m = TreeRequest{} }
+func (m *TreeRequest) String() string { return proto.CompactTextString(m) }
+func (*TreeRequest) ProtoMessage()    {}
+func (*TreeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{10}
 }
-func (m *NSN) XXX_Unmarshal(b []byte) error {
+func (m *TreeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *NSN) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *TreeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_NSN.Marshal(b, m, deterministic)
+		return xxx_messageInfo_TreeRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -545,117 +775,408 @@ func (m *NSN) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *NSN) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NSN.Merge(m, src)
+func (m *TreeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeRequest.Merge(m, src)
 }
-func (m *NSN) XXX_Size() int {
+func (m *TreeRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *NSN) XXX_DiscardUnknown() {
-	xxx_messageInfo_NSN.DiscardUnknown(m)
+func (m *TreeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_NSN proto.InternalMessageInfo
+var xxx_messageInfo_TreeRequest proto.InternalMessageInfo
 
-func (m *NSN) GetNamespace() string {
+func (m *TreeRequest) GetHeader() *Header {
 	if m != nil {
-		return m.Namespace
+		return m.Header
 	}
-	return ""
+	return nil
 }
 
-func (m *NSN) GetName() string {
+func (m *TreeRequest) GetRoot() string {
 	if m != nil {
-		return m.Name
+		return m.Root
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterEnum("resource.StatusCode", StatusCode_name, StatusCode_value)
-	proto.RegisterType((*Instance)(nil), "resource.Instance")
-	proto.RegisterType((*ClaimRequest)(nil), "resource.ClaimRequest")
-	proto.RegisterType((*EmptyResponse)(nil), "resource.EmptyResponse")
-	proto.RegisterType((*ClaimResponse)(nil), "resource.ClaimResponse")
-	proto.RegisterType((*WatchResponse)(nil), "resource.WatchResponse")
-	proto.RegisterType((*WatchRequest)(nil), "resource.WatchRequest")
-	proto.RegisterType((*Header)(nil), "resource.Header")
-	proto.RegisterType((*GVK)(nil), "resource.GVK")
-	proto.RegisterType((*NSN)(nil), "resource.NSN")
+func (m *TreeRequest) GetDepth() uint32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
 }
 
-func init() {
-	proto.RegisterFile("pkg/proto/resourcepb/resource.proto", fileDescriptor_20916bbff21c491c)
+type TreeResponse struct {
+	// tree of the entries of the index in json
+	Tree                 string    `protobuf:"bytes,1,opt,name=tree,proto3" json:"tree,omitempty"`
+	ErrorCode            ErrorCode `protobuf:"varint,2,opt,name=errorCode,proto3,enum=resource.ErrorCode" json:"errorCode,omitempty"`
+	ErrorMessage         string    `protobuf:"bytes,3,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
-var fileDescriptor_20916bbff21c491c = []byte{
-	// 562 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xd1, 0x8e, 0x12, 0x31,
-	0x14, 0xa5, 0xb0, 0xb0, 0x70, 0x59, 0x94, 0x34, 0x9b, 0x95, 0x18, 0x83, 0x64, 0x7c, 0x41, 0x8d,
-	0xa0, 0x68, 0xd4, 0x98, 0x98, 0xa8, 0x68, 0x58, 0xb2, 0x09, 0x0f, 0x83, 0x62, 0xe2, 0xdb, 0x30,
-	0xdc, 0xc0, 0x08, 0xd3, 0xd6, 0x69, 0x61, 0x77, 0x7f, 0xc2, 0x67, 0x1f, 0xfd, 0x0f, 0x7f, 0xc0,
-	0x47, 0x3f, 0xc1, 0xe0, 0x8f, 0x98, 0x29, 0x65, 0x66, 0x58, 0x62, 0x82, 0xbb, 0x6f, 0xf7, 0xde,
-	0xd3, 0x7b, 0x7a, 0xda, 0x9e, 0x5b, 0xb8, 0x23, 0xa6, 0xe3, 0xa6, 0x08, 0xb8, 0xe2, 0xcd, 0x00,
-	0x25, 0x9f, 0x07, 0x2e, 0x8a, 0x61, 0x14, 0x36, 0x34, 0x42, 0xf3, 0xeb, 0xdc, 0xba, 0x0f, 0xf9,
-	0x2e, 0x93, 0xca, 0x61, 0x2e, 0xd2, 0xdb, 0x90, 0x61, 0x92, 0x55, 0x48, 0x8d, 0xd4, 0x8b, 0xad,
-	0x52, 0x23, 0xea, 0xe9, 0xf5, 0x7b, 0x76, 0x88, 0x58, 0x33, 0x38, 0x68, 0xcf, 0x1c, 0xcf, 0xb7,
-	0xf1, 0xcb, 0x1c, 0xa5, 0xa2, 0x75, 0xc8, 0x4d, 0xd0, 0x19, 0x61, 0x60, 0x7a, 0xca, 0x71, 0xcf,
-	0xb1, 0xae, 0xdb, 0x06, 0xa7, 0x14, 0xf6, 0xa4, 0x40, 0xb7, 0x92, 0xae, 0x91, 0x7a, 0xc1, 0xd6,
-	0x31, 0xad, 0x02, 0xe0, 0x99, 0xf0, 0x82, 0xf3, 0xf7, 0x9e, 0x8f, 0x95, 0x8c, 0x46, 0x12, 0x15,
-	0xeb, 0x3a, 0x94, 0xde, 0xf9, 0x42, 0x9d, 0xdb, 0x28, 0x05, 0x67, 0x12, 0xad, 0x1f, 0x04, 0x4a,
-	0x66, 0xff, 0x55, 0xe5, 0x8a, 0x02, 0x8e, 0x20, 0x27, 0x95, 0xa3, 0xe6, 0xd2, 0x6c, 0x6e, 0x32,
-	0xfa, 0x04, 0x60, 0x15, 0xb5, 0xf9, 0x08, 0x2b, 0x7b, 0x35, 0x52, 0xbf, 0xd6, 0x3a, 0x8c, 0x99,
-	0xfb, 0x11, 0x66, 0x27, 0xd6, 0x5d, 0x38, 0x4e, 0x76, 0xeb, 0x38, 0x1c, 0x4a, 0x1f, 0x1d, 0xe5,
-	0x4e, 0x2e, 0x21, 0x7e, 0x53, 0x50, 0x7a, 0x37, 0x41, 0xd6, 0x73, 0x38, 0x30, 0x1b, 0xfe, 0xe7,
-	0x6b, 0x59, 0xdf, 0x09, 0xe4, 0x56, 0xa5, 0xd0, 0x13, 0xe3, 0xc5, 0x74, 0xdb, 0x13, 0x9d, 0xc1,
-	0x89, 0x1d, 0x22, 0x6b, 0xd3, 0xa4, 0xff, 0x65, 0x1a, 0x7a, 0x17, 0xf2, 0xfc, 0x94, 0x61, 0xd0,
-	0x59, 0x4c, 0xf5, 0x3d, 0x6f, 0xd1, 0x44, 0x70, 0xb4, 0xb4, 0x27, 0x99, 0xbe, 0xf6, 0x2d, 0xc2,
-	0x08, 0xb6, 0xba, 0x90, 0xe9, 0x0c, 0x4e, 0xe8, 0x21, 0x64, 0xc7, 0x01, 0x9f, 0x0b, 0x2d, 0xb0,
-	0x60, 0xaf, 0x12, 0x5a, 0x81, 0xfd, 0x05, 0x06, 0xd2, 0xe3, 0xcc, 0xbc, 0xf7, 0x3a, 0x0d, 0x6d,
-	0x30, 0xf5, 0xd8, 0xc8, 0x3c, 0xb8, 0x8e, 0xad, 0x67, 0x90, 0xe9, 0xf5, 0x7b, 0xf4, 0x16, 0x14,
-	0x98, 0xe3, 0xa3, 0x14, 0x8e, 0x8b, 0x86, 0x2e, 0x2e, 0x84, 0x8d, 0x61, 0xb2, 0xf6, 0x4f, 0x18,
-	0xdf, 0x7b, 0x04, 0x10, 0x5f, 0x3d, 0x2d, 0x40, 0x76, 0xe0, 0xcc, 0xbc, 0x51, 0x39, 0x45, 0x8b,
-	0xb0, 0xdf, 0x65, 0xab, 0x84, 0x84, 0xc9, 0x07, 0x36, 0x65, 0xfc, 0x94, 0x95, 0xd3, 0xad, 0xaf,
-	0x19, 0xc8, 0xdb, 0xe6, 0x44, 0xf4, 0x15, 0x14, 0xdb, 0x01, 0x3a, 0x0a, 0xbb, 0x6c, 0x84, 0x67,
-	0xf4, 0x28, 0x3e, 0x6b, 0x72, 0xca, 0x6e, 0xde, 0x88, 0xeb, 0x9b, 0xf3, 0x90, 0x0a, 0x19, 0xde,
-	0xe2, 0x0c, 0xaf, 0xc0, 0xf0, 0x12, 0xf2, 0x1d, 0x54, 0x7a, 0xf5, 0x2e, 0xed, 0x1b, 0xe3, 0x67,
-	0xa5, 0xe8, 0x0b, 0xc8, 0x5e, 0xba, 0x37, 0x12, 0xbf, 0x33, 0xc3, 0x45, 0xf1, 0xaf, 0x01, 0xb4,
-	0xc3, 0xb7, 0x08, 0x92, 0xbe, 0x4f, 0x12, 0x6c, 0x0c, 0xa0, 0x95, 0x7a, 0x48, 0xde, 0x1c, 0xff,
-	0x5c, 0x56, 0xc9, 0xaf, 0x65, 0x95, 0xfc, 0x5e, 0x56, 0xc9, 0xb7, 0x3f, 0xd5, 0xd4, 0xa7, 0xa7,
-	0x63, 0x4f, 0x4d, 0xe6, 0xc3, 0x86, 0xcb, 0xfd, 0x26, 0x43, 0x31, 0xf1, 0xf8, 0x03, 0x11, 0xf0,
-	0xcf, 0xe8, 0xaa, 0xa6, 0x27, 0x1c, 0xbf, 0x19, 0xfe, 0xaf, 0x6b, 0xc2, 0xc4, 0x17, 0x3b, 0xcc,
-	0xe9, 0xaf, 0xf5, 0xf1, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x91, 0xff, 0x3b, 0xc6, 0x81, 0x05,
-	0x00, 0x00,
+func (m *TreeResponse) Reset()         { *m = TreeResponse{} }
+func (m *TreeResponse) String() string { return proto.CompactTextString(m) }
+func (*TreeResponse) ProtoMessage()    {}
+func (*TreeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{11}
+}
+func (m *TreeResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TreeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TreeResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TreeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeResponse.Merge(m, src)
+}
+func (m *TreeResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TreeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeResponse.DiscardUnknown(m)
 }
 
-func (m *Instance) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_TreeResponse proto.InternalMessageInfo
+
+func (m *TreeResponse) GetTree() string {
+	if m != nil {
+		return m.Tree
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Instance) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *TreeResponse) GetErrorCode() ErrorCode {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ErrorCode_NoError
 }
 
-func (m *Instance) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
+func (m *TreeResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
 	}
-	if m.Nsn != nil {
-		{
-			size, err := m.Nsn.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
+	return ""
+}
+
+type Header struct {
+	Gvk                  *GVK     `protobuf:"bytes,1,opt,name=gvk,proto3" json:"gvk,omitempty"`
+	Nsn                  *NSN     `protobuf:"bytes,2,opt,name=nsn,proto3" json:"nsn,omitempty"`
+	OwnerGvk             *GVK     `protobuf:"bytes,3,opt,name=ownerGvk,proto3" json:"ownerGvk,omitempty"`
+	OwnerNsn             *NSN     `protobuf:"bytes,4,opt,name=ownerNsn,proto3" json:"ownerNsn,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Header) Reset()         { *m = Header{} }
+func (m *Header) String() string { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()    {}
+func (*Header) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{12}
+}
+func (m *Header) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Header.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Header) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Header.Merge(m, src)
+}
+func (m *Header) XXX_Size() int {
+	return m.Size()
+}
+func (m *Header) XXX_DiscardUnknown() {
+	xxx_messageInfo_Header.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Header proto.InternalMessageInfo
+
+func (m *Header) GetGvk() *GVK {
+	if m != nil {
+		return m.Gvk
+	}
+	return nil
+}
+
+func (m *Header) GetNsn() *NSN {
+	if m != nil {
+		return m.Nsn
+	}
+	return nil
+}
+
+func (m *Header) GetOwnerGvk() *GVK {
+	if m != nil {
+		return m.OwnerGvk
+	}
+	return nil
+}
+
+func (m *Header) GetOwnerNsn() *NSN {
+	if m != nil {
+		return m.OwnerNsn
+	}
+	return nil
+}
+
+type GVK struct {
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Kind                 string   `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GVK) Reset()         { *m = GVK{} }
+func (m *GVK) String() string { return proto.CompactTextString(m) }
+func (*GVK) ProtoMessage()    {}
+func (*GVK) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{13}
+}
+func (m *GVK) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GVK) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GVK.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GVK) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GVK.Merge(m, src)
+}
+func (m *GVK) XXX_Size() int {
+	return m.Size()
+}
+func (m *GVK) XXX_DiscardUnknown() {
+	xxx_messageInfo_GVK.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GVK proto.InternalMessageInfo
+
+func (m *GVK) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *GVK) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *GVK) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+type NSN struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NSN) Reset()         { *m = NSN{} }
+func (m *NSN) String() string { return proto.CompactTextString(m) }
+func (*NSN) ProtoMessage()    {}
+func (*NSN) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20916bbff21c491c, []int{14}
+}
+func (m *NSN) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NSN) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NSN.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NSN) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NSN.Merge(m, src)
+}
+func (m *NSN) XXX_Size() int {
+	return m.Size()
+}
+func (m *NSN) XXX_DiscardUnknown() {
+	xxx_messageInfo_NSN.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NSN proto.InternalMessageInfo
+
+func (m *NSN) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *NSN) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("resource.StatusCode", StatusCode_name, StatusCode_value)
+	proto.RegisterEnum("resource.ErrorCode", ErrorCode_name, ErrorCode_value)
+	proto.RegisterType((*Instance)(nil), "resource.Instance")
+	proto.RegisterType((*ClaimRequest)(nil), "resource.ClaimRequest")
+	proto.RegisterType((*EmptyResponse)(nil), "resource.EmptyResponse")
+	proto.RegisterType((*ClaimResponse)(nil), "resource.ClaimResponse")
+	proto.RegisterType((*WatchResponse)(nil), "resource.WatchResponse")
+	proto.RegisterType((*WatchRequest)(nil), "resource.WatchRequest")
+	proto.RegisterType((*ExportRequest)(nil), "resource.ExportRequest")
+	proto.RegisterType((*ExportResponse)(nil), "resource.ExportResponse")
+	proto.RegisterType((*ImportRequest)(nil), "resource.ImportRequest")
+	proto.RegisterType((*ImportResponse)(nil), "resource.ImportResponse")
+	proto.RegisterType((*TreeRequest)(nil), "resource.TreeRequest")
+	proto.RegisterType((*TreeResponse)(nil), "resource.TreeResponse")
+	proto.RegisterType((*Header)(nil), "resource.Header")
+	proto.RegisterType((*GVK)(nil), "resource.GVK")
+	proto.RegisterType((*NSN)(nil), "resource.NSN")
+}
+
+func init() {
+	proto.RegisterFile("pkg/proto/resourcepb/resource.proto", fileDescriptor_20916bbff21c491c)
+}
+
+var fileDescriptor_20916bbff21c491c = []byte{
+	// 952 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x5d, 0x6f, 0x1b, 0x45,
+	0x17, 0xce, 0xda, 0x8e, 0x63, 0x9f, 0xc4, 0x79, 0xf7, 0x9d, 0x86, 0xd4, 0xb2, 0x50, 0x88, 0x16,
+	0x2e, 0x42, 0x11, 0x31, 0x0d, 0x5f, 0x12, 0x52, 0x25, 0xc0, 0x18, 0x77, 0x55, 0xd5, 0x82, 0x4d,
+	0x09, 0x12, 0x77, 0x93, 0xdd, 0x53, 0x7b, 0xf1, 0xee, 0xcc, 0x32, 0x3b, 0x4e, 0xed, 0x6b, 0xb8,
+	0xe3, 0x8e, 0xab, 0xde, 0xc1, 0xcf, 0xe9, 0x25, 0x3f, 0x01, 0x85, 0x3f, 0x82, 0x66, 0xf6, 0xdb,
+	0x4e, 0xab, 0xe0, 0x8a, 0xbb, 0x79, 0xce, 0x99, 0xf3, 0xf5, 0x9c, 0x99, 0x33, 0x03, 0x6f, 0x47,
+	0xb3, 0x49, 0x3f, 0x12, 0x5c, 0xf2, 0xbe, 0xc0, 0x98, 0xcf, 0x85, 0x8b, 0xd1, 0x65, 0xbe, 0x3c,
+	0xd5, 0x1a, 0xd2, 0xca, 0xb0, 0xf5, 0x1e, 0xb4, 0x6c, 0x16, 0x4b, 0xca, 0x5c, 0x24, 0x6f, 0x41,
+	0x9d, 0xc5, 0xac, 0x6b, 0x1c, 0x1b, 0x27, 0xbb, 0x67, 0x9d, 0xd3, 0xdc, 0x66, 0x7c, 0x3e, 0x76,
+	0x94, 0xc6, 0xfa, 0xc5, 0x80, 0xbd, 0x41, 0x40, 0xfd, 0xd0, 0xc1, 0x9f, 0xe6, 0x18, 0x4b, 0x72,
+	0x02, 0xcd, 0x29, 0x52, 0x0f, 0x45, 0x6a, 0x64, 0x16, 0x46, 0x0f, 0xb5, 0xdc, 0x49, 0xf5, 0x84,
+	0x40, 0x23, 0x8e, 0xd0, 0xed, 0xd6, 0x8e, 0x8d, 0x93, 0xb6, 0xa3, 0xd7, 0xe4, 0x08, 0x00, 0x17,
+	0x91, 0x2f, 0x96, 0x4f, 0xfc, 0x10, 0xbb, 0x75, 0xad, 0x29, 0x49, 0xc8, 0x21, 0x34, 0x3d, 0xb1,
+	0x74, 0xe6, 0xac, 0xdb, 0x38, 0x36, 0x4e, 0x5a, 0x4e, 0x8a, 0xac, 0xa7, 0xd0, 0x19, 0x86, 0x91,
+	0x5c, 0x3a, 0x18, 0x47, 0x9c, 0xc5, 0x48, 0xee, 0x43, 0x1b, 0x85, 0xe0, 0x62, 0xc0, 0x3d, 0xd4,
+	0x99, 0xec, 0x9f, 0xdd, 0x29, 0x32, 0x19, 0x66, 0x2a, 0xa7, 0xd8, 0x45, 0x2c, 0xd8, 0xd3, 0xe0,
+	0x31, 0xc6, 0x31, 0x9d, 0x60, 0x9a, 0x57, 0x45, 0x66, 0xfd, 0x56, 0x83, 0x4e, 0x5a, 0x6e, 0x1a,
+	0xe8, 0xf5, 0xea, 0x3d, 0x84, 0x66, 0x2c, 0xa9, 0x9c, 0xc7, 0x69, 0xad, 0x29, 0x22, 0x1f, 0x01,
+	0x24, 0x2b, 0x9d, 0x7f, 0x43, 0xe7, 0x7f, 0x50, 0x78, 0x3e, 0xcf, 0x75, 0x4e, 0x69, 0xdf, 0x0a,
+	0x7b, 0xdb, 0x6b, 0xec, 0x55, 0x48, 0x69, 0x6e, 0x44, 0xca, 0xce, 0x0d, 0xa4, 0xfc, 0x6a, 0x40,
+	0xe7, 0x7b, 0x2a, 0xdd, 0xe9, 0x06, 0xa4, 0x54, 0x0b, 0xad, 0xdd, 0xb2, 0xd0, 0x1e, 0xb4, 0x62,
+	0x75, 0xde, 0x98, 0x9b, 0x1c, 0x92, 0x86, 0x93, 0x63, 0x2b, 0x82, 0xbd, 0x34, 0x99, 0x7f, 0x7b,
+	0x20, 0x7b, 0xd0, 0x72, 0x03, 0x1f, 0x99, 0xb4, 0xbd, 0xb4, 0x49, 0x39, 0x7e, 0x65, 0xc4, 0x8f,
+	0xa1, 0x33, 0x5c, 0x44, 0x5c, 0xc8, 0x2c, 0xe4, 0x3b, 0xd0, 0x99, 0x08, 0x3e, 0x8f, 0x2e, 0x50,
+	0xc4, 0x3e, 0x67, 0x71, 0xd7, 0x38, 0xae, 0x9f, 0xb4, 0x9d, 0xaa, 0xd0, 0xfa, 0xd9, 0x80, 0xfd,
+	0xcc, 0x2e, 0xe5, 0x4d, 0x45, 0x61, 0x34, 0x8a, 0xa7, 0x5c, 0xea, 0x6c, 0xdb, 0x4e, 0x8e, 0xab,
+	0xcd, 0xab, 0x6d, 0xd4, 0xbc, 0xfa, 0x0d, 0xcd, 0x1b, 0x40, 0xc7, 0x0e, 0xcb, 0xc9, 0xbf, 0x2a,
+	0x87, 0xe2, 0xfa, 0xd5, 0x2a, 0xd7, 0xef, 0x77, 0x03, 0xf6, 0xed, 0xb0, 0x52, 0xca, 0x9b, 0xd0,
+	0x76, 0x39, 0x7b, 0x1a, 0xf8, 0xae, 0xcc, 0xea, 0x2f, 0x04, 0xa4, 0x0b, 0x3b, 0x34, 0x8a, 0x02,
+	0x1f, 0xbd, 0xd4, 0x53, 0x06, 0xab, 0x65, 0xd6, 0x37, 0x2a, 0xb3, 0x71, 0x43, 0x99, 0x14, 0x76,
+	0x9f, 0x08, 0xc4, 0x8d, 0xa6, 0x94, 0xe0, 0x5c, 0x66, 0xb7, 0x56, 0xad, 0xc9, 0x01, 0x6c, 0x7b,
+	0x18, 0xc9, 0xa9, 0xce, 0xaf, 0xe3, 0x24, 0xc0, 0x5a, 0xc2, 0x5e, 0x12, 0x22, 0x65, 0x80, 0x40,
+	0x43, 0x0a, 0xc4, 0x94, 0x44, 0xbd, 0xfe, 0xaf, 0x9a, 0xf8, 0x87, 0x01, 0xcd, 0x24, 0x6f, 0x35,
+	0xb1, 0x27, 0x57, 0xb3, 0xf5, 0x89, 0x3d, 0xba, 0x78, 0xe4, 0x28, 0x4d, 0x36, 0xd2, 0x6b, 0x2f,
+	0x1b, 0xe9, 0xe4, 0x5d, 0x68, 0xf1, 0x67, 0x0c, 0xc5, 0xe8, 0x6a, 0xd6, 0xad, 0xaf, 0xee, 0x52,
+	0x6e, 0x72, 0x75, 0xbe, 0x75, 0x1c, 0x27, 0x03, 0x79, 0xcd, 0x61, 0xae, 0xb6, 0x6c, 0xa8, 0x8f,
+	0x2e, 0x1e, 0x29, 0xea, 0xf4, 0x2d, 0x48, 0x59, 0x49, 0x80, 0x3a, 0x0e, 0x57, 0xc9, 0xb5, 0x48,
+	0x79, 0xce, 0xa0, 0x22, 0x71, 0xe6, 0x33, 0x2f, 0xad, 0x5a, 0xaf, 0xad, 0x4f, 0xa1, 0x3e, 0x3e,
+	0x1f, 0xab, 0x13, 0xc6, 0x68, 0x88, 0x71, 0x44, 0xdd, 0x8c, 0xe4, 0x42, 0xa0, 0x0c, 0x15, 0xc8,
+	0xfa, 0xa6, 0xd6, 0xf7, 0xee, 0x03, 0x14, 0x03, 0x85, 0xb4, 0x61, 0xfb, 0x82, 0x06, 0xbe, 0x67,
+	0x6e, 0x91, 0x5d, 0xd8, 0xb1, 0x59, 0x02, 0x0c, 0x05, 0xbe, 0x63, 0x33, 0xc6, 0x9f, 0x31, 0xb3,
+	0x76, 0xef, 0xb9, 0x01, 0xed, 0xbc, 0x2d, 0x4a, 0x35, 0xe6, 0x1a, 0x9a, 0x5b, 0xe4, 0xff, 0xd0,
+	0xb1, 0x99, 0x87, 0x8b, 0x31, 0x97, 0x0e, 0x52, 0x6f, 0x69, 0x1a, 0x4a, 0xf4, 0x0d, 0xe7, 0xc1,
+	0x70, 0x31, 0xa5, 0xf3, 0x58, 0xa2, 0x67, 0xd6, 0xc8, 0x1d, 0xf8, 0xdf, 0x39, 0x06, 0xe8, 0x4a,
+	0x2e, 0xc6, 0xfc, 0xb1, 0x1a, 0x4c, 0x66, 0x9d, 0xec, 0x41, 0x6b, 0x90, 0xde, 0x05, 0xb3, 0x41,
+	0x0e, 0xc0, 0xd4, 0xb1, 0xa9, 0xf4, 0x39, 0xfb, 0x9a, 0xfa, 0x01, 0x7a, 0xe6, 0xb6, 0xda, 0x63,
+	0x33, 0x89, 0x82, 0xd1, 0xc0, 0x6c, 0x2a, 0xcf, 0xdf, 0xce, 0xb9, 0xa4, 0xc3, 0x85, 0x8b, 0xe8,
+	0xa1, 0x67, 0xee, 0x9c, 0xbd, 0x68, 0x40, 0xcb, 0x49, 0xc9, 0x26, 0x9f, 0xc3, 0xee, 0x40, 0x20,
+	0x95, 0xa8, 0x53, 0x22, 0x87, 0x45, 0x1b, 0xca, 0xaf, 0x73, 0xef, 0x6e, 0xe9, 0xb0, 0x95, 0xdf,
+	0x4b, 0x6b, 0x4b, 0x79, 0xf8, 0x0a, 0x03, 0x7c, 0x0d, 0x0f, 0x0f, 0xa0, 0x35, 0x42, 0xa9, 0x77,
+	0xdf, 0xc6, 0xbc, 0xf2, 0x8e, 0x5a, 0x5b, 0xe4, 0x33, 0xd8, 0xde, 0xd8, 0x36, 0x4f, 0xfe, 0xd6,
+	0x1e, 0x56, 0x93, 0xff, 0x02, 0x40, 0x3f, 0x1b, 0x6b, 0x0e, 0xca, 0x8f, 0x49, 0xef, 0xee, 0x9a,
+	0x3c, 0x73, 0xf0, 0x81, 0x41, 0x1e, 0x40, 0x33, 0x99, 0xe7, 0xa4, 0x1c, 0xa7, 0xfc, 0x32, 0xf4,
+	0xba, 0xeb, 0x8a, 0x12, 0x7d, 0x4d, 0x3b, 0x5c, 0x35, 0xb7, 0xc3, 0x97, 0x98, 0x57, 0xc7, 0xad,
+	0xa6, 0x6f, 0x67, 0x84, 0x52, 0x4d, 0x20, 0xf2, 0x46, 0xb1, 0xad, 0x34, 0xf4, 0x7a, 0x87, 0xab,
+	0xe2, 0xcc, 0xf6, 0xcb, 0x87, 0x2f, 0xae, 0x8f, 0x8c, 0x3f, 0xaf, 0x8f, 0x8c, 0xbf, 0xae, 0x8f,
+	0x8c, 0xe7, 0x7f, 0x1f, 0x6d, 0xfd, 0xf0, 0xc9, 0xc4, 0x97, 0xd3, 0xf9, 0xe5, 0xa9, 0xcb, 0xc3,
+	0x3e, 0xc3, 0x68, 0xea, 0xf3, 0xf7, 0x23, 0xc1, 0x7f, 0x44, 0x57, 0xf6, 0xfd, 0x88, 0x86, 0x7d,
+	0xf5, 0xa5, 0xcc, 0xbc, 0x95, 0x7e, 0x95, 0x97, 0x4d, 0xfd, 0x9b, 0xfc, 0xf0, 0x9f, 0x01, 0x00,
+	0x43, 0xb9, 0xf7, 0xce, 0x74, 0x0a, 0x00, 0x00,
+}
+
+func (m *Instance) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Instance) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Instance) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Nsn != nil {
+		{
+			size, err := m.Nsn.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
 				return 0, err
 			}
 			i -= size
@@ -691,6 +1212,16 @@ func (m *ClaimRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.DryRun {
+		i--
+		if m.DryRun {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if len(m.ExpiryTime) > 0 {
 		i -= len(m.ExpiryTime)
 		copy(dAtA[i:], m.ExpiryTime)
@@ -744,6 +1275,18 @@ func (m *EmptyResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ErrorCode != 0 {
+		i = encodeVarintResource(dAtA, i, uint64(m.ErrorCode))
+		i--
+		dAtA[i] = 0x8
+	}
 	return len(dAtA) - i, nil
 }
 
@@ -771,6 +1314,18 @@ func (m *ClaimResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.ErrorCode != 0 {
+		i = encodeVarintResource(dAtA, i, uint64(m.ErrorCode))
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.ExpiryTime) > 0 {
 		i -= len(m.ExpiryTime)
 		copy(dAtA[i:], m.ExpiryTime)
@@ -836,6 +1391,11 @@ func (m *WatchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Sequence != 0 {
+		i = encodeVarintResource(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.StatusCode != 0 {
 		i = encodeVarintResource(dAtA, i, uint64(m.StatusCode))
 		i--
@@ -880,9 +1440,256 @@ func (m *WatchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.Header != nil {
-		{
-			size, err := m.Header.MarshalToSizedBuffer(dAtA[:i])
+	if m.Sequence != 0 {
+		i = encodeVarintResource(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.ClientId) > 0 {
+		i -= len(m.ClientId)
+		copy(dAtA[i:], m.ClientId)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.ClientId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Header != nil {
+		{
+			size, err := m.Header.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintResource(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExportRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExportRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExportRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.GroupVersions) > 0 {
+		for iNdEx := len(m.GroupVersions) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.GroupVersions[iNdEx])
+			copy(dAtA[i:], m.GroupVersions[iNdEx])
+			i = encodeVarintResource(dAtA, i, uint64(len(m.GroupVersions[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExportResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExportResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExportResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.ErrorCode != 0 {
+		i = encodeVarintResource(dAtA, i, uint64(m.ErrorCode))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Snapshot) > 0 {
+		i -= len(m.Snapshot)
+		copy(dAtA[i:], m.Snapshot)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.Snapshot)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ImportRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ImportRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ImportRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.DryRun {
+		i--
+		if m.DryRun {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Snapshot) > 0 {
+		i -= len(m.Snapshot)
+		copy(dAtA[i:], m.Snapshot)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.Snapshot)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ImportResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ImportResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ImportResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.ErrorCode != 0 {
+		i = encodeVarintResource(dAtA, i, uint64(m.ErrorCode))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Applied {
+		i--
+		if m.Applied {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Conflicts) > 0 {
+		for iNdEx := len(m.Conflicts) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Conflicts[iNdEx])
+			copy(dAtA[i:], m.Conflicts[iNdEx])
+			i = encodeVarintResource(dAtA, i, uint64(len(m.Conflicts[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TreeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TreeRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TreeRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Depth != 0 {
+		i = encodeVarintResource(dAtA, i, uint64(m.Depth))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Root) > 0 {
+		i -= len(m.Root)
+		copy(dAtA[i:], m.Root)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.Root)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Header != nil {
+		{
+			size, err := m.Header.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -895,6 +1702,52 @@ func (m *WatchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *TreeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TreeResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TreeResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.ErrorMessage) > 0 {
+		i -= len(m.ErrorMessage)
+		copy(dAtA[i:], m.ErrorMessage)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.ErrorMessage)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.ErrorCode != 0 {
+		i = encodeVarintResource(dAtA, i, uint64(m.ErrorCode))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Tree) > 0 {
+		i -= len(m.Tree)
+		copy(dAtA[i:], m.Tree)
+		i = encodeVarintResource(dAtA, i, uint64(len(m.Tree)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Header) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1104,6 +1957,9 @@ func (m *ClaimRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovResource(uint64(l))
 	}
+	if m.DryRun {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1116,6 +1972,13 @@ func (m *EmptyResponse) Size() (n int) {
 	}
 	var l int
 	_ = l
+	if m.ErrorCode != 0 {
+		n += 1 + sovResource(uint64(m.ErrorCode))
+	}
+	l = len(m.ErrorMessage)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1147,6 +2010,13 @@ func (m *ClaimResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovResource(uint64(l))
 	}
+	if m.ErrorCode != 0 {
+		n += 1 + sovResource(uint64(m.ErrorCode))
+	}
+	l = len(m.ErrorMessage)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1166,6 +2036,9 @@ func (m *WatchResponse) Size() (n int) {
 	if m.StatusCode != 0 {
 		n += 1 + sovResource(uint64(m.StatusCode))
 	}
+	if m.Sequence != 0 {
+		n += 1 + sovResource(uint64(m.Sequence))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1182,33 +2055,30 @@ func (m *WatchRequest) Size() (n int) {
 		l = m.Header.Size()
 		n += 1 + l + sovResource(uint64(l))
 	}
+	l = len(m.ClientId)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovResource(uint64(m.Sequence))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
 	return n
 }
 
-func (m *Header) Size() (n int) {
+func (m *ExportRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Gvk != nil {
-		l = m.Gvk.Size()
-		n += 1 + l + sovResource(uint64(l))
-	}
-	if m.Nsn != nil {
-		l = m.Nsn.Size()
-		n += 1 + l + sovResource(uint64(l))
-	}
-	if m.OwnerGvk != nil {
-		l = m.OwnerGvk.Size()
-		n += 1 + l + sovResource(uint64(l))
-	}
-	if m.OwnerNsn != nil {
-		l = m.OwnerNsn.Size()
-		n += 1 + l + sovResource(uint64(l))
+	if len(m.GroupVersions) > 0 {
+		for _, s := range m.GroupVersions {
+			l = len(s)
+			n += 1 + l + sovResource(uint64(l))
+		}
 	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
@@ -1216,21 +2086,20 @@ func (m *Header) Size() (n int) {
 	return n
 }
 
-func (m *GVK) Size() (n int) {
+func (m *ExportResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.Group)
+	l = len(m.Snapshot)
 	if l > 0 {
 		n += 1 + l + sovResource(uint64(l))
 	}
-	l = len(m.Version)
-	if l > 0 {
-		n += 1 + l + sovResource(uint64(l))
+	if m.ErrorCode != 0 {
+		n += 1 + sovResource(uint64(m.ErrorCode))
 	}
-	l = len(m.Kind)
+	l = len(m.ErrorMessage)
 	if l > 0 {
 		n += 1 + l + sovResource(uint64(l))
 	}
@@ -1240,19 +2109,18 @@ func (m *GVK) Size() (n int) {
 	return n
 }
 
-func (m *NSN) Size() (n int) {
+func (m *ImportRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.Namespace)
+	l = len(m.Snapshot)
 	if l > 0 {
 		n += 1 + l + sovResource(uint64(l))
 	}
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovResource(uint64(l))
+	if m.DryRun {
+		n += 2
 	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
@@ -1260,11 +2128,157 @@ func (m *NSN) Size() (n int) {
 	return n
 }
 
-func sovResource(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozResource(x uint64) (n int) {
-	return sovResource(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+func (m *ImportResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Conflicts) > 0 {
+		for _, s := range m.Conflicts {
+			l = len(s)
+			n += 1 + l + sovResource(uint64(l))
+		}
+	}
+	if m.Applied {
+		n += 2
+	}
+	if m.ErrorCode != 0 {
+		n += 1 + sovResource(uint64(m.ErrorCode))
+	}
+	l = len(m.ErrorMessage)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TreeRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovResource(uint64(l))
+	}
+	l = len(m.Root)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.Depth != 0 {
+		n += 1 + sovResource(uint64(m.Depth))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TreeResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Tree)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.ErrorCode != 0 {
+		n += 1 + sovResource(uint64(m.ErrorCode))
+	}
+	l = len(m.ErrorMessage)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Header) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Gvk != nil {
+		l = m.Gvk.Size()
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.Nsn != nil {
+		l = m.Nsn.Size()
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.OwnerGvk != nil {
+		l = m.OwnerGvk.Size()
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.OwnerNsn != nil {
+		l = m.OwnerNsn.Size()
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GVK) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Group)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	l = len(m.Kind)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *NSN) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovResource(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovResource(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozResource(x uint64) (n int) {
+	return sovResource(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
 func (m *Instance) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
@@ -1353,7 +2367,879 @@ func (m *Instance) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ClaimRequest) Unmarshal(dAtA []byte) error {
+func (m *ClaimRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowResource
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClaimRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClaimRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &Header{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Spec = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiryTime", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExpiryTime = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DryRun", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DryRun = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipResource(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthResource
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EmptyResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowResource
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EmptyResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EmptyResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCode", wireType)
+			}
+			m.ErrorCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCode |= ErrorCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipResource(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthResource
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClaimResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowResource
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClaimResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClaimResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &Header{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Spec = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Status = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
+			}
+			m.StatusCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StatusCode |= StatusCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiryTime", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExpiryTime = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCode", wireType)
+			}
+			m.ErrorCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCode |= ErrorCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipResource(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthResource
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WatchResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowResource
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WatchResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WatchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &Header{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
+			}
+			m.StatusCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StatusCode |= StatusCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipResource(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthResource
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WatchRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowResource
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WatchRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WatchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &Header{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipResource(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthResource
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExportRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowResource
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExportRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExportRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupVersions", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GroupVersions = append(m.GroupVersions, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipResource(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthResource
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExportResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1376,17 +3262,17 @@ func (m *ClaimRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ClaimRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: ExportResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ClaimRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ExportResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Snapshot", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowResource
@@ -1396,33 +3282,29 @@ func (m *ClaimRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthResource
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthResource
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Header == nil {
-				m.Header = &Header{}
-			}
-			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Snapshot = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCode", wireType)
 			}
-			var stringLen uint64
+			m.ErrorCode = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowResource
@@ -1432,27 +3314,14 @@ func (m *ClaimRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.ErrorCode |= ErrorCode(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthResource
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthResource
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Spec = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExpiryTime", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1480,7 +3349,7 @@ func (m *ClaimRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExpiryTime = string(dAtA[iNdEx:postIndex])
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1504,7 +3373,7 @@ func (m *ClaimRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *EmptyResponse) Unmarshal(dAtA []byte) error {
+func (m *ImportRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1527,12 +3396,64 @@ func (m *EmptyResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: EmptyResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ImportRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: EmptyResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ImportRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Snapshot", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Snapshot = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DryRun", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DryRun = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipResource(dAtA[iNdEx:])
@@ -1555,7 +3476,7 @@ func (m *EmptyResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ClaimResponse) Unmarshal(dAtA []byte) error {
+func (m *ImportResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1578,51 +3499,15 @@ func (m *ClaimResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ClaimResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ImportResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ClaimResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ImportResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowResource
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthResource
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthResource
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Header == nil {
-				m.Header = &Header{}
-			}
-			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Conflicts", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1650,13 +3535,13 @@ func (m *ClaimResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Spec = string(dAtA[iNdEx:postIndex])
+			m.Conflicts = append(m.Conflicts, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Applied", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowResource
@@ -1666,29 +3551,17 @@ func (m *ClaimResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthResource
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthResource
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Status = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 4:
+			m.Applied = bool(v != 0)
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCode", wireType)
 			}
-			m.StatusCode = 0
+			m.ErrorCode = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowResource
@@ -1698,14 +3571,14 @@ func (m *ClaimResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StatusCode |= StatusCode(b&0x7F) << shift
+				m.ErrorCode |= ErrorCode(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExpiryTime", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1733,7 +3606,7 @@ func (m *ClaimResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExpiryTime = string(dAtA[iNdEx:postIndex])
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1757,7 +3630,7 @@ func (m *ClaimResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *WatchResponse) Unmarshal(dAtA []byte) error {
+func (m *TreeRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1780,10 +3653,10 @@ func (m *WatchResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WatchResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TreeRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WatchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TreeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1823,10 +3696,42 @@ func (m *WatchResponse) Unmarshal(dAtA []byte) error {
 			}
 			iNdEx = postIndex
 		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Root", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Root = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Depth", wireType)
 			}
-			m.StatusCode = 0
+			m.Depth = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowResource
@@ -1836,7 +3741,7 @@ func (m *WatchResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StatusCode |= StatusCode(b&0x7F) << shift
+				m.Depth |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1863,7 +3768,7 @@ func (m *WatchResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *WatchRequest) Unmarshal(dAtA []byte) error {
+func (m *TreeResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1886,17 +3791,17 @@ func (m *WatchRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: WatchRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TreeResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: WatchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TreeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Tree", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowResource
@@ -1906,27 +3811,74 @@ func (m *WatchRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthResource
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthResource
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Header == nil {
-				m.Header = &Header{}
+			m.Tree = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorCode", wireType)
 			}
-			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.ErrorCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ErrorCode |= ErrorCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ErrorMessage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResource
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthResource
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthResource
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			m.ErrorMessage = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -30,6 +30,11 @@ type ResourceClient interface {
 	Claim(ctx context.Context, in *ClaimRequest, opts ...grpc.CallOption) (*ClaimResponse, error)
 	DeleteClaim(ctx context.Context, in *ClaimRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
 	WatchClaim(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Resource_WatchClaimClient, error)
+	// snapshot of the indices and claims of the resource backend
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+	Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportResponse, error)
+	// tree of the entries of an index, for the backends with nested entries
+	GetTree(ctx context.Context, in *TreeRequest, opts ...grpc.CallOption) (*TreeResponse, error)
 }
 
 type resourceClient struct {
@@ -117,6 +122,33 @@ func (x *resourceWatchClaimClient) Recv() (*WatchResponse, error) {
 	return m, nil
 }
 
+func (c *resourceClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	out := new(ExportResponse)
+	err := c.cc.Invoke(ctx, "/resource.Resource/Export", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceClient) Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportResponse, error) {
+	out := new(ImportResponse)
+	err := c.cc.Invoke(ctx, "/resource.Resource/Import", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resourceClient) GetTree(ctx context.Context, in *TreeRequest, opts ...grpc.CallOption) (*TreeResponse, error) {
+	out := new(TreeResponse)
+	err := c.cc.Invoke(ctx, "/resource.Resource/GetTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ResourceServer is the server API for Resource service.
 // All implementations must embed UnimplementedResourceServer
 // for forward compatibility
@@ -129,6 +161,11 @@ type ResourceServer interface {
 	Claim(context.Context, *ClaimRequest) (*ClaimResponse, error)
 	DeleteClaim(context.Context, *ClaimRequest) (*EmptyResponse, error)
 	WatchClaim(*WatchRequest, Resource_WatchClaimServer) error
+	// snapshot of the indices and claims of the resource backend
+	Export(context.Context, *ExportRequest) (*ExportResponse, error)
+	Import(context.Context, *ImportRequest) (*ImportResponse, error)
+	// tree of the entries of an index, for the backends with nested entries
+	GetTree(context.Context, *TreeRequest) (*TreeResponse, error)
 	mustEmbedUnimplementedResourceServer()
 }
 
@@ -154,6 +191,15 @@ func (UnimplementedResourceServer) DeleteClaim(context.Context, *ClaimRequest) (
 func (UnimplementedResourceServer) WatchClaim(*WatchRequest, Resource_WatchClaimServer) error {
 	return status.Errorf(codes.Unimplemented, "method WatchClaim not implemented")
 }
+func (UnimplementedResourceServer) Export(context.Context, *ExportRequest) (*ExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedResourceServer) Import(context.Context, *ImportRequest) (*ImportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (UnimplementedResourceServer) GetTree(context.Context, *TreeRequest) (*TreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTree not implemented")
+}
 func (UnimplementedResourceServer) mustEmbedUnimplementedResourceServer() {}
 
 // UnsafeResourceServer may be embedded to opt out of forward compatibility for this service.
@@ -278,6 +324,60 @@ func (x *resourceWatchClaimServer) Send(m *WatchResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Resource_Export_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServer).Export(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/resource.Resource/Export",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServer).Export(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Resource_Import_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServer).Import(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/resource.Resource/Import",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServer).Import(ctx, req.(*ImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Resource_GetTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceServer).GetTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/resource.Resource/GetTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceServer).GetTree(ctx, req.(*TreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Resource_ServiceDesc is the grpc.ServiceDesc for Resource service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +405,18 @@ var Resource_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteClaim",
 			Handler:    _Resource_DeleteClaim_Handler,
 		},
+		{
+			MethodName: "Export",
+			Handler:    _Resource_Export_Handler,
+		},
+		{
+			MethodName: "Import",
+			Handler:    _Resource_Import_Handler,
+		},
+		{
+			MethodName: "GetTree",
+			Handler:    _Resource_GetTree_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -17,6 +17,7 @@ limitations under the License.
 package clientproxy
 
 import (
+	"container/list"
 	"context"
 	"sync"
 	"time"
@@ -28,16 +29,39 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+const (
+	// defaultCacheSize is the max amount of claim responses held in the cache
+	defaultCacheSize = 10000
+)
+
 type Cache interface {
+	// Get returns the cached claim response of the key, nil if not found
 	Get(ObjectKindKey) *resourcepb.ClaimResponse
-	Add(ObjectKindKey, *resourcepb.ClaimResponse)
+	// Add adds the claim response to the cache if the cache was not invalidated
+	// since the generation was retrieved, the least recently used entry is
+	// evicted when the cache is full
+	Add(ObjectKindKey, *resourcepb.ClaimResponse, uint64)
+	// Delete invalidates the cached claim response of the key
 	Delete(ObjectKindKey)
+	// Flush invalidates all cached claim responses
+	Flush()
+	// List returns all cached claim responses
+	List() map[ObjectKindKey]*resourcepb.ClaimResponse
+	// Generation returns the current generation of the cache, which changes
+	// every time the cache is invalidated
+	Generation() uint64
 	ValidateExpiryTime(context.Context) map[ObjectKindKey]*resourcepb.ClaimResponse
 }
 
-func NewCache() Cache {
+func NewCache(name string, size int) Cache {
+	if size <= 0 {
+		size = defaultCacheSize
+	}
 	return &cache{
-		c: map[ObjectKindKey]*resourcepb.ClaimResponse{},
+		name: name,
+		size: size,
+		c:    map[ObjectKindKey]*list.Element{},
+		lru:  list.New(),
 	}
 }
 
@@ -47,31 +71,90 @@ type ObjectKindKey struct {
 	nsn types.NamespacedName
 }
 
+type cacheEntry struct {
+	key      ObjectKindKey
+	claimRsp *resourcepb.ClaimResponse
+}
+
 type cache struct {
-	m sync.RWMutex
-	c map[ObjectKindKey]*resourcepb.ClaimResponse
-	l logr.Logger
+	m    sync.Mutex
+	name string
+	size int
+	c    map[ObjectKindKey]*list.Element
+	// lru holds the entries ordered by usage, the front is the most recently used
+	lru *list.List
+	// gen is incremented on every invalidation
+	gen uint64
+	l   logr.Logger
 }
 
 func (r *cache) Get(key ObjectKindKey) *resourcepb.ClaimResponse {
-	r.m.RLock()
-	defer r.m.RUnlock()
-	if claimRsp, ok := r.c[key]; ok {
-		return claimRsp
+	r.m.Lock()
+	defer r.m.Unlock()
+	if e, ok := r.c[key]; ok {
+		r.lru.MoveToFront(e)
+		return e.Value.(*cacheEntry).claimRsp
 	}
 	return nil
 }
 
-func (r *cache) Add(key ObjectKindKey, claimRsp *resourcepb.ClaimResponse) {
+func (r *cache) Add(key ObjectKindKey, claimRsp *resourcepb.ClaimResponse, gen uint64) {
 	r.m.Lock()
 	defer r.m.Unlock()
-	r.c[key] = claimRsp
+	// the cache was invalidated while the response was retrieved, the response
+	// might be stale
+	if gen != r.gen {
+		return
+	}
+	if e, ok := r.c[key]; ok {
+		e.Value.(*cacheEntry).claimRsp = claimRsp
+		r.lru.MoveToFront(e)
+		return
+	}
+	r.c[key] = r.lru.PushFront(&cacheEntry{key: key, claimRsp: claimRsp})
+	for r.lru.Len() > r.size {
+		e := r.lru.Back()
+		r.lru.Remove(e)
+		delete(r.c, e.Value.(*cacheEntry).key)
+		cacheEvictions.WithLabelValues(r.name).Inc()
+	}
+	cacheEntries.WithLabelValues(r.name).Set(float64(r.lru.Len()))
 }
 
 func (r *cache) Delete(key ObjectKindKey) {
 	r.m.Lock()
 	defer r.m.Unlock()
-	delete(r.c, key)
+	r.gen++
+	if e, ok := r.c[key]; ok {
+		r.lru.Remove(e)
+		delete(r.c, key)
+	}
+	cacheEntries.WithLabelValues(r.name).Set(float64(r.lru.Len()))
+}
+
+func (r *cache) Flush() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.gen++
+	r.c = map[ObjectKindKey]*list.Element{}
+	r.lru.Init()
+	cacheEntries.WithLabelValues(r.name).Set(0)
+}
+
+func (r *cache) List() map[ObjectKindKey]*resourcepb.ClaimResponse {
+	r.m.Lock()
+	defer r.m.Unlock()
+	claims := make(map[ObjectKindKey]*resourcepb.ClaimResponse, len(r.c))
+	for key, e := range r.c {
+		claims[key] = e.Value.(*cacheEntry).claimRsp
+	}
+	return claims
+}
+
+func (r *cache) Generation() uint64 {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.gen
 }
 
 func getKey(claim *resourcepb.ClaimRequest) ObjectKindKey {
@@ -95,15 +178,23 @@ func isCacheDataValid(cacheResp *resourcepb.ClaimResponse, claim *resourcepb.Cla
 	if cacheResp.StatusCode != resourcepb.StatusCode_Valid {
 		return false
 	}
+	// an expired claim might be claimed by somebody else in the backend
+	if cacheResp.ExpiryTime != "never" {
+		t, err := time.Parse(time.RFC3339, cacheResp.ExpiryTime)
+		if err != nil || !time.Now().Before(t) {
+			return false
+		}
+	}
 	return true
 }
 
 func (r *cache) ValidateExpiryTime(ctx context.Context) map[ObjectKindKey]*resourcepb.ClaimResponse {
 	r.l = log.FromContext(ctx)
-	r.m.RLock()
-	defer r.m.RUnlock()
+	r.m.Lock()
+	defer r.m.Unlock()
 	claimsToRefresh := map[ObjectKindKey]*resourcepb.ClaimResponse{}
-	for key, resourcepbResp := range r.c {
+	for key, e := range r.c {
+		resourcepbResp := e.Value.(*cacheEntry).claimRsp
 		if resourcepbResp.ExpiryTime != "never" && resourcepbResp.StatusCode == resourcepb.StatusCode_Valid {
 			t, err := time.Parse(time.RFC3339, resourcepbResp.ExpiryTime)
 			if err != nil {
@@ -19,14 +19,19 @@ package clientproxy
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/meta"
 	"github.com/nokia/k8s-ipam/pkg/proto/resource"
 	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -46,6 +51,9 @@ type Proxy[T1, T2 client.Object] interface {
 	Claim(ctx context.Context, cr client.Object, d any) (T2, error)
 	// DeleteClaim deletes the claim
 	DeleteClaim(ctx context.Context, cr client.Object, d any) error
+	// Healthz returns an error if the backend is not reachable, it is used
+	// as a healthz checker of the manager
+	Healthz(req *http.Request) error
 }
 
 type Normalizefn func(o client.Object, d any) (*resourcepb.ClaimRequest, error)
@@ -56,16 +64,62 @@ type Config struct {
 	Group       string // Group of GVK for event handling
 	Normalizefn Normalizefn
 	ValidateFn  RefreshRespValidatorFn
+	// ClientID is the stable identity of the client towards the backend,
+	// it defaults to the hostname and the name of the proxy
+	ClientID string
+	// CacheSize is the max amount of claim responses held in the cache,
+	// defaults to 10000
+	CacheSize int
+	// DialTimeout is the timeout of a single connection attempt to the backend
+	DialTimeout time.Duration
+	// MaxMsgSize is the max size of a message sent to or received from the backend
+	MaxMsgSize int
+	// ReconnectBaseDelay is the delay after the first failed connection
+	// attempt, the delay is increased exponentially on every subsequent failure
+	ReconnectBaseDelay time.Duration
+	// ReconnectMaxDelay caps the delay between connection attempts
+	ReconnectMaxDelay time.Duration
+	// HealthService is the grpc health service of the backend
+	HealthService string
+	// TLS connects to the backend with tls, the token file requires tls
+	TLS bool
+	// TLSCA is the ca that verifies the certificate of the backend, the
+	// system roots are used when empty
+	TLSCA string
+	// TokenFile is the file with the bearer token that authenticates the proxy
+	TokenFile string
+	// Client lists the owners of the claims when the claims are resynced,
+	// without a client only the cached claims are resynced
+	Client client.Reader
 }
 
 func New[T1, T2 client.Object](ctx context.Context, cfg Config) Proxy[T1, T2] {
 	l := ctrl.Log.WithName(cfg.Name)
 
+	clientID := cfg.ClientID
+	if clientID == "" {
+		hostname, _ := os.Hostname()
+		clientID = hostname + "/" + cfg.Name
+	}
+
 	cp := &clientproxy[T1, T2]{
-		address:     cfg.Address,
+		name:     cfg.Name,
+		clientID: clientID,
+		clientConfig: &resource.Config{
+			Address:            cfg.Address,
+			Insecure:           !cfg.TLS,
+			TLSCA:              cfg.TLSCA,
+			MaxMsgSize:         cfg.MaxMsgSize,
+			DialTimeout:        cfg.DialTimeout,
+			ReconnectBaseDelay: cfg.ReconnectBaseDelay,
+			ReconnectMaxDelay:  cfg.ReconnectMaxDelay,
+			HealthService:      cfg.HealthService,
+			TokenFile:          cfg.TokenFile,
+		},
 		normalizeFn: cfg.Normalizefn,
+		client:      cfg.Client,
 		informer:    NewNopInformer(),
-		cache:       NewCache(),
+		cache:       NewCache(cfg.Name, cfg.CacheSize),
 		validator:   NewResponseValidator(),
 		l:           l,
 	}
@@ -77,13 +131,26 @@ func New[T1, T2 client.Object](ctx context.Context, cfg Config) Proxy[T1, T2] {
 }
 
 type clientproxy[T1, T2 client.Object] struct {
-	// adress for the server
-	address string
+	// name of the proxy
+	name string
+	// clientID is the stable identity of the client used by the watches
+	clientID string
+	// config of the client to the backend server
+	clientConfig *resource.Config
 	// client
 	m              sync.RWMutex
 	resourceClient resource.Client
-	// watch channel for the watch
+	// watchCtx controls the lifecyle of the watches
+	watchCtx    context.Context
 	watchCancel context.CancelFunc
+	// resyncClaims holds the claims that were invalidated in the cache and
+	// need to be resynced with the backend, resyncPending ensures the claims
+	// are resynced once per invalidation
+	resyncM       sync.Mutex
+	resyncClaims  map[ObjectKindKey]*resourcepb.ClaimResponse
+	resyncPending bool
+	// client lists the owners of the claims
+	client client.Reader
 	// normalizes the specific resource to the resourcePB GRPC message
 	normalizeFn Normalizefn
 	// this is the cache with GVK namespace, name
@@ -96,15 +163,18 @@ type clientproxy[T1, T2 client.Object] struct {
 	l logr.Logger
 }
 
-// AddEventChs add the ownerGvk's event channels to the informer
+// AddEventChs add the ownerGvk's event channels to the informer and starts
+// watching the backend for changes of the claims of the ownerGvks
 func (r *clientproxy[T1, T2]) AddEventChs(eventChannels map[schema.GroupVersionKind]chan event.GenericEvent) {
 	r.informer = NewInformer(eventChannels)
+	r.startWatches(r.watchCtx)
 }
 
 func (r *clientproxy[T1, T2]) start(ctx context.Context) {
 	r.l.Info("starting...")
 	// this is used to control the watch go routine
 	watchCtx, cancel := context.WithCancel(ctx)
+	r.watchCtx = watchCtx
 	r.watchCancel = cancel
 
 	// the client connection would always be connected
@@ -124,67 +194,77 @@ func (r *clientproxy[T1, T2]) start(ctx context.Context) {
 				// cache refresh handler
 				// walks the cache and check the expiry time
 				keysToRefresh := r.cache.ValidateExpiryTime(ctx)
-				var wg sync.WaitGroup
-				for objKey, resourcepbResp := range keysToRefresh {
-					r.l.Info("refresh claim", "gvk", objKey.gvk, "nsn", objKey.nsn)
-					wg.Add(1)
-					t := time.Now().Add(time.Minute * 60)
-					b, err := t.MarshalText()
-					if err != nil {
-						r.l.Error(err, "cannot marshal the time during refresh")
-					}
-					req := &resourcepb.ClaimRequest{
-						Header:     resourcepbResp.GetHeader(),
-						Spec:       resourcepbResp.GetSpec(),
-						ExpiryTime: string(b)}
-
-					ownerGvk := schema.GroupVersionKind{
-						Group:   resourcepbResp.GetHeader().GetOwnerGvk().GetGroup(),
-						Version: resourcepbResp.GetHeader().GetOwnerGvk().GetVersion(),
-						Kind:    resourcepbResp.GetHeader().GetOwnerGvk().GetKind(),
-					}
-					ownerNsn := types.NamespacedName{
-						Namespace: resourcepbResp.GetHeader().GetOwnerNsn().GetNamespace(),
-						Name:      resourcepbResp.GetHeader().GetOwnerNsn().GetName(),
-					}
-					group := resourcepbResp.GetHeader().GetGvk().GetGroup()
-					origresp := resourcepbResp
-					objKey := objKey
-
-					go func() {
-						defer wg.Done()
-
-						// refresh the claim
-						resp, err := r.refreshClaim(ctx, req)
-						if err != nil {
-							// if we get an error in the response, log it and inform the client
-							r.l.Error(err, "refresh claim failed")
-							// remove the cache entry
-							r.cache.Delete(objKey)
-							r.informer.NotifyClient(ownerGvk, ownerNsn)
-						}
-						// TBD if we need more protection
-						if resp != nil {
-							r.l.Info("refresh resp", "resp", resp.Status)
-							// Validate the response through the client proxy registered validator
-							// if the validator is not happy with the response we notify the client
-							if r.validator.Get(group) != nil {
-								if !r.validator.Get(group)(origresp, resp) {
-									r.l.Error(err, "refresh validation NOK")
-									// remove the cache entry
-									r.cache.Delete(objKey)
-									r.informer.NotifyClient(ownerGvk, ownerNsn)
-								}
-							}
-						}
-					}()
+				t := time.Now().Add(time.Minute * 60)
+				b, err := t.MarshalText()
+				if err != nil {
+					r.l.Error(err, "cannot marshal the time during refresh")
 				}
-				wg.Wait()
+				r.refreshClaims(ctx, keysToRefresh, string(b))
 			}
 		}
 	}()
 }
 
+// refreshClaims refreshes the claims in the backend, the owner of a claim is
+// notified when the refresh fails or the claimed resource changed. An empty
+// expiryTime keeps the expiryTime of the claim.
+func (r *clientproxy[T1, T2]) refreshClaims(ctx context.Context, claims map[ObjectKindKey]*resourcepb.ClaimResponse, expiryTime string) {
+	var wg sync.WaitGroup
+	for objKey, resourcepbResp := range claims {
+		r.l.Info("refresh claim", "gvk", objKey.gvk, "nsn", objKey.nsn)
+		wg.Add(1)
+		req := &resourcepb.ClaimRequest{
+			Header:     resourcepbResp.GetHeader(),
+			Spec:       resourcepbResp.GetSpec(),
+			ExpiryTime: expiryTime,
+		}
+		if expiryTime == "" {
+			req.ExpiryTime = resourcepbResp.GetExpiryTime()
+		}
+		ownerGvk := schema.GroupVersionKind{
+			Group:   resourcepbResp.GetHeader().GetOwnerGvk().GetGroup(),
+			Version: resourcepbResp.GetHeader().GetOwnerGvk().GetVersion(),
+			Kind:    resourcepbResp.GetHeader().GetOwnerGvk().GetKind(),
+		}
+		ownerNsn := types.NamespacedName{
+			Namespace: resourcepbResp.GetHeader().GetOwnerNsn().GetNamespace(),
+			Name:      resourcepbResp.GetHeader().GetOwnerNsn().GetName(),
+		}
+		group := resourcepbResp.GetHeader().GetGvk().GetGroup()
+		origresp := resourcepbResp
+		objKey := objKey
+
+		go func() {
+			defer wg.Done()
+
+			// refresh the claim
+			resp, err := r.refreshClaim(ctx, req)
+			if err != nil {
+				// if we get an error in the response, log it and inform the client
+				r.l.Error(err, "refresh claim failed")
+				// remove the cache entry
+				r.cache.Delete(objKey)
+				r.informer.NotifyClient(ownerGvk, ownerNsn)
+			}
+			// TBD if we need more protection
+			if resp != nil {
+				r.l.Info("refresh resp", "resp", resp.Status)
+				// Validate the response through the client proxy registered validator
+				// if the validator is not happy with the response we notify the client
+				if r.validator.Get(group) != nil {
+					if !r.validator.Get(group)(origresp, resp) {
+						r.l.Error(err, "refresh validation NOK")
+						// remove the cache entry
+						r.cache.Delete(objKey)
+						r.informer.NotifyClient(ownerGvk, ownerNsn)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func (r *clientproxy[T1, T2]) CreateIndex(ctx context.Context, cr T1) error {
 	b, err := json.Marshal(cr)
 	if err != nil {
@@ -232,9 +312,15 @@ func (r *clientproxy[T1, T2]) GetClaim(ctx context.Context, o client.Object, d a
 	}
 	// TBD if we need to use the cache here
 	resp, err := resourceClient.GetClaim(ctx, req)
-	if err != nil || resp.GetStatusCode() != resourcepb.StatusCode_Valid {
+	if err != nil {
+		return x, err
+	}
+	if err := getResponseError(resp.GetErrorCode(), resp.GetErrorMessage()); err != nil {
 		return x, err
 	}
+	if resp.GetStatusCode() != resourcepb.StatusCode_Valid {
+		return x, nil
+	}
 
 	if err := json.Unmarshal([]byte(resp.Status), &x); err != nil {
 		return x, err
@@ -253,8 +339,11 @@ func (r *clientproxy[T1, T2]) Claim(ctx context.Context, o client.Object, d any)
 	}
 	r.l.Info("claim resource", "resourcePbrequest", req)
 
+	ctx, span := tracing.Start(ctx, "clientproxy.Claim", tracing.ClaimAttributes(req.GetHeader())...)
+	defer span.End()
 	resp, err := r.claim(ctx, req, false)
 	if err != nil {
+		tracing.SetError(span, err)
 		return x, err
 	}
 	if err := json.Unmarshal([]byte(resp.Status), &x); err != nil {
@@ -271,9 +360,6 @@ func (r *clientproxy[T1, T2]) claim(ctx context.Context, claim *resourcepb.Claim
 		return nil, err
 	}
 
-	// TEST
-	refresh = false
-
 	key := getKey(claim)
 	if !refresh {
 		cacheData := r.cache.Get(key)
@@ -281,10 +367,17 @@ func (r *clientproxy[T1, T2]) claim(ctx context.Context, claim *resourcepb.Claim
 			// check if the data is available and consistent
 			if isCacheDataValid(cacheData, claim) {
 				r.l.Info("cache hit OK -> response from cache", "keyGVK", key.gvk, "keyNsn", key.nsn)
+				cacheHits.WithLabelValues(r.name).Inc()
+				trace.SpanFromContext(ctx).SetAttributes(tracing.CacheHitKey.Bool(true))
 				return cacheData, nil
 			}
 		}
+		cacheMisses.WithLabelValues(r.name).Inc()
+		trace.SpanFromContext(ctx).SetAttributes(tracing.CacheHitKey.Bool(false))
 	}
+	// the generation is retrieved before the claim such that a response is not
+	// cached when the cache got invalidated while the claim was in flight
+	gen := r.cache.Generation()
 	if refresh {
 		r.l.Info("cache hit NOK -> refresh from backend server", "keyGVK", key.gvk, "keyNsn", key.nsn)
 	} else {
@@ -293,13 +386,21 @@ func (r *clientproxy[T1, T2]) claim(ctx context.Context, claim *resourcepb.Claim
 	}
 
 	resp, err := resourceClient.Claim(ctx, claim)
-	if err != nil || resp.GetStatusCode() != resourcepb.StatusCode_Valid {
+	if err != nil {
+		r.cache.Delete(key)
 		return resp, err
 	}
+	if err := getResponseError(resp.GetErrorCode(), resp.GetErrorMessage()); err != nil {
+		r.cache.Delete(key)
+		return nil, err
+	}
+	if resp.GetStatusCode() != resourcepb.StatusCode_Valid {
+		r.cache.Delete(key)
+		return resp, nil
+	}
 	// if the claim is successfull we add the entry in the cache
-	// TEST DISABLE CACHE
-	//r.cache.Add(key, resp)
-	return resp, err
+	r.cache.Add(key, resp, gen)
+	return resp, nil
 }
 
 func (r *clientproxy[T1, T2]) DeleteClaim(ctx context.Context, o client.Object, d any) error {
@@ -308,17 +409,27 @@ func (r *clientproxy[T1, T2]) DeleteClaim(ctx context.Context, o client.Object,
 	if err != nil {
 		return err
 	}
+	// invalidate the cache before the claim is deleted such that a claim that
+	// is in flight does not add the deleted claim to the cache
+	r.cache.Delete(getKey(req))
 	resourceClient, err := r.getClient()
 	if err != nil {
 		return err
 	}
-	_, err = resourceClient.DeleteClaim(ctx, req)
+	resp, err := resourceClient.DeleteClaim(ctx, req)
 	if err != nil {
 		return err
 	}
-	// delete the cache only if the Delete Claim is successfull
-	r.cache.Delete(getKey(req))
-	return nil
+	return getResponseError(resp.GetErrorCode(), resp.GetErrorMessage())
+}
+
+// getResponseError returns the backend error of a response, nil if the
+// response has no error
+func getResponseError(code resourcepb.ErrorCode, msg string) error {
+	if code == resourcepb.ErrorCode_NoError {
+		return nil
+	}
+	return backend.NewError(code, "%s", msg)
 }
 
 func BuildResourcePb(o client.Object, nsnName, specBody, expiryTime string, gvk schema.GroupVersionKind) *resourcepb.ClaimRequest {
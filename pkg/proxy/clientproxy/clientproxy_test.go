@@ -0,0 +1,381 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientproxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/proto/resource"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// mockBackend allocates the prefix of a claim, the allocation of a claim
+// can be changed to simulate a change in the backend
+type mockBackend struct {
+	resourcepb.ResourceClient
+	claims int
+	// allocations holds the allocation per claim name
+	allocations map[string]string
+	// errorCode is returned in the claim response
+	errorCode resourcepb.ErrorCode
+	// inFlight is called while the claim is in flight
+	inFlight func()
+	// disconnected simulates a lost connection to the backend
+	disconnected bool
+}
+
+func (r *mockBackend) Get() resourcepb.ResourceClient { return r }
+
+func (r *mockBackend) Delete() error { return nil }
+
+func (r *mockBackend) IsReady() bool { return !r.disconnected }
+
+func (r *mockBackend) OnReconnect(fn func()) {}
+
+func (r *mockBackend) Claim(ctx context.Context, in *resourcepb.ClaimRequest, opts ...grpc.CallOption) (*resourcepb.ClaimResponse, error) {
+	r.claims++
+	if r.inFlight != nil {
+		r.inFlight()
+	}
+	if r.errorCode != resourcepb.ErrorCode_NoError {
+		return &resourcepb.ClaimResponse{Header: in.Header, Spec: in.Spec, StatusCode: resourcepb.StatusCode_InValid, ErrorCode: r.errorCode, ErrorMessage: "failed"}, nil
+	}
+	return &resourcepb.ClaimResponse{
+		Header:     in.Header,
+		Spec:       in.Spec,
+		Status:     r.allocations[in.Header.Nsn.Name],
+		StatusCode: resourcepb.StatusCode_Valid,
+		ExpiryTime: in.ExpiryTime,
+	}, nil
+}
+
+func (r *mockBackend) DeleteClaim(ctx context.Context, in *resourcepb.ClaimRequest, opts ...grpc.CallOption) (*resourcepb.EmptyResponse, error) {
+	delete(r.allocations, in.Header.Nsn.Name)
+	return &resourcepb.EmptyResponse{}, nil
+}
+
+func newTestClientProxy(be *mockBackend, cacheSize int) *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim] {
+	return &clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim]{
+		name:           "test",
+		resourceClient: be,
+		cache:          NewCache("test", cacheSize),
+		informer:       NewNopInformer(),
+		validator:      NewResponseValidator(),
+		l:              logr.Discard(),
+	}
+}
+
+func newTestClaimRequest(name, spec, expiryTime string) *resourcepb.ClaimRequest {
+	return &resourcepb.ClaimRequest{
+		Header: &resourcepb.Header{
+			Gvk:      &resourcepb.GVK{Group: ipamv1alpha1.GroupVersion.Group, Version: ipamv1alpha1.GroupVersion.Version, Kind: ipamv1alpha1.IPClaimKind},
+			Nsn:      &resourcepb.NSN{Namespace: "default", Name: name},
+			OwnerGvk: &resourcepb.GVK{Group: ipamv1alpha1.GroupVersion.Group, Version: ipamv1alpha1.GroupVersion.Version, Kind: ipamv1alpha1.IPClaimKind},
+			OwnerNsn: &resourcepb.NSN{Namespace: "default", Name: name},
+		},
+		Spec:       spec,
+		ExpiryTime: expiryTime,
+	}
+}
+
+func TestClaimCache(t *testing.T) {
+	expired := time.Now().Add(-time.Minute).Format(time.RFC3339)
+
+	cases := map[string]struct {
+		// run changes the backend or the cache after the first claim
+		run        func(cp *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim], be *mockBackend)
+		expiryTime string
+		spec       string
+		wantClaims int
+		wantStatus string
+	}{
+		"Hit": {
+			run:        func(cp *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim], be *mockBackend) {},
+			wantClaims: 1,
+			wantStatus: "10.0.0.1/24",
+		},
+		"SpecChanged": {
+			run:        func(cp *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim], be *mockBackend) {},
+			spec:       "spec2",
+			wantClaims: 2,
+			wantStatus: "10.0.0.1/24",
+		},
+		"WatchInvalidation": {
+			run: func(cp *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim], be *mockBackend) {
+				be.allocations["a"] = "10.0.0.2/24"
+				cp.cache.Delete(getKey(newTestClaimRequest("a", "", "")))
+			},
+			wantClaims: 2,
+			wantStatus: "10.0.0.2/24",
+		},
+		"Reconnect": {
+			run: func(cp *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim], be *mockBackend) {
+				be.allocations["a"] = "10.0.0.2/24"
+				cp.cache.Flush()
+			},
+			wantClaims: 2,
+			wantStatus: "10.0.0.2/24",
+		},
+		"DeleteClaim": {
+			run: func(cp *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim], be *mockBackend) {
+				cp.normalizeFn = func(o client.Object, d any) (*resourcepb.ClaimRequest, error) {
+					return newTestClaimRequest("a", "spec", "never"), nil
+				}
+				if err := cp.DeleteClaim(context.Background(), &ipamv1alpha1.IPClaim{}, nil); err != nil {
+					t.Fatalf("cannot delete claim: %s", err.Error())
+				}
+				be.allocations["a"] = "10.0.0.3/24"
+			},
+			wantClaims: 2,
+			wantStatus: "10.0.0.3/24",
+		},
+		"Evicted": {
+			run: func(cp *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim], be *mockBackend) {
+				for _, name := range []string{"b", "c"} {
+					if _, err := cp.claim(context.Background(), newTestClaimRequest(name, "spec", "never"), false); err != nil {
+						t.Fatalf("cannot claim %s: %s", name, err.Error())
+					}
+				}
+			},
+			wantClaims: 4,
+			wantStatus: "10.0.0.1/24",
+		},
+		"Expired": {
+			run:        func(cp *clientproxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim], be *mockBackend) {},
+			expiryTime: expired,
+			wantClaims: 2,
+			wantStatus: "10.0.0.1/24",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			be := &mockBackend{allocations: map[string]string{"a": "10.0.0.1/24", "b": "10.0.1.1/24", "c": "10.0.2.1/24"}}
+			cp := newTestClientProxy(be, 2)
+
+			expiryTime := "never"
+			if tc.expiryTime != "" {
+				expiryTime = tc.expiryTime
+			}
+			if _, err := cp.claim(context.Background(), newTestClaimRequest("a", "spec", expiryTime), false); err != nil {
+				t.Fatalf("cannot claim: %s", err.Error())
+			}
+			tc.run(cp, be)
+
+			spec := "spec"
+			if tc.spec != "" {
+				spec = tc.spec
+			}
+			resp, err := cp.claim(context.Background(), newTestClaimRequest("a", spec, expiryTime), false)
+			if err != nil {
+				t.Fatalf("cannot claim: %s", err.Error())
+			}
+			if resp.Status != tc.wantStatus {
+				t.Errorf("status: want %s, got %s", tc.wantStatus, resp.Status)
+			}
+			if be.claims != tc.wantClaims {
+				t.Errorf("backend claims: want %d, got %d", tc.wantClaims, be.claims)
+			}
+		})
+	}
+}
+
+// TestClaimCacheInFlightInvalidation validates that a response is not cached
+// when the cache got invalidated while the claim was in flight
+func TestClaimCacheInFlightInvalidation(t *testing.T) {
+	be := &mockBackend{allocations: map[string]string{"a": "10.0.0.1/24"}}
+	cp := newTestClientProxy(be, 10)
+	be.inFlight = func() {
+		cp.cache.Delete(getKey(newTestClaimRequest("a", "", "")))
+		be.inFlight = nil
+	}
+	if _, err := cp.claim(context.Background(), newTestClaimRequest("a", "spec", "never"), false); err != nil {
+		t.Fatalf("cannot claim: %s", err.Error())
+	}
+	be.allocations["a"] = "10.0.0.2/24"
+	resp, err := cp.claim(context.Background(), newTestClaimRequest("a", "spec", "never"), false)
+	if err != nil {
+		t.Fatalf("cannot claim: %s", err.Error())
+	}
+	if resp.Status != "10.0.0.2/24" {
+		t.Errorf("stale allocation returned: want 10.0.0.2/24, got %s", resp.Status)
+	}
+}
+
+// TestClaimCacheError validates that a failed claim removes the cached response
+func TestClaimCacheError(t *testing.T) {
+	be := &mockBackend{allocations: map[string]string{"a": "10.0.0.1/24"}}
+	cp := newTestClientProxy(be, 10)
+	if _, err := cp.claim(context.Background(), newTestClaimRequest("a", "spec", "never"), false); err != nil {
+		t.Fatalf("cannot claim: %s", err.Error())
+	}
+	// the refresh bypasses the cache and returns an error
+	be.errorCode = resourcepb.ErrorCode_Conflict
+	if _, err := cp.claim(context.Background(), newTestClaimRequest("a", "spec", "never"), true); err == nil {
+		t.Fatalf("expected an error")
+	}
+	be.errorCode = resourcepb.ErrorCode_NoError
+	be.allocations["a"] = "10.0.0.2/24"
+	resp, err := cp.claim(context.Background(), newTestClaimRequest("a", "spec", "never"), false)
+	if err != nil {
+		t.Fatalf("cannot claim: %s", err.Error())
+	}
+	if resp.Status != "10.0.0.2/24" {
+		t.Errorf("stale allocation returned: want 10.0.0.2/24, got %s", resp.Status)
+	}
+	if be.claims != 3 {
+		t.Errorf("backend claims: want 3, got %d", be.claims)
+	}
+}
+
+func TestCacheSize(t *testing.T) {
+	c := NewCache("test", 2)
+	for i := 0; i < 3; i++ {
+		req := newTestClaimRequest(fmt.Sprintf("claim%d", i), "spec", "never")
+		c.Add(getKey(req), &resourcepb.ClaimResponse{Header: req.Header}, c.Generation())
+		// use the first entry such that the second is the least recently used
+		c.Get(getKey(newTestClaimRequest("claim0", "", "")))
+	}
+	for i, want := range []bool{true, false, true} {
+		if got := c.Get(getKey(newTestClaimRequest(fmt.Sprintf("claim%d", i), "", ""))) != nil; got != want {
+			t.Errorf("claim%d cached: want %t, got %t", i, want, got)
+		}
+	}
+}
+
+// TestResync validates that the claims are refreshed after the connection to
+// the backend was lost and that the owners of changed claims are notified
+func TestResync(t *testing.T) {
+	be := &mockBackend{allocations: map[string]string{"a": "10.0.0.1/24", "b": "10.0.1.1/24"}}
+	cp := newTestClientProxy(be, 10)
+	cp.validator.Add(ipamv1alpha1.GroupVersion.Group, func(origResp, newResp *resourcepb.ClaimResponse) bool {
+		return origResp.Status == newResp.Status
+	})
+	ch := make(chan event.GenericEvent, 2)
+	cp.informer = NewInformer(map[schema.GroupVersionKind]chan event.GenericEvent{ipamv1alpha1.IPClaimGroupVersionKind: ch})
+
+	for _, name := range []string{"a", "b"} {
+		if _, err := cp.claim(context.Background(), newTestClaimRequest(name, "spec", "never"), false); err != nil {
+			t.Fatalf("cannot claim %s: %s", name, err.Error())
+		}
+	}
+	// the watch stream fails and the backend changes the allocation of a
+	cp.invalidateCache()
+	be.allocations["a"] = "10.0.0.2/24"
+	cp.resync(context.Background())
+
+	if be.claims != 4 {
+		t.Errorf("backend claims: want 4, got %d", be.claims)
+	}
+	select {
+	case e := <-ch:
+		if e.Object.GetName() != "a" {
+			t.Errorf("notified owner: want a, got %s", e.Object.GetName())
+		}
+	default:
+		t.Errorf("owner of the changed claim is not notified")
+	}
+	if len(ch) != 0 {
+		t.Errorf("owner of an unchanged claim is notified")
+	}
+	// the resynced claim of b is served from the cache
+	resp, err := cp.claim(context.Background(), newTestClaimRequest("b", "spec", "never"), false)
+	if err != nil {
+		t.Fatalf("cannot claim: %s", err.Error())
+	}
+	if resp.Status != "10.0.1.1/24" || be.claims != 4 {
+		t.Errorf("resynced claim not cached, status %s, backend claims %d", resp.Status, be.claims)
+	}
+}
+
+// TestResyncOnce validates that the claims are resynced once per invalidation
+// although the resync is triggered by the reconnect and by the watches
+func TestResyncOnce(t *testing.T) {
+	be := &mockBackend{allocations: map[string]string{"a": "10.0.0.1/24"}}
+	cp := newTestClientProxy(be, 10)
+	if _, err := cp.claim(context.Background(), newTestClaimRequest("a", "spec", "never"), false); err != nil {
+		t.Fatalf("cannot claim: %s", err.Error())
+	}
+	cp.invalidateCache()
+	cp.resync(context.Background())
+	cp.resync(context.Background())
+	if be.claims != 2 {
+		t.Errorf("backend claims: want 2, got %d", be.claims)
+	}
+}
+
+// TestResyncOwners validates that all owners are notified on a resync, including
+// the owners whose claims were not cached
+func TestResyncOwners(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := ipamv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	objs := []client.Object{}
+	for _, name := range []string{"a", "b"} {
+		objs = append(objs, ipamv1alpha1.BuildIPClaim(metav1.ObjectMeta{Namespace: "default", Name: name},
+			ipamv1alpha1.IPClaimSpec{}, ipamv1alpha1.IPClaimStatus{}))
+	}
+	be := &mockBackend{allocations: map[string]string{"a": "10.0.0.1/24"}}
+	cp := newTestClientProxy(be, 10)
+	cp.client = fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+	ch := make(chan event.GenericEvent, 2)
+	cp.informer = NewInformer(map[schema.GroupVersionKind]chan event.GenericEvent{ipamv1alpha1.IPClaimGroupVersionKind: ch})
+
+	if _, err := cp.claim(context.Background(), newTestClaimRequest("a", "spec", "never"), false); err != nil {
+		t.Fatalf("cannot claim: %s", err.Error())
+	}
+	cp.invalidateCache()
+	cp.resync(context.Background())
+
+	notified := map[string]bool{}
+	for len(ch) > 0 {
+		notified[(<-ch).Object.GetName()] = true
+	}
+	if !notified["a"] || !notified["b"] {
+		t.Errorf("want owners a and b notified, got %v", notified)
+	}
+	// the owners reclaim, the proxy does not refresh the claims itself
+	if be.claims != 1 {
+		t.Errorf("backend claims: want 1, got %d", be.claims)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	be := &mockBackend{}
+	cp := newTestClientProxy(be, 10)
+	cp.clientConfig = &resource.Config{Address: "backend:9999"}
+	if err := cp.Healthz(nil); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	be.disconnected = true
+	if err := cp.Healthz(nil); err == nil {
+		t.Errorf("expected an error when the backend is disconnected")
+	}
+}
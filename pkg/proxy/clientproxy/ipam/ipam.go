@@ -36,14 +36,12 @@ import (
 )
 
 func New(ctx context.Context, cfg clientproxy.Config) clientproxy.Proxy[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim] {
-	return clientproxy.New[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim](
-		ctx, clientproxy.Config{
-			Address:     cfg.Address,
-			Name:        "ipam-client-proxy",
-			Group:       ipamv1alpha1.GroupVersion.Group, // Group of GVK for event handling
-			Normalizefn: NormalizeKRMToResourcePb,
-			ValidateFn:  ValidateResponse,
-		})
+	cfg.Name = "ipam-client-proxy"
+	cfg.Group = ipamv1alpha1.GroupVersion.Group // Group of GVK for event handling
+	cfg.HealthService = ipamv1alpha1.GroupVersion.String()
+	cfg.Normalizefn = NormalizeKRMToResourcePb
+	cfg.ValidateFn = ValidateResponse
+	return clientproxy.New[*ipamv1alpha1.NetworkInstance, *ipamv1alpha1.IPClaim](ctx, cfg)
 }
 
 // ValidateResponse handes validates changes in the claim response
@@ -19,6 +19,7 @@ package ipam
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/backend"
@@ -96,3 +97,5 @@ func (r *bemock) DeleteClaim(ctx context.Context, cr client.Object, d any) error
 	}
 	return r.be.DeleteClaim(ctx, b)
 }
+
+func (r *bemock) Healthz(req *http.Request) error { return nil }
@@ -19,6 +19,7 @@ package ipam
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
 
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
@@ -46,6 +47,7 @@ func (r *mock) Claim(ctx context.Context, cr client.Object, d any) (*ipamv1alpha
 	return r.getClaim(cr)
 }
 func (r *mock) DeleteClaim(ctx context.Context, cr client.Object, d any) error { return nil }
+func (r *mock) Healthz(req *http.Request) error                                { return nil }
 
 func (r *mock) getClaim(cr client.Object) (*ipamv1alpha1.IPClaim, error) {
 	claim, ok := cr.(*ipamv1alpha1.IPClaim)
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientproxy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	cacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clientproxy_cache_hits_total",
+		Help: "Number of claims served from the client proxy cache",
+	}, []string{"proxy"})
+	cacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clientproxy_cache_misses_total",
+		Help: "Number of claims sent to the backend since the client proxy cache had no valid response",
+	}, []string{"proxy"})
+	cacheEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clientproxy_cache_evictions_total",
+		Help: "Number of claim responses evicted from the client proxy cache since it was full",
+	}, []string{"proxy"})
+	cacheEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "clientproxy_cache_entries",
+		Help: "Number of claim responses in the client proxy cache",
+	}, []string{"proxy"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(cacheHits, cacheMisses, cacheEvictions, cacheEntries)
+}
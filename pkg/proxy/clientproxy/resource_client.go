@@ -51,18 +51,15 @@ func (r *clientproxy[T1, T2]) deleteClient(ctx context.Context) error {
 func (r *clientproxy[T1, T2]) createClient(ctx context.Context) error {
 	r.m.Lock()
 	defer r.m.Unlock()
-	r.l.Info("create client", "address", r.address)
-	ac, err := resource.New(&resource.Config{
-		Address:  r.address,
-		Insecure: true,
-	})
+	r.l.Info("create client", "address", r.clientConfig.Address)
+	ac, err := resource.New(r.clientConfig)
 	if err != nil {
 		r.l.Error(err, "cannot create client")
 		r.resourceClient = nil
 		return err
 	}
-
-	r.startWatches(ctx)
+	// claims might have changed while the client was disconnected
+	ac.OnReconnect(func() { r.resync(ctx) })
 
 	r.resourceClient = ac
 	return nil
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientproxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// invalidateCache flushes the cache when the claims might have changed in the
+// backend without being notified. The flushed claims are kept such that they
+// are resynced once the backend is reachable again.
+func (r *clientproxy[T1, T2]) invalidateCache() {
+	r.resyncM.Lock()
+	defer r.resyncM.Unlock()
+	r.resyncPending = true
+	if r.resyncClaims == nil {
+		r.resyncClaims = map[ObjectKindKey]*resourcepb.ClaimResponse{}
+	}
+	for key, claimRsp := range r.cache.List() {
+		r.resyncClaims[key] = claimRsp
+	}
+	r.cache.Flush()
+}
+
+// resync resyncs the claims once after the cache was invalidated, e.g. when
+// the connection to the backend was re-established. The owners are the source
+// of truth of the claims, when the proxy has a client all owners are notified
+// such that they reconcile their claims. Without a client the claims that
+// were cached before are refreshed and the owners are notified when a claim
+// changed.
+func (r *clientproxy[T1, T2]) resync(ctx context.Context) {
+	r.resyncM.Lock()
+	if !r.resyncPending {
+		// the claims were resynced since the cache was invalidated
+		r.resyncM.Unlock()
+		return
+	}
+	r.resyncPending = false
+	claims := r.resyncClaims
+	r.resyncClaims = nil
+	r.resyncM.Unlock()
+	// updates of the claims might have been missed
+	r.cache.Flush()
+
+	if r.client == nil {
+		r.l.Info("resync claims", "claims", len(claims))
+		r.refreshClaims(ctx, claims, "")
+		return
+	}
+	r.resyncOwners(ctx)
+}
+
+// resyncOwners notifies all owners of the owner kinds, the claims of the
+// owners that were not cached are resynced as well
+func (r *clientproxy[T1, T2]) resyncOwners(ctx context.Context) {
+	for _, gvk := range r.informer.GetGVK() {
+		owners := &metav1.PartialObjectMetadataList{}
+		owners.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.client.List(ctx, owners); err != nil {
+			r.l.Error(err, "cannot list owners", "gvk", gvk)
+			continue
+		}
+		r.l.Info("resync owners", "gvk", gvk, "owners", len(owners.Items))
+		for _, o := range owners.Items {
+			r.informer.NotifyClient(gvk, types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()})
+		}
+	}
+}
+
+// discardResync discards the claims that need to be resynced, since the missed
+// updates were replayed by the server
+func (r *clientproxy[T1, T2]) discardResync() {
+	r.resyncM.Lock()
+	defer r.resyncM.Unlock()
+	r.resyncPending = false
+	r.resyncClaims = nil
+}
+
+func (r *clientproxy[T1, T2]) Healthz(req *http.Request) error {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	if r.resourceClient == nil || !r.resourceClient.IsReady() {
+		return fmt.Errorf("backend server %s unreachable", r.clientConfig.Address)
+	}
+	return nil
+}
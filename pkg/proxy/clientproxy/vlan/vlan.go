@@ -31,14 +31,12 @@ import (
 )
 
 func New(ctx context.Context, cfg clientproxy.Config) clientproxy.Proxy[*vlanv1alpha1.VLANIndex, *vlanv1alpha1.VLANClaim] {
-	return clientproxy.New[*vlanv1alpha1.VLANIndex, *vlanv1alpha1.VLANClaim](
-		ctx, clientproxy.Config{
-			Address:     cfg.Address,
-			Name:        "vlan-client-proxy",
-			Group:       vlanv1alpha1.GroupVersion.Group, // Group of GVK for event handling
-			Normalizefn: NormalizeKRMToResourcePb,
-			ValidateFn:  ValidateResponse,
-		})
+	cfg.Name = "vlan-client-proxy"
+	cfg.Group = vlanv1alpha1.GroupVersion.Group // Group of GVK for event handling
+	cfg.HealthService = vlanv1alpha1.GroupVersion.String()
+	cfg.Normalizefn = NormalizeKRMToResourcePb
+	cfg.ValidateFn = ValidateResponse
+	return clientproxy.New[*vlanv1alpha1.VLANIndex, *vlanv1alpha1.VLANClaim](ctx, cfg)
 }
 
 // ValidateResponse handes validates changes in the claim response
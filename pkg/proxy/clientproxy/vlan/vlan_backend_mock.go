@@ -19,6 +19,7 @@ package vlan
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 
 	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/backend"
@@ -96,3 +97,5 @@ func (r *bemock) DeleteClaim(ctx context.Context, cr client.Object, d any) error
 	}
 	return r.be.DeleteClaim(ctx, b)
 }
+
+func (r *bemock) Healthz(req *http.Request) error { return nil }
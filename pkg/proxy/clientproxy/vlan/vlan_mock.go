@@ -19,6 +19,7 @@ package vlan
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
 
 	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
@@ -45,6 +46,7 @@ func (r *mock) Claim(ctx context.Context, cr client.Object, d any) (*vlanv1alpha
 	return r.getClaim(cr)
 }
 func (r *mock) DeleteClaim(ctx context.Context, cr client.Object, d any) error { return nil }
+func (r *mock) Healthz(req *http.Request) error                                { return nil }
 
 func (r *mock) getClaim(cr client.Object) (*vlanv1alpha1.VLANClaim, error) {
 	claim, ok := cr.(*vlanv1alpha1.VLANClaim)
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vxlan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/proxy/clientproxy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func New(ctx context.Context, cfg clientproxy.Config) clientproxy.Proxy[*vxlanv1alpha1.VXLANIndex, *vxlanv1alpha1.VXLANClaim] {
+	cfg.Name = "vxlan-client-proxy"
+	cfg.Group = vxlanv1alpha1.GroupVersion.Group // Group of GVK for event handling
+	cfg.HealthService = vxlanv1alpha1.GroupVersion.String()
+	cfg.Normalizefn = NormalizeKRMToResourcePb
+	cfg.ValidateFn = ValidateResponse
+	return clientproxy.New[*vxlanv1alpha1.VXLANIndex, *vxlanv1alpha1.VXLANClaim](ctx, cfg)
+}
+
+// ValidateResponse handes validates changes in the claim response
+// when doing refreshes
+func ValidateResponse(origResp *resourcepb.ClaimResponse, newResp *resourcepb.ClaimResponse) bool {
+	origClaim := vxlanv1alpha1.VXLANClaim{}
+	if err := json.Unmarshal([]byte(origResp.Status), &origClaim); err != nil {
+		return false
+	}
+	newClaim := vxlanv1alpha1.VXLANClaim{}
+	if err := json.Unmarshal([]byte(newResp.Status), &newClaim); err != nil {
+		return false
+	}
+	if origClaim.Status.VXLANID != nil {
+		if newClaim.Status.VXLANID == nil {
+			return false
+		}
+		if *origClaim.Status.VXLANID != *newClaim.Status.VXLANID {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeKRMToResourcePb normalizes the input to a generalized GRPC claim request
+// First we normalize the object to an claim -> this is specific to the source/own client.Object
+// Once normalized we can do generic processing -> add system desfined labels in the user defined labels
+// in the spec and transform to an resourcePB proto message
+func NormalizeKRMToResourcePb(o client.Object, d any) (*resourcepb.ClaimRequest, error) {
+	var claim *vxlanv1alpha1.VXLANClaim
+	expiryTime := "never"
+	nsnName := o.GetName()
+	switch o.GetObjectKind().GroupVersionKind().Kind {
+	case vxlanv1alpha1.VXLANClaimKind:
+		cr, ok := o.(*vxlanv1alpha1.VXLANClaim)
+		if !ok {
+			return nil, fmt.Errorf("unexpected error casting object to VXLANClaim failed")
+		}
+		// given the cr exists we just do a deepcopy
+		claim = cr.DeepCopy()
+		// addExpiryTime
+		t := time.Now().Add(time.Minute * 60)
+		b, err := t.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		expiryTime = string(b)
+	default:
+		return nil, fmt.Errorf("cannot claim resource for unknown kind, got %s", o.GetObjectKind().GroupVersionKind().Kind)
+	}
+
+	// generic processing
+	// add system defined labels to the user defined label section of the claim spec
+	claim.AddOwnerLabelsToCR()
+	// marshal the claim
+	b, err := json.Marshal(claim)
+	if err != nil {
+		return nil, err
+	}
+	return clientproxy.BuildResourcePb(
+			o,
+			nsnName,
+			string(b),
+			expiryTime,
+			vxlanv1alpha1.VXLANClaimGroupVersionKind),
+		nil
+}
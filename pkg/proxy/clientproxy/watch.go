@@ -22,16 +22,22 @@ import (
 	"time"
 
 	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backoff"
 	"github.com/nokia/k8s-ipam/pkg/meta"
 	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// watchRetryBaseDelay is the delay of the first resubscription of a failed watch
+	watchRetryBaseDelay = time.Second
+	// watchRetryMaxDelay caps the delay of the resubscription of a failed watch
+	watchRetryMaxDelay = time.Minute
 )
 
 func (r *clientproxy[T1, T2]) startWatches(ctx context.Context) {
-	r.l = log.FromContext(ctx)
 	// subscribe to the server for events
 	go func() {
 		defer r.stopWatches()
@@ -51,13 +57,26 @@ func (r *clientproxy[T1, T2]) stopWatches() {
 }
 
 func (r *clientproxy[T1, T2]) startWatch(ctx context.Context, gvk schema.GroupVersionKind) {
+	// delays the resubscription exponentially while the backend is unavailable
+	retry := backoff.NewRateLimiter(watchRetryBaseDelay, watchRetryMaxDelay)
 	// client side of the RPC stream
 	var stream resourcepb.Resource_WatchClaimClient
+	// lost indicates the stream failed, updates might have been missed
+	lost := false
+	// seq is the sequence number of the last update, it is used to resume
+	// the watch such that the server replays the missed updates
+	var seq uint64
+	// resuming indicates the watch is resumed but not yet confirmed by the server
+	resuming := false
 
 	for {
+		if ctx.Err() != nil {
+			return
+		}
 		resourceClient, err := r.getClient()
 		if err != nil {
 			r.l.Error(err, "failed to get client")
+			waitRetry(ctx, retry.When(gvk))
 			continue
 		}
 
@@ -66,44 +85,67 @@ func (r *clientproxy[T1, T2]) startWatch(ctx context.Context, gvk schema.GroupVe
 				Header: &resourcepb.Header{
 					OwnerGvk: meta.PointerResourcePBGVK(meta.GetResourcePbGVKFromSchemaGVK(gvk)),
 					Gvk:      meta.PointerResourcePBGVK(meta.GetResourcePbGVKFromSchemaGVK(ipamv1alpha1.IPClaimGroupVersionKind)), // TODO make it independent
-				}}); err != nil && !errors.Is(err, context.Canceled) {
-				if er, ok := status.FromError(err); ok {
-					switch er.Code() {
-					case codes.Canceled:
-						// dont log when context got cancelled
-					default:
-						r.l.Error(err, "failed to subscribe")
-					}
+				},
+				ClientId: r.clientID,
+				Sequence: seq,
+			}); err != nil {
+				// dont log when context got cancelled
+				if status.Code(err) != codes.Canceled && !errors.Is(err, context.Canceled) {
+					r.l.Error(err, "failed to subscribe")
 				}
-				time.Sleep(time.Second * 1) //- resilience for server crash
+				stream = nil
 				// retry on failure
+				waitRetry(ctx, retry.When(gvk))
 				continue
 			}
+			retry.Forget(gvk)
+			switch {
+			case lost && seq != 0:
+				// the server replays the missed updates
+				resuming = true
+			case lost:
+				// resync the claims since updates might have been missed
+				// while the stream was down, this is a noop when the claims
+				// were already resynced when the connection became ready
+				go r.resync(ctx)
+			}
+			lost = false
 		}
 		response, err := stream.Recv()
-		if err != nil && !errors.Is(err, context.Canceled) {
-			if er, ok := status.FromError(err); ok {
-				switch er.Code() {
-				case codes.Canceled:
-					// dont log when context got cancelled
-				default:
-					r.l.Error(err, "failed to receive a message from stream")
-				}
+		if err != nil {
+			// dont log when context got cancelled
+			if status.Code(err) != codes.Canceled && !errors.Is(err, context.Canceled) {
+				r.l.Error(err, "failed to receive a message from stream")
 			}
 			// clearing the stream will force the client to resubscribe in the next iteration
 			stream = nil
-			time.Sleep(time.Second * 1) //- resilience for server crash
+			lost = true
+			resuming = false
+			if status.Code(err) == codes.OutOfRange {
+				// the server cannot replay the missed updates, a new watch is
+				// started and the claims are resynced
+				r.l.Info("watch cannot be resumed", "gvk", gvk, "sequence", seq)
+				seq = 0
+			}
+			// updates might be missed till the client is resubscribed
+			r.invalidateCache()
 			// retry on failure
+			waitRetry(ctx, retry.When(gvk))
 			continue
 		}
-		r.l.Info("watch response -> notify client", "gvk", gvk, "header", response.Header, "state", response.StatusCode)
-		if response.StatusCode == resourcepb.StatusCode_Unknown {
-			// invalidate the cache
-			r.cache.Delete(ObjectKindKey{
-				gvk: meta.GetSchemaGVKFromResourcePbGVK(response.Header.Gvk),
-				nsn: meta.GetTypeNSNFromResourcePbNSN(response.Header.Nsn),
-			})
+		r.l.Info("watch response -> notify client", "gvk", gvk, "header", response.Header, "state", response.StatusCode, "sequence", response.Sequence)
+		seq = response.Sequence
+		if resuming {
+			// the server replays the missed updates, no resync is needed
+			resuming = false
+			r.discardResync()
 		}
+		// every update indicates the claim changed in the backend, so the cached
+		// response is invalidated
+		r.cache.Delete(ObjectKindKey{
+			gvk: meta.GetSchemaGVKFromResourcePbGVK(response.Header.Gvk),
+			nsn: meta.GetTypeNSNFromResourcePbNSN(response.Header.Nsn),
+		})
 
 		// inform the ownerGVK to retrigger a reconcilation event
 		r.informer.NotifyClient(
@@ -112,3 +154,11 @@ func (r *clientproxy[T1, T2]) startWatch(ctx context.Context, gvk schema.GroupVe
 		)
 	}
 }
+
+// waitRetry waits for the delay or till the context is cancelled
+func waitRetry(ctx context.Context, delay time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
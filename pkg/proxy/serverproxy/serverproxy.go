@@ -19,6 +19,9 @@ type Proxy interface {
 	Claim(ctx context.Context, claim *resourcepb.ClaimRequest) (*resourcepb.ClaimResponse, error)
 	DeleteClaim(ctx context.Context, claim *resourcepb.ClaimRequest) (*resourcepb.EmptyResponse, error)
 	Watch(in *resourcepb.WatchRequest, stream resourcepb.Resource_WatchClaimServer) error
+	Export(ctx context.Context, in *resourcepb.ExportRequest) (*resourcepb.ExportResponse, error)
+	Import(ctx context.Context, in *resourcepb.ImportRequest) (*resourcepb.ImportResponse, error)
+	GetTree(ctx context.Context, in *resourcepb.TreeRequest) (*resourcepb.TreeResponse, error)
 }
 
 type Config struct {
@@ -79,7 +82,7 @@ func (r *serverproxy) GetClaim(ctx context.Context, claim *resourcepb.ClaimReque
 	b, err := be.GetClaim(ctx, []byte(claim.Spec))
 	if err != nil {
 		log.Error(err, "cannot get claim", "spec", claim.Spec)
-		return nil, err
+		return buildErrorClaimResponse(claim, err), nil
 	}
 	resp := &resourcepb.ClaimResponse{Header: claim.Header, Spec: claim.Spec, StatusCode: resourcepb.StatusCode_Unknown, ExpiryTime: claim.ExpiryTime}
 	resp.Status = string(b)
@@ -96,15 +99,24 @@ func (r *serverproxy) Claim(ctx context.Context, claim *resourcepb.ClaimRequest)
 		return nil, fmt.Errorf("backend not registered, got: %v", claim.Header.Gvk)
 	}
 
-	b, err := be.Claim(ctx, []byte(claim.Spec))
+	claimFn := be.Claim
+	if claim.DryRun {
+		dbe, ok := be.(backend.DryRunBackend)
+		if !ok {
+			err := backend.NewError(resourcepb.ErrorCode_ValidationFailed, "backend %s has no dry run", meta.GetSchemaGVKFromResourcePbGVK(claim.Header.Gvk).GroupVersion().String())
+			return buildErrorClaimResponse(claim, err), nil
+		}
+		claimFn = dbe.DryRunClaim
+	}
+	b, err := claimFn(ctx, []byte(claim.Spec))
 	if err != nil {
-		log.Error(err, "cannot claim", "spec", claim.Spec)
-		return nil, err
+		log.Error(err, "cannot claim", "spec", claim.Spec, "dryRun", claim.DryRun)
+		return buildErrorClaimResponse(claim, err), nil
 	}
 	resp := &resourcepb.ClaimResponse{Header: claim.Header, Spec: claim.Spec, StatusCode: resourcepb.StatusCode_Unknown, ExpiryTime: claim.ExpiryTime}
 	resp.Status = string(b)
 	resp.StatusCode = resourcepb.StatusCode_Valid
-	log.Info("claim done")
+	log.Info("claim done", "dryRun", claim.DryRun)
 	return resp, nil
 }
 
@@ -118,7 +130,7 @@ func (r *serverproxy) DeleteClaim(ctx context.Context, claim *resourcepb.ClaimRe
 	err := be.DeleteClaim(ctx, []byte(claim.Spec))
 	if err != nil {
 		log.Error(err, "cannot delete claim", "spec", claim.Spec)
-		return nil, err
+		return &resourcepb.EmptyResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
 	}
 	log.Info("delete claim done")
 	return &resourcepb.EmptyResponse{}, nil
@@ -132,7 +144,7 @@ func (r *serverproxy) Watch(in *resourcepb.WatchRequest, stream resourcepb.Resou
 	if p != nil {
 		addr = p.Addr.String()
 	}
-	log.Info("watch started", "client", addr, "ownerGvk", in.Header.OwnerGvk)
+	log.Info("watch started", "client", addr, "clientId", in.ClientId, "sequence", in.Sequence, "ownerGvk", in.Header.OwnerGvk)
 
 	_, ok := r.backends[meta.GetSchemaGVKFromResourcePbGVK(in.Header.Gvk).GroupVersion()]
 	if !ok {
@@ -140,6 +152,18 @@ func (r *serverproxy) Watch(in *resourcepb.WatchRequest, stream resourcepb.Resou
 		return fmt.Errorf("backend not registered, got: %v", in.Header.Gvk)
 	}
 
-	r.proxyState.AddCallBackFn(in.Header, stream)
-	return nil
+	return r.proxyState.AddCallBackFn(in, stream)
+}
+
+// buildErrorClaimResponse returns an invalid claim response with the error code
+// of the backend error, such that the client can act upon the cause of the error
+func buildErrorClaimResponse(claim *resourcepb.ClaimRequest, err error) *resourcepb.ClaimResponse {
+	return &resourcepb.ClaimResponse{
+		Header:       claim.Header,
+		Spec:         claim.Spec,
+		StatusCode:   resourcepb.StatusCode_InValid,
+		ExpiryTime:   claim.ExpiryTime,
+		ErrorCode:    backend.GetErrorCode(err),
+		ErrorMessage: err.Error(),
+	}
 }
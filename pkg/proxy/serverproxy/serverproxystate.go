@@ -2,106 +2,323 @@ package serverproxy
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hansthienpondt/nipam/pkg/table"
 	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
 	"github.com/nokia/k8s-ipam/pkg/backend"
 	"github.com/nokia/k8s-ipam/pkg/meta"
 	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+const (
+	// defaultWatchBufferSize is the amount of updates buffered per client watch
+	defaultWatchBufferSize = 1000
+	// defaultWatchSessionTTL is the time the updates of a client watch are
+	// buffered after its stream ended
+	defaultWatchSessionTTL = 5 * time.Minute
+)
+
 type ProxyState struct {
-	m sync.RWMutex
-	// key is clientName
+	// watchM serializes adding and deleting the backend watches, which are
+	// called without holding m since the backend callbacks take m
+	watchM sync.Mutex
+	m      sync.Mutex
+	// key is the client identity, the groupVersion and the ownerGvk
 	clients  map[string]*clientContext
 	backends map[schema.GroupVersion]backend.Backend
+
+	bufferSize int
+	sessionTTL time.Duration
 }
 
 type ProxyStateConfig struct {
 	Backends map[schema.GroupVersion]backend.Backend
+	// WatchBufferSize is the amount of updates buffered per client watch to
+	// replay the updates a client missed while it was disconnected
+	WatchBufferSize int
+	// WatchSessionTTL is the time the updates of a client watch are buffered
+	// after its stream ended, a client that does not resume within the ttl
+	// has to start a new watch
+	WatchSessionTTL time.Duration
 }
 
+// clientContext is the watch of a client, it outlives the stream of the
+// client such that the client can resume the watch when it reconnects
 type clientContext struct {
-	stream resourcepb.Resource_WatchClaimServer
+	gv       schema.GroupVersion
+	ownerGvk string
+
+	m sync.Mutex
+	// buffer holds the last updates ordered by sequence number
+	buffer []*resourcepb.WatchResponse
+	// seq is the sequence number of the last update
+	seq uint64
+	// notify signals the stream that updates are added
+	notify chan struct{}
+	// cancel stops the stream that is attached to the watch, nil when no
+	// stream is attached
 	cancel context.CancelFunc
+	// streamID identifies the stream that is attached to the watch
+	streamID uint64
+	// expire deletes the watch when no stream is attached within the ttl
+	expire *time.Timer
 }
 
 func NewProxyState(cfg *ProxyStateConfig) *ProxyState {
-	return &ProxyState{
-		clients: map[string]*clientContext{},
-		//ipam:    c.Ipam,
-		backends: cfg.Backends,
+	r := &ProxyState{
+		clients:    map[string]*clientContext{},
+		backends:   cfg.Backends,
+		bufferSize: cfg.WatchBufferSize,
+		sessionTTL: cfg.WatchSessionTTL,
+	}
+	if r.bufferSize <= 0 {
+		r.bufferSize = defaultWatchBufferSize
+	}
+	if r.sessionTTL <= 0 {
+		r.sessionTTL = defaultWatchSessionTTL
+	}
+	return r
+}
+
+// AddCallBackFn attaches the stream to the watch of the client and sends the
+// updates of the claims of the ownerGvk till the stream ends. A watch that is
+// resumed from a sequence number first replays the buffered updates after it.
+func (r *ProxyState) AddCallBackFn(in *resourcepb.WatchRequest, stream resourcepb.Resource_WatchClaimServer) error {
+	ownerGVK := meta.ResourcePbGVKTostring(*in.Header.OwnerGvk)
+	// we already validated the existance of the backend before calling this function
+	gv := meta.GetSchemaGVKFromResourcePbGVK(in.Header.Gvk).GroupVersion()
+	key := getClientKey(in.GetClientId(), stream, gv, ownerGVK)
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	log := log.FromContext(ctx)
+
+	clientCtx, streamID, lastSeq, err := r.attach(key, gv, ownerGVK, in.GetSequence(), cancel)
+	if err != nil {
+		return err
+	}
+	defer r.detach(key, clientCtx, streamID)
+	log.Info("watch started", "client", key, "sequence", lastSeq)
+
+	for {
+		updates, ok := clientCtx.getUpdates(lastSeq)
+		if !ok {
+			// the client is too slow, updates were dropped from the buffer
+			return status.Errorf(codes.OutOfRange, "watch %s lost updates after sequence %d", key, lastSeq)
+		}
+		for _, update := range updates {
+			if err := stream.Send(update); err != nil {
+				log.Error(err, "watch send failed", "client", key)
+				return err
+			}
+			lastSeq = update.Sequence
+		}
+		select {
+		case <-ctx.Done():
+			log.Info("watch stopped", "client", key, "sequence", lastSeq)
+			return nil
+		case <-clientCtx.notify:
+		}
+	}
+}
+
+// getClientKey returns the key of the watch of the client, the peer address is
+// used for clients that do not provide their identity
+func getClientKey(clientID string, stream resourcepb.Resource_WatchClaimServer, gv schema.GroupVersion, ownerGvk string) string {
+	if clientID == "" {
+		clientID = "unknown"
+		if p, ok := peer.FromContext(stream.Context()); ok && p.Addr != nil {
+			clientID = p.Addr.String()
+		}
 	}
+	return fmt.Sprintf("%s:::%s:::%s", clientID, gv.String(), ownerGvk)
 }
 
-func (r *ProxyState) AddCallBackFn(header *resourcepb.Header, stream resourcepb.Resource_WatchClaimServer) {
-	p, _ := peer.FromContext(stream.Context())
+// attach attaches the stream to the watch of the client, the watch is created
+// if it does not exist. The id of the stream and the sequence number from
+// which updates have to be sent are returned.
+func (r *ProxyState) attach(key string, gv schema.GroupVersion, ownerGvk string, seq uint64, cancel context.CancelFunc) (*clientContext, uint64, uint64, error) {
+	r.watchM.Lock()
+	defer r.watchM.Unlock()
 
-	ownerGVK := meta.ResourcePbGVKTostring(*header.OwnerGvk)
+	clientCtx, streamID, seq, addWatch, err := r.attachClient(key, gv, ownerGvk, seq, cancel)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if addWatch {
+		r.backends[gv].AddWatch(resourcev1alpha1.NephioOwnerGvkKey, ownerGvk, r.CreateCallBackFn(gv, ownerGvk))
+	}
+	return clientCtx, streamID, seq, nil
+}
 
+// attachClient attaches the stream to the watch of the client, true is
+// returned when the watch is the first watch of the ownerGvk in the backend
+func (r *ProxyState) attachClient(key string, gv schema.GroupVersion, ownerGvk string, seq uint64, cancel context.CancelFunc) (*clientContext, uint64, uint64, bool, error) {
 	r.m.Lock()
-	// cancelFn if a client adss another entry the client is misbehaving
-	if clientCtx, ok := r.clients[p.Addr.String()+":::"+ownerGVK]; ok {
+	defer r.m.Unlock()
+
+	addWatch := false
+	clientCtx, ok := r.clients[key]
+	if !ok {
+		if seq != 0 {
+			return nil, 0, 0, false, status.Errorf(codes.OutOfRange, "watch %s not found, cannot resume from sequence %d", key, seq)
+		}
+		clientCtx = &clientContext{
+			gv:       gv,
+			ownerGvk: ownerGvk,
+			notify:   make(chan struct{}, 1),
+		}
+		addWatch = !r.hasWatch(gv, ownerGvk)
+		r.clients[key] = clientCtx
+	}
+
+	clientCtx.m.Lock()
+	defer clientCtx.m.Unlock()
+	if seq == 0 {
+		seq = clientCtx.seq
+	}
+	if seq > clientCtx.seq || (len(clientCtx.buffer) > 0 && seq+1 < clientCtx.buffer[0].Sequence) ||
+		(len(clientCtx.buffer) == 0 && seq != clientCtx.seq) {
+		return nil, 0, 0, false, status.Errorf(codes.OutOfRange, "watch %s cannot resume from sequence %d", key, seq)
+	}
+	// a client that reconnects replaces the stream of its previous connection
+	if clientCtx.cancel != nil {
 		clientCtx.cancel()
 	}
-	ctx, cancel := context.WithCancel(stream.Context())
-	log := log.FromContext(ctx)
+	if clientCtx.expire != nil {
+		clientCtx.expire.Stop()
+		clientCtx.expire = nil
+	}
+	clientCtx.cancel = cancel
+	clientCtx.streamID++
+	return clientCtx, clientCtx.streamID, seq, addWatch, nil
+}
 
-	r.clients[p.Addr.String()+":::"+ownerGVK] = &clientContext{
-		stream: stream,
-		cancel: cancel,
+// detach detaches the stream from the watch of the client, the watch is
+// deleted when no stream is attached within the ttl
+func (r *ProxyState) detach(key string, clientCtx *clientContext, streamID uint64) {
+	clientCtx.m.Lock()
+	defer clientCtx.m.Unlock()
+	// the stream got replaced by a new stream of the client
+	if clientCtx.streamID != streamID {
+		return
 	}
-	r.m.Unlock()
+	clientCtx.cancel = nil
+	clientCtx.expire = time.AfterFunc(r.sessionTTL, func() {
+		r.DeleteCallBackFn(key, clientCtx)
+	})
+}
 
-	// we already validated the existance of the backend before calling this function
-	gv := meta.GetSchemaGVKFromResourcePbGVK(header.Gvk).GroupVersion()
-	be := r.backends[gv]
-	be.AddWatch(resourcev1alpha1.NephioOwnerGvkKey, ownerGVK, r.CreateCallBackFn(stream))
+// DeleteCallBackFn deletes the watch of the client, the watch in the backend
+// is deleted when no other client watches the ownerGvk
+func (r *ProxyState) DeleteCallBackFn(key string, clientCtx *clientContext) {
+	r.watchM.Lock()
+	defer r.watchM.Unlock()
 
-	for range ctx.Done() {
-		r.DeleteCallBackFn(p.Addr.String(), ownerGVK, gv)
-		log.Info("watch stopped", "ownerGvk", ownerGVK, "groupVersion", gv)
-		return
+	if r.deleteClient(key, clientCtx) {
+		r.backends[clientCtx.gv].DeleteWatch(resourcev1alpha1.NephioOwnerGvkKey, clientCtx.ownerGvk)
 	}
 }
 
-func (r *ProxyState) DeleteCallBackFn(clientName, ownerGvk string, gv schema.GroupVersion) {
+// deleteClient deletes the watch of the client, true is returned when no
+// other client watches the ownerGvk in the backend
+func (r *ProxyState) deleteClient(key string, clientCtx *clientContext) bool {
 	r.m.Lock()
 	defer r.m.Unlock()
-	r.backends[gv].DeleteWatch(resourcev1alpha1.NephioOwnerGvkKey, ownerGvk)
-	delete(r.clients, clientName+":::"+ownerGvk)
+	if r.clients[key] != clientCtx {
+		return false
+	}
+	clientCtx.m.Lock()
+	attached := clientCtx.cancel != nil
+	clientCtx.m.Unlock()
+	// a stream got attached while the watch expired
+	if attached {
+		return false
+	}
+	delete(r.clients, key)
+	return !r.hasWatch(clientCtx.gv, clientCtx.ownerGvk)
 }
 
-func (r *ProxyState) CreateCallBackFn(stream resourcepb.Resource_WatchClaimServer) backend.CallbackFn {
-	log := log.FromContext(context.Background())
+// hasWatch returns true if a client watches the ownerGvk in the backend of the
+// groupVersion, the caller must hold the lock
+func (r *ProxyState) hasWatch(gv schema.GroupVersion, ownerGvk string) bool {
+	for _, clientCtx := range r.clients {
+		if clientCtx.gv == gv && clientCtx.ownerGvk == ownerGvk {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateCallBackFn returns the callback of the backend watch of the ownerGvk,
+// which adds the updates to the watches of all clients of the ownerGvk
+func (r *ProxyState) CreateCallBackFn(gv schema.GroupVersion, ownerGvk string) backend.CallbackFn {
 	return func(routes table.Routes, statusCode resourcepb.StatusCode) {
-		for _, route := range routes {
-			if err := stream.Send(&resourcepb.WatchResponse{
-				Header: &resourcepb.Header{
-					Gvk: meta.PointerResourcePBGVK(meta.StringToResourcePbGVK(route.Labels()[resourcev1alpha1.NephioGvkKey])),
-					Nsn: &resourcepb.NSN{
-						Namespace: route.Labels()[resourcev1alpha1.NephioNsnNamespaceKey],
-						Name:      route.Labels()[resourcev1alpha1.NephioNsnNameKey],
-					},
-					OwnerGvk: meta.PointerResourcePBGVK(meta.StringToResourcePbGVK(route.Labels()[resourcev1alpha1.NephioOwnerGvkKey])),
-					OwnerNsn: &resourcepb.NSN{
-						Namespace: route.Labels()[resourcev1alpha1.NephioOwnerNsnNamespaceKey],
-						Name:      route.Labels()[resourcev1alpha1.NephioOwnerNsnNameKey],
+		r.m.Lock()
+		defer r.m.Unlock()
+		for _, clientCtx := range r.clients {
+			if clientCtx.gv != gv || clientCtx.ownerGvk != ownerGvk {
+				continue
+			}
+			for _, route := range routes {
+				clientCtx.addUpdate(&resourcepb.WatchResponse{
+					Header: &resourcepb.Header{
+						Gvk: meta.PointerResourcePBGVK(meta.StringToResourcePbGVK(route.Labels()[resourcev1alpha1.NephioGvkKey])),
+						Nsn: &resourcepb.NSN{
+							Namespace: route.Labels()[resourcev1alpha1.NephioNsnNamespaceKey],
+							Name:      route.Labels()[resourcev1alpha1.NephioNsnNameKey],
+						},
+						OwnerGvk: meta.PointerResourcePBGVK(meta.StringToResourcePbGVK(route.Labels()[resourcev1alpha1.NephioOwnerGvkKey])),
+						OwnerNsn: &resourcepb.NSN{
+							Namespace: route.Labels()[resourcev1alpha1.NephioOwnerNsnNamespaceKey],
+							Name:      route.Labels()[resourcev1alpha1.NephioOwnerNsnNameKey],
+						},
 					},
-				},
-				StatusCode: statusCode,
-			}); err != nil {
-				p, _ := peer.FromContext(stream.Context())
-				addr := "unknown"
-				if p != nil {
-					addr = p.Addr.String()
-				}
-				log.Error(err, "callback failed", "client", addr, "ownerGvk", route.Labels()[resourcev1alpha1.NephioOwnerGvkKey])
+					StatusCode: statusCode,
+				}, r.bufferSize)
 			}
 		}
 	}
 }
+
+// addUpdate adds the update to the buffer of the watch, the oldest update is
+// dropped when the buffer is full
+func (r *clientContext) addUpdate(update *resourcepb.WatchResponse, bufferSize int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.seq++
+	update.Sequence = r.seq
+	r.buffer = append(r.buffer, update)
+	if len(r.buffer) > bufferSize {
+		r.buffer = r.buffer[len(r.buffer)-bufferSize:]
+	}
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// getUpdates returns the buffered updates after the sequence number, false is
+// returned when updates after the sequence number were dropped
+func (r *clientContext) getUpdates(seq uint64) ([]*resourcepb.WatchResponse, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if len(r.buffer) == 0 || seq >= r.seq {
+		return nil, true
+	}
+	first := r.buffer[0].Sequence
+	if seq+1 < first {
+		return nil, false
+	}
+	updates := make([]*resourcepb.WatchResponse, len(r.buffer)-int(seq+1-first))
+	copy(updates, r.buffer[seq+1-first:])
+	return updates, true
+}
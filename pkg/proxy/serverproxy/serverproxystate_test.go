@@ -0,0 +1,316 @@
+package serverproxy
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hansthienpondt/nipam/pkg/table"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var ownerGvk = ipamv1alpha1.IPClaimGroupVersionKind
+
+type mockBackend struct {
+	backend.Backend
+	m       sync.Mutex
+	watches map[string]backend.CallbackFn
+}
+
+func (r *mockBackend) AddWatch(ownerGvkKey, ownerGvk string, fn backend.CallbackFn) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.watches[ownerGvk] = fn
+}
+
+func (r *mockBackend) DeleteWatch(ownerGvkKey, ownerGvk string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	delete(r.watches, ownerGvk)
+}
+
+func (r *mockBackend) getWatch() backend.CallbackFn {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.watches[meta.GVKToString(ownerGvk)]
+}
+
+// update sends an update of the claim through the watch of the backend, the
+// lock is held during the callback like the watchers of the backends do
+func (r *mockBackend) update(name string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	fn, ok := r.watches[meta.GVKToString(ownerGvk)]
+	if !ok {
+		return
+	}
+	fn(table.Routes{table.NewRoute(netip.MustParsePrefix("10.0.0.0/24"), map[string]string{
+		resourcev1alpha1.NephioGvkKey:          meta.GVKToString(ownerGvk),
+		resourcev1alpha1.NephioOwnerGvkKey:     meta.GVKToString(ownerGvk),
+		resourcev1alpha1.NephioNsnNameKey:      name,
+		resourcev1alpha1.NephioOwnerNsnNameKey: name,
+	}, nil)}, resourcepb.StatusCode_Unknown)
+}
+
+type mockStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	ch     chan *resourcepb.WatchResponse
+}
+
+func newMockStream(port int) *mockStream {
+	ctx, cancel := context.WithCancel(peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port},
+	}))
+	return &mockStream{ctx: ctx, cancel: cancel, ch: make(chan *resourcepb.WatchResponse, 10)}
+}
+
+func (r *mockStream) Context() context.Context { return r.ctx }
+
+func (r *mockStream) Send(resp *resourcepb.WatchResponse) error {
+	r.ch <- resp
+	return nil
+}
+
+func (r *mockStream) recv(t *testing.T) *resourcepb.WatchResponse {
+	select {
+	case resp := <-r.ch:
+		return resp
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for a watch update")
+	}
+	return nil
+}
+
+func newWatchRequest(clientID string, seq uint64) *resourcepb.WatchRequest {
+	return &resourcepb.WatchRequest{
+		Header: &resourcepb.Header{
+			Gvk:      meta.PointerResourcePBGVK(meta.GetResourcePbGVKFromSchemaGVK(ipamv1alpha1.IPClaimGroupVersionKind)),
+			OwnerGvk: meta.PointerResourcePBGVK(meta.GetResourcePbGVKFromSchemaGVK(ownerGvk)),
+		},
+		ClientId: clientID,
+		Sequence: seq,
+	}
+}
+
+func newTestProxyState(bufferSize int, ttl time.Duration) (*ProxyState, *mockBackend) {
+	be := &mockBackend{watches: map[string]backend.CallbackFn{}}
+	return NewProxyState(&ProxyStateConfig{
+		Backends:        map[schema.GroupVersion]backend.Backend{ipamv1alpha1.GroupVersion: be},
+		WatchBufferSize: bufferSize,
+		WatchSessionTTL: ttl,
+	}), be
+}
+
+// watch runs the watch of the stream in the background and returns the result
+func watch(r *ProxyState, in *resourcepb.WatchRequest, stream *mockStream) chan error {
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.AddCallBackFn(in, stream) }()
+	return errCh
+}
+
+func waitFor(t *testing.T, msg string, fn func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !fn() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (r *ProxyState) numClients() int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return len(r.clients)
+}
+
+func TestWatchResume(t *testing.T) {
+	ps, be := newTestProxyState(10, time.Minute)
+
+	s1 := newMockStream(1000)
+	errCh1 := watch(ps, newWatchRequest("client1", 0), s1)
+	waitFor(t, "the backend watch", func() bool { return be.getWatch() != nil })
+	be.update("a")
+	if resp := s1.recv(t); resp.Sequence != 1 || resp.Header.Nsn.Name != "a" {
+		t.Fatalf("unexpected update: %v", resp)
+	}
+
+	// the stream ends and updates are sent while the client is disconnected
+	s1.cancel()
+	if err := <-errCh1; err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	be.update("b")
+	be.update("c")
+
+	// the client reconnects from another port and resumes the watch
+	s2 := newMockStream(2000)
+	errCh2 := watch(ps, newWatchRequest("client1", 1), s2)
+	for i, name := range []string{"b", "c"} {
+		if resp := s2.recv(t); resp.Sequence != uint64(i+2) || resp.Header.Nsn.Name != name {
+			t.Fatalf("unexpected replayed update: %v", resp)
+		}
+	}
+	if n := ps.numClients(); n != 1 {
+		t.Errorf("clients: want 1, got %d", n)
+	}
+
+	// a reconnect replaces the stream of the previous connection
+	s3 := newMockStream(3000)
+	errCh3 := watch(ps, newWatchRequest("client1", 3), s3)
+	if err := <-errCh2; err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	be.update("d")
+	if resp := s3.recv(t); resp.Sequence != 4 || resp.Header.Nsn.Name != "d" {
+		t.Fatalf("unexpected update: %v", resp)
+	}
+	if len(s2.ch) != 0 {
+		t.Errorf("update sent to the replaced stream")
+	}
+	s3.cancel()
+	<-errCh3
+}
+
+func TestWatchResumeOutOfRange(t *testing.T) {
+	cases := map[string]struct {
+		clientID string
+		seq      uint64
+	}{
+		"UnknownClient": {clientID: "client2", seq: 1},
+		"DroppedUpdate": {clientID: "client1", seq: 1},
+		"FutureUpdate":  {clientID: "client1", seq: 10},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ps, be := newTestProxyState(2, time.Minute)
+			s1 := newMockStream(1000)
+			errCh := watch(ps, newWatchRequest("client1", 0), s1)
+			waitFor(t, "the backend watch", func() bool { return be.getWatch() != nil })
+			s1.cancel()
+			<-errCh
+			// update 1 and 2 are dropped from the buffer
+			for _, name := range []string{"a", "b", "c", "d"} {
+				be.update(name)
+			}
+
+			err := ps.AddCallBackFn(newWatchRequest(tc.clientID, tc.seq), newMockStream(2000))
+			if status.Code(err) != codes.OutOfRange {
+				t.Errorf("want OutOfRange, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWatchExpiry(t *testing.T) {
+	ps, be := newTestProxyState(10, 50*time.Millisecond)
+
+	s1 := newMockStream(1000)
+	errCh := watch(ps, newWatchRequest("client1", 0), s1)
+	waitFor(t, "the backend watch", func() bool { return be.getWatch() != nil })
+	s1.cancel()
+	<-errCh
+
+	waitFor(t, "the expiry of the watch", func() bool { return ps.numClients() == 0 })
+	if be.getWatch() != nil {
+		t.Errorf("backend watch not deleted after the watch expired")
+	}
+}
+
+func TestWatchMultipleClients(t *testing.T) {
+	ps, be := newTestProxyState(10, 50*time.Millisecond)
+
+	s1 := newMockStream(1000)
+	errCh1 := watch(ps, newWatchRequest("client1", 0), s1)
+	s2 := newMockStream(2000)
+	errCh2 := watch(ps, newWatchRequest("client2", 0), s2)
+	waitFor(t, "both clients", func() bool { return ps.numClients() == 2 })
+
+	be.update("a")
+	for _, s := range []*mockStream{s1, s2} {
+		if resp := s.recv(t); resp.Header.Nsn.Name != "a" {
+			t.Fatalf("unexpected update: %v", resp)
+		}
+	}
+
+	// the backend watch is kept while another client watches the ownerGvk
+	s1.cancel()
+	<-errCh1
+	waitFor(t, "the expiry of the watch", func() bool { return ps.numClients() == 1 })
+	if be.getWatch() == nil {
+		t.Errorf("backend watch deleted while a client is watching")
+	}
+	s2.cancel()
+	<-errCh2
+}
+
+func TestWatchConcurrentUpdates(t *testing.T) {
+	ps, be := newTestProxyState(1000, time.Millisecond)
+
+	// updates are sent while watches are added and deleted in the backend
+	stop := make(chan struct{})
+	updated := make(chan struct{})
+	go func() {
+		defer close(updated)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				be.update("b")
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			s := newMockStream(1000 + i)
+			errCh := watch(ps, newWatchRequest("", 0), s)
+			// drain the stream till the watch ended, such that the watch
+			// does not block on sending
+			ended := make(chan struct{})
+			go func() {
+				for {
+					select {
+					case <-s.ch:
+					case <-ended:
+						return
+					}
+				}
+			}()
+			be.update("a")
+			s.cancel()
+			<-errCh
+			close(ended)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout, the updates and the watches deadlocked")
+	}
+	close(stop)
+	<-updated
+
+	waitFor(t, "the expiry of the watches", func() bool { return ps.numClients() == 0 })
+	if be.getWatch() != nil {
+		t.Errorf("backend watch not deleted after the watches expired")
+	}
+}
@@ -0,0 +1,129 @@
+package serverproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Export returns the snapshot of the backends of the requested group
+// versions, the snapshot holds all backends if no group version is requested
+func (r *serverproxy) Export(ctx context.Context, in *resourcepb.ExportRequest) (*resourcepb.ExportResponse, error) {
+	log := log.FromContext(ctx)
+	gvs, err := r.getGroupVersions(in.GroupVersions)
+	if err != nil {
+		return &resourcepb.ExportResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
+	}
+
+	backends := make([]backend.BackendSnapshot, 0, len(gvs))
+	for _, gv := range gvs {
+		indices, err := r.backends[gv].Export(ctx)
+		if err != nil {
+			log.Error(err, "cannot export backend", "groupVersion", gv.String())
+			return &resourcepb.ExportResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
+		}
+		backends = append(backends, backend.BackendSnapshot{GroupVersion: gv.String(), Indices: indices})
+	}
+	b, err := json.MarshalIndent(backend.NewSnapshot(backends), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	log.Info("export done", "backends", len(backends))
+	return &resourcepb.ExportResponse{Snapshot: string(b)}, nil
+}
+
+// Import validates the snapshot against all backends before the snapshot is
+// applied, such that a snapshot that conflicts with a backend is not applied.
+// The backends are not locked across the backends, they are applied one after
+// the other and every backend validates its part again while it is applied.
+// When a backend fails or a claim conflicts in between, the backends before it
+// stay applied; the response reports the applied backends and the conflicts of
+// the backend that is not applied.
+func (r *serverproxy) Import(ctx context.Context, in *resourcepb.ImportRequest) (*resourcepb.ImportResponse, error) {
+	log := log.FromContext(ctx)
+	s, err := backend.ParseSnapshot([]byte(in.Snapshot))
+	if err != nil {
+		return &resourcepb.ImportResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
+	}
+	gvs := make([]string, 0, len(s.Backends))
+	for _, b := range s.Backends {
+		gvs = append(gvs, b.GroupVersion)
+	}
+	if _, err := r.getGroupVersions(gvs); err != nil {
+		return &resourcepb.ImportResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
+	}
+
+	conflicts := []string{}
+	for _, b := range s.Backends {
+		c, err := r.importBackend(ctx, b, true)
+		if err != nil {
+			return &resourcepb.ImportResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
+		}
+		conflicts = append(conflicts, c...)
+	}
+	if len(conflicts) > 0 || in.DryRun {
+		log.Info("import validated", "conflicts", len(conflicts), "dryRun", in.DryRun)
+		return &resourcepb.ImportResponse{Conflicts: conflicts}, nil
+	}
+
+	applied := []string{}
+	for _, b := range s.Backends {
+		conflicts, err := r.importBackend(ctx, b, false)
+		if err == nil && len(conflicts) == 0 {
+			applied = append(applied, b.GroupVersion)
+			continue
+		}
+		msg := fmt.Sprintf("snapshot applied partially, applied backends %v, backend %s not applied", applied, b.GroupVersion)
+		if err != nil {
+			log.Error(err, "cannot import snapshot", "applied", applied, "groupVersion", b.GroupVersion)
+			return &resourcepb.ImportResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: msg + ": " + err.Error()}, nil
+		}
+		log.Info("import conflicts", "applied", applied, "groupVersion", b.GroupVersion, "conflicts", len(conflicts))
+		return &resourcepb.ImportResponse{Conflicts: conflicts, ErrorCode: resourcepb.ErrorCode_Conflict, ErrorMessage: msg}, nil
+	}
+	log.Info("import done", "backends", len(applied))
+	return &resourcepb.ImportResponse{Applied: true}, nil
+}
+
+// importBackend imports the part of the snapshot of a backend, the conflicts
+// are prefixed with the group version of the backend
+func (r *serverproxy) importBackend(ctx context.Context, b backend.BackendSnapshot, dryRun bool) ([]string, error) {
+	gv, _ := schema.ParseGroupVersion(b.GroupVersion)
+	conflicts, err := r.backends[gv].Import(ctx, b.Indices, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range conflicts {
+		conflicts[i] = b.GroupVersion + ": " + c
+	}
+	return conflicts, nil
+}
+
+// getGroupVersions returns the sorted group versions of the registered
+// backends, all backends are returned if no group versions are provided
+func (r *serverproxy) getGroupVersions(groupVersions []string) ([]schema.GroupVersion, error) {
+	gvs := []schema.GroupVersion{}
+	if len(groupVersions) == 0 {
+		for gv := range r.backends {
+			gvs = append(gvs, gv)
+		}
+	}
+	for _, s := range groupVersions {
+		gv, err := schema.ParseGroupVersion(s)
+		if err != nil {
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "invalid group version %s: %s", s, err.Error())
+		}
+		if _, ok := r.backends[gv]; !ok {
+			return nil, backend.NewError(resourcepb.ErrorCode_ValidationFailed, "backend not registered, got: %s", s)
+		}
+		gvs = append(gvs, gv)
+	}
+	sort.Slice(gvs, func(i, j int) bool { return gvs[i].String() < gvs[j].String() })
+	return gvs, nil
+}
@@ -0,0 +1,138 @@
+package serverproxy
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	vxlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vxlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/backend/vlan"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newVLANProxy(t *testing.T) (Proxy, backend.Backend) {
+	be, err := vlan.New(nil, backend.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(&Config{Backends: map[schema.GroupVersion]backend.Backend{vlanv1alpha1.GroupVersion: be}}), be
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	p, be := newVLANProxy(t)
+	index := vlanv1alpha1.BuildVLANIndex(metav1.ObjectMeta{Namespace: "default", Name: "a"}, vlanv1alpha1.VLANIndexSpec{}, vlanv1alpha1.VLANIndexStatus{})
+	b, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := be.CreateIndex(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	claim := vlanv1alpha1.BuildVLANClaim(
+		metav1.ObjectMeta{Namespace: "default", Name: "claim"},
+		vlanv1alpha1.VLANClaimSpec{
+			VLANIndex: corev1.ObjectReference{Namespace: "default", Name: "a"},
+			VLANID:    util.PointerUint16(100),
+		},
+		vlanv1alpha1.VLANClaimStatus{},
+	)
+	claim.AddOwnerLabelsToCR()
+	b, err = json.Marshal(claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := be.Claim(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := p.Export(ctx, &resourcepb.ExportRequest{GroupVersions: []string{"ipam.resource.nephio.org/v1alpha1"}})
+	if err != nil || resp.ErrorCode != resourcepb.ErrorCode_ValidationFailed {
+		t.Errorf("want ValidationFailed for a backend that is not registered, got %v, %v", resp, err)
+	}
+	resp, err = p.Export(ctx, &resourcepb.ExportRequest{})
+	if err != nil || resp.ErrorCode != resourcepb.ErrorCode_NoError {
+		t.Fatalf("export failed: %v, %v", resp, err)
+	}
+	s, err := backend.ParseSnapshot([]byte(resp.Snapshot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Backends) != 1 || s.Backends[0].GroupVersion != vlanv1alpha1.GroupVersion.String() {
+		t.Fatalf("want the vlan backend, got %v", s.Backends)
+	}
+
+	restored, _ := newVLANProxy(t)
+	importResp, err := restored.Import(ctx, &resourcepb.ImportRequest{Snapshot: resp.Snapshot, DryRun: true})
+	if err != nil || importResp.Applied || len(importResp.Conflicts) != 0 {
+		t.Fatalf("dry run failed: %v, %v", importResp, err)
+	}
+	importResp, err = restored.Import(ctx, &resourcepb.ImportRequest{Snapshot: resp.Snapshot})
+	if err != nil || !importResp.Applied {
+		t.Fatalf("import failed: %v, %v", importResp, err)
+	}
+	got, err := restored.Export(ctx, &resourcepb.ExportRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got.Snapshot, `"id": "100"`) {
+		t.Errorf("want vlan 100 in the restored snapshot, got %s", got.Snapshot)
+	}
+
+	importResp, err = restored.Import(ctx, &resourcepb.ImportRequest{Snapshot: strings.Replace(resp.Snapshot, backend.SnapshotKind, "Other", 1)})
+	if err != nil || importResp.ErrorCode != resourcepb.ErrorCode_ValidationFailed {
+		t.Errorf("want ValidationFailed for an unsupported snapshot, got %v, %v", importResp, err)
+	}
+}
+
+// conflictingBackend validates a snapshot without conflicts, but conflicts
+// when the snapshot is applied e.g. due to a claim in between
+type conflictingBackend struct {
+	backend.Backend
+}
+
+func (r *conflictingBackend) Import(ctx context.Context, indices []backend.IndexSnapshot, dryRun bool) ([]string, error) {
+	if dryRun {
+		return nil, nil
+	}
+	return []string{"index default/a: 100 is claimed"}, nil
+}
+
+func TestImportPartial(t *testing.T) {
+	ctx := context.Background()
+	be, err := vlan.New(nil, backend.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := New(&Config{Backends: map[schema.GroupVersion]backend.Backend{
+		vlanv1alpha1.GroupVersion:  be,
+		vxlanv1alpha1.GroupVersion: &conflictingBackend{},
+	}})
+	b, err := json.Marshal(backend.NewSnapshot([]backend.BackendSnapshot{
+		{GroupVersion: vlanv1alpha1.GroupVersion.String()},
+		{GroupVersion: vxlanv1alpha1.GroupVersion.String()},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := p.Import(ctx, &resourcepb.ImportRequest{Snapshot: string(b)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Applied || resp.ErrorCode != resourcepb.ErrorCode_Conflict {
+		t.Errorf("want a conflict, got %v", resp)
+	}
+	if len(resp.Conflicts) != 1 || !strings.HasPrefix(resp.Conflicts[0], vxlanv1alpha1.GroupVersion.String()+": ") {
+		t.Errorf("want the conflict of the vxlan backend, got %v", resp.Conflicts)
+	}
+	if !strings.Contains(resp.ErrorMessage, "applied backends ["+vlanv1alpha1.GroupVersion.String()+"]") {
+		t.Errorf("want the applied vlan backend reported, got %q", resp.ErrorMessage)
+	}
+}
@@ -0,0 +1,44 @@
+package serverproxy
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nokia/k8s-ipam/pkg/backend"
+	"github.com/nokia/k8s-ipam/pkg/meta"
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// GetTree returns the entries of the index in the header as a tree, for the
+// backends of which the entries nest
+func (r *serverproxy) GetTree(ctx context.Context, in *resourcepb.TreeRequest) (*resourcepb.TreeResponse, error) {
+	log := log.FromContext(ctx)
+	gv := meta.GetSchemaGVKFromResourcePbGVK(in.GetHeader().GetGvk()).GroupVersion()
+	be, ok := r.backends[gv]
+	if !ok {
+		err := backend.NewError(resourcepb.ErrorCode_ValidationFailed, "backend not registered, got: %s", gv.String())
+		return &resourcepb.TreeResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
+	}
+	tb, ok := be.(backend.TreeBackend)
+	if !ok {
+		err := backend.NewError(resourcepb.ErrorCode_ValidationFailed, "backend %s has no tree", gv.String())
+		return &resourcepb.TreeResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
+	}
+	ref := corev1.ObjectReference{
+		Namespace: in.GetHeader().GetNsn().GetNamespace(),
+		Name:      in.GetHeader().GetNsn().GetName(),
+	}
+	t, err := tb.Tree(ctx, ref, in.Root, int(in.Depth))
+	if err != nil {
+		log.Error(err, "cannot get tree", "index", ref)
+		return &resourcepb.TreeResponse{ErrorCode: backend.GetErrorCode(err), ErrorMessage: err.Error()}, nil
+	}
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	log.Info("get tree done", "index", ref)
+	return &resourcepb.TreeResponse{Tree: string(b)}, nil
+}
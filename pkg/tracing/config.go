@@ -0,0 +1,128 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterLog    = "log"
+	ExporterMemory = "memory"
+
+	defaultServiceName = "resource-backend"
+)
+
+// Config selects the exporter of the spans
+type Config struct {
+	// Exporter is none, log or memory. The memory exporter keeps the spans
+	// in memory and is meant for tests.
+	Exporter string
+	// ServiceName is the name of the service the spans are reported for
+	ServiceName string
+	// SampleRatio is the ratio of the traces started by this service that are
+	// sampled, traces started by a caller follow the sampling of the caller.
+	// All traces are sampled when unset.
+	SampleRatio float64
+}
+
+// BindFlags registers the flags of the tracing
+func (r *Config) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&r.Exporter, "tracing-exporter", ExporterNone,
+		"The exporter of the tracing spans: none, log or memory.")
+	fs.StringVar(&r.ServiceName, "tracing-service-name", defaultServiceName,
+		"The service name the tracing spans are reported for.")
+	fs.Float64Var(&r.SampleRatio, "tracing-sample-ratio", 1,
+		"The ratio of the traces started by this service that are sampled.")
+}
+
+// Tracing is the tracer provider set up by the config
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+	memory   *tracetest.InMemoryExporter
+}
+
+// Setup sets up the global tracer provider and the trace context propagation
+// selected by the config. No tracer provider is set up for the none exporter
+// such that the spans are not recorded, the trace context of the callers is
+// still propagated.
+func Setup(cfg Config) (*Tracing, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var exporter sdktrace.SpanExporter
+	t := &Tracing{}
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return t, nil
+	case ExporterLog:
+		exporter = NewLogExporter(ctrl.Log.WithName("tracing"))
+	case ExporterMemory:
+		t.memory = tracetest.NewInMemoryExporter()
+		exporter = t.memory
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	sampleRatio := cfg.SampleRatio
+	if sampleRatio <= 0 {
+		sampleRatio = 1
+	}
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	}
+	if t.memory != nil {
+		// the spans are available to the tests as soon as they ended
+		opts = append(opts, sdktrace.WithSyncer(exporter))
+	} else {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	t.provider = sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(t.provider)
+	return t, nil
+}
+
+// Spans returns the spans that ended, only the memory exporter keeps them
+func (r *Tracing) Spans() tracetest.SpanStubs {
+	if r.memory == nil {
+		return nil
+	}
+	return r.memory.GetSpans()
+}
+
+// Shutdown flushes the spans that are not exported yet and stops the tracer
+// provider
+func (r *Tracing) Shutdown(ctx context.Context) error {
+	if r.provider == nil {
+		return nil
+	}
+	return r.provider.Shutdown(ctx)
+}
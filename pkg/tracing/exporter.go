@@ -0,0 +1,55 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewLogExporter returns an exporter that logs every span that ended
+func NewLogExporter(l logr.Logger) sdktrace.SpanExporter {
+	return &logExporter{l: l}
+}
+
+type logExporter struct {
+	l logr.Logger
+}
+
+func (r *logExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, s := range spans {
+		kv := []any{
+			"name", s.Name(),
+			"traceID", s.SpanContext().TraceID().String(),
+			"spanID", s.SpanContext().SpanID().String(),
+			"parentSpanID", s.Parent().SpanID().String(),
+			"duration", s.EndTime().Sub(s.StartTime()).String(),
+			"status", s.Status().Code.String(),
+		}
+		for _, a := range s.Attributes() {
+			kv = append(kv, string(a.Key), a.Value.Emit())
+		}
+		r.l.Info("span", kv...)
+	}
+	return nil
+}
+
+func (r *logExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
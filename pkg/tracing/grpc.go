@@ -0,0 +1,163 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// the trace context is always propagated in the w3c format, independent of
+// the global propagator, such that the client and the server agree
+var propagator = propagation.TraceContext{}
+
+// metadataCarrier adapts the grpc metadata to the text map carrier of the
+// propagator
+type metadataCarrier metadata.MD
+
+func (r metadataCarrier) Get(key string) string {
+	v := metadata.MD(r).Get(key)
+	if len(v) == 0 {
+		return ""
+	}
+	return v[0]
+}
+
+func (r metadataCarrier) Set(key, value string) {
+	metadata.MD(r).Set(key, value)
+}
+
+func (r metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(r))
+	for k := range r {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// inject adds the trace context of the span in the context to the outgoing
+// metadata of the grpc call
+func inject(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// extract returns a context with the remote span of the incoming metadata of
+// the grpc call
+func extract(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return propagator.Extract(ctx, metadataCarrier(md))
+}
+
+// UnaryClientInterceptor propagates the trace context of the caller to the
+// resource api
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(inject(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor propagates the trace context of the caller to the
+// resource api
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(inject(ctx), desc, cc, method, opts...)
+	}
+}
+
+// UnaryServerInterceptor starts a server span for every unary call as a child
+// of the span of the caller
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := otel.Tracer(instrumentationName).Start(extract(ctx), info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(requestAttributes(req)...),
+		)
+		defer span.End()
+		resp, err := handler(ctx, req)
+		if err != nil {
+			SetError(span, err)
+			span.SetAttributes(attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor starts a server span for every stream as a child of
+// the span of the caller
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := otel.Tracer(instrumentationName).Start(extract(ss.Context()), info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+		)
+		defer span.End()
+		err := handler(srv, &tracedStream{ServerStream: ss, ctx: ctx})
+		SetError(span, err)
+		return err
+	}
+}
+
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (r *tracedStream) Context() context.Context {
+	return r.ctx
+}
+
+// ClaimAttributes returns the span attributes that identify the claim
+func ClaimAttributes(h *resourcepb.Header) []attribute.KeyValue {
+	if h == nil {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	if h.GetGvk() != nil {
+		attrs = append(attrs, KindKey.String(h.GetGvk().GetKind()))
+	}
+	if h.GetNsn() != nil {
+		attrs = append(attrs,
+			NamespaceKey.String(h.GetNsn().GetNamespace()),
+			NameKey.String(h.GetNsn().GetName()),
+		)
+	}
+	return attrs
+}
+
+func requestAttributes(req any) []attribute.KeyValue {
+	if r, ok := req.(*resourcepb.ClaimRequest); ok {
+		return ClaimAttributes(r.GetHeader())
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package tracing provides the OpenTelemetry spans of the claim path from the
+// reconcilers through the client proxy and the grpc api to the backend
+// storage.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/nokia/k8s-ipam"
+
+const (
+	KindKey      = attribute.Key("resource.kind")
+	NamespaceKey = attribute.Key("resource.namespace")
+	NameKey      = attribute.Key("resource.name")
+	CacheHitKey  = attribute.Key("clientproxy.cache_hit")
+)
+
+// Start starts a span as a child of the span in the context. The spans are
+// not recorded unless a tracer provider is set up.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// SetError records the error on the span, it is a noop for a nil error
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// End records the error on the span and ends it
+func End(span trace.Span, err error) {
+	SetError(span, err)
+	span.End()
+}
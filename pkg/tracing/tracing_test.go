@@ -0,0 +1,146 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nokia/k8s-ipam/pkg/proto/resourcepb"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/resource.Resource/Claim"
+
+func setupMemory(t *testing.T) *Tracing {
+	t.Helper()
+	tp, err := Setup(Config{Exporter: ExporterMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return tp
+}
+
+// call passes the request through the client and the server interceptors,
+// the outgoing metadata of the client is the incoming metadata of the server
+func call(ctx context.Context, req any, handler grpc.UnaryHandler) error {
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		// the server does not share the span of the client in process
+		srvCtx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := UnaryServerInterceptor()(srvCtx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+	return UnaryClientInterceptor()(ctx, testMethod, req, nil, nil, invoker)
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("span %s not found in %v", name, spans)
+	return tracetest.SpanStub{}
+}
+
+func TestPropagation(t *testing.T) {
+	tp := setupMemory(t)
+
+	req := &resourcepb.ClaimRequest{
+		Header: &resourcepb.Header{
+			Gvk: &resourcepb.GVK{Kind: "IPClaim"},
+			Nsn: &resourcepb.NSN{Namespace: "default", Name: "claim"},
+		},
+	}
+	ctx, span := Start(context.Background(), "reconcile")
+	err := call(ctx, req, func(ctx context.Context, req any) (any, error) {
+		_, span := Start(ctx, "apply")
+		span.End()
+		return nil, nil
+	})
+	span.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := tp.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("want 3 spans, got %d", len(spans))
+	}
+	reconcile := findSpan(t, spans, "reconcile")
+	server := findSpan(t, spans, testMethod)
+	apply := findSpan(t, spans, "apply")
+	if server.SpanContext.TraceID() != reconcile.SpanContext.TraceID() {
+		t.Errorf("want trace %s, got %s", reconcile.SpanContext.TraceID(), server.SpanContext.TraceID())
+	}
+	if !server.Parent.IsRemote() || server.Parent.SpanID() != reconcile.SpanContext.SpanID() {
+		t.Errorf("want remote parent %s, got %s", reconcile.SpanContext.SpanID(), server.Parent.SpanID())
+	}
+	if apply.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("want parent %s, got %s", server.SpanContext.SpanID(), apply.Parent.SpanID())
+	}
+	attrs := map[string]string{}
+	for _, a := range server.Attributes {
+		attrs[string(a.Key)] = a.Value.Emit()
+	}
+	if attrs[string(KindKey)] != "IPClaim" || attrs[string(NameKey)] != "claim" {
+		t.Errorf("want claim attributes, got %v", attrs)
+	}
+}
+
+func TestServerError(t *testing.T) {
+	tp := setupMemory(t)
+
+	err := call(context.Background(), nil, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(grpccodes.Unavailable, "index not ready")
+	})
+	if status.Code(err) != grpccodes.Unavailable {
+		t.Fatalf("want %s, got %v", grpccodes.Unavailable, err)
+	}
+	server := findSpan(t, tp.Spans(), testMethod)
+	if server.Status.Code != codes.Error {
+		t.Errorf("want status %s, got %s", codes.Error, server.Status.Code)
+	}
+	// the call without a caller span starts a new trace
+	if server.Parent.IsValid() {
+		t.Errorf("want no parent, got %s", server.Parent.SpanID())
+	}
+}
+
+func TestSetup(t *testing.T) {
+	tp, err := Setup(Config{Exporter: ExporterNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tp.Spans() != nil {
+		t.Errorf("want no spans, got %v", tp.Spans())
+	}
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Error(err)
+	}
+	if _, err := Setup(Config{Exporter: "jaeger"}); err == nil {
+		t.Error("want error for unknown exporter")
+	}
+}
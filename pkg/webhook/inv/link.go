@@ -0,0 +1,63 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package inv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/webhook"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-inv-nephio-org-v1alpha1-link,mutating=false,failurePolicy=fail,sideEffects=None,groups=inv.nephio.org,resources=links,verbs=create;update,versions=v1alpha1,name=vlink.inv.nephio.org,admissionReviewVersions=v1
+
+type linkValidator struct{}
+
+var _ admission.CustomValidator = &linkValidator{}
+
+func (r *linkValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*invv1alpha1.Link)
+	if !ok {
+		return nil, fmt.Errorf("expected a Link, got: %v", reflect.TypeOf(obj))
+	}
+	return nil, webhook.ToInvalid(cr, validateLink(cr))
+}
+
+func (r *linkValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*invv1alpha1.Link)
+	if !ok {
+		return nil, fmt.Errorf("expected a Link, got: %v", reflect.TypeOf(newObj))
+	}
+	return nil, webhook.ToInvalid(cr, validateLink(cr))
+}
+
+func (r *linkValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateLink(cr *invv1alpha1.Link) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if err := cr.Validate(); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "endpoints"), cr.Spec.Endpoints, err.Error()))
+	}
+	return allErrs
+}
@@ -0,0 +1,87 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package inv
+
+import (
+	"context"
+	"testing"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildLinkEndpoint(topology, nodeName, interfaceName string) invv1alpha1.LinkEndpointSpec {
+	return invv1alpha1.LinkEndpointSpec{
+		Topology: topology,
+		EndpointSpec: invv1alpha1.EndpointSpec{
+			NodeName:      nodeName,
+			InterfaceName: interfaceName,
+		},
+	}
+}
+
+func TestLinkValidateCreate(t *testing.T) {
+	cases := map[string]struct {
+		endpoints []invv1alpha1.LinkEndpointSpec
+		expectErr bool
+	}{
+		"Valid": {
+			endpoints: []invv1alpha1.LinkEndpointSpec{
+				buildLinkEndpoint("t1", "n1", "e1"),
+				buildLinkEndpoint("t1", "n2", "e1"),
+			},
+		},
+		"SameEndpoint": {
+			endpoints: []invv1alpha1.LinkEndpointSpec{
+				buildLinkEndpoint("t1", "n1", "e1"),
+				buildLinkEndpoint("t1", "n1", "e1"),
+			},
+			expectErr: true,
+		},
+		"SameEndpointOtherTopology": {
+			endpoints: []invv1alpha1.LinkEndpointSpec{
+				buildLinkEndpoint("t1", "n1", "e1"),
+				buildLinkEndpoint("t2", "n1", "e1"),
+			},
+		},
+		"NoTopology": {
+			endpoints: []invv1alpha1.LinkEndpointSpec{
+				buildLinkEndpoint("t1", "n1", "e1"),
+				buildLinkEndpoint("", "n2", "e1"),
+			},
+			expectErr: true,
+		},
+		"SingleEndpoint": {
+			endpoints: []invv1alpha1.LinkEndpointSpec{
+				buildLinkEndpoint("t1", "n1", "e1"),
+			},
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := invv1alpha1.BuildLink(metav1.ObjectMeta{Name: "a", Namespace: "default"}, invv1alpha1.LinkSpec{Endpoints: tc.endpoints}, invv1alpha1.LinkStatus{})
+			_, err := (&linkValidator{}).ValidateCreate(context.Background(), cr)
+			if tc.expectErr && err == nil {
+				t.Errorf("%s expected error, got nil", name)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+			}
+		})
+	}
+}
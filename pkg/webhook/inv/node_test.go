@@ -0,0 +1,67 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package inv
+
+import (
+	"context"
+	"testing"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeValidateCreate(t *testing.T) {
+	cases := map[string]struct {
+		labels    map[string]string
+		spec      invv1alpha1.NodeSpec
+		expectErr bool
+	}{
+		"Valid": {
+			labels: map[string]string{invv1alpha1.NephioTopologyKey: "default"},
+			spec:   invv1alpha1.NodeSpec{Provider: "srl.nokia.com"},
+		},
+		"NoProvider": {
+			spec:      invv1alpha1.NodeSpec{},
+			expectErr: true,
+		},
+		"TopologyLabelMismatch": {
+			labels:    map[string]string{invv1alpha1.NephioTopologyKey: "other"},
+			spec:      invv1alpha1.NodeSpec{Provider: "srl.nokia.com"},
+			expectErr: true,
+		},
+		"TopologyUserDefinedLabel": {
+			spec: invv1alpha1.NodeSpec{
+				Provider:          "srl.nokia.com",
+				UserDefinedLabels: resourcev1alpha1.UserDefinedLabels{Labels: map[string]string{invv1alpha1.NephioTopologyKey: "default"}},
+			},
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := invv1alpha1.BuildNode(metav1.ObjectMeta{Name: "a", Namespace: "default", Labels: tc.labels}, tc.spec, invv1alpha1.NodeStatus{})
+			_, err := (&nodeValidator{}).ValidateCreate(context.Background(), cr)
+			if tc.expectErr && err == nil {
+				t.Errorf("%s expected error, got nil", name)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend/ipam"
+	"github.com/nokia/k8s-ipam/pkg/webhook"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-ipam-resource-nephio-org-v1alpha1-ipclaim,mutating=false,failurePolicy=fail,sideEffects=None,groups=ipam.resource.nephio.org,resources=ipclaims,verbs=create;update,versions=v1alpha1,name=vipclaim.ipam.resource.nephio.org,admissionReviewVersions=v1
+
+type ipClaimValidator struct{}
+
+var _ admission.CustomValidator = &ipClaimValidator{}
+
+func (r *ipClaimValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*ipamv1alpha1.IPClaim)
+	if !ok {
+		return nil, fmt.Errorf("expected an IPClaim, got: %v", reflect.TypeOf(obj))
+	}
+	return nil, webhook.ToInvalid(cr, validateIPClaim(cr))
+}
+
+func (r *ipClaimValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCr, ok := oldObj.(*ipamv1alpha1.IPClaim)
+	if !ok {
+		return nil, fmt.Errorf("expected an IPClaim, got: %v", reflect.TypeOf(oldObj))
+	}
+	cr, ok := newObj.(*ipamv1alpha1.IPClaim)
+	if !ok {
+		return nil, fmt.Errorf("expected an IPClaim, got: %v", reflect.TypeOf(newObj))
+	}
+	allErrs := validateIPClaim(cr)
+	allErrs = append(allErrs, validateIPClaimImmutable(oldCr, cr)...)
+	return nil, webhook.ToInvalid(cr, allErrs)
+}
+
+func (r *ipClaimValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateIPClaim(cr *ipamv1alpha1.IPClaim) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if msg := ipam.ValidateClaim(cr); msg != "" {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec"), cr.Spec, msg))
+	}
+	return allErrs
+}
+
+// validateIPClaimImmutable validates that the fields that determine the
+// claimed prefix in the backend are not changed
+func validateIPClaimImmutable(oldCr, cr *ipamv1alpha1.IPClaim) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("kind"), oldCr.Spec.Kind, cr.Spec.Kind)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("networkInstance"), oldCr.Spec.NetworkInstance, cr.Spec.NetworkInstance)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("addressFamily"), oldCr.Spec.AddressFamily, cr.Spec.AddressFamily)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("prefix"), oldCr.Spec.Prefix, cr.Spec.Prefix)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("prefixLength"), oldCr.Spec.PrefixLength, cr.Spec.PrefixLength)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("index"), oldCr.Spec.Index, cr.Spec.Index)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("createPrefix"), oldCr.Spec.CreatePrefix, cr.Spec.CreatePrefix)...)
+	return allErrs
+}
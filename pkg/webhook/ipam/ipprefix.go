@@ -0,0 +1,97 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend/ipam"
+	"github.com/nokia/k8s-ipam/pkg/iputil"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	"github.com/nokia/k8s-ipam/pkg/webhook"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-ipam-resource-nephio-org-v1alpha1-ipprefix,mutating=false,failurePolicy=fail,sideEffects=None,groups=ipam.resource.nephio.org,resources=ipprefixes,verbs=create;update,versions=v1alpha1,name=vipprefix.ipam.resource.nephio.org,admissionReviewVersions=v1
+
+type ipPrefixValidator struct{}
+
+var _ admission.CustomValidator = &ipPrefixValidator{}
+
+func (r *ipPrefixValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*ipamv1alpha1.IPPrefix)
+	if !ok {
+		return nil, fmt.Errorf("expected an IPPrefix, got: %v", reflect.TypeOf(obj))
+	}
+	return nil, webhook.ToInvalid(cr, validateIPPrefix(cr))
+}
+
+func (r *ipPrefixValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCr, ok := oldObj.(*ipamv1alpha1.IPPrefix)
+	if !ok {
+		return nil, fmt.Errorf("expected an IPPrefix, got: %v", reflect.TypeOf(oldObj))
+	}
+	cr, ok := newObj.(*ipamv1alpha1.IPPrefix)
+	if !ok {
+		return nil, fmt.Errorf("expected an IPPrefix, got: %v", reflect.TypeOf(newObj))
+	}
+	allErrs := validateIPPrefix(cr)
+	specPath := field.NewPath("spec")
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("kind"), oldCr.Spec.Kind, cr.Spec.Kind)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("networkInstance"), oldCr.Spec.NetworkInstance, cr.Spec.NetworkInstance)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("prefix"), oldCr.Spec.Prefix, cr.Spec.Prefix)...)
+	return nil, webhook.ToInvalid(cr, allErrs)
+}
+
+func (r *ipPrefixValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateIPPrefix validates the prefix as the claim the prefix is
+// transformed into by the client proxy
+func validateIPPrefix(cr *ipamv1alpha1.IPPrefix) field.ErrorList {
+	allErrs := field.ErrorList{}
+	prefixPath := field.NewPath("spec", "prefix")
+	pi, err := iputil.New(cr.Spec.Prefix)
+	if err != nil {
+		return append(allErrs, field.Invalid(prefixPath, cr.Spec.Prefix, err.Error()))
+	}
+	claim := ipamv1alpha1.BuildIPClaim(
+		cr.ObjectMeta,
+		ipamv1alpha1.IPClaimSpec{
+			Kind:            cr.Spec.Kind,
+			NetworkInstance: cr.Spec.NetworkInstance,
+			Prefix:          &cr.Spec.Prefix,
+			PrefixLength:    util.PointerUint8(pi.GetPrefixLength().Int()),
+			CreatePrefix:    pointer.Bool(true),
+			ClaimLabels: resourcev1alpha1.ClaimLabels{
+				UserDefinedLabels: cr.Spec.UserDefinedLabels,
+			},
+		},
+		ipamv1alpha1.IPClaimStatus{})
+	if msg := ipam.ValidateClaim(claim); msg != "" {
+		allErrs = append(allErrs, field.Invalid(prefixPath, cr.Spec.Prefix, msg))
+	}
+	return allErrs
+}
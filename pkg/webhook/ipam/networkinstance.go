@@ -0,0 +1,103 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend/ipam"
+	"github.com/nokia/k8s-ipam/pkg/iputil"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	"github.com/nokia/k8s-ipam/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-ipam-resource-nephio-org-v1alpha1-networkinstance,mutating=false,failurePolicy=fail,sideEffects=None,groups=ipam.resource.nephio.org,resources=networkinstances,verbs=create;update,versions=v1alpha1,name=vnetworkinstance.ipam.resource.nephio.org,admissionReviewVersions=v1
+
+type networkInstanceValidator struct{}
+
+var _ admission.CustomValidator = &networkInstanceValidator{}
+
+func (r *networkInstanceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*ipamv1alpha1.NetworkInstance)
+	if !ok {
+		return nil, fmt.Errorf("expected a NetworkInstance, got: %v", reflect.TypeOf(obj))
+	}
+	return nil, webhook.ToInvalid(cr, validateNetworkInstance(cr))
+}
+
+func (r *networkInstanceValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*ipamv1alpha1.NetworkInstance)
+	if !ok {
+		return nil, fmt.Errorf("expected a NetworkInstance, got: %v", reflect.TypeOf(newObj))
+	}
+	// prefixes can be added and removed from a network instance
+	return nil, webhook.ToInvalid(cr, validateNetworkInstance(cr))
+}
+
+func (r *networkInstanceValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateNetworkInstance validates the prefixes as the aggregate claims the
+// prefixes are transformed into by the client proxy
+func validateNetworkInstance(cr *ipamv1alpha1.NetworkInstance) field.ErrorList {
+	allErrs := field.ErrorList{}
+	prefixes := map[string]struct{}{}
+	for idx, prefix := range cr.Spec.Prefixes {
+		prefixPath := field.NewPath("spec", "prefixes").Index(idx).Child("prefix")
+		pi, err := iputil.New(prefix.Prefix)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(prefixPath, prefix.Prefix, err.Error()))
+			continue
+		}
+		if _, ok := prefixes[pi.GetIPPrefix().String()]; ok {
+			allErrs = append(allErrs, field.Duplicate(prefixPath, prefix.Prefix))
+			continue
+		}
+		prefixes[pi.GetIPPrefix().String()] = struct{}{}
+
+		claim := ipamv1alpha1.BuildIPClaim(
+			cr.ObjectMeta,
+			ipamv1alpha1.IPClaimSpec{
+				Kind: ipamv1alpha1.PrefixKindAggregate,
+				NetworkInstance: corev1.ObjectReference{
+					Name:      cr.GetName(),
+					Namespace: cr.GetNamespace(),
+				},
+				Prefix:       &prefix.Prefix,
+				PrefixLength: util.PointerUint8(pi.GetPrefixLength().Int()),
+				CreatePrefix: pointer.Bool(true),
+				ClaimLabels: resourcev1alpha1.ClaimLabels{
+					UserDefinedLabels: prefix.UserDefinedLabels,
+				},
+			},
+			ipamv1alpha1.IPClaimStatus{})
+		if msg := ipam.ValidateClaim(claim); msg != "" {
+			allErrs = append(allErrs, field.Invalid(prefixPath, prefix.Prefix, msg))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Nephio Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"testing"
+
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func buildIPClaim(spec ipamv1alpha1.IPClaimSpec) *ipamv1alpha1.IPClaim {
+	spec.NetworkInstance = corev1.ObjectReference{Name: "vpc-1"}
+	return ipamv1alpha1.BuildIPClaim(metav1.ObjectMeta{Name: "a", Namespace: "default"}, spec, ipamv1alpha1.IPClaimStatus{})
+}
+
+func TestIPClaimValidateCreate(t *testing.T) {
+	cases := map[string]struct {
+		spec      ipamv1alpha1.IPClaimSpec
+		expectErr bool
+	}{
+		"DynamicAddress": {
+			spec: ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindNetwork},
+		},
+		"DynamicPool": {
+			spec: ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindPool, PrefixLength: util.PointerUint8(24), CreatePrefix: pointer.Bool(true)},
+		},
+		"DynamicPoolWithoutPrefixLength": {
+			spec:      ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindPool},
+			expectErr: true,
+		},
+		"DynamicPoolCreatePrefixWithoutPrefixLength": {
+			spec:      ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindPool, CreatePrefix: pointer.Bool(true)},
+			expectErr: true,
+		},
+		"DynamicAggregate": {
+			spec:      ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindAggregate, PrefixLength: util.PointerUint8(24), CreatePrefix: pointer.Bool(true)},
+			expectErr: true,
+		},
+		"StaticNetwork": {
+			spec: ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindNetwork, Prefix: pointer.String("10.0.0.1/24")},
+		},
+		"MalformedPrefix": {
+			spec:      ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindNetwork, Prefix: pointer.String("10.0.0.300/24")},
+			expectErr: true,
+		},
+		"StaticPoolNetAddress": {
+			spec:      ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindPool, Prefix: pointer.String("10.0.0.1/24")},
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&ipClaimValidator{}).ValidateCreate(context.Background(), buildIPClaim(tc.spec))
+			if tc.expectErr && err == nil {
+				t.Errorf("%s expected error, got nil", name)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+			}
+		})
+	}
+}
+
+func TestIPClaimValidateUpdate(t *testing.T) {
+	oldCr := buildIPClaim(ipamv1alpha1.IPClaimSpec{Kind: ipamv1alpha1.PrefixKindNetwork, Prefix: pointer.String("10.0.0.1/24")})
+	cases := map[string]struct {
+		mutate    func(cr *ipamv1alpha1.IPClaim)
+		expectErr bool
+	}{
+		"Labels": {
+			mutate: func(cr *ipamv1alpha1.IPClaim) { cr.Spec.Labels = map[string]string{"a": "b"} },
+		},
+		"Prefix": {
+			mutate:    func(cr *ipamv1alpha1.IPClaim) { cr.Spec.Prefix = pointer.String("10.0.0.2/24") },
+			expectErr: true,
+		},
+		"Kind": {
+			mutate:    func(cr *ipamv1alpha1.IPClaim) { cr.Spec.Kind = ipamv1alpha1.PrefixKindLoopback },
+			expectErr: true,
+		},
+		"NetworkInstance": {
+			mutate:    func(cr *ipamv1alpha1.IPClaim) { cr.Spec.NetworkInstance.Name = "vpc-2" },
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := oldCr.DeepCopy()
+			tc.mutate(cr)
+			_, err := (&ipClaimValidator{}).ValidateUpdate(context.Background(), oldCr, cr)
+			if tc.expectErr && err == nil {
+				t.Errorf("%s expected error, got nil", name)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+			}
+		})
+	}
+}
+
+func TestIPPrefixValidateCreate(t *testing.T) {
+	cases := map[string]struct {
+		kind      ipamv1alpha1.PrefixKind
+		prefix    string
+		expectErr bool
+	}{
+		"Network": {
+			kind:   ipamv1alpha1.PrefixKindNetwork,
+			prefix: "10.0.0.1/24",
+		},
+		"Aggregate": {
+			kind:   ipamv1alpha1.PrefixKindAggregate,
+			prefix: "10.0.0.0/8",
+		},
+		"AggregateAddress": {
+			kind:      ipamv1alpha1.PrefixKindAggregate,
+			prefix:    "10.0.0.1/32",
+			expectErr: true,
+		},
+		"Malformed": {
+			kind:      ipamv1alpha1.PrefixKindNetwork,
+			prefix:    "10.0.0/24",
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := ipamv1alpha1.BuildIPPrefix(
+				metav1.ObjectMeta{Name: "a", Namespace: "default"},
+				ipamv1alpha1.IPPrefixSpec{Kind: tc.kind, Prefix: tc.prefix},
+				ipamv1alpha1.IPPrefixStatus{})
+			_, err := (&ipPrefixValidator{}).ValidateCreate(context.Background(), cr)
+			if tc.expectErr && err == nil {
+				t.Errorf("%s expected error, got nil", name)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+			}
+		})
+	}
+}
+
+func TestNetworkInstanceValidateCreate(t *testing.T) {
+	cases := map[string]struct {
+		prefixes  []string
+		expectErr bool
+	}{
+		"Valid": {
+			prefixes: []string{"10.0.0.0/8", "2000::/64"},
+		},
+		"Duplicate": {
+			prefixes:  []string{"10.0.0.0/8", "10.0.0.0/8"},
+			expectErr: true,
+		},
+		"Address": {
+			prefixes:  []string{"10.0.0.1/32"},
+			expectErr: true,
+		},
+		"Malformed": {
+			prefixes:  []string{"10.0.0.0"},
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			prefixes := []ipamv1alpha1.Prefix{}
+			for _, p := range tc.prefixes {
+				prefixes = append(prefixes, ipamv1alpha1.Prefix{Prefix: p})
+			}
+			cr := ipamv1alpha1.BuildNetworkInstance(
+				metav1.ObjectMeta{Name: "vpc-1", Namespace: "default"},
+				ipamv1alpha1.NetworkInstanceSpec{Prefixes: prefixes},
+				ipamv1alpha1.NetworkInstanceStatus{})
+			_, err := (&networkInstanceValidator{}).ValidateCreate(context.Background(), cr)
+			if tc.expectErr && err == nil {
+				t.Errorf("%s expected error, got nil", name)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+			}
+		})
+	}
+}
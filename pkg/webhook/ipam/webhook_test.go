@@ -0,0 +1,159 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ipamv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/ipam/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// TestWebhooks runs the webhooks against an api server, it requires the envtest
+// binaries, e.g. KUBEBUILDER_ASSETS as set by make test
+func TestWebhooks(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS not set, skipping envtest")
+	}
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "..", "config", "webhook")},
+		},
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("cannot start envtest: %s", err.Error())
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("cannot stop envtest: %s", err.Error())
+		}
+	}()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot add scheme: %s", err.Error())
+	}
+	opts := &testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    opts.LocalServingHost,
+			Port:    opts.LocalServingPort,
+			CertDir: opts.LocalServingCertDir,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("cannot create manager: %s", err.Error())
+	}
+	if err := SetupWebhookWithManager(mgr); err != nil {
+		t.Fatalf("cannot setup webhooks: %s", err.Error())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("cannot start manager: %s", err.Error())
+		}
+	}()
+	if err := waitForWebhookServer(opts); err != nil {
+		t.Fatalf("webhook server not ready: %s", err.Error())
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err.Error())
+	}
+
+	// invalid objects are rejected
+	invalid := []client.Object{
+		ipamv1alpha1.BuildIPClaim(
+			metav1.ObjectMeta{Name: "dynamic-aggregate", Namespace: "default"},
+			ipamv1alpha1.IPClaimSpec{
+				Kind:            ipamv1alpha1.PrefixKindAggregate,
+				NetworkInstance: corev1.ObjectReference{Name: "vpc-1"},
+			},
+			ipamv1alpha1.IPClaimStatus{}),
+		ipamv1alpha1.BuildIPPrefix(
+			metav1.ObjectMeta{Name: "aggregate-address", Namespace: "default"},
+			ipamv1alpha1.IPPrefixSpec{
+				Kind:            ipamv1alpha1.PrefixKindAggregate,
+				NetworkInstance: corev1.ObjectReference{Name: "vpc-1"},
+				Prefix:          "10.0.0.1/32",
+			},
+			ipamv1alpha1.IPPrefixStatus{}),
+		ipamv1alpha1.BuildNetworkInstance(
+			metav1.ObjectMeta{Name: "duplicate", Namespace: "default"},
+			ipamv1alpha1.NetworkInstanceSpec{Prefixes: []ipamv1alpha1.Prefix{{Prefix: "10.0.0.0/8"}, {Prefix: "10.0.0.0/8"}}},
+			ipamv1alpha1.NetworkInstanceStatus{}),
+	}
+	for _, o := range invalid {
+		if err := c.Create(ctx, o); err == nil {
+			t.Errorf("expected %s %s to be rejected", o.GetObjectKind().GroupVersionKind().Kind, o.GetName())
+		}
+	}
+
+	// immutable fields cannot be changed
+	claim := ipamv1alpha1.BuildIPClaim(
+		metav1.ObjectMeta{Name: "static", Namespace: "default"},
+		ipamv1alpha1.IPClaimSpec{
+			Kind:            ipamv1alpha1.PrefixKindNetwork,
+			NetworkInstance: corev1.ObjectReference{Name: "vpc-1"},
+			Prefix:          pointer.String("10.0.0.1/24"),
+		},
+		ipamv1alpha1.IPClaimStatus{})
+	if err := c.Create(ctx, claim); err != nil {
+		t.Fatalf("cannot create claim: %s", err.Error())
+	}
+	claim.Spec.Prefix = pointer.String("10.0.0.2/24")
+	if err := c.Update(ctx, claim); err == nil {
+		t.Errorf("expected the prefix update to be rejected")
+	}
+}
+
+func waitForWebhookServer(opts *envtest.WebhookInstallOptions) error {
+	addr := net.JoinHostPort(opts.LocalServingHost, fmt.Sprintf("%d", opts.LocalServingPort))
+	dialer := &net.Dialer{Timeout: time.Second}
+	var err error
+	for i := 0; i < 20; i++ {
+		var conn *tls.Conn
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{InsecureSkipVerify: true}) // nolint:gosec
+		if err == nil {
+			return conn.Close()
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return err
+}
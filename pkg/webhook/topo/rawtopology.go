@@ -0,0 +1,64 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/webhook"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-topo-nephio-org-v1alpha1-rawtopology,mutating=false,failurePolicy=fail,sideEffects=None,groups=topo.nephio.org,resources=rawtopologies,verbs=create;update,versions=v1alpha1,name=vrawtopology.topo.nephio.org,admissionReviewVersions=v1
+
+type rawTopologyValidator struct{}
+
+var _ admission.CustomValidator = &rawTopologyValidator{}
+
+func (r *rawTopologyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*topov1alpha1.RawTopology)
+	if !ok {
+		return nil, fmt.Errorf("expected a RawTopology, got: %v", reflect.TypeOf(obj))
+	}
+	return nil, webhook.ToInvalid(cr, validateRawTopology(cr))
+}
+
+func (r *rawTopologyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*topov1alpha1.RawTopology)
+	if !ok {
+		return nil, fmt.Errorf("expected a RawTopology, got: %v", reflect.TypeOf(newObj))
+	}
+	return nil, webhook.ToInvalid(cr, validateRawTopology(cr))
+}
+
+func (r *rawTopologyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateRawTopology(cr *topov1alpha1.RawTopology) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if err := cr.Validate(); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec"), cr.Spec, err.Error()))
+	}
+	allErrs = append(allErrs, webhook.ValidateLabels(field.NewPath("spec", "labels"), cr.Spec.Labels)...)
+	return allErrs
+}
@@ -0,0 +1,97 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"testing"
+
+	invv1alpha1 "github.com/nokia/k8s-ipam/apis/inv/v1alpha1"
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+)
+
+func buildLinkSpec(eps ...string) invv1alpha1.LinkSpec {
+	l := invv1alpha1.LinkSpec{}
+	for i := 0; i+1 < len(eps); i += 2 {
+		l.Endpoints = append(l.Endpoints, invv1alpha1.LinkEndpointSpec{
+			EndpointSpec: invv1alpha1.EndpointSpec{NodeName: eps[i], InterfaceName: eps[i+1]},
+		})
+	}
+	return l
+}
+
+func TestRawTopologyValidateCreate(t *testing.T) {
+	nodes := map[string]invv1alpha1.NodeSpec{
+		"n1": {Provider: "srl.nokia.com"},
+		"n2": {Provider: "srl.nokia.com"},
+	}
+	cases := map[string]struct {
+		spec      topov1alpha1.RawTopologySpec
+		expectErr bool
+	}{
+		"Valid": {
+			spec: topov1alpha1.RawTopologySpec{
+				Nodes: nodes,
+				Links: []invv1alpha1.LinkSpec{buildLinkSpec("n1", "e1", "n2", "e1")},
+			},
+		},
+		"UnknownNode": {
+			spec: topov1alpha1.RawTopologySpec{
+				Nodes: nodes,
+				Links: []invv1alpha1.LinkSpec{buildLinkSpec("n1", "e1", "n3", "e1")},
+			},
+			expectErr: true,
+		},
+		"DuplicateEndpoint": {
+			spec: topov1alpha1.RawTopologySpec{
+				Nodes: nodes,
+				Links: []invv1alpha1.LinkSpec{
+					buildLinkSpec("n1", "e1", "n2", "e1"),
+					buildLinkSpec("n1", "e1", "n2", "e2"),
+				},
+			},
+			expectErr: true,
+		},
+		"SingleEndpoint": {
+			spec: topov1alpha1.RawTopologySpec{
+				Nodes: nodes,
+				Links: []invv1alpha1.LinkSpec{buildLinkSpec("n1", "e1")},
+			},
+			expectErr: true,
+		},
+		"NodeWithoutProvider": {
+			spec: topov1alpha1.RawTopologySpec{
+				Nodes: map[string]invv1alpha1.NodeSpec{"n1": {}},
+			},
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &topov1alpha1.RawTopology{Spec: tc.spec}
+			cr.SetName("a")
+			cr.SetNamespace("default")
+			_, err := (&rawTopologyValidator{}).ValidateCreate(context.Background(), cr)
+			if tc.expectErr && err == nil {
+				t.Errorf("%s expected error, got nil", name)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+			}
+		})
+	}
+}
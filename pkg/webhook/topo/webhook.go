@@ -0,0 +1,35 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package topo
+
+import (
+	topov1alpha1 "github.com/nokia/k8s-ipam/apis/topo/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// SetupWebhookWithManager registers the validating webhooks of the topology
+// resources with the manager
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// register scheme
+	if err := topov1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&topov1alpha1.RawTopology{}).
+		WithValidator(&rawTopologyValidator{}).
+		Complete()
+}
@@ -0,0 +1,47 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webhook
+
+import (
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ValidateImmutable returns an error if the value of the field changed
+func ValidateImmutable(path *field.Path, oldValue, newValue any) field.ErrorList {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(path, "field is immutable")}
+}
+
+// ValidateLabels returns an error for every label with an invalid key or value
+func ValidateLabels(path *field.Path, labels map[string]string) field.ErrorList {
+	return metav1validation.ValidateLabels(labels, path)
+}
+
+// ToInvalid returns an invalid api error for the object, nil if there are no errors
+func ToInvalid(o client.Object, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(o.GetObjectKind().GroupVersionKind().GroupKind(), o.GetName(), allErrs)
+}
@@ -0,0 +1,82 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/backend/vlan"
+	"github.com/nokia/k8s-ipam/pkg/webhook"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-vlan-resource-nephio-org-v1alpha1-vlanclaim,mutating=false,failurePolicy=fail,sideEffects=None,groups=vlan.resource.nephio.org,resources=vlanclaims,verbs=create;update,versions=v1alpha1,name=vvlanclaim.vlan.resource.nephio.org,admissionReviewVersions=v1
+
+type vlanClaimValidator struct{}
+
+var _ admission.CustomValidator = &vlanClaimValidator{}
+
+func (r *vlanClaimValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*vlanv1alpha1.VLANClaim)
+	if !ok {
+		return nil, fmt.Errorf("expected a VLANClaim, got: %v", reflect.TypeOf(obj))
+	}
+	return nil, webhook.ToInvalid(cr, validateVLANClaim(cr))
+}
+
+func (r *vlanClaimValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCr, ok := oldObj.(*vlanv1alpha1.VLANClaim)
+	if !ok {
+		return nil, fmt.Errorf("expected a VLANClaim, got: %v", reflect.TypeOf(oldObj))
+	}
+	cr, ok := newObj.(*vlanv1alpha1.VLANClaim)
+	if !ok {
+		return nil, fmt.Errorf("expected a VLANClaim, got: %v", reflect.TypeOf(newObj))
+	}
+	allErrs := validateVLANClaim(cr)
+	allErrs = append(allErrs, validateVLANClaimImmutable(oldCr, cr)...)
+	return nil, webhook.ToInvalid(cr, allErrs)
+}
+
+func (r *vlanClaimValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateVLANClaim(cr *vlanv1alpha1.VLANClaim) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if msg := vlan.ValidateClaim(cr); msg != "" {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec"), cr.Spec, msg))
+	}
+	allErrs = append(allErrs, webhook.ValidateLabels(field.NewPath("spec", "labels"), cr.Spec.Labels)...)
+	return allErrs
+}
+
+// validateVLANClaimImmutable validates that the fields that determine the
+// claimed vlans in the backend are not changed
+func validateVLANClaimImmutable(oldCr, cr *vlanv1alpha1.VLANClaim) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("vlanIndex"), oldCr.Spec.VLANIndex, cr.Spec.VLANIndex)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("vlanID"), oldCr.Spec.VLANID, cr.Spec.VLANID)...)
+	allErrs = append(allErrs, webhook.ValidateImmutable(specPath.Child("range"), oldCr.Spec.VLANRange, cr.Spec.VLANRange)...)
+	return allErrs
+}
@@ -0,0 +1,123 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"testing"
+
+	resourcev1alpha1 "github.com/nokia/k8s-ipam/apis/resource/common/v1alpha1"
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/utils/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func buildVLANClaim(spec vlanv1alpha1.VLANClaimSpec) *vlanv1alpha1.VLANClaim {
+	spec.VLANIndex = corev1.ObjectReference{Name: "index-1"}
+	return vlanv1alpha1.BuildVLANClaim(metav1.ObjectMeta{Name: "a", Namespace: "default"}, spec, vlanv1alpha1.VLANClaimStatus{})
+}
+
+func TestVLANClaimValidateCreate(t *testing.T) {
+	cases := map[string]struct {
+		spec      vlanv1alpha1.VLANClaimSpec
+		expectErr bool
+	}{
+		"Dynamic": {
+			spec: vlanv1alpha1.VLANClaimSpec{},
+		},
+		"Static": {
+			spec: vlanv1alpha1.VLANClaimSpec{VLANID: util.PointerUint16(100)},
+		},
+		"StaticReserved": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANID: util.PointerUint16(4095)},
+			expectErr: true,
+		},
+		"StaticOutOfRange": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANID: util.PointerUint16(5000)},
+			expectErr: true,
+		},
+		"StaticAndRange": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANID: util.PointerUint16(100), VLANRange: pointer.String("10")},
+			expectErr: true,
+		},
+		"Size": {
+			spec: vlanv1alpha1.VLANClaimSpec{VLANRange: pointer.String("10")},
+		},
+		"SizeZero": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANRange: pointer.String("0")},
+			expectErr: true,
+		},
+		"SizeTooBig": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANRange: pointer.String("4094")},
+			expectErr: true,
+		},
+		"Range": {
+			spec: vlanv1alpha1.VLANClaimSpec{VLANRange: pointer.String("100:199")},
+		},
+		"RangeStartReserved": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANRange: pointer.String("1:10")},
+			expectErr: true,
+		},
+		"RangeEndOutOfRange": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANRange: pointer.String("4000:70000")},
+			expectErr: true,
+		},
+		"RangeEndBeforeStart": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANRange: pointer.String("200:100")},
+			expectErr: true,
+		},
+		"RangeMalformed": {
+			spec:      vlanv1alpha1.VLANClaimSpec{VLANRange: pointer.String("10:20:30")},
+			expectErr: true,
+		},
+		"InvalidLabel": {
+			spec: vlanv1alpha1.VLANClaimSpec{ClaimLabels: resourcev1alpha1.ClaimLabels{
+				UserDefinedLabels: resourcev1alpha1.UserDefinedLabels{Labels: map[string]string{"a b": "c"}},
+			}},
+			expectErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&vlanClaimValidator{}).ValidateCreate(context.Background(), buildVLANClaim(tc.spec))
+			if tc.expectErr && err == nil {
+				t.Errorf("%s expected error, got nil", name)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("%s unexpected error: %s", name, err.Error())
+			}
+		})
+	}
+}
+
+func TestVLANClaimValidateUpdate(t *testing.T) {
+	oldCr := buildVLANClaim(vlanv1alpha1.VLANClaimSpec{VLANID: util.PointerUint16(100)})
+
+	cr := oldCr.DeepCopy()
+	cr.Spec.Labels = map[string]string{"a": "b"}
+	if _, err := (&vlanClaimValidator{}).ValidateUpdate(context.Background(), oldCr, cr); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+
+	cr = oldCr.DeepCopy()
+	cr.Spec.VLANID = util.PointerUint16(200)
+	if _, err := (&vlanClaimValidator{}).ValidateUpdate(context.Background(), oldCr, cr); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ Copyright 2023 The Nephio Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	vlanv1alpha1 "github.com/nokia/k8s-ipam/apis/resource/vlan/v1alpha1"
+	"github.com/nokia/k8s-ipam/pkg/webhook"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-vlan-resource-nephio-org-v1alpha1-vlanindex,mutating=false,failurePolicy=fail,sideEffects=None,groups=vlan.resource.nephio.org,resources=vlanindices,verbs=create;update,versions=v1alpha1,name=vvlanindex.vlan.resource.nephio.org,admissionReviewVersions=v1
+
+type vlanIndexValidator struct{}
+
+var _ admission.CustomValidator = &vlanIndexValidator{}
+
+func (r *vlanIndexValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*vlanv1alpha1.VLANIndex)
+	if !ok {
+		return nil, fmt.Errorf("expected a VLANIndex, got: %v", reflect.TypeOf(obj))
+	}
+	return nil, webhook.ToInvalid(cr, validateVLANIndex(cr))
+}
+
+func (r *vlanIndexValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*vlanv1alpha1.VLANIndex)
+	if !ok {
+		return nil, fmt.Errorf("expected a VLANIndex, got: %v", reflect.TypeOf(newObj))
+	}
+	return nil, webhook.ToInvalid(cr, validateVLANIndex(cr))
+}
+
+func (r *vlanIndexValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateVLANIndex(cr *vlanv1alpha1.VLANIndex) field.ErrorList {
+	return webhook.ValidateLabels(field.NewPath("spec", "labels"), cr.Spec.Labels)
+}